@@ -5,14 +5,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations"
@@ -26,13 +29,18 @@ import (
 	"github.com/treeverse/lakefs/api/gen/restapi/operations/repositories"
 	retentionop "github.com/treeverse/lakefs/api/gen/restapi/operations/retention"
 	setupop "github.com/treeverse/lakefs/api/gen/restapi/operations/setup"
+	"github.com/treeverse/lakefs/api/gen/restapi/operations/tags"
+	"github.com/treeverse/lakefs/archive"
+	"github.com/treeverse/lakefs/audit"
 	"github.com/treeverse/lakefs/auth"
 	"github.com/treeverse/lakefs/auth/model"
 	"github.com/treeverse/lakefs/block"
+	s3a "github.com/treeverse/lakefs/block/s3"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/dedup"
 	"github.com/treeverse/lakefs/httputil"
+	"github.com/treeverse/lakefs/ident"
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/onboard"
 	"github.com/treeverse/lakefs/permissions"
@@ -63,13 +71,15 @@ type Dependencies struct {
 	Migrator     db.Migrator
 	Collector    stats.Collector
 	logger       logging.Logger
+	// VerifyChecksum verifies the checksum of objects read in full
+	VerifyChecksum bool
 }
 
 func (d *Dependencies) WithContext(ctx context.Context) *Dependencies {
 	return &Dependencies{
 		ctx:          ctx,
 		Cataloger:    d.Cataloger,
-		Auth:         d.Auth,
+		Auth:         d.Auth.WithContext(ctx),
 		BlockAdapter: d.BlockAdapter.WithContext(ctx),
 		Stats:        d.Stats,
 		Retention:    d.Retention,
@@ -78,6 +88,8 @@ func (d *Dependencies) WithContext(ctx context.Context) *Dependencies {
 		Migrator:     d.Migrator,
 		Collector:    d.Collector,
 		logger:       d.logger.WithContext(ctx),
+
+		VerifyChecksum: d.VerifyChecksum,
 	}
 }
 
@@ -87,6 +99,7 @@ func (d *Dependencies) LogAction(action string) {
 		WithField("message_type", "action").
 		Debug("performing API action")
 	d.Stats.CollectEvent("api_server", action)
+	audit.SetAction(d.ctx, action)
 }
 
 type Controller struct {
@@ -94,7 +107,7 @@ type Controller struct {
 }
 
 func NewController(cataloger catalog.Cataloger, auth auth.Service, blockAdapter block.Adapter, stats stats.Collector, retention retention.Service,
-	dedupCleaner *dedup.Cleaner, meta auth.MetadataManager, migrator db.Migrator, collector stats.Collector, logger logging.Logger) *Controller {
+	dedupCleaner *dedup.Cleaner, meta auth.MetadataManager, migrator db.Migrator, collector stats.Collector, verifyChecksum bool, logger logging.Logger) *Controller {
 	c := &Controller{
 		deps: &Dependencies{
 			ctx:          context.Background(),
@@ -108,6 +121,8 @@ func NewController(cataloger catalog.Cataloger, auth auth.Service, blockAdapter
 			Migrator:     migrator,
 			Collector:    collector,
 			logger:       logger,
+
+			VerifyChecksum: verifyChecksum,
 		},
 	}
 	return c
@@ -159,39 +174,79 @@ func (c *Controller) Configure(api *operations.LakefsAPI) {
 	api.RepositoriesListRepositoriesHandler = c.ListRepositoriesHandler()
 	api.RepositoriesGetRepositoryHandler = c.GetRepoHandler()
 	api.RepositoriesCreateRepositoryHandler = c.CreateRepositoryHandler()
+	api.RepositoriesForkRepositoryHandler = c.ForkRepositoryHandler()
 	api.RepositoriesDeleteRepositoryHandler = c.DeleteRepositoryHandler()
+	api.RepositoriesUpdateRepositoryHandler = c.UpdateRepositoryHandler()
 	api.RepositoriesImportFromS3InventoryHandler = c.ImportFromS3InventoryHandler()
+	api.RepositoriesGetEventsHandler = c.GetEventsHandler()
+	api.RepositoriesGetReplicationStreamHandler = c.GetReplicationStreamHandler()
+	api.RepositoriesGetProtectedPathsHandler = c.GetProtectedPathsHandler()
+	api.RepositoriesSetProtectedPathsHandler = c.SetProtectedPathsHandler()
+	api.RepositoriesGetRepositoryQuotasHandler = c.GetRepositoryQuotasHandler()
+	api.RepositoriesSetRepositoryQuotasHandler = c.SetRepositoryQuotasHandler()
 
 	api.BranchesListBranchesHandler = c.ListBranchesHandler()
 	api.BranchesGetBranchHandler = c.GetBranchHandler()
 	api.BranchesCreateBranchHandler = c.CreateBranchHandler()
 	api.BranchesDeleteBranchHandler = c.DeleteBranchHandler()
 	api.BranchesRevertBranchHandler = c.RevertBranchHandler()
+	api.BranchesRebaseBranchHandler = c.RebaseBranchHandler()
+	api.BranchesCopyToBranchHandler = c.CopyToBranchHandler()
+
+	api.TagsListTagsHandler = c.ListTagsHandler()
+	api.TagsGetTagHandler = c.GetTagHandler()
+	api.TagsCreateTagHandler = c.CreateTagHandler()
+	api.TagsDeleteTagHandler = c.DeleteTagHandler()
 
 	api.CommitsCommitHandler = c.CommitHandler()
 	api.CommitsGetCommitHandler = c.GetCommitHandler()
 	api.CommitsGetBranchCommitLogHandler = c.CommitsGetBranchCommitLogHandler()
+	api.CommitsSearchCommitsHandler = c.SearchCommitsHandler()
 
 	api.RefsDiffRefsHandler = c.RefsDiffRefsHandler()
 	api.BranchesDiffBranchHandler = c.BranchesDiffBranchHandler()
+	api.BranchesExportWorkspaceHandler = c.BranchesExportWorkspaceHandler()
+	api.BranchesImportWorkspaceHandler = c.BranchesImportWorkspaceHandler()
 	api.RefsMergeIntoBranchHandler = c.MergeMergeIntoBranchHandler()
+	api.RefsListMergeConflictsHandler = c.RefsListMergeConflictsHandler()
 
 	api.ObjectsStatObjectHandler = c.ObjectsStatObjectHandler()
+	api.ObjectsBlameObjectHandler = c.ObjectsBlameObjectHandler()
 	api.ObjectsGetUnderlyingPropertiesHandler = c.ObjectsGetUnderlyingPropertiesHandler()
 	api.ObjectsListObjectsHandler = c.ObjectsListObjectsHandler()
 	api.ObjectsGetObjectHandler = c.ObjectsGetObjectHandler()
+	api.ObjectsGetArchiveHandler = c.ObjectsGetArchiveHandler()
 	api.ObjectsUploadObjectHandler = c.ObjectsUploadObjectHandler()
 	api.ObjectsDeleteObjectHandler = c.ObjectsDeleteObjectHandler()
+	api.ObjectsRenameObjectHandler = c.ObjectsRenameObjectHandler()
+	api.ObjectsCopyObjectHandler = c.ObjectsCopyObjectHandler()
+	api.ObjectsStageObjectsHandler = c.ObjectsStageObjectsHandler()
+	api.ObjectsDeleteObjectsHandler = c.ObjectsDeleteObjectsHandler()
 
 	api.RetentionGetRetentionPolicyHandler = c.RetentionGetRetentionPolicyHandler()
 	api.RetentionUpdateRetentionPolicyHandler = c.RetentionUpdateRetentionPolicyHandler()
+	api.RetentionGetExpiryReportHandler = c.RetentionGetExpiryReportHandler()
+	api.RetentionGetBranchExpiryPolicyHandler = c.RetentionGetBranchExpiryPolicyHandler()
+	api.RetentionUpdateBranchExpiryPolicyHandler = c.RetentionUpdateBranchExpiryPolicyHandler()
+	api.RetentionGetWorkspaceExpiryPolicyHandler = c.RetentionGetWorkspaceExpiryPolicyHandler()
+	api.RetentionUpdateWorkspaceExpiryPolicyHandler = c.RetentionUpdateWorkspaceExpiryPolicyHandler()
 	api.MetadataCreateSymlinkHandler = c.MetadataCreateSymlinkHandler()
 }
 
 func (c *Controller) setupRequest(user *models.User, r *http.Request, permissions []permissions.Permission) (*Dependencies, error) {
-	// add user to context
-	ctx := logging.AddFields(r.Context(), logging.Fields{"user": user.ID})
+	// add user and the resource to context
+	repository, ref, path := auditResource(r)
+	fields := logging.Fields{"user": user.ID}
+	if repository != "" {
+		fields["repository"] = repository
+	}
+	if ref != "" {
+		fields["ref"] = ref
+	}
+	ctx := logging.AddFields(r.Context(), fields)
 	ctx = context.WithValue(ctx, UserContextKey, user)
+	audit.SetActor(ctx, user.ID)
+	audit.SetResource(ctx, repository, ref, path)
 	deps := c.deps.WithContext(ctx)
 	return deps, authorize(deps.Auth, user, permissions)
 }
@@ -302,7 +357,24 @@ func (c *Controller) ListRepositoriesHandler() repositories.ListRepositoriesHand
 
 		after, amount := getPaginationParams(params.After, params.Amount)
 
-		repos, hasMore, err := deps.Cataloger.ListRepositories(c.Context(), amount, after)
+		listParams := catalog.ListRepositoriesParams{
+			Prefix: swag.StringValue(params.Prefix),
+		}
+		if swag.StringValue(params.OrderBy) == "creation_date" {
+			listParams.OrderBy = catalog.RepositoriesOrderByCreationDate
+		}
+		for _, label := range params.Label {
+			kv := strings.SplitN(label, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return repositories.NewListRepositoriesDefault(http.StatusBadRequest).
+					WithPayload(responseError("invalid label %q, expected key=value", label))
+			}
+			if listParams.Labels == nil {
+				listParams.Labels = make(catalog.Metadata)
+			}
+			listParams.Labels[kv[0]] = kv[1]
+		}
+		repos, hasMore, err := deps.Cataloger.ListRepositories(c.Context(), listParams, amount, after)
 		if err != nil {
 			return repositories.NewListRepositoriesDefault(http.StatusInternalServerError).
 				WithPayload(responseError("error listing repositories: %s", err))
@@ -316,6 +388,9 @@ func (c *Controller) ListRepositoriesHandler() repositories.ListRepositoriesHand
 				CreationDate:     repo.CreationDate.Unix(),
 				DefaultBranch:    repo.DefaultBranch,
 				ID:               repo.Name,
+				ReadOnly:         repo.ReadOnly,
+				Labels:           repo.Labels,
+				HashAlgorithm:    string(repo.HashAlgorithm),
 			}
 			lastID = repo.Name
 		}
@@ -341,6 +416,10 @@ func getPaginationParams(swagAfter *string, swagAmount *int64) (string, int) {
 	if swagAmount != nil {
 		amount = int(swag.Int64Value(swagAmount))
 	}
+	// -1 lists all results, used by clients that read a listing in one request
+	if amount != -1 && (amount < 0 || amount > MaxResultsPerPage) {
+		amount = MaxResultsPerPage
+	}
 
 	// paginate after
 	after := ""
@@ -378,10 +457,103 @@ func (c *Controller) GetRepoHandler() repositories.GetRepositoryHandler {
 				CreationDate:     repo.CreationDate.Unix(),
 				DefaultBranch:    repo.DefaultBranch,
 				ID:               repo.Name,
+				ReadOnly:         repo.ReadOnly,
+				Labels:           repo.Labels,
+				HashAlgorithm:    string(repo.HashAlgorithm),
 			})
 	})
 }
 
+func (c *Controller) GetEventsHandler() repositories.GetEventsHandler {
+	return repositories.GetEventsHandlerFunc(func(params repositories.GetEventsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewGetEventsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_events")
+		_, amount := getPaginationParams(nil, params.Amount)
+		res, hasMore, err := deps.Cataloger.GetEvents(c.Context(), params.Repository, swag.Int64Value(params.After), amount)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetEventsNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetEventsDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not list events: %s", err))
+		}
+
+		events := make([]*models.RepositoryEvent, len(res))
+		var lastSeq int64
+		for i, event := range res {
+			events[i] = &models.RepositoryEvent{
+				Seq:          swag.Int64(event.Seq),
+				Type:         swag.String(event.Type),
+				Branch:       swag.String(event.Branch),
+				Path:         event.Path,
+				Reference:    event.Reference,
+				CreationDate: swag.Int64(event.CreationDate.Unix()),
+			}
+			lastSeq = event.Seq
+		}
+		returnValue := repositories.NewGetEventsOK().WithPayload(&repositories.GetEventsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(events))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: events,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = strconv.FormatInt(lastSeq, 10)
+		}
+		return returnValue
+	})
+}
+
+func (c *Controller) GetReplicationStreamHandler() repositories.GetReplicationStreamHandler {
+	return repositories.GetReplicationStreamHandlerFunc(func(params repositories.GetReplicationStreamParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewGetReplicationStreamUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_replication_stream")
+		cataloger := deps.Cataloger
+
+		_, err = cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetReplicationStreamNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetReplicationStreamDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		// the stream is exported while it is sent to the client
+		reader, writer := io.Pipe()
+		go func() {
+			_, err := cataloger.ExportReplication(c.Context(), params.Repository, swag.Int64Value(params.After), writer)
+			if err != nil {
+				deps.logger.WithError(err).Error("failed to export replication stream")
+			}
+			_ = writer.CloseWithError(err)
+		}()
+		return repositories.NewGetReplicationStreamOK().WithPayload(reader)
+	})
+}
+
 func (c *Controller) GetCommitHandler() commits.GetCommitHandler {
 	return commits.GetCommitHandlerFunc(func(params commits.GetCommitParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -404,7 +576,7 @@ func (c *Controller) GetCommitHandler() commits.GetCommitHandler {
 		return commits.NewGetCommitOK().WithPayload(&models.Commit{
 			Committer:    commit.Committer,
 			CreationDate: commit.CreationDate.Unix(),
-			ID:           params.CommitID,
+			ID:           commit.Reference,
 			Message:      commit.Message,
 			Metadata:     commit.Metadata,
 			Parents:      commit.Parents,
@@ -431,7 +603,16 @@ func (c *Controller) CommitHandler() commits.CommitHandler {
 		committer := userModel.Username
 		commitMessage := swag.StringValue(params.Commit.Message)
 		commit, err := deps.Cataloger.Commit(c.Context(), params.Repository,
-			params.Branch, commitMessage, committer, params.Commit.Metadata)
+			params.Branch, commitMessage, committer, params.Commit.Metadata,
+			catalog.WithAllowEmpty(params.Commit.AllowEmpty),
+			catalog.WithExpectedCommit(params.Commit.ExpectedCommit),
+			catalog.WithExpectedVersion(params.Commit.ExpectedVersion))
+		if errors.Is(err, catalog.ErrBranchHeadMoved) || errors.Is(err, catalog.ErrBranchConcurrentUpdate) {
+			return commits.NewCommitConflict().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrHookRejected) {
+			return commits.NewCommitPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return commits.NewCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
@@ -461,8 +642,18 @@ func (c *Controller) CommitsGetBranchCommitLogHandler() commits.GetBranchCommitL
 		cataloger := deps.Cataloger
 
 		after, amount := getPaginationParams(params.After, params.Amount)
+		var since, until time.Time
+		if params.Since != nil {
+			since = time.Time(*params.Since)
+		}
+		if params.Until != nil {
+			until = time.Time(*params.Until)
+		}
 		// get commit log
-		commitLog, hasMore, err := cataloger.ListCommits(c.Context(), params.Repository, params.Branch, after, amount)
+		commitLog, hasMore, err := cataloger.ListCommits(c.Context(), params.Repository, params.Branch, after, amount,
+			catalog.WithPathPrefix(swag.StringValue(params.Prefix)),
+			catalog.WithCommitter(swag.StringValue(params.Committer)),
+			catalog.WithTimeRange(since, until))
 		if errors.Is(err, db.ErrNotFound) {
 			return commits.NewGetBranchCommitLogNotFound().WithPayload(responseError("branch '%s' not found", params.Branch))
 		}
@@ -499,6 +690,79 @@ func (c *Controller) CommitsGetBranchCommitLogHandler() commits.GetBranchCommitL
 	})
 }
 
+func (c *Controller) SearchCommitsHandler() commits.SearchCommitsHandler {
+	return commits.SearchCommitsHandlerFunc(func(params commits.SearchCommitsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return commits.NewSearchCommitsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("search_commits")
+
+		searchParams := catalog.SearchCommitsParams{
+			Message:        swag.StringValue(params.Message),
+			MessagePattern: swag.StringValue(params.MessagePattern),
+			Committer:      swag.StringValue(params.Committer),
+		}
+		if params.Since != nil {
+			searchParams.Since = time.Time(*params.Since)
+		}
+		if params.Until != nil {
+			searchParams.Until = time.Time(*params.Until)
+		}
+		if len(params.Metadata) > 0 {
+			const keyValueParts = 2
+			searchParams.Metadata = make(catalog.Metadata, len(params.Metadata))
+			for _, pair := range params.Metadata {
+				kv := strings.SplitN(pair, "=", keyValueParts)
+				if len(kv) != keyValueParts {
+					return commits.NewSearchCommitsBadRequest().WithPayload(responseError("invalid metadata '%s', expected key=value", pair))
+				}
+				searchParams.Metadata[kv[0]] = kv[1]
+			}
+		}
+		after, amount := getPaginationParams(params.After, params.Amount)
+		res, hasMore, err := deps.Cataloger.SearchCommits(c.Context(), params.Repository, searchParams, amount, after)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return commits.NewSearchCommitsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return commits.NewSearchCommitsNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return commits.NewSearchCommitsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.Commit, len(res))
+		for i, commit := range res {
+			results[i] = &models.Commit{
+				Committer:    commit.Committer,
+				CreationDate: commit.CreationDate.Unix(),
+				ID:           commit.Reference,
+				Message:      commit.Message,
+				Metadata:     commit.Metadata,
+				Parents:      commit.Parents,
+			}
+		}
+		returnValue := commits.NewSearchCommitsOK().WithPayload(&commits.SearchCommitsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(results))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: results,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = results[len(results)-1].ID
+		}
+		return returnValue
+	})
+}
+
 func ensureStorageNamespaceRW(adapter block.Adapter, storageNamespace string) error {
 	const (
 		dummyKey  = "dummy"
@@ -544,6 +808,14 @@ func (c *Controller) CreateRepositoryHandler() repositories.CreateRepositoryHand
 			swag.StringValue(params.Repository.ID),
 			swag.StringValue(params.Repository.StorageNamespace),
 			params.Repository.DefaultBranch)
+		if errors.Is(err, catalog.ErrStorageNamespaceOverlap) {
+			return repositories.NewCreateRepositoryBadRequest().
+				WithPayload(responseError("error creating repository: %s", err))
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			return repositories.NewGetRepositoryDefault(http.StatusForbidden).
+				WithPayload(responseError(fmt.Sprintf("error creating repository: %s", err)))
+		}
 		if err != nil {
 			return repositories.NewGetRepositoryDefault(http.StatusInternalServerError).
 				WithPayload(responseError(fmt.Sprintf("error creating repository: %s", err)))
@@ -588,6 +860,171 @@ func (c *Controller) DeleteRepositoryHandler() repositories.DeleteRepositoryHand
 	})
 }
 
+func (c *Controller) UpdateRepositoryHandler() repositories.UpdateRepositoryHandler {
+	return repositories.UpdateRepositoryHandlerFunc(func(params repositories.UpdateRepositoryParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.UpdateRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewUpdateRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("update_repo")
+		err = deps.Cataloger.UpdateRepository(c.Context(), params.Repository, catalog.UpdateRepositoryParams{
+			DefaultBranch: params.Settings.DefaultBranch,
+			ReadOnly:      params.Settings.ReadOnly,
+			Labels:        params.Settings.Labels,
+			HashAlgorithm: ident.Algorithm(params.Settings.HashAlgorithm),
+		})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewUpdateRepositoryBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewUpdateRepositoryNotFound().WithPayload(responseError("repository or branch not found"))
+		}
+		if err != nil {
+			return repositories.NewUpdateRepositoryDefault(http.StatusInternalServerError).
+				WithPayload(responseError("error updating repository"))
+		}
+		return repositories.NewUpdateRepositoryNoContent()
+	})
+}
+
+func (c *Controller) GetProtectedPathsHandler() repositories.GetProtectedPathsHandler {
+	return repositories.GetProtectedPathsHandlerFunc(func(params repositories.GetProtectedPathsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewGetProtectedPathsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_protected_paths")
+		rules, err := deps.Cataloger.GetProtectedPaths(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetProtectedPathsNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetProtectedPathsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		payload := &models.ProtectedPaths{
+			Rules: make([]*models.ProtectedPathRule, len(rules)),
+		}
+		for i, rule := range rules {
+			payload.Rules[i] = &models.ProtectedPathRule{
+				Pattern:  swag.String(rule.Pattern),
+				Branches: rule.Branches,
+			}
+		}
+		return repositories.NewGetProtectedPathsOK().WithPayload(payload)
+	})
+}
+
+func (c *Controller) SetProtectedPathsHandler() repositories.SetProtectedPathsHandler {
+	return repositories.SetProtectedPathsHandlerFunc(func(params repositories.SetProtectedPathsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.UpdateRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewSetProtectedPathsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_protected_paths")
+		rules := make([]*catalog.ProtectedPathRule, len(params.ProtectedPaths.Rules))
+		for i, rule := range params.ProtectedPaths.Rules {
+			rules[i] = &catalog.ProtectedPathRule{
+				Pattern:  swag.StringValue(rule.Pattern),
+				Branches: rule.Branches,
+			}
+		}
+		err = deps.Cataloger.SetProtectedPaths(c.Context(), params.Repository, rules)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetProtectedPathsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetProtectedPathsNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewSetProtectedPathsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewSetProtectedPathsNoContent()
+	})
+}
+
+func (c *Controller) GetRepositoryQuotasHandler() repositories.GetRepositoryQuotasHandler {
+	return repositories.GetRepositoryQuotasHandlerFunc(func(params repositories.GetRepositoryQuotasParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewGetRepositoryQuotasUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_repository_quotas")
+		quotas, err := deps.Cataloger.GetRepositoryQuotas(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetRepositoryQuotasNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetRepositoryQuotasDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		usage, err := deps.Cataloger.GetRepositoryUsage(c.Context(), params.Repository)
+		if err != nil {
+			return repositories.NewGetRepositoryQuotasDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewGetRepositoryQuotasOK().WithPayload(&models.RepositoryQuotasStatus{
+			Quotas: &models.RepositoryQuotas{
+				MaxBranches:  quotas.MaxBranches,
+				MaxObjects:   quotas.MaxObjects,
+				MaxSizeBytes: quotas.MaxSizeBytes,
+			},
+			Usage: &models.RepositoryUsage{
+				Branches:  swag.Int64(usage.Branches),
+				Objects:   swag.Int64(usage.Objects),
+				SizeBytes: swag.Int64(usage.SizeBytes),
+			},
+		})
+	})
+}
+
+func (c *Controller) SetRepositoryQuotasHandler() repositories.SetRepositoryQuotasHandler {
+	return repositories.SetRepositoryQuotasHandlerFunc(func(params repositories.SetRepositoryQuotasParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.SetQuotasAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewSetRepositoryQuotasUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_repository_quotas")
+		err = deps.Cataloger.SetRepositoryQuotas(c.Context(), params.Repository, &catalog.RepositoryQuotas{
+			MaxBranches:  params.Quotas.MaxBranches,
+			MaxObjects:   params.Quotas.MaxObjects,
+			MaxSizeBytes: params.Quotas.MaxSizeBytes,
+		})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetRepositoryQuotasBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetRepositoryQuotasNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewSetRepositoryQuotasDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewSetRepositoryQuotasNoContent()
+	})
+}
+
 func (c *Controller) ListBranchesHandler() branches.ListBranchesHandler {
 	return branches.ListBranchesHandlerFunc(func(params branches.ListBranchesParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -645,7 +1082,7 @@ func (c *Controller) GetBranchHandler() branches.GetBranchHandler {
 			return branches.NewGetBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("get_branch")
-		reference, err := deps.Cataloger.GetBranchReference(c.Context(), params.Repository, params.Branch)
+		branch, err := deps.Cataloger.GetBranch(c.Context(), params.Repository, params.Branch)
 		if errors.Is(err, db.ErrNotFound) {
 			return branches.NewGetBranchNotFound().
 				WithPayload(responseError("branch '%s' not found", params.Branch))
@@ -655,7 +1092,9 @@ func (c *Controller) GetBranchHandler() branches.GetBranchHandler {
 				WithPayload(responseError("error fetching branch: %s", err))
 		}
 
-		return branches.NewGetBranchOK().WithPayload(reference)
+		return branches.NewGetBranchOK().
+			WithPayload(branch.CommitReference).
+			WithETag(strconv.FormatInt(branch.Version, 10))
 	})
 }
 
@@ -674,8 +1113,11 @@ func (c *Controller) CreateBranchHandler() branches.CreateBranchHandler {
 		}
 		deps.LogAction("create_branch")
 		cataloger := deps.Cataloger
-		sourceBranch := swag.StringValue(params.Branch.Source)
-		commitLog, err := cataloger.CreateBranch(c.Context(), repository, branch, sourceBranch)
+		sourceRef := swag.StringValue(params.Branch.Source)
+		commitLog, err := cataloger.CreateBranch(c.Context(), repository, branch, sourceRef)
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			return branches.NewCreateBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return branches.NewCreateBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
@@ -696,72 +1138,234 @@ func (c *Controller) DeleteBranchHandler() branches.DeleteBranchHandler {
 		}
 		deps.LogAction("delete_branch")
 		cataloger := deps.Cataloger
-		err = cataloger.DeleteBranch(c.Context(), params.Repository, params.Branch)
+		reference, err := cataloger.DeleteBranch(c.Context(), params.Repository, params.Branch)
 		if errors.Is(err, db.ErrNotFound) {
 			return branches.NewDeleteBranchNotFound().
 				WithPayload(responseError("branch '%s' not found", params.Branch))
 		}
+		if errors.Is(err, catalog.ErrOperationNotPermitted) {
+			return branches.NewDeleteBranchDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return branches.NewDeleteBranchDefault(http.StatusInternalServerError).
 				WithPayload(responseError("error fetching branch: %s", err))
 		}
 
-		return branches.NewDeleteBranchNoContent()
+		return branches.NewDeleteBranchOK().WithPayload(reference)
 	})
 }
 
-func (c *Controller) MergeMergeIntoBranchHandler() refs.MergeIntoBranchHandler {
-	return refs.MergeIntoBranchHandlerFunc(func(params refs.MergeIntoBranchParams, user *models.User) middleware.Responder {
+func tagModel(tag *catalog.Tag) *models.Tag {
+	return &models.Tag{
+		Name:         tag.Name,
+		ID:           tag.ID,
+		CommitID:     tag.CommitReference,
+		Tagger:       tag.Tagger,
+		Message:      tag.Message,
+		Metadata:     tag.Metadata,
+		CreationDate: tag.CreationDate.Unix(),
+	}
+}
+
+func (c *Controller) ListTagsHandler() tags.ListTagsHandler {
+	return tags.ListTagsHandlerFunc(func(params tags.ListTagsParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.CreateCommitAction,
-				Resource: permissions.BranchArn(params.Repository, params.DestinationRef),
+				Action:   permissions.ListTagsAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
+			return tags.NewListTagsUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("merge_branches")
-		userModel, err := deps.Auth.GetUser(user.ID)
+		deps.LogAction("list_tags")
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		res, hasMore, err := deps.Cataloger.ListTags(c.Context(), params.Repository, amount, after)
 		if err != nil {
-			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
+			return tags.NewListTagsDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not list tags: %s", err))
 		}
-		var message string
-		var metadata map[string]string
-		if params.Merge != nil {
-			message = params.Merge.Message
-			metadata = params.Merge.Metadata
-		}
-		res, err := deps.Cataloger.Merge(c.Context(),
-			params.Repository, params.SourceRef, params.DestinationRef,
-			userModel.Username,
-			message,
-			metadata)
 
-		switch err {
-		case nil:
-			payload := newMergeResultFromCatalog(res)
-			return refs.NewMergeIntoBranchOK().WithPayload(payload)
-		case catalog.ErrUnsupportedRelation:
-			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("branches have no common base"))
-		case catalog.ErrBranchNotFound:
-			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("a branch does not exist "))
-		case catalog.ErrConflictFound:
-			payload := newMergeResultFromCatalog(res)
-			return refs.NewMergeIntoBranchConflict().WithPayload(payload)
-		case catalog.ErrNoDifferenceWasFound:
-			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("no difference was found"))
-		default:
-			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("internal error"))
+		tagList := make([]*models.Tag, len(res))
+		var lastID string
+		for i, tag := range res {
+			tagList[i] = tagModel(tag)
+			lastID = tag.Name
+		}
+		returnValue := tags.NewListTagsOK().WithPayload(&tags.ListTagsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(tagList))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: tagList,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
 		}
+		return returnValue
 	})
 }
 
-func newMergeResultFromCatalog(res *catalog.MergeResult) *models.MergeResult {
-	if res == nil {
-		return nil
-	}
-	var summary models.MergeResultSummary
+func (c *Controller) GetTagHandler() tags.GetTagHandler {
+	return tags.GetTagHandlerFunc(func(params tags.GetTagParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadTagAction,
+				Resource: permissions.TagArn(params.Repository, params.Tag),
+			},
+		})
+		if err != nil {
+			return tags.NewGetTagUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_tag")
+		tag, err := deps.Cataloger.GetTag(c.Context(), params.Repository, params.Tag)
+		if errors.Is(err, db.ErrNotFound) {
+			return tags.NewGetTagNotFound().
+				WithPayload(responseError("tag '%s' not found", params.Tag))
+		}
+		if err != nil {
+			return tags.NewGetTagDefault(http.StatusInternalServerError).
+				WithPayload(responseError("error fetching tag: %s", err))
+		}
+		return tags.NewGetTagOK().WithPayload(tagModel(tag))
+	})
+}
+
+func (c *Controller) CreateTagHandler() tags.CreateTagHandler {
+	return tags.CreateTagHandlerFunc(func(params tags.CreateTagParams, user *models.User) middleware.Responder {
+		tagName := swag.StringValue(params.Tag.Name)
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateTagAction,
+				Resource: permissions.TagArn(params.Repository, tagName),
+			},
+		})
+		if err != nil {
+			return tags.NewCreateTagUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("create_tag")
+		userModel, err := deps.Auth.GetUser(user.ID)
+		if err != nil {
+			return tags.NewCreateTagUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		tag, err := deps.Cataloger.CreateTag(c.Context(), params.Repository, tagName,
+			swag.StringValue(params.Tag.Ref), userModel.Username, params.Tag.Message, params.Tag.Metadata)
+		switch {
+		case errors.Is(err, catalog.ErrTagAlreadyExists):
+			return tags.NewCreateTagConflict().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidValue):
+			return tags.NewCreateTagBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, db.ErrNotFound):
+			return tags.NewCreateTagNotFound().WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return tags.NewCreateTagDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return tags.NewCreateTagCreated().WithPayload(tagModel(tag))
+	})
+}
+
+func (c *Controller) DeleteTagHandler() tags.DeleteTagHandler {
+	return tags.DeleteTagHandlerFunc(func(params tags.DeleteTagParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.DeleteTagAction,
+				Resource: permissions.TagArn(params.Repository, params.Tag),
+			},
+		})
+		if err != nil {
+			return tags.NewDeleteTagUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_tag")
+		err = deps.Cataloger.DeleteTag(c.Context(), params.Repository, params.Tag)
+		if errors.Is(err, db.ErrNotFound) {
+			return tags.NewDeleteTagNotFound().
+				WithPayload(responseError("tag '%s' not found", params.Tag))
+		}
+		if err != nil {
+			return tags.NewDeleteTagDefault(http.StatusInternalServerError).
+				WithPayload(responseError("error deleting tag: %s", err))
+		}
+		return tags.NewDeleteTagNoContent()
+	})
+}
+
+func (c *Controller) MergeMergeIntoBranchHandler() refs.MergeIntoBranchHandler {
+	return refs.MergeIntoBranchHandlerFunc(func(params refs.MergeIntoBranchParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.DestinationRef),
+			},
+		})
+		if err != nil {
+			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("merge_branches")
+		userModel, err := deps.Auth.GetUser(user.ID)
+		if err != nil {
+			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		var message string
+		var metadata map[string]string
+		var opts []catalog.MergeOpt
+		if params.Merge != nil {
+			message = params.Merge.Message
+			metadata = params.Merge.Metadata
+			if params.Merge.Squash {
+				opts = append(opts, catalog.WithSquash())
+			}
+			if params.Merge.Strategy != "" {
+				opts = append(opts, catalog.WithStrategy(catalog.MergeStrategy(params.Merge.Strategy)))
+			}
+			if params.Merge.DryRun {
+				opts = append(opts, catalog.WithDryRun())
+			}
+			if params.Merge.ExpectedDestinationVersion != 0 {
+				opts = append(opts, catalog.WithExpectedDestinationVersion(params.Merge.ExpectedDestinationVersion))
+			}
+		}
+		res, err := deps.Cataloger.Merge(c.Context(),
+			params.Repository, params.SourceRef, params.DestinationRef,
+			userModel.Username,
+			message,
+			metadata,
+			opts...)
+		if errors.Is(err, catalog.ErrHookRejected) || errors.Is(err, catalog.ErrBranchConcurrentUpdate) {
+			return refs.NewMergeIntoBranchPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrOperationNotPermitted) || errors.Is(err, catalog.ErrInvalidValue) {
+			return refs.NewMergeIntoBranchDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			return refs.NewMergeIntoBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+
+		switch err {
+		case nil:
+			payload := newMergeResultFromCatalog(res)
+			return refs.NewMergeIntoBranchOK().WithPayload(payload)
+		case catalog.ErrUnsupportedRelation:
+			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("branches have no common base"))
+		case catalog.ErrBranchNotFound:
+			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("a branch does not exist "))
+		case catalog.ErrConflictFound:
+			payload := newMergeResultFromCatalog(res)
+			return refs.NewMergeIntoBranchConflict().WithPayload(payload)
+		case catalog.ErrNoDifferenceWasFound:
+			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("no difference was found"))
+		default:
+			return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseError("internal error"))
+		}
+	})
+}
+
+func newMergeResultFromCatalog(res *catalog.MergeResult) *models.MergeResult {
+	if res == nil {
+		return nil
+	}
+	var summary models.MergeResultSummary
 	for k, v := range res.Summary {
 		val := int64(v)
 		switch k {
@@ -778,7 +1382,57 @@ func newMergeResultFromCatalog(res *catalog.MergeResult) *models.MergeResult {
 	return &models.MergeResult{
 		Reference: res.Reference,
 		Summary:   &summary,
+		Conflicts: newMergeConflictsFromCatalog(res.Conflicts),
+	}
+}
+
+func newMergeConflictsFromCatalog(conflicts []*catalog.MergeConflict) []*models.MergeConflict {
+	results := make([]*models.MergeConflict, len(conflicts))
+	for i, conflict := range conflicts {
+		results[i] = &models.MergeConflict{
+			Path: swag.String(conflict.Path),
+			Type: swag.String(string(conflict.Type)),
+		}
 	}
+	return results
+}
+
+func (c *Controller) RefsListMergeConflictsHandler() refs.ListMergeConflictsHandler {
+	return refs.ListMergeConflictsHandlerFunc(func(params refs.ListMergeConflictsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return refs.NewListMergeConflictsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("list_merge_conflicts")
+		limit := int(swag.Int64Value(params.Amount))
+		after := swag.StringValue(params.After)
+		conflicts, hasMore, err := deps.Cataloger.ListMergeConflicts(c.Context(), params.Repository, params.SourceRef, params.DestinationRef, limit, after)
+		if errors.Is(err, db.ErrNotFound) {
+			return refs.NewListMergeConflictsNotFound().WithPayload(responseError("branch not found"))
+		}
+		if err != nil {
+			return refs.NewListMergeConflictsDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not list merge conflicts: %s", err))
+		}
+		var nextOffset string
+		if hasMore && len(conflicts) > 0 {
+			nextOffset = conflicts[len(conflicts)-1].Path
+		}
+		return refs.NewListMergeConflictsOK().WithPayload(&refs.ListMergeConflictsOKBody{
+			Results: newMergeConflictsFromCatalog(conflicts),
+			Pagination: &models.Pagination{
+				NextOffset: nextOffset,
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(conflicts))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+		})
+	})
 }
 
 func (c *Controller) BranchesDiffBranchHandler() branches.DiffBranchHandler {
@@ -822,6 +1476,78 @@ func (c *Controller) BranchesDiffBranchHandler() branches.DiffBranchHandler {
 	})
 }
 
+func (c *Controller) BranchesExportWorkspaceHandler() branches.ExportWorkspaceHandler {
+	return branches.ExportWorkspaceHandlerFunc(func(params branches.ExportWorkspaceParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return branches.NewExportWorkspaceUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("export_workspace")
+		cataloger := deps.Cataloger
+
+		_, err = cataloger.GetBranchReference(c.Context(), params.Repository, params.Branch)
+		if errors.Is(err, db.ErrNotFound) {
+			return branches.NewExportWorkspaceNotFound().WithPayload(responseError("branch not found"))
+		}
+		if err != nil {
+			return branches.NewExportWorkspaceDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		// the workspace is exported while it is sent to the client
+		reader, writer := io.Pipe()
+		go func() {
+			err := catalog.ExportWorkspace(c.Context(), cataloger, params.Repository, params.Branch, writer)
+			if err != nil {
+				deps.logger.WithError(err).Error("failed to export workspace")
+			}
+			_ = writer.CloseWithError(err)
+		}()
+		return branches.NewExportWorkspaceOK().WithPayload(reader)
+	})
+}
+
+func (c *Controller) BranchesImportWorkspaceHandler() branches.ImportWorkspaceHandler {
+	return branches.ImportWorkspaceHandlerFunc(func(params branches.ImportWorkspaceParams, user *models.User) middleware.Responder {
+		// the paths the workspace writes are not known in advance
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, "*"),
+			},
+			{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, "*"),
+			},
+		})
+		if err != nil {
+			return branches.NewImportWorkspaceUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("import_workspace")
+		defer func() { _ = params.Content.Close() }()
+
+		err = catalog.ImportWorkspace(c.Context(), deps.Cataloger, params.Repository, params.Branch, params.Content)
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrWorkspaceVersion) ||
+			errors.Is(err, catalog.ErrAddressOutsideNamespace) || errors.Is(err, catalog.ErrOperationNotPermitted) {
+			return branches.NewImportWorkspaceBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return branches.NewImportWorkspaceForbidden().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return branches.NewImportWorkspaceNotFound().WithPayload(responseError("branch not found"))
+		}
+		if err != nil {
+			return branches.NewImportWorkspaceDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return branches.NewImportWorkspaceNoContent()
+	})
+}
+
 func (c *Controller) RefsDiffRefsHandler() refs.DiffRefsHandler {
 	return refs.DiffRefsHandlerFunc(func(params refs.DiffRefsParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -880,7 +1606,7 @@ func (c *Controller) ObjectsStatObjectHandler() objects.StatObjectHandler {
 		deps.LogAction("stat_object")
 		cataloger := deps.Cataloger
 
-		entry, err := cataloger.GetEntry(c.Context(), params.Repository, params.Ref, params.Path, catalog.GetEntryParams{ReturnExpired: true})
+		entry, err := cataloger.StatEntry(c.Context(), params.Repository, params.Ref, params.Path)
 		if errors.Is(err, db.ErrNotFound) {
 			return objects.NewStatObjectNotFound().WithPayload(responseError("resource not found"))
 		}
@@ -891,11 +1617,14 @@ func (c *Controller) ObjectsStatObjectHandler() objects.StatObjectHandler {
 
 		// serialize entry
 		obj := &models.ObjectStats{
-			Checksum:  entry.Checksum,
-			Mtime:     entry.CreationDate.Unix(),
-			Path:      params.Path,
-			PathType:  models.ObjectStatsPathTypeObject,
-			SizeBytes: entry.Size,
+			Checksum:        entry.Checksum,
+			ContentType:     entry.ContentType,
+			Mtime:           entry.CreationDate.Unix(),
+			Path:            params.Path,
+			PathType:        models.ObjectStatsPathTypeObject,
+			SizeBytes:       entry.Size,
+			PhysicalAddress: entry.PhysicalAddress,
+			Commit:          entry.CommitReference,
 		}
 
 		if entry.Expired {
@@ -905,6 +1634,40 @@ func (c *Controller) ObjectsStatObjectHandler() objects.StatObjectHandler {
 	})
 }
 
+func (c *Controller) ObjectsBlameObjectHandler() objects.BlameObjectHandler {
+	return objects.BlameObjectHandlerFunc(func(params objects.BlameObjectParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewBlameObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("blame_object")
+
+		commit, err := deps.Cataloger.Blame(c.Context(), params.Repository, params.Ref, params.Path)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewBlameObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if errors.Is(err, catalog.ErrEntryUncommitted) {
+			return objects.NewBlameObjectConflict().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewBlameObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewBlameObjectOK().WithPayload(&models.Commit{
+			Committer:    commit.Committer,
+			CreationDate: commit.CreationDate.Unix(),
+			ID:           commit.Reference,
+			Message:      commit.Message,
+			Metadata:     commit.Metadata,
+			Parents:      commit.Parents,
+		})
+	})
+}
+
 func (c *Controller) ObjectsGetUnderlyingPropertiesHandler() objects.GetUnderlyingPropertiesHandler {
 	return objects.GetUnderlyingPropertiesHandlerFunc(func(params objects.GetUnderlyingPropertiesParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -995,6 +1758,13 @@ func (c *Controller) ObjectsGetObjectHandler() objects.GetObjectHandler {
 		if err != nil {
 			return objects.NewGetObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
+		if deps.VerifyChecksum {
+			reader = block.NewVerifyingReader(reader, entry.Checksum)
+			// the payload is copied after the response headers are sent, abort the connection on a mismatch
+			reader = block.NewAbortOnIntegrityErrorReader(reader, func(err error) {
+				deps.logger.WithError(err).WithField("physical_address", entry.PhysicalAddress).Error("object content does not match its checksum")
+			})
+		}
 
 		// done
 		res.Payload = reader
@@ -1002,6 +1772,52 @@ func (c *Controller) ObjectsGetObjectHandler() objects.GetObjectHandler {
 	})
 }
 
+func (c *Controller) ObjectsGetArchiveHandler() objects.GetArchiveHandler {
+	return objects.GetArchiveHandlerFunc(func(params objects.GetArchiveParams, user *models.User) middleware.Responder {
+		prefix := swag.StringValue(params.Prefix)
+		// the archive holds every object under the prefix directory
+		resource := prefix
+		if resource != "" && !strings.HasSuffix(resource, catalog.DefaultPathDelimiter) {
+			resource += catalog.DefaultPathDelimiter
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, resource+"*"),
+			},
+		})
+		if err != nil {
+			return objects.NewGetArchiveUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_archive")
+		cataloger := deps.Cataloger
+
+		repo, err := cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewGetArchiveNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewGetArchiveDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		// the archive is generated while it is streamed to the client
+		reader, writer := io.Pipe()
+		archiveWriter := archive.NewWriter(cataloger, deps.BlockAdapter)
+		go func() {
+			err := archiveWriter.WriteTar(c.Context(), writer, repo, params.Ref, prefix)
+			if err != nil {
+				deps.logger.WithError(err).Error("failed to write archive")
+			}
+			_ = writer.CloseWithError(err)
+		}()
+
+		res := objects.NewGetArchiveOK()
+		res.ContentDisposition = fmt.Sprintf("filename=\"%s.tar\"", params.Repository)
+		res.Payload = reader
+		return res
+	})
+}
+
 func (c *Controller) MetadataCreateSymlinkHandler() metadataop.CreateSymlinkHandler {
 	return metadataop.CreateSymlinkHandlerFunc(func(params metadataop.CreateSymlinkParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -1110,6 +1926,9 @@ func (c *Controller) ObjectsListObjectsHandler() objects.ListObjectsHandler {
 		after, amount := getPaginationParams(params.After, params.Amount)
 
 		delimiter := catalog.DefaultPathDelimiter
+		if swag.BoolValue(params.Recursive) {
+			delimiter = ""
+		}
 		res, hasMore, err := cataloger.ListEntries(
 			c.Context(),
 			params.Repository,
@@ -1140,11 +1959,12 @@ func (c *Controller) ObjectsListObjectsHandler() objects.ListObjectsHandler {
 					mtime = entry.CreationDate.Unix()
 				}
 				objList[i] = &models.ObjectStats{
-					Checksum:  entry.Checksum,
-					Mtime:     mtime,
-					Path:      entry.Path,
-					PathType:  models.ObjectStatsPathTypeObject,
-					SizeBytes: entry.Size,
+					Checksum:    entry.Checksum,
+					ContentType: entry.ContentType,
+					Mtime:       mtime,
+					Path:        entry.Path,
+					PathType:    models.ObjectStatsPathTypeObject,
+					SizeBytes:   entry.Size,
 				}
 			}
 			lastID = entry.Path
@@ -1158,110 +1978,377 @@ func (c *Controller) ObjectsListObjectsHandler() objects.ListObjectsHandler {
 			Results: objList,
 		})
 
-		if hasMore {
-			returnValue.Payload.Pagination.NextOffset = lastID
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
+	})
+}
+
+func (c *Controller) ObjectsUploadObjectHandler() objects.UploadObjectHandler {
+	return objects.UploadObjectHandlerFunc(func(params objects.UploadObjectParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewUploadObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("put_object")
+		cataloger := deps.Cataloger
+
+		repo, err := cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewUploadObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
+		branchExists, err := cataloger.BranchExists(c.Context(), params.Repository, params.Branch)
+		if err != nil {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if !branchExists {
+			return objects.NewUploadObjectNotFound().WithPayload(responseError("branch '%s' not found", params.Branch))
+		}
+		// workaround in order to extract file content-length using swagger
+		file, ok := params.Content.(*runtime.File)
+		if !ok {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseError("failed extracting size from file"))
+		}
+		byteSize := file.Header.Size
+
+		// read the content
+		blob, err := upload.WriteBlob(deps.BlockAdapter, repo.StorageNamespace, params.Content, byteSize, block.PutOpts{StorageClass: params.StorageClass})
+		if err != nil {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if err := blob.VerifyChecksum(swag.StringValue(params.Checksum)); err != nil {
+			if err := upload.RemoveBlob(deps.BlockAdapter, repo.StorageNamespace, blob); err != nil {
+				deps.logger.WithError(err).Warn("could not remove uploaded content that does not match checksum")
+			}
+			return objects.NewUploadObjectBadRequest().WithPayload(responseErrorFrom(err))
+		}
+
+		// write metadata
+		writeTime := time.Now()
+		entry := catalog.Entry{
+			Path:            params.Path,
+			PhysicalAddress: blob.PhysicalAddress,
+			CreationDate:    writeTime,
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+			ContentType:     file.Header.Header.Get("Content-Type"),
+		}
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		err = cataloger.CreateEntry(ctx, repo.Name, params.Branch, entry,
+			catalog.CreateEntryParams{
+				Dedup: catalog.DedupParams{
+					ID:               blob.DedupID,
+					StorageNamespace: repo.StorageNamespace,
+				},
+				IfAbsent: swag.StringValue(params.IfNoneMatch) == "*",
+			})
+		if errors.Is(err, catalog.ErrEntryAlreadyExists) {
+			return objects.NewUploadObjectPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return objects.NewUploadObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewUploadObjectNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewUploadObjectCreated().WithPayload(&models.ObjectStats{
+			Checksum:    blob.Checksum,
+			ContentType: entry.ContentType,
+			Mtime:       writeTime.Unix(),
+			Path:        params.Path,
+			PathType:    models.ObjectStatsPathTypeObject,
+			SizeBytes:   blob.Size,
+		})
+	})
+}
+
+func (c *Controller) ObjectsDeleteObjectHandler() objects.DeleteObjectHandler {
+	return objects.DeleteObjectHandlerFunc(func(params objects.DeleteObjectParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewDeleteObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_object")
+		cataloger := deps.Cataloger
+
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		err = cataloger.DeleteEntry(ctx, params.Repository, params.Branch, params.Path)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewDeleteObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) {
+			return objects.NewDeleteObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewDeleteObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		return objects.NewDeleteObjectNoContent()
+	})
+}
+
+func (c *Controller) ObjectsStageObjectsHandler() objects.StageObjectsHandler {
+	return objects.StageObjectsHandlerFunc(func(params objects.StageObjectsParams, user *models.User) middleware.Responder {
+		perms := make([]permissions.Permission, len(params.Objects.Objects))
+		for i, obj := range params.Objects.Objects {
+			perms[i] = permissions.Permission{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, swag.StringValue(obj.Path)),
+			}
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return objects.NewStageObjectsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("stage_objects")
+		cataloger := deps.Cataloger
+
+		writeTime := time.Now()
+		entries := make([]catalog.Entry, len(params.Objects.Objects))
+		for i, obj := range params.Objects.Objects {
+			entries[i] = catalog.Entry{
+				Path:            swag.StringValue(obj.Path),
+				PhysicalAddress: swag.StringValue(obj.PhysicalAddress),
+				CreationDate:    writeTime,
+				Size:            swag.Int64Value(obj.SizeBytes),
+				Checksum:        swag.StringValue(obj.Checksum),
+				ContentType:     obj.ContentType,
+				Metadata:        obj.Metadata,
+			}
+		}
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		err = cataloger.CreateEntries(ctx, params.Repository, params.Branch, entries)
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrAddressOutsideNamespace) {
+			return objects.NewStageObjectsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return objects.NewStageObjectsDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewStageObjectsNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewStageObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewStageObjectsNoContent()
+	})
+}
+
+func (c *Controller) ObjectsDeleteObjectsHandler() objects.DeleteObjectsHandler {
+	return objects.DeleteObjectsHandlerFunc(func(params objects.DeleteObjectsParams, user *models.User) middleware.Responder {
+		perms := make([]permissions.Permission, len(params.PathList.Paths))
+		for i, p := range params.PathList.Paths {
+			perms[i] = permissions.Permission{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, p),
+			}
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return objects.NewDeleteObjectsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_objects")
+		cataloger := deps.Cataloger
+
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		err = cataloger.DeleteEntries(ctx, params.Repository, params.Branch, params.PathList.Paths)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewDeleteObjectsBadRequest().WithPayload(responseErrorFrom(err))
 		}
-		return returnValue
+		if errors.Is(err, catalog.ErrPathProtected) {
+			return objects.NewDeleteObjectsDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewDeleteObjectsNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewDeleteObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewDeleteObjectsNoContent()
 	})
 }
 
-func (c *Controller) ObjectsUploadObjectHandler() objects.UploadObjectHandler {
-	return objects.UploadObjectHandlerFunc(func(params objects.UploadObjectParams, user *models.User) middleware.Responder {
+func (c *Controller) ObjectsCopyObjectHandler() objects.CopyObjectHandler {
+	return objects.CopyObjectHandlerFunc(func(params objects.CopyObjectParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.SourcePath),
+			},
 			{
 				Action:   permissions.WriteObjectAction,
 				Resource: permissions.ObjectArn(params.Repository, params.Path),
 			},
 		})
 		if err != nil {
-			return objects.NewUploadObjectUnauthorized().WithPayload(responseErrorFrom(err))
+			return objects.NewCopyObjectUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("put_object")
+		deps.LogAction("copy_object")
 		cataloger := deps.Cataloger
 
-		repo, err := cataloger.GetRepository(c.Context(), params.Repository)
-		if errors.Is(err, db.ErrNotFound) {
-			return objects.NewUploadObjectNotFound().WithPayload(responseError("resource not found"))
-		}
-		if err != nil {
-			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		entry, err := cataloger.CopyEntry(ctx, params.Repository, params.SourceRef, params.SourcePath, params.Branch, params.Path)
+		if errors.Is(err, catalog.ErrExpired) {
+			return objects.NewCopyObjectGone().WithPayload(responseError("resource expired"))
 		}
-		// check if branch exists - it is still a possibility, but we don't want to upload large object when the branch was not there in the first place
-		branchExists, err := cataloger.BranchExists(c.Context(), params.Repository, params.Branch)
-		if err != nil {
-			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return objects.NewCopyObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
 		}
-		if !branchExists {
-			return objects.NewUploadObjectNotFound().WithPayload(responseError("branch '%s' not found", params.Branch))
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewCopyObjectNotFound().WithPayload(responseError("resource not found"))
 		}
-		// workaround in order to extract file content-length using swagger
-		file, ok := params.Content.(*runtime.File)
-		if !ok {
-			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseError("failed extracting size from file"))
+		if err != nil {
+			return objects.NewCopyObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-		byteSize := file.Header.Size
+		return objects.NewCopyObjectCreated().WithPayload(&models.ObjectStats{
+			Checksum:    entry.Checksum,
+			ContentType: entry.ContentType,
+			Mtime:       entry.CreationDate.Unix(),
+			Path:        entry.Path,
+			PathType:    models.ObjectStatsPathTypeObject,
+			SizeBytes:   entry.Size,
+		})
+	})
+}
 
-		// read the content
-		blob, err := upload.WriteBlob(deps.BlockAdapter, repo.StorageNamespace, params.Content, byteSize, block.PutOpts{StorageClass: params.StorageClass})
+func (c *Controller) ObjectsRenameObjectHandler() objects.RenameObjectHandler {
+	return objects.RenameObjectHandlerFunc(func(params objects.RenameObjectParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+			{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Destination),
+			},
+		})
 		if err != nil {
-			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			return objects.NewRenameObjectUnauthorized().WithPayload(responseErrorFrom(err))
 		}
+		deps.LogAction("rename_object")
+		cataloger := deps.Cataloger
 
-		// write metadata
-		writeTime := time.Now()
-		entry := catalog.Entry{
-			Path:            params.Path,
-			PhysicalAddress: blob.PhysicalAddress,
-			CreationDate:    writeTime,
-			Size:            blob.Size,
-			Checksum:        blob.Checksum,
+		ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+		err = cataloger.RenameEntry(ctx, params.Repository, params.Branch, params.Path, params.Destination)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewRenameObjectBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) {
+			return objects.NewRenameObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
 		}
-		err = cataloger.CreateEntry(c.Context(), repo.Name, params.Branch, entry,
-			catalog.CreateEntryParams{
-				Dedup: catalog.DedupParams{
-					ID:               blob.DedupID,
-					StorageNamespace: repo.StorageNamespace,
-				},
-			})
 		if errors.Is(err, db.ErrNotFound) {
-			return objects.NewUploadObjectNotFound().WithPayload(responseErrorFrom(err))
+			return objects.NewRenameObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if errors.Is(err, catalog.ErrExpired) {
+			return objects.NewRenameObjectGone().WithPayload(responseError("resource expired"))
 		}
 		if err != nil {
-			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			return objects.NewRenameObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-		return objects.NewUploadObjectCreated().WithPayload(&models.ObjectStats{
-			Checksum:  blob.Checksum,
-			Mtime:     writeTime.Unix(),
-			Path:      params.Path,
-			PathType:  models.ObjectStatsPathTypeObject,
-			SizeBytes: blob.Size,
-		})
+
+		return objects.NewRenameObjectNoContent()
 	})
 }
 
-func (c *Controller) ObjectsDeleteObjectHandler() objects.DeleteObjectHandler {
-	return objects.DeleteObjectHandlerFunc(func(params objects.DeleteObjectParams, user *models.User) middleware.Responder {
+func (c *Controller) CopyToBranchHandler() branches.CopyToBranchHandler {
+	return branches.CopyToBranchHandlerFunc(func(params branches.CopyToBranchParams, user *models.User) middleware.Responder {
+		sourceRepository := swag.StringValue(params.Copy.SourceRepository)
+		sourceRef := swag.StringValue(params.Copy.SourceRef)
+		sourcePath := params.Copy.SourcePath
+		destinationPath := params.Copy.DestinationPath
+		if destinationPath == "" {
+			destinationPath = sourcePath
+		}
+		// cherry-picking a commit reads and writes paths not known in advance
+		readResource := permissions.ObjectArn(sourceRepository, "*")
+		writeResource := permissions.ObjectArn(params.Repository, "*")
+		if sourcePath != "" {
+			readResource = permissions.ObjectArn(sourceRepository, sourcePath)
+			writeResource = permissions.ObjectArn(params.Repository, destinationPath)
+		}
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.DeleteObjectAction,
-				Resource: permissions.ObjectArn(params.Repository, params.Path),
+				Action:   permissions.ReadObjectAction,
+				Resource: readResource,
+			},
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: writeResource,
 			},
 		})
 		if err != nil {
-			return objects.NewDeleteObjectUnauthorized().WithPayload(responseErrorFrom(err))
+			return branches.NewCopyToBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("delete_object")
+		deps.LogAction("copy_to_branch")
 		cataloger := deps.Cataloger
 
-		err = cataloger.DeleteEntry(c.Context(), params.Repository, params.Branch, params.Path)
+		var changes int
+		if sourcePath != "" {
+			ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+			// the request authorizes the paths given, every copied path is authorized as well
+			ctx = catalog.WithCopyAuthorizer(ctx, func(sourcePaths, destinationPaths []string) error {
+				perms := make([]permissions.Permission, 0, len(sourcePaths)+len(destinationPaths))
+				for _, p := range sourcePaths {
+					perms = append(perms, permissions.Permission{
+						Action:   permissions.ReadObjectAction,
+						Resource: permissions.ObjectArn(sourceRepository, p),
+					})
+				}
+				for _, p := range destinationPaths {
+					perms = append(perms, permissions.Permission{
+						Action:   permissions.WriteObjectAction,
+						Resource: permissions.ObjectArn(params.Repository, p),
+					})
+				}
+				return authorize(deps.Auth, user, perms)
+			})
+			changes, err = cataloger.CopyPath(ctx, sourceRepository, sourceRef, sourcePath,
+				params.Repository, params.Branch, destinationPath)
+		} else {
+			ctx := protectedPathsContext(c.Context(), deps.Auth, user, params.Repository)
+			changes, err = cataloger.CherryPick(ctx, sourceRepository, sourceRef, params.Repository, params.Branch)
+		}
+		if errors.Is(err, ErrAuthorization) || errors.Is(err, auth.ErrInsufficientPermissions) {
+			return branches.NewCopyToBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrExpired) {
+			return branches.NewCopyToBranchBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return branches.NewCopyToBranchForbidden().WithPayload(responseErrorFrom(err))
+		}
 		if errors.Is(err, db.ErrNotFound) {
-			return objects.NewDeleteObjectNotFound().WithPayload(responseError("resource not found"))
+			return branches.NewCopyToBranchNotFound().WithPayload(responseErrorFrom(err))
 		}
 		if err != nil {
-			return objects.NewDeleteObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			return branches.NewCopyToBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-
-		return objects.NewDeleteObjectNoContent()
+		return branches.NewCopyToBranchOK().WithPayload(&models.CopyResult{
+			Changes: swag.Int64(int64(changes)),
+		})
 	})
 }
 
@@ -1282,9 +2369,13 @@ func (c *Controller) RevertBranchHandler() branches.RevertBranchHandler {
 		ctx := c.Context()
 		switch swag.StringValue(params.Revert.Type) {
 		case models.RevertCreationTypeCommit:
-			err = cataloger.RollbackCommit(ctx, params.Repository, params.Revert.Commit)
+			err = cataloger.ResetBranchToCommit(ctx, params.Repository, params.Branch, params.Revert.Commit)
+		case models.RevertCreationTypeCommitPath:
+			err = cataloger.RevertPath(ctx, params.Repository, params.Branch, params.Revert.Commit, params.Revert.Path)
 		case models.RevertCreationTypeCommonPrefix:
 			err = cataloger.ResetEntries(ctx, params.Repository, params.Branch, params.Revert.Path)
+		case models.RevertCreationTypePath:
+			err = cataloger.ResetPath(ctx, params.Repository, params.Branch, params.Revert.Path)
 		case models.RevertCreationTypeReset:
 			err = cataloger.ResetBranch(ctx, params.Repository, params.Branch)
 		case models.RevertCreationTypeObject:
@@ -1296,6 +2387,9 @@ func (c *Controller) RevertBranchHandler() branches.RevertBranchHandler {
 		if errors.Is(err, db.ErrNotFound) {
 			return branches.NewRevertBranchNotFound().WithPayload(responseErrorFrom(err))
 		}
+		if errors.Is(err, catalog.ErrOperationNotPermitted) || errors.Is(err, catalog.ErrInvalidValue) {
+			return branches.NewRevertBranchDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return branches.NewRevertBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
@@ -1304,6 +2398,60 @@ func (c *Controller) RevertBranchHandler() branches.RevertBranchHandler {
 	})
 }
 
+func (c *Controller) RebaseBranchHandler() branches.RebaseBranchHandler {
+	return branches.RebaseBranchHandlerFunc(func(params branches.RebaseBranchParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return branches.NewRebaseBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("rebase_branch")
+		userModel, err := deps.Auth.GetUser(user.ID)
+		if err != nil {
+			return branches.NewRebaseBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		res, err := deps.Cataloger.Rebase(c.Context(), params.Repository, params.Branch,
+			swag.StringValue(params.Rebase.Onto), userModel.Username)
+		if errors.Is(err, db.ErrNotFound) {
+			return branches.NewRebaseBranchNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrConflictFound) {
+			return branches.NewRebaseBranchConflict().WithPayload(newRebaseResultFromCatalog(res))
+		}
+		if errors.Is(err, catalog.ErrOperationNotPermitted) || errors.Is(err, catalog.ErrInvalidValue) ||
+			errors.Is(err, catalog.ErrNoDifferenceWasFound) {
+			return branches.NewRebaseBranchBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			return branches.NewRebaseBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return branches.NewRebaseBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return branches.NewRebaseBranchOK().WithPayload(newRebaseResultFromCatalog(res))
+	})
+}
+
+func newRebaseResultFromCatalog(res *catalog.RebaseResult) *models.RebaseResult {
+	conflicts := make([]*models.RebaseConflict, len(res.Conflicts))
+	for i, conflict := range res.Conflicts {
+		conflicts[i] = &models.RebaseConflict{
+			Reference: swag.String(conflict.Reference),
+			Path:      swag.String(conflict.Path),
+			Type:      swag.String(string(conflict.Type)),
+		}
+	}
+	return &models.RebaseResult{
+		Reference: res.Reference,
+		Commits:   res.Commits,
+		Conflicts: conflicts,
+	}
+}
+
 func (c *Controller) CreateUserHandler() authop.CreateUserHandler {
 	return authop.CreateUserHandlerFunc(func(params authop.CreateUserParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -2228,6 +3376,212 @@ func (c *Controller) RetentionUpdateRetentionPolicyHandler() retentionop.UpdateR
 	})
 }
 
+func (c *Controller) RetentionGetExpiryReportHandler() retentionop.GetExpiryReportHandler {
+	return retentionop.GetExpiryReportHandlerFunc(func(params retentionop.GetExpiryReportParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetentionReadPolicyAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return retentionop.NewGetExpiryReportUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("get_expiry_report")
+
+		modelPolicy, err := deps.Retention.GetPolicy(params.Repository)
+		if errors.Is(err, retention.ErrPolicyNotFound) {
+			return retentionop.NewGetExpiryReportNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewGetExpiryReportDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		policy, err := retention.ParsePolicy(modelPolicy.RetentionPolicy)
+		if err != nil {
+			return retentionop.NewGetExpiryReportDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		report, err := deps.Cataloger.GetExpiryReport(c.Context(), params.Repository, policy)
+		if errors.Is(err, db.ErrNotFound) {
+			return retentionop.NewGetExpiryReportNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewGetExpiryReportDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		commits := make([]*models.ExpiryReportCommit, len(report.Commits))
+		for i, commit := range report.Commits {
+			commits[i] = &models.ExpiryReportCommit{
+				Branch:       swag.String(commit.Branch),
+				Reference:    commit.Reference,
+				Message:      commit.Message,
+				CreationDate: strfmt.DateTime(commit.CreationDate),
+				Entries:      swag.Int64(commit.Entries),
+				LogicalBytes: swag.Int64(commit.LogicalBytes),
+			}
+		}
+		return retentionop.NewGetExpiryReportOK().WithPayload(&models.ExpiryReport{
+			Entries:             swag.Int64(report.Entries),
+			LogicalBytes:        swag.Int64(report.LogicalBytes),
+			Objects:             swag.Int64(report.Objects),
+			UnreferencedObjects: swag.Int64(report.UnreferencedObjects),
+			PhysicalBytes:       swag.Int64(report.PhysicalBytes),
+			Commits:             commits,
+		})
+	})
+}
+
+func (c *Controller) RetentionGetBranchExpiryPolicyHandler() retentionop.GetBranchExpiryPolicyHandler {
+	return retentionop.GetBranchExpiryPolicyHandlerFunc(func(params retentionop.GetBranchExpiryPolicyParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetentionReadPolicyAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return retentionop.NewGetBranchExpiryPolicyUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("get_branch_expiry_policy")
+
+		policy, err := deps.Cataloger.GetBranchExpiryPolicy(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return retentionop.NewGetBranchExpiryPolicyNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewGetBranchExpiryPolicyDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		olderThanDays := int64(policy.OlderThanDays)
+		creationDate := strfmt.DateTime(policy.CreatedAt)
+		return retentionop.NewGetBranchExpiryPolicyOK().WithPayload(&models.BranchExpiryPolicyWithCreationDate{
+			BranchExpiryPolicy: models.BranchExpiryPolicy{
+				Patterns:      policy.Patterns,
+				OlderThanDays: &olderThanDays,
+				Exempt:        policy.Exempt,
+				Description:   policy.Description,
+			},
+			CreationDate: &creationDate,
+		})
+	})
+}
+
+func (c *Controller) RetentionUpdateBranchExpiryPolicyHandler() retentionop.UpdateBranchExpiryPolicyHandler {
+	return retentionop.UpdateBranchExpiryPolicyHandlerFunc(func(params retentionop.UpdateBranchExpiryPolicyParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetentionWritePolicyAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return retentionop.NewUpdateBranchExpiryPolicyUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("update_branch_expiry_policy")
+
+		err = deps.Cataloger.SetBranchExpiryPolicy(c.Context(), params.Repository, &catalog.BranchExpiryPolicy{
+			Patterns:      params.Policy.Patterns,
+			OlderThanDays: int(swag.Int64Value(params.Policy.OlderThanDays)),
+			Exempt:        params.Policy.Exempt,
+			Description:   params.Policy.Description,
+		})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return retentionop.NewUpdateBranchExpiryPolicyBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return retentionop.NewUpdateBranchExpiryPolicyNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewUpdateBranchExpiryPolicyDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return retentionop.NewUpdateBranchExpiryPolicyCreated()
+	})
+}
+
+func (c *Controller) RetentionGetWorkspaceExpiryPolicyHandler() retentionop.GetWorkspaceExpiryPolicyHandler {
+	return retentionop.GetWorkspaceExpiryPolicyHandlerFunc(func(params retentionop.GetWorkspaceExpiryPolicyParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetentionReadPolicyAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return retentionop.NewGetWorkspaceExpiryPolicyUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("get_workspace_expiry_policy")
+
+		policy, err := deps.Cataloger.GetWorkspaceExpiryPolicy(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return retentionop.NewGetWorkspaceExpiryPolicyNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewGetWorkspaceExpiryPolicyDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		olderThanDays := int64(policy.OlderThanDays)
+		creationDate := strfmt.DateTime(policy.CreatedAt)
+		return retentionop.NewGetWorkspaceExpiryPolicyOK().WithPayload(&models.WorkspaceExpiryPolicyWithCreationDate{
+			WorkspaceExpiryPolicy: models.WorkspaceExpiryPolicy{
+				OlderThanDays: &olderThanDays,
+				Description:   policy.Description,
+			},
+			CreationDate: &creationDate,
+		})
+	})
+}
+
+func (c *Controller) RetentionUpdateWorkspaceExpiryPolicyHandler() retentionop.UpdateWorkspaceExpiryPolicyHandler {
+	return retentionop.UpdateWorkspaceExpiryPolicyHandlerFunc(func(params retentionop.UpdateWorkspaceExpiryPolicyParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetentionWritePolicyAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return retentionop.NewUpdateWorkspaceExpiryPolicyUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("update_workspace_expiry_policy")
+
+		err = deps.Cataloger.SetWorkspaceExpiryPolicy(c.Context(), params.Repository, &catalog.WorkspaceExpiryPolicy{
+			OlderThanDays: int(swag.Int64Value(params.Policy.OlderThanDays)),
+			Description:   params.Policy.Description,
+		})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return retentionop.NewUpdateWorkspaceExpiryPolicyBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return retentionop.NewUpdateWorkspaceExpiryPolicyNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return retentionop.NewUpdateWorkspaceExpiryPolicyDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return retentionop.NewUpdateWorkspaceExpiryPolicyCreated()
+	})
+}
+
 func (c *Controller) ImportFromS3InventoryHandler() repositories.ImportFromS3InventoryHandler {
 	return repositories.ImportFromS3InventoryHandlerFunc(func(params repositories.ImportFromS3InventoryParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -2240,6 +3594,10 @@ func (c *Controller) ImportFromS3InventoryHandler() repositories.ImportFromS3Inv
 			return repositories.NewImportFromS3InventoryUnauthorized().WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("import_from_s3_inventory")
+		if !s3a.IsManifestURL(params.ManifestURL) {
+			return repositories.NewImportFromS3InventoryDefault(http.StatusBadRequest).
+				WithPayload(responseError("manifest url must point to a manifest.json file"))
+		}
 		userModel, err := c.deps.Auth.GetUser(user.ID)
 		username := "lakeFS"
 		if err == nil {
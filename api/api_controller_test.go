@@ -62,8 +62,8 @@ func TestHandler_ListRepositoriesHandler(t *testing.T) {
 		// write some repos
 		ctx := context.Background()
 		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "foo1", "s3://foo1", "master"))
-		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "foo2", "s3://foo1", "master"))
-		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "foo3", "s3://foo1", "master"))
+		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "foo2", "s3://foo2", "master"))
+		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "foo3", "s3://foo3", "master"))
 
 		resp, err := clt.Repositories.ListRepositories(&repositories.ListRepositoriesParams{},
 			httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey))
@@ -201,7 +201,7 @@ func TestHandler_CommitsGetBranchCommitLogHandler(t *testing.T) {
 	})
 
 	t.Run("get branch log", func(t *testing.T) {
-		err := deps.cataloger.CreateRepository(ctx, "repo2", "ns1", "master")
+		err := deps.cataloger.CreateRepository(ctx, "repo2", "ns2", "master")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -339,6 +339,41 @@ func TestHandler_CommitHandler(t *testing.T) {
 			t.Fatalf("unexpected error on commit: %s", err)
 		}
 	})
+
+	t.Run("commit expected version", func(t *testing.T) {
+		ctx := context.Background()
+		resp, err := clt.Branches.GetBranch(&branches.GetBranchParams{
+			Branch:     "master",
+			Repository: "foo1",
+		}, bauth)
+		testutil.MustDo(t, "get branch", err)
+		version, err := strconv.ParseInt(resp.ETag, 10, 64)
+		testutil.MustDo(t, "parse branch version", err)
+		testutil.MustDo(t, "create entry", deps.cataloger.CreateEntry(ctx, "foo1", "master",
+			catalog.Entry{Path: "foo/baz", PhysicalAddress: "pa2", CreationDate: time.Now(), Size: 666, Checksum: "cs2", Metadata: nil},
+			catalog.CreateEntryParams{},
+		))
+		_, err = clt.Commits.Commit(&commits.CommitParams{
+			Branch: "master",
+			Commit: &models.CommitCreation{
+				Message:         swag.String("stale version"),
+				ExpectedVersion: version + 1,
+			},
+			Repository: "foo1",
+		}, bauth)
+		if _, ok := err.(*commits.CommitConflict); !ok {
+			t.Fatalf("commit with a stale version err=%v, expected conflict", err)
+		}
+		_, err = clt.Commits.Commit(&commits.CommitParams{
+			Branch: "master",
+			Commit: &models.CommitCreation{
+				Message:         swag.String("current version"),
+				ExpectedVersion: version,
+			},
+			Repository: "foo1",
+		}, bauth)
+		testutil.MustDo(t, "commit with the current version", err)
+	})
 }
 
 func TestHandler_CreateRepositoryHandler(t *testing.T) {
@@ -378,7 +413,7 @@ func TestHandler_CreateRepositoryHandler(t *testing.T) {
 		}
 		_, err = clt.Repositories.CreateRepository(&repositories.CreateRepositoryParams{
 			Repository: &models.RepositoryCreation{
-				StorageNamespace: swag.String("s3://foo-bucket/"),
+				StorageNamespace: swag.String("s3://foo2/"),
 				ID:               swag.String("repo2"),
 				DefaultBranch:    "master",
 			},
@@ -388,6 +423,19 @@ func TestHandler_CreateRepositoryHandler(t *testing.T) {
 			t.Fatalf("expected error creating duplicate repo")
 		}
 	})
+
+	t.Run("create repo overlapping namespace", func(t *testing.T) {
+		_, err := clt.Repositories.CreateRepository(&repositories.CreateRepositoryParams{
+			Repository: &models.RepositoryCreation{
+				StorageNamespace: swag.String("s3://foo-bucket/nested"),
+				ID:               swag.String("my-nested-repo"),
+				DefaultBranch:    "master",
+			},
+		}, bauth)
+		if _, ok := err.(*repositories.CreateRepositoryBadRequest); !ok {
+			t.Fatalf("expected bad request creating repo in an overlapping namespace, got %v", err)
+		}
+	})
 }
 
 func TestHandler_DeleteRepositoryHandler(t *testing.T) {
@@ -431,9 +479,9 @@ func TestHandler_DeleteRepositoryHandler(t *testing.T) {
 
 	t.Run("delete repo doesnt delete other repos", func(t *testing.T) {
 		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr0", "s3://foo1", "master"))
-		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr1", "s3://foo1", "master"))
-		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr11", "s3://foo1", "master"))
-		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr2", "s3://foo1", "master"))
+		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr1", "s3://foo2", "master"))
+		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr11", "s3://foo3", "master"))
+		testutil.Must(t, deps.cataloger.CreateRepository(ctx, "rr2", "s3://foo4", "master"))
 		_, err := clt.Repositories.DeleteRepository(&repositories.DeleteRepositoryParams{
 			Repository: "rr1",
 		}, bauth)
@@ -661,7 +709,7 @@ func TestHandler_DeleteBranchHandler(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, err = clt.Branches.DeleteBranch(&branches.DeleteBranchParams{
+		resp, err := clt.Branches.DeleteBranch(&branches.DeleteBranchParams{
 			Branch:     "master2",
 			Repository: "my-new-repo",
 		}, bauth)
@@ -674,6 +722,12 @@ func TestHandler_DeleteBranchHandler(t *testing.T) {
 		if !errors.Is(err, db.ErrNotFound) {
 			t.Fatalf("expected branch to be gone, instead got error: %s", err)
 		}
+
+		// the returned reference re-creates the branch
+		_, err = deps.cataloger.CreateBranch(ctx, "my-new-repo", "master2", resp.GetPayload())
+		if err != nil {
+			t.Fatalf("failed to re-create branch from %s: %s", resp.GetPayload(), err)
+		}
 	})
 
 	t.Run("delete branch doesnt exist", func(t *testing.T) {
@@ -852,6 +906,27 @@ func TestHandler_ObjectsListObjectsHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("get object list recursive", func(t *testing.T) {
+		resp, err := clt.Objects.ListObjects(&objects.ListObjectsParams{
+			Ref:        "master",
+			Repository: "repo1",
+			Prefix:     swag.String("foo/"),
+			Recursive:  swag.Bool(true),
+		}, basicAuth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedPaths := []string{"foo/a_dir/baz", "foo/bar", "foo/baz", "foo/quuux"}
+		if len(resp.Payload.Results) != len(expectedPaths) {
+			t.Fatalf("expected %d entries, got back %d", len(expectedPaths), len(resp.Payload.Results))
+		}
+		for i, result := range resp.Payload.Results {
+			if result.Path != expectedPaths[i] || result.PathType != models.ObjectStatsPathTypeObject {
+				t.Errorf("expected object %s, got %s %s", expectedPaths[i], result.PathType, result.Path)
+			}
+		}
+	})
+
 	t.Run("get object list paginated", func(t *testing.T) {
 		resp, err := clt.Objects.ListObjects(&objects.ListObjectsParams{
 			Amount:     swag.Int64(2),
@@ -1083,6 +1158,139 @@ func TestHandler_ObjectsUploadObjectHandler(t *testing.T) {
 	})
 }
 
+func TestHandler_ObjectsStageObjectsHandler(t *testing.T) {
+	handler, deps := getHandler(t)
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("stage objects", func(t *testing.T) {
+		_, err := clt.Objects.StageObjects(&objects.StageObjectsParams{
+			Repository: "repo1",
+			Branch:     "master",
+			Objects: &models.ObjectStageList{
+				Objects: []*models.ObjectStageCreation{
+					{Path: swag.String("data/a"), PhysicalAddress: swag.String("addr-a"), Checksum: swag.String("aa"), SizeBytes: swag.Int64(1)},
+					{Path: swag.String("data/b"), PhysicalAddress: swag.String("addr-b"), Checksum: swag.String("bb"), SizeBytes: swag.Int64(2), Metadata: map[string]string{"k": "v"}},
+				},
+			},
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error staging objects: %s", err)
+		}
+		for _, p := range []string{"data/a", "data/b"} {
+			ent, err := deps.cataloger.GetEntry(ctx, "repo1", "master", p, catalog.GetEntryParams{})
+			if err != nil {
+				t.Fatalf("get staged entry %s: %s", p, err)
+			}
+			if ent.PhysicalAddress != "addr-"+p[len("data/"):] {
+				t.Errorf("staged entry %s address = %s", p, ent.PhysicalAddress)
+			}
+		}
+	})
+
+	t.Run("stage objects missing branch", func(t *testing.T) {
+		_, err := clt.Objects.StageObjects(&objects.StageObjectsParams{
+			Repository: "repo1",
+			Branch:     "no-branch",
+			Objects: &models.ObjectStageList{
+				Objects: []*models.ObjectStageCreation{
+					{Path: swag.String("data/c"), PhysicalAddress: swag.String("addr-c"), Checksum: swag.String("cc"), SizeBytes: swag.Int64(3)},
+				},
+			},
+		}, bauth)
+		if _, ok := err.(*objects.StageObjectsNotFound); !ok {
+			t.Fatalf("expected not found error staging objects, got %v", err)
+		}
+	})
+
+	t.Run("stage objects outside storage namespace", func(t *testing.T) {
+		for _, addr := range []string{"s3://other-bucket/data/d", "/data/d", "../data/d"} {
+			_, err := clt.Objects.StageObjects(&objects.StageObjectsParams{
+				Repository: "repo1",
+				Branch:     "master",
+				Objects: &models.ObjectStageList{
+					Objects: []*models.ObjectStageCreation{
+						{Path: swag.String("data/d"), PhysicalAddress: swag.String(addr), Checksum: swag.String("dd"), SizeBytes: swag.Int64(4)},
+					},
+				},
+			}, bauth)
+			if _, ok := err.(*objects.StageObjectsBadRequest); !ok {
+				t.Fatalf("expected bad request staging object at %s, got %v", addr, err)
+			}
+		}
+		if _, err := deps.cataloger.GetEntry(ctx, "repo1", "master", "data/d", catalog.GetEntryParams{}); !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("get rejected entry data/d err=%v, expected %s", err, db.ErrNotFound)
+		}
+	})
+}
+
+func TestHandler_ObjectsDeleteObjectsHandler(t *testing.T) {
+	handler, deps := getHandler(t)
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"data/a", "data/b", "data/c"} {
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            p,
+			PhysicalAddress: "addr-" + p,
+			Checksum:        "ff",
+			Size:            1,
+		}, catalog.CreateEntryParams{}))
+	}
+
+	t.Run("delete objects", func(t *testing.T) {
+		_, err := clt.Objects.DeleteObjects(&objects.DeleteObjectsParams{
+			Repository: "repo1",
+			Branch:     "master",
+			PathList:   &models.PathList{Paths: []string{"data/a", "data/b"}},
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error deleting objects: %s", err)
+		}
+		for _, p := range []string{"data/a", "data/b"} {
+			_, err := deps.cataloger.GetEntry(ctx, "repo1", "master", p, catalog.GetEntryParams{})
+			if !errors.Is(err, db.ErrNotFound) {
+				t.Errorf("get deleted entry %s: expected not found, got %v", p, err)
+			}
+		}
+		if _, err := deps.cataloger.GetEntry(ctx, "repo1", "master", "data/c", catalog.GetEntryParams{}); err != nil {
+			t.Errorf("get entry data/c: %s", err)
+		}
+	})
+
+	t.Run("delete objects missing branch", func(t *testing.T) {
+		_, err := clt.Objects.DeleteObjects(&objects.DeleteObjectsParams{
+			Repository: "repo1",
+			Branch:     "no-branch",
+			PathList:   &models.PathList{Paths: []string{"data/c"}},
+		}, bauth)
+		if _, ok := err.(*objects.DeleteObjectsNotFound); !ok {
+			t.Fatalf("expected not found error deleting objects, got %v", err)
+		}
+	})
+}
+
 func TestHandler_ObjectsDeleteObjectHandler(t *testing.T) {
 	handler, deps := getHandler(t)
 
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// auditResource extracts the repository, reference and object path addressed by an API request,
+// returning empty values for the parts that do not apply
+func auditResource(r *http.Request) (repository, ref, path string) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] != "repositories" {
+			continue
+		}
+		repository = parts[i+1]
+		if i+3 < len(parts) && (parts[i+2] == "branches" || parts[i+2] == "refs") {
+			ref = parts[i+3]
+		}
+		break
+	}
+	path = r.URL.Query().Get("path")
+	return repository, ref, path
+}
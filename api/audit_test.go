@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuditResource(t *testing.T) {
+	tests := []struct {
+		target         string
+		wantRepository string
+		wantRef        string
+		wantPath       string
+	}{
+		{target: "/api/v1/repositories", wantRepository: "", wantRef: "", wantPath: ""},
+		{target: "/api/v1/repositories/repo1", wantRepository: "repo1", wantRef: "", wantPath: ""},
+		{target: "/api/v1/repositories/repo1/branches", wantRepository: "repo1", wantRef: "", wantPath: ""},
+		{target: "/api/v1/repositories/repo1/branches/master", wantRepository: "repo1", wantRef: "master", wantPath: ""},
+		{target: "/api/v1/repositories/repo1/branches/master/objects?path=a/b.txt", wantRepository: "repo1", wantRef: "master", wantPath: "a/b.txt"},
+		{target: "/api/v1/repositories/repo1/refs/master/symlink?location=s3://bucket", wantRepository: "repo1", wantRef: "master", wantPath: ""},
+		{target: "/api/v1/auth/users/alice", wantRepository: "", wantRef: "", wantPath: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			r := httptest.NewRequest("POST", tt.target, nil)
+			repository, ref, path := auditResource(r)
+			if repository != tt.wantRepository || ref != tt.wantRef || path != tt.wantPath {
+				t.Errorf("auditResource() = (%s, %s, %s), want (%s, %s, %s)",
+					repository, ref, path, tt.wantRepository, tt.wantRef, tt.wantPath)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/catalog"
 
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/permissions"
@@ -30,3 +32,18 @@ func authorize(a auth.Service, user *models.User, permissions []permissions.Perm
 	}
 	return nil
 }
+
+// protectedPathsContext returns ctx allowing changes to protected paths in repository that user
+// may write protected objects at
+func protectedPathsContext(ctx context.Context, a auth.Service, user *models.User, repository string) context.Context {
+	return catalog.WithProtectedPathsAuthorizer(ctx, func(paths []string) error {
+		perms := make([]permissions.Permission, len(paths))
+		for i, p := range paths {
+			perms[i] = permissions.Permission{
+				Action:   permissions.WriteProtectedObjectAction,
+				Resource: permissions.ObjectArn(repository, p),
+			}
+		}
+		return authorize(a, user, perms)
+	})
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/go-openapi/runtime"
 	httptransport "github.com/go-openapi/runtime/client"
@@ -57,31 +59,44 @@ type RepositoryClient interface {
 	ListRepositories(ctx context.Context, after string, amount int) ([]*models.Repository, *models.Pagination, error)
 	GetRepository(ctx context.Context, repository string) (*models.Repository, error)
 	CreateRepository(ctx context.Context, repository *models.RepositoryCreation) error
+	ForkRepository(ctx context.Context, sourceRepository, repository string) (*models.Repository, error)
 	DeleteRepository(ctx context.Context, repository string) error
+	GetReplicationStream(ctx context.Context, repository string, after int64, writer io.Writer) error
 
 	ListBranches(ctx context.Context, repository string, from string, amount int) ([]string, *models.Pagination, error)
-	GetBranch(ctx context.Context, repository, branchID string) (string, error)
+	// GetBranch returns the reference of the last commit of the branch and the branch version
+	GetBranch(ctx context.Context, repository, branchID string) (string, int64, error)
 	CreateBranch(ctx context.Context, repository string, branch *models.BranchCreation) (string, error)
-	DeleteBranch(ctx context.Context, repository, branchID string) error
+	DeleteBranch(ctx context.Context, repository, branchID string) (string, error)
 	RevertBranch(ctx context.Context, repository, branchID string, revertProps *models.RevertCreation) error
-
-	Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Commit, error)
+	RebaseBranch(ctx context.Context, repository, branchID, onto string) (*models.RebaseResult, error)
+	CopyToBranch(ctx context.Context, repository, branchID string, copyProps *models.CopyCreation) (int64, error)
+	ExportWorkspace(ctx context.Context, repository, branchID string, writer io.Writer) error
+	ImportWorkspace(ctx context.Context, repository, branchID string, r io.Reader) error
+
+	// Commit commits the changes on the branch.  A non-zero expectedVersion fails the commit unless
+	// the branch is still at that version, as returned by GetBranch.
+	Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string, expectedVersion int64) (*models.Commit, error)
 	GetCommit(ctx context.Context, repository, commitID string) (*models.Commit, error)
-	GetCommitLog(ctx context.Context, repository, branchID, after string, amount int) ([]*models.Commit, *models.Pagination, error)
+	GetCommitLog(ctx context.Context, repository, branchID, after string, amount int, filter catalog.ListCommitsOptions) ([]*models.Commit, *models.Pagination, error)
 
 	StatObject(ctx context.Context, repository, ref, path string) (*models.ObjectStats, error)
-	ListObjects(ctx context.Context, repository, ref, prefix, from string, amount int) ([]*models.ObjectStats, *models.Pagination, error)
+	BlameObject(ctx context.Context, repository, ref, path string) (*models.Commit, error)
+	ListObjects(ctx context.Context, repository, ref, prefix, from string, amount int, recursive bool) ([]*models.ObjectStats, *models.Pagination, error)
 	GetObject(ctx context.Context, repository, ref, path string, w io.Writer) (*objects.GetObjectOK, error)
 	UploadObject(ctx context.Context, repository, branchID, path string, r io.Reader) (*models.ObjectStats, error)
 	DeleteObject(ctx context.Context, repository, branchID, path string) error
+	DeleteObjects(ctx context.Context, repository, branchID string, paths []string) error
 
 	DiffRefs(ctx context.Context, repository, leftRef, rightRef string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
-	Merge(ctx context.Context, repository, leftRef, rightRef string) (*models.MergeResult, error)
+	Merge(ctx context.Context, repository, leftRef, rightRef string, merge *models.Merge) (*models.MergeResult, error)
+	ListMergeConflicts(ctx context.Context, repository, sourceRef, destinationRef string, after string, amount int) ([]*models.MergeConflict, *models.Pagination, error)
 
 	DiffBranch(ctx context.Context, repository, branch string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
 
 	GetRetentionPolicy(ctx context.Context, repository string) (*models.RetentionPolicyWithCreationDate, error)
 	UpdateRetentionPolicy(ctx context.Context, repository string, policy *models.RetentionPolicy) error
+	GetExpiryReport(ctx context.Context, repository string) (*models.ExpiryReport, error)
 	Symlink(ctx context.Context, repoID, ref, path string) (string, error)
 }
 
@@ -433,6 +448,27 @@ func (c *client) CreateRepository(ctx context.Context, repository *models.Reposi
 	return err
 }
 
+func (c *client) ForkRepository(ctx context.Context, sourceRepository, repository string) (*models.Repository, error) {
+	resp, err := c.remote.Repositories.ForkRepository(&repositories.ForkRepositoryParams{
+		Repository: sourceRepository,
+		Fork:       &models.RepositoryFork{ID: swag.String(repository)},
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetReplicationStream(ctx context.Context, repository string, after int64, writer io.Writer) error {
+	_, err := c.remote.Repositories.GetReplicationStream(&repositories.GetReplicationStreamParams{
+		Repository: repository,
+		After:      swag.Int64(after),
+		Context:    ctx,
+	}, c.auth, writer)
+	return err
+}
+
 func (c *client) DeleteRepository(ctx context.Context, repository string) error {
 	_, err := c.remote.Repositories.DeleteRepository(&repositories.DeleteRepositoryParams{
 		Repository: repository,
@@ -441,16 +477,20 @@ func (c *client) DeleteRepository(ctx context.Context, repository string) error
 	return err
 }
 
-func (c *client) GetBranch(ctx context.Context, repository, branchID string) (string, error) {
+func (c *client) GetBranch(ctx context.Context, repository, branchID string) (string, int64, error) {
 	resp, err := c.remote.Branches.GetBranch(&branches.GetBranchParams{
 		Branch:     branchID,
 		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
-	return resp.GetPayload(), nil
+	version, err := strconv.ParseInt(resp.ETag, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("branch version: %w", err)
+	}
+	return resp.GetPayload(), version, nil
 }
 
 func (c *client) CreateBranch(ctx context.Context, repository string, branch *models.BranchCreation) (string, error) {
@@ -465,9 +505,31 @@ func (c *client) CreateBranch(ctx context.Context, repository string, branch *mo
 	return resp.GetPayload(), nil
 }
 
-func (c *client) DeleteBranch(ctx context.Context, repository, branchID string) error {
-	_, err := c.remote.Branches.DeleteBranch(&branches.DeleteBranchParams{
+func (c *client) DeleteBranch(ctx context.Context, repository, branchID string) (string, error) {
+	resp, err := c.remote.Branches.DeleteBranch(&branches.DeleteBranchParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ExportWorkspace(ctx context.Context, repository, branchID string, writer io.Writer) error {
+	_, err := c.remote.Branches.ExportWorkspace(&branches.ExportWorkspaceParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth, writer)
+	return err
+}
+
+func (c *client) ImportWorkspace(ctx context.Context, repository, branchID string, r io.Reader) error {
+	_, err := c.remote.Branches.ImportWorkspace(&branches.ImportWorkspaceParams{
 		Branch:     branchID,
+		Content:    runtime.NamedReader("content", r),
 		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
@@ -484,12 +546,43 @@ func (c *client) RevertBranch(ctx context.Context, repository, branchID string,
 	return err
 }
 
-func (c *client) Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Commit, error) {
+func (c *client) RebaseBranch(ctx context.Context, repository, branchID, onto string) (*models.RebaseResult, error) {
+	resp, err := c.remote.Branches.RebaseBranch(&branches.RebaseBranchParams{
+		Branch:     branchID,
+		Rebase:     &models.RebaseCreation{Onto: swag.String(onto)},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err == nil {
+		return resp.GetPayload(), nil
+	}
+	conflict, ok := err.(*branches.RebaseBranchConflict)
+	if ok {
+		return conflict.Payload, catalog.ErrConflictFound
+	}
+	return nil, err
+}
+
+func (c *client) CopyToBranch(ctx context.Context, repository, branchID string, copyProps *models.CopyCreation) (int64, error) {
+	resp, err := c.remote.Branches.CopyToBranch(&branches.CopyToBranchParams{
+		Branch:     branchID,
+		Copy:       copyProps,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return 0, err
+	}
+	return swag.Int64Value(resp.GetPayload().Changes), nil
+}
+
+func (c *client) Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string, expectedVersion int64) (*models.Commit, error) {
 	commit, err := c.remote.Commits.Commit(&commits.CommitParams{
 		Branch: branchID,
 		Commit: &models.CommitCreation{
-			Message:  &message,
-			Metadata: metadata,
+			Message:         &message,
+			Metadata:        metadata,
+			ExpectedVersion: expectedVersion,
 		},
 		Repository: repository,
 		Context:    ctx,
@@ -512,14 +605,29 @@ func (c *client) GetCommit(ctx context.Context, repository, commitID string) (*m
 	return commit.GetPayload(), nil
 }
 
-func (c *client) GetCommitLog(ctx context.Context, repository, branchID, after string, amount int) ([]*models.Commit, *models.Pagination, error) {
-	resp, err := c.remote.Commits.GetBranchCommitLog(&commits.GetBranchCommitLogParams{
+func (c *client) GetCommitLog(ctx context.Context, repository, branchID, after string, amount int, filter catalog.ListCommitsOptions) ([]*models.Commit, *models.Pagination, error) {
+	params := &commits.GetBranchCommitLogParams{
 		Amount:     swag.Int64(int64(amount)),
 		After:      swag.String(after),
 		Branch:     branchID,
 		Repository: repository,
 		Context:    ctx,
-	}, c.auth)
+	}
+	if filter.Prefix != "" {
+		params.Prefix = swag.String(filter.Prefix)
+	}
+	if filter.Committer != "" {
+		params.Committer = swag.String(filter.Committer)
+	}
+	if !filter.Since.IsZero() {
+		since := strfmt.DateTime(filter.Since)
+		params.Since = &since
+	}
+	if !filter.Until.IsZero() {
+		until := strfmt.DateTime(filter.Until)
+		params.Until = &until
+	}
+	resp, err := c.remote.Commits.GetBranchCommitLog(params, c.auth)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -542,24 +650,42 @@ func (c *client) DiffRefs(ctx context.Context, repository, leftRef, rightRef, af
 	return payload.Results, payload.Pagination, nil
 }
 
-func (c *client) Merge(ctx context.Context, repository, leftRef, rightRef string) (*models.MergeResult, error) {
+func (c *client) Merge(ctx context.Context, repository, leftRef, rightRef string, merge *models.Merge) (*models.MergeResult, error) {
 	statusOK, err := c.remote.Refs.MergeIntoBranch(&refs.MergeIntoBranchParams{
 		DestinationRef: leftRef,
 		SourceRef:      rightRef,
 		Repository:     repository,
+		Merge:          merge,
 		Context:        ctx,
 	}, c.auth)
 
 	if err == nil {
 		return statusOK.Payload, nil
 	}
+	// other errors, such as a merge rejected by a pre-merge hook, carry their message in the payload
 	conflict, ok := err.(*refs.MergeIntoBranchConflict)
-	if !ok {
+	if ok {
 		return conflict.Payload, catalog.ErrConflictFound
 	}
 	return nil, err
 }
 
+func (c *client) ListMergeConflicts(ctx context.Context, repository, sourceRef, destinationRef string, after string, amount int) ([]*models.MergeConflict, *models.Pagination, error) {
+	resp, err := c.remote.Refs.ListMergeConflicts(&refs.ListMergeConflictsParams{
+		After:          swag.String(after),
+		Amount:         swag.Int64(int64(amount)),
+		DestinationRef: destinationRef,
+		SourceRef:      sourceRef,
+		Repository:     repository,
+		Context:        ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := resp.GetPayload()
+	return payload.Results, payload.Pagination, nil
+}
+
 func (c *client) DiffBranch(ctx context.Context, repoID, branch string, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
 	diff, err := c.remote.Branches.DiffBranch(&branches.DiffBranchParams{
 		After:      swag.String(after),
@@ -608,6 +734,17 @@ func (c *client) UpdateRetentionPolicy(ctx context.Context, repository string, p
 	return err
 }
 
+func (c *client) GetExpiryReport(ctx context.Context, repository string) (*models.ExpiryReport, error) {
+	resp, err := c.remote.Retention.GetExpiryReport(&retention.GetExpiryReportParams{
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
 func (c *client) StatObject(ctx context.Context, repoID, ref, path string) (*models.ObjectStats, error) {
 	resp, err := c.remote.Objects.StatObject(&objects.StatObjectParams{
 		Ref:        ref,
@@ -621,13 +758,27 @@ func (c *client) StatObject(ctx context.Context, repoID, ref, path string) (*mod
 	return resp.GetPayload(), nil
 }
 
-func (c *client) ListObjects(ctx context.Context, repoID, ref, prefix, after string, amount int) ([]*models.ObjectStats, *models.Pagination, error) {
+func (c *client) BlameObject(ctx context.Context, repoID, ref, path string) (*models.Commit, error) {
+	resp, err := c.remote.Objects.BlameObject(&objects.BlameObjectParams{
+		Ref:        ref,
+		Path:       path,
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ListObjects(ctx context.Context, repoID, ref, prefix, after string, amount int, recursive bool) ([]*models.ObjectStats, *models.Pagination, error) {
 	resp, err := c.remote.Objects.ListObjects(&objects.ListObjectsParams{
 		After:      swag.String(after),
 		Amount:     swag.Int64(int64(amount)),
 		Ref:        ref,
 		Repository: repoID,
 		Prefix:     swag.String(prefix),
+		Recursive:  swag.Bool(recursive),
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
@@ -674,6 +825,16 @@ func (c *client) DeleteObject(ctx context.Context, repository, branchID, path st
 	return err
 }
 
+func (c *client) DeleteObjects(ctx context.Context, repository, branchID string, paths []string) error {
+	_, err := c.remote.Objects.DeleteObjects(&objects.DeleteObjectsParams{
+		Branch:     branchID,
+		PathList:   &models.PathList{Paths: paths},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
 func NewClient(endpointURL, accessKeyID, secretAccessKey string) (Client, error) {
 	parsedURL, err := url.Parse(endpointURL)
 	if err != nil {
@@ -3,6 +3,7 @@
 package api
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/treeverse/lakefs/retention"
 	_ "github.com/treeverse/lakefs/statik"
 	"github.com/treeverse/lakefs/stats"
+	"github.com/treeverse/lakefs/tracing"
 	"gopkg.in/dgrijalva/jwt-go.v3"
 )
 
@@ -52,6 +54,8 @@ type Handler struct {
 	handler      *http.ServeMux
 	dedupCleaner *dedup.Cleaner
 	logger       logging.Logger
+
+	verifyChecksum bool
 }
 
 func NewHandler(
@@ -63,6 +67,7 @@ func NewHandler(
 	retention retention.Service,
 	migrator db.Migrator,
 	dedupCleaner *dedup.Cleaner,
+	verifyChecksum bool,
 	logger logging.Logger,
 ) http.Handler {
 	logger.Info("initialized OpenAPI server")
@@ -76,6 +81,8 @@ func NewHandler(
 		migrator:     migrator,
 		dedupCleaner: dedupCleaner,
 		logger:       logger,
+
+		verifyChecksum: verifyChecksum,
 	}
 	s.buildAPI()
 	return s.handler
@@ -123,7 +130,7 @@ func (s *Handler) BasicAuth() func(accessKey, secretKey string) (user *models.Us
 			logger.WithError(err).WithField("access_key", accessKey).Warn("could not get access key for login")
 			return nil, ErrAuthenticationFailed
 		}
-		if secretKey != credentials.AccessSecretKey {
+		if !secretMatches(credentials.AccessSecretKey, secretKey) {
 			logger.WithField("access_key", accessKey).Warn("access key secret does not match")
 			return nil, ErrAuthenticationFailed
 		}
@@ -138,6 +145,12 @@ func (s *Handler) BasicAuth() func(accessKey, secretKey string) (user *models.Us
 	}
 }
 
+// secretMatches compares the access secret key supplied by a client with the stored one in
+// constant time, so the time the comparison takes does not leak the stored secret.
+func secretMatches(expected, actual string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) == 1
+}
+
 func (s *Handler) setupHandler(api http.Handler, ui http.Handler) {
 	mux := http.NewServeMux()
 	// health check
@@ -167,14 +180,14 @@ func (s *Handler) buildAPI() {
 	api.BasicAuthAuth = s.BasicAuth()
 	api.JwtTokenAuth = s.JwtTokenAuth()
 	// bind our handlers to the server
-	NewController(s.cataloger, s.authService, s.blockStore, s.stats, s.retention, s.dedupCleaner, s.meta, s.migrator, s.stats, s.logger).Configure(api)
+	NewController(s.cataloger, s.authService, s.blockStore, s.stats, s.retention, s.dedupCleaner, s.meta, s.migrator, s.stats, s.verifyChecksum, s.logger).Configure(api)
 
 	// setup host/port
 	s.apiServer = restapi.NewServer(api)
 	s.apiServer.ConfigureAPI()
 	s.setupHandler(
 		// api handler
-		httputil.LoggingMiddleware(
+		tracing.Middleware(LoggerServiceName, httputil.LoggingMiddleware(
 			RequestIDHeaderName,
 			logging.Fields{"service_name": LoggerServiceName},
 			promhttp.InstrumentHandlerCounter(requestCounter,
@@ -183,7 +196,7 @@ func (s *Handler) buildAPI() {
 						s.apiServer.GetHandler(),
 					)),
 			),
-		),
+		)),
 
 		// ui handler
 		UIHandler(s.authService),
@@ -102,6 +102,7 @@ func getHandler(t *testing.T, opts ...testutil.GetDBOption) (http.Handler, *depe
 		retentionService,
 		migrator,
 		dedupCleaner,
+		false,
 		logging.Default(),
 	)
 
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/go-openapi/swag"
+)
+
+func TestGetPaginationParams(t *testing.T) {
+	cases := []struct {
+		Name           string
+		After          *string
+		Amount         *int64
+		ExpectedAfter  string
+		ExpectedAmount int
+	}{
+		{Name: "defaults", ExpectedAmount: MaxResultsPerPage},
+		{Name: "after", After: swag.String("a"), Amount: swag.Int64(10), ExpectedAfter: "a", ExpectedAmount: 10},
+		{Name: "above_max", Amount: swag.Int64(MaxResultsPerPage + 1), ExpectedAmount: MaxResultsPerPage},
+		{Name: "all", Amount: swag.Int64(-1), ExpectedAmount: -1},
+		{Name: "negative", Amount: swag.Int64(-2), ExpectedAmount: MaxResultsPerPage},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			after, amount := getPaginationParams(tt.After, tt.Amount)
+			if after != tt.ExpectedAfter || amount != tt.ExpectedAmount {
+				t.Errorf("getPaginationParams() = %q, %d, expected %q, %d", after, amount, tt.ExpectedAfter, tt.ExpectedAmount)
+			}
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package api
 
 import (
-	"strings"
-
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/catalog"
 )
@@ -24,10 +22,11 @@ func transformDifferenceTypeToString(d catalog.DifferenceType) string {
 
 func transformDifferenceToDiff(difference catalog.Difference) *models.Diff {
 	d := &models.Diff{
-		Path: difference.Path,
+		Path:      difference.Path,
+		SizeDelta: difference.SizeDelta,
 	}
 	d.Type = transformDifferenceTypeToString(difference.Type)
-	if strings.HasSuffix(difference.Path, catalog.DefaultPathDelimiter) {
+	if difference.EntryType == catalog.DifferenceEntryTypeTree {
 		d.PathType = models.DiffPathTypeCommonPrefix
 	} else {
 		d.PathType = models.DiffPathTypeObject
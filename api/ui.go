@@ -58,7 +58,7 @@ func UIHandler(authService auth.Service) http.Handler {
 
 		// check login
 		credentials, err := authService.GetCredentials(login.AccessKeyID)
-		if err != nil || credentials.AccessSecretKey != login.AccessSecretKey {
+		if err != nil || !secretMatches(credentials.AccessSecretKey, login.AccessSecretKey) {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+// DefaultListBatchSize is the number of entries read from the catalog on each listing call.  Only
+// a single batch of entries is held in memory while the archive is written.
+const DefaultListBatchSize = 1000
+
+type Writer struct {
+	Cataloger     catalog.Cataloger
+	Adapter       block.Adapter
+	ListBatchSize int
+}
+
+func NewWriter(cataloger catalog.Cataloger, adapter block.Adapter) *Writer {
+	return &Writer{
+		Cataloger:     cataloger,
+		Adapter:       adapter,
+		ListBatchSize: DefaultListBatchSize,
+	}
+}
+
+// WriteTar streams a tar archive of all the objects under prefix at reference into w.  The prefix
+// is a directory: "data" archives the objects under "data/", not "data2/".  Paths in the archive
+// are relative to prefix.  Expired objects are skipped.
+func (a *Writer) WriteTar(ctx context.Context, w io.Writer, repository *catalog.Repository, reference, prefix string) error {
+	if prefix != "" && !strings.HasSuffix(prefix, catalog.DefaultPathDelimiter) {
+		prefix += catalog.DefaultPathDelimiter
+	}
+	tw := tar.NewWriter(w)
+	adapter := a.Adapter.WithContext(ctx)
+	it := catalog.NewEntryIterator(ctx, a.Cataloger, repository.Name, reference, prefix, a.ListBatchSize)
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entry := it.Value()
+		if entry.Expired {
+			continue
+		}
+		if err := writeEntry(tw, adapter, repository.StorageNamespace, prefix, entry); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	return tw.Close()
+}
+
+func writeEntry(tw *tar.Writer, adapter block.Adapter, storageNamespace, prefix string, entry *catalog.Entry) error {
+	name := strings.TrimPrefix(entry.Path, prefix)
+	if name == "" {
+		name = entry.Path
+	}
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     entry.Size,
+		Mode:     0644,
+		ModTime:  entry.CreationDate,
+	})
+	if err != nil {
+		return fmt.Errorf("write header %s: %w", entry.Path, err)
+	}
+	reader, err := adapter.Get(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: entry.PhysicalAddress}, entry.Size)
+	if err != nil {
+		return fmt.Errorf("get object %s: %w", entry.Path, err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	if _, err := io.CopyN(tw, reader, entry.Size); err != nil {
+		return fmt.Errorf("copy object %s: %w", entry.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/archive"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+type mockCataloger struct {
+	catalog.Cataloger
+	entries []*catalog.Entry
+}
+
+func (m *mockCataloger) ListEntries(_ context.Context, _, _ string, prefix, after string, _ string, limit int) ([]*catalog.Entry, bool, error) {
+	var res []*catalog.Entry
+	for _, e := range m.entries {
+		if !strings.HasPrefix(e.Path, prefix) || e.Path <= after {
+			continue
+		}
+		if len(res) == limit {
+			return res, true, nil
+		}
+		res = append(res, e)
+	}
+	return res, false, nil
+}
+
+func TestWriter_WriteTar(t *testing.T) {
+	const storageNamespace = "mem://archive"
+	ctx := context.Background()
+	adapter := mem.New()
+	objects := map[string]string{
+		"data/a.txt":     "content of a",
+		"data/b/c.txt":   "content of c",
+		"data/d.txt":     "expired",
+		"other/file.txt": "not in prefix",
+		"data2/file.txt": "prefix of path is not a directory",
+	}
+	cataloger := &mockCataloger{}
+	for p, content := range objects {
+		addr := "addr-" + strings.ReplaceAll(p, "/", "-")
+		err := adapter.Put(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: addr}, int64(len(content)), strings.NewReader(content), block.PutOpts{})
+		if err != nil {
+			t.Fatalf("put %s: %s", p, err)
+		}
+		cataloger.entries = append(cataloger.entries, &catalog.Entry{
+			Path:            p,
+			PhysicalAddress: addr,
+			Size:            int64(len(content)),
+			Expired:         p == "data/d.txt",
+		})
+	}
+	sort.Slice(cataloger.entries, func(i, j int) bool {
+		return cataloger.entries[i].Path < cataloger.entries[j].Path
+	})
+
+	expected := map[string]string{
+		"a.txt":   "content of a",
+		"b/c.txt": "content of c",
+	}
+	for _, prefix := range []string{"data/", "data"} {
+		t.Run(prefix, func(t *testing.T) {
+			testWriteTar(t, ctx, cataloger, adapter, storageNamespace, prefix, expected)
+		})
+	}
+}
+
+func testWriteTar(t *testing.T, ctx context.Context, cataloger catalog.Cataloger, adapter block.Adapter, storageNamespace, prefix string, expected map[string]string) {
+	t.Helper()
+	w := archive.NewWriter(cataloger, adapter)
+	w.ListBatchSize = 1
+	var buf bytes.Buffer
+	repo := &catalog.Repository{Name: "repo", StorageNamespace: storageNamespace}
+	if err := w.WriteTar(ctx, &buf, repo, "master", prefix); err != nil {
+		t.Fatalf("WriteTar() error = %s", err)
+	}
+
+	got := make(map[string]string)
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read tar: %s", err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read tar entry %s: %s", hdr.Name, err)
+		}
+		got[hdr.Name] = string(data)
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("WriteTar() got %d files (%v), expected %d", len(got), got, len(expected))
+	}
+	for name, content := range expected {
+		if got[name] != content {
+			t.Errorf("WriteTar() file %s content = '%s', expected '%s'", name, got[name], content)
+		}
+	}
+}
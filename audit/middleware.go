@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/treeverse/lakefs/httputil"
+	"github.com/treeverse/lakefs/logging"
+)
+
+type contextKey string
+
+const eventContextKey contextKey = "audit_event"
+
+func eventFromContext(ctx context.Context) *Event {
+	event, _ := ctx.Value(eventContextKey).(*Event)
+	return event
+}
+
+// SetActor sets the user performing the audited request. Requests without an actor are not recorded.
+func SetActor(ctx context.Context, actor string) {
+	if event := eventFromContext(ctx); event != nil {
+		event.Actor = actor
+	}
+}
+
+// SetAction sets the name of the operation performed by the audited request
+func SetAction(ctx context.Context, action string) {
+	if event := eventFromContext(ctx); event != nil {
+		event.Action = action
+	}
+}
+
+// SetResource sets the repository, reference and path the audited request operates on
+func SetResource(ctx context.Context, repository, ref, path string) {
+	if event := eventFromContext(ctx); event != nil {
+		event.Repository = repository
+		event.Ref = ref
+		event.Path = path
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Middleware records an audit event for every authenticated request that may change metadata.
+// Handlers annotate the event using SetActor, SetAction and SetResource. Failing to record an
+// event is logged and does not fail the request.
+func Middleware(service Service, serviceName string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadOnlyMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		event := &Event{
+			Service: serviceName,
+			Method:  r.Method,
+		}
+		r = r.WithContext(context.WithValue(r.Context(), eventContextKey, event))
+		writer := &httputil.ResponseRecordingWriter{Writer: w, StatusCode: http.StatusOK}
+		next.ServeHTTP(writer, r)
+
+		if event.Actor == "" {
+			return
+		}
+		event.StatusCode = writer.StatusCode
+		// record even if the client went away while the request was served
+		if err := service.Record(context.Background(), event); err != nil {
+			logging.FromContext(r.Context()).WithError(err).WithFields(logging.Fields{
+				"actor":  event.Actor,
+				"action": event.Action,
+			}).Error("failed to record audit event")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package audit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/treeverse/lakefs/audit"
+)
+
+type recordingService struct {
+	audit.Service
+	events []*audit.Event
+}
+
+func (s *recordingService) Record(_ context.Context, event *audit.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get("X-Actor"); actor != "" {
+			audit.SetActor(r.Context(), actor)
+		}
+		audit.SetAction(r.Context(), "delete_object")
+		audit.SetResource(r.Context(), "repo1", "master", "a.txt")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		actor     string
+		wantEvent bool
+	}{
+		{name: "change", method: http.MethodDelete, actor: "alice", wantEvent: true},
+		{name: "read", method: http.MethodGet, actor: "alice", wantEvent: false},
+		{name: "unauthenticated", method: http.MethodDelete, actor: "", wantEvent: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &recordingService{}
+			h := audit.Middleware(service, "api", handler)
+			req := httptest.NewRequest(tt.method, "/repositories/repo1/branches/master/objects?path=a.txt", nil)
+			req.Header.Set("X-Actor", tt.actor)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !tt.wantEvent {
+				if len(service.events) != 0 {
+					t.Fatalf("Middleware() recorded %d events, expected none", len(service.events))
+				}
+				return
+			}
+			if len(service.events) != 1 {
+				t.Fatalf("Middleware() recorded %d events, expected 1", len(service.events))
+			}
+			expected := audit.Event{
+				Service:    "api",
+				Actor:      tt.actor,
+				Action:     "delete_object",
+				Method:     tt.method,
+				Repository: "repo1",
+				Ref:        "master",
+				Path:       "a.txt",
+				StatusCode: http.StatusNoContent,
+			}
+			if *service.events[0] != expected {
+				t.Errorf("Middleware() recorded %+v, expected %+v", *service.events[0], expected)
+			}
+		})
+	}
+}
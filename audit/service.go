@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+// Event is a single audit log record of an operation that changed lakeFS metadata
+type Event struct {
+	ID         int64     `db:"id"`
+	CreatedAt  time.Time `db:"created_at"`
+	Service    string    `db:"service"`
+	Actor      string    `db:"actor"`
+	Action     string    `db:"action"`
+	Method     string    `db:"method"`
+	Repository string    `db:"repository"`
+	Ref        string    `db:"ref"`
+	Path       string    `db:"path"`
+	StatusCode int       `db:"status_code"`
+}
+
+// ListParams filters the events returned by List. Zero values are not used as a filter.
+type ListParams struct {
+	From       time.Time
+	To         time.Time
+	Actor      string
+	Repository string
+}
+
+type Service interface {
+	Record(ctx context.Context, event *Event) error
+	List(ctx context.Context, params ListParams, limit int, after int64) ([]*Event, bool, error)
+}
+
+type DBService struct {
+	db db.Database
+}
+
+func NewDBService(db db.Database) *DBService {
+	return &DBService{db: db}
+}
+
+func (s *DBService) Record(ctx context.Context, event *Event) error {
+	_, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return nil, tx.Get(&event.ID, `INSERT INTO audit_log (service, actor, action, method, repository, ref, path, status_code)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			event.Service, event.Actor, event.Action, event.Method, event.Repository, event.Ref, event.Path, event.StatusCode)
+	}, db.WithContext(ctx))
+	return err
+}
+
+// List returns up to limit events matching params with id greater than after, oldest first.
+// The returned bool reports whether more events are available.
+func (s *DBService) List(ctx context.Context, params ListParams, limit int, after int64) ([]*Event, bool, error) {
+	q := psql.Select("*").
+		From("audit_log").
+		Where(sq.Gt{"id": after}).
+		OrderBy("id")
+	if !params.From.IsZero() {
+		q = q.Where(sq.GtOrEq{"created_at": params.From})
+	}
+	if !params.To.IsZero() {
+		q = q.Where(sq.Lt{"created_at": params.To})
+	}
+	if params.Actor != "" {
+		q = q.Where(sq.Eq{"actor": params.Actor})
+	}
+	if params.Repository != "" {
+		q = q.Where(sq.Eq{"repository": params.Repository})
+	}
+	if limit >= 0 {
+		q = q.Limit(uint64(limit) + 1)
+	}
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var events []*Event
+		if err := tx.Select(&events, sql, args...); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, false, err
+	}
+	events := res.([]*Event)
+	hasMore := false
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+		hasMore = true
+	}
+	return events, hasMore, nil
+}
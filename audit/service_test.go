@@ -0,0 +1,67 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/audit"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestDBService_List(t *testing.T) {
+	ctx := context.Background()
+	cdb, _ := testutil.GetDB(t, databaseURI)
+	s := audit.NewDBService(cdb)
+
+	events := []*audit.Event{
+		{Service: "api", Actor: "alice", Action: "create_repo", Method: "POST", Repository: "repo1", StatusCode: 201},
+		{Service: "api", Actor: "bob", Action: "delete_object", Method: "DELETE", Repository: "repo1", Ref: "master", Path: "a.txt", StatusCode: 204},
+		{Service: "s3", Actor: "alice", Action: "put_object", Method: "PUT", Repository: "repo2", Ref: "master", Path: "b.txt", StatusCode: 200},
+	}
+	for _, e := range events {
+		testutil.MustDo(t, "record event", s.Record(ctx, e))
+	}
+
+	tests := []struct {
+		name        string
+		params      audit.ListParams
+		limit       int
+		after       int64
+		wantIDs     []int64
+		wantHasMore bool
+	}{
+		{name: "all", params: audit.ListParams{}, limit: -1, wantIDs: []int64{events[0].ID, events[1].ID, events[2].ID}},
+		{name: "actor", params: audit.ListParams{Actor: "alice"}, limit: -1, wantIDs: []int64{events[0].ID, events[2].ID}},
+		{name: "repository", params: audit.ListParams{Repository: "repo1"}, limit: -1, wantIDs: []int64{events[0].ID, events[1].ID}},
+		{name: "actor and repository", params: audit.ListParams{Actor: "alice", Repository: "repo1"}, limit: -1, wantIDs: []int64{events[0].ID}},
+		{name: "future", params: audit.ListParams{From: time.Now().Add(time.Hour)}, limit: -1, wantIDs: nil},
+		{name: "past", params: audit.ListParams{To: time.Now().Add(-time.Hour)}, limit: -1, wantIDs: nil},
+		{name: "time range", params: audit.ListParams{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour)}, limit: -1, wantIDs: []int64{events[0].ID, events[1].ID, events[2].ID}},
+		{name: "limit", params: audit.ListParams{}, limit: 2, wantIDs: []int64{events[0].ID, events[1].ID}, wantHasMore: true},
+		{name: "after", params: audit.ListParams{}, limit: 2, after: events[1].ID, wantIDs: []int64{events[2].ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasMore, err := s.List(ctx, tt.params, tt.limit, tt.after)
+			if err != nil {
+				t.Fatalf("List() error = %s", err)
+			}
+			var gotIDs []int64
+			for _, e := range got {
+				gotIDs = append(gotIDs, e.ID)
+			}
+			if len(gotIDs) != len(tt.wantIDs) {
+				t.Fatalf("List() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+			for i := range gotIDs {
+				if gotIDs[i] != tt.wantIDs[i] {
+					t.Fatalf("List() ids = %v, want %v", gotIDs, tt.wantIDs)
+				}
+			}
+			if hasMore != tt.wantHasMore {
+				t.Errorf("List() hasMore = %t, want %t", hasMore, tt.wantHasMore)
+			}
+		})
+	}
+}
@@ -27,9 +27,9 @@ type LRUCache struct {
 func NewLRUCache(size int, expiry, jitter time.Duration) *LRUCache {
 	jitterFn := cache.NewJitterFn(jitter)
 	return &LRUCache{
-		credentialsCache: cache.NewCache(size, expiry, jitterFn),
-		userCache:        cache.NewCache(size, expiry, jitterFn),
-		policyCache:      cache.NewCache(size, expiry, jitterFn),
+		credentialsCache: cache.NewCache("auth_credentials", size, expiry, jitterFn),
+		userCache:        cache.NewCache("auth_user", size, expiry, jitterFn),
+		policyCache:      cache.NewCache("auth_policy", size, expiry, jitterFn),
 	}
 }
 
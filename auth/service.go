@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -29,6 +30,7 @@ type AuthorizationResponse struct {
 }
 
 type Service interface {
+	WithContext(ctx context.Context) Service
 	SecretStore() crypt.SecretStore
 
 	// users
@@ -207,6 +209,15 @@ func (s *DBAuthService) encryptSecret(secretAccessKey string) ([]byte, error) {
 	return encrypted, nil
 }
 
+// WithContext returns a service that runs its database operations using ctx
+func (s *DBAuthService) WithContext(ctx context.Context) Service {
+	return &DBAuthService{
+		db:          s.db.WithContext(ctx),
+		secretStore: s.secretStore,
+		cache:       s.cache,
+	}
+}
+
 func (s *DBAuthService) SecretStore() crypt.SecretStore {
 	return s.secretStore
 }
@@ -788,8 +799,15 @@ func (s *DBAuthService) Authorize(req *AuthorizationRequest) (*AuthorizationResp
 	if err != nil {
 		return nil, err
 	}
-	allowed := false
+	if len(req.RequiredPermissions) == 0 {
+		return &AuthorizationResponse{
+			Allowed: false,
+			Error:   ErrInsufficientPermissions,
+		}, nil
+	}
+	// every required permission must be allowed by at least one statement and denied by none
 	for _, perm := range req.RequiredPermissions {
+		allowed := false
 		for _, policy := range policies {
 			for _, stmt := range policy.Statement {
 				resource := interpolateUser(stmt.Resource, req.Username)
@@ -813,13 +831,12 @@ func (s *DBAuthService) Authorize(req *AuthorizationRequest) (*AuthorizationResp
 				}
 			}
 		}
-	}
-
-	if !allowed {
-		return &AuthorizationResponse{
-			Allowed: false,
-			Error:   ErrInsufficientPermissions,
-		}, nil
+		if !allowed {
+			return &AuthorizationResponse{
+				Allowed: false,
+				Error:   ErrInsufficientPermissions,
+			}, nil
+		}
 	}
 
 	// we're allowed!
@@ -1,6 +1,7 @@
 package auth_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -253,6 +254,78 @@ func TestDBAuthService_Authorize(t *testing.T) {
 			expectedAllowed: true,
 			expectedError:   nil,
 		},
+		{
+			name: "policy_allows_some_permissions",
+			policies: []*model.Policy{
+				{
+					Statement: model.Statements{
+						{
+							Action:   []string{"fs:ReadObject"},
+							Resource: "arn:lakefs:fs:::repository/foo/object/*",
+							Effect:   model.StatementEffectAllow,
+						},
+						{
+							Action:   []string{"fs:WriteObject"},
+							Resource: "arn:lakefs:fs:::repository/foo/object/dev/*",
+							Effect:   model.StatementEffectAllow,
+						},
+					},
+				},
+			},
+			request: func(userName string) *auth.AuthorizationRequest {
+				return &auth.AuthorizationRequest{
+					Username: userName,
+					RequiredPermissions: []permissions.Permission{
+						{
+							Action:   "fs:ReadObject",
+							Resource: "arn:lakefs:fs:::repository/foo/object/dev/source",
+						},
+						{
+							Action:   "fs:WriteObject",
+							Resource: "arn:lakefs:fs:::repository/foo/object/prod/destination",
+						},
+					},
+				}
+			},
+			expectedAllowed: false,
+			expectedError:   auth.ErrInsufficientPermissions,
+		},
+		{
+			name: "policy_allows_all_permissions",
+			policies: []*model.Policy{
+				{
+					Statement: model.Statements{
+						{
+							Action:   []string{"fs:ReadObject"},
+							Resource: "arn:lakefs:fs:::repository/foo/object/*",
+							Effect:   model.StatementEffectAllow,
+						},
+						{
+							Action:   []string{"fs:WriteObject"},
+							Resource: "arn:lakefs:fs:::repository/foo/object/dev/*",
+							Effect:   model.StatementEffectAllow,
+						},
+					},
+				},
+			},
+			request: func(userName string) *auth.AuthorizationRequest {
+				return &auth.AuthorizationRequest{
+					Username: userName,
+					RequiredPermissions: []permissions.Permission{
+						{
+							Action:   "fs:ReadObject",
+							Resource: "arn:lakefs:fs:::repository/foo/object/prod/source",
+						},
+						{
+							Action:   "fs:WriteObject",
+							Resource: "arn:lakefs:fs:::repository/foo/object/dev/destination",
+						},
+					},
+				}
+			},
+			expectedAllowed: true,
+			expectedError:   nil,
+		},
 		{
 			name: "policy_with_invalid_user",
 			policies: []*model.Policy{
@@ -609,6 +682,21 @@ func TestDbAuthService_GetUser(t *testing.T) {
 	}
 }
 
+func TestDBAuthService_WithContext(t *testing.T) {
+	s := setupService(t)
+	if err := s.CreateUser(&model.User{Username: "foo"}); err != nil {
+		t.Fatalf("CreateUser(foo): %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.WithContext(ctx).GetUser("foo"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetUser() with canceled context err = %v, expected %s", err, context.Canceled)
+	}
+	if _, err := s.GetUser("foo"); err != nil {
+		t.Errorf("GetUser() err = %s, expected no error", err)
+	}
+}
+
 func TestDbAuthService_GetUserById(t *testing.T) {
 	s := setupService(t)
 	const userName = "foo"
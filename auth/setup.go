@@ -83,6 +83,10 @@ func SetupBaseGroups(authService Service, ts time.Time) error {
 						permissions.CreateBranchAction,
 						permissions.DeleteBranchAction,
 						permissions.CreateCommitAction,
+						permissions.ListTagsAction,
+						permissions.ReadTagAction,
+						permissions.CreateTagAction,
+						permissions.DeleteTagAction,
 					},
 					Resource: permissions.All,
 					Effect:   model.StatementEffectAllow,
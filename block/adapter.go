@@ -48,6 +48,9 @@ type Properties struct {
 	StorageClass *string
 }
 
+// Adapter stores object data on an underlying block store (S3, GCS, local disk or memory).  The
+// catalog keeps only the physical address, size and checksum of each object, and reads and writes
+// the data through an Adapter selected by the blockstore configuration.
 type Adapter interface {
 	InventoryGenerator
 	WithContext(ctx context.Context) Adapter
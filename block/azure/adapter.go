@@ -0,0 +1,318 @@
+package azure
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const BlockstoreType = "azure"
+
+var (
+	ErrNotImplemented    = fmt.Errorf("not implemented")
+	ErrMissingPartNumber = errors.New("missing part number")
+	ErrMissingPartETag   = errors.New("missing part ETag")
+)
+
+// Adapter stores objects as block blobs.  Multipart uploads stage each part as a block of the
+// target blob, and commit the blocks of the parts on completion.
+type Adapter struct {
+	client             *Client
+	ctx                context.Context
+	uploadIDTranslator block.UploadIDTranslator
+}
+
+func WithContext(ctx context.Context) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.ctx = ctx
+	}
+}
+
+func WithTranslator(t block.UploadIDTranslator) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.uploadIDTranslator = t
+	}
+}
+
+func NewAdapter(client *Client, opts ...func(a *Adapter)) *Adapter {
+	a := &Adapter{
+		ctx:                context.Background(),
+		client:             client,
+		uploadIDTranslator: &block.NoOpTranslator{},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *Adapter) WithContext(ctx context.Context) block.Adapter {
+	return &Adapter{
+		ctx:                ctx,
+		client:             a.client,
+		uploadIDTranslator: a.uploadIDTranslator,
+	}
+}
+
+func (a *Adapter) log() logging.Logger {
+	return logging.FromContext(a.ctx)
+}
+
+func resolveNamespace(obj block.ObjectPointer) (block.QualifiedKey, error) {
+	qualifiedKey, err := block.ResolveNamespace(obj.StorageNamespace, obj.Identifier)
+	if err != nil {
+		return qualifiedKey, err
+	}
+	if qualifiedKey.StorageType != block.StorageTypeAzure {
+		return qualifiedKey, block.ErrInvalidNamespace
+	}
+	return qualifiedKey, nil
+}
+
+func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	var err error
+	defer reportMetrics("Put", time.Now(), &sizeBytes, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("x-ms-blob-type", "BlockBlob")
+	if opts.StorageClass != nil {
+		header.Set("x-ms-access-tier", *opts.StorageClass)
+	}
+	resp, err := a.client.do(a.ctx, http.MethodPut, qualifiedKey.StorageNamespace, qualifiedKey.Key, nil, header, reader, sizeBytes)
+	if err != nil {
+		return fmt.Errorf("put blob %s: %w", qualifiedKey.Key, err)
+	}
+	return resp.Body.Close()
+}
+
+func (a *Adapter) Get(obj block.ObjectPointer, _ int64) (io.ReadCloser, error) {
+	var err error
+	defer reportMetrics("Get", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.do(a.ctx, http.MethodGet, qualifiedKey.StorageNamespace, qualifiedKey.Key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get blob %s: %w", qualifiedKey.Key, err)
+	}
+	return resp.Body, nil
+}
+
+func (a *Adapter) GetRange(obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	var err error
+	defer reportMetrics("GetRange", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("x-ms-range", fmt.Sprintf("bytes=%d-%d", startPosition, endPosition))
+	resp, err := a.client.do(a.ctx, http.MethodGet, qualifiedKey.StorageNamespace, qualifiedKey.Key, nil, header, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get blob %s range: %w", qualifiedKey.Key, err)
+	}
+	return resp.Body, nil
+}
+
+func (a *Adapter) GetProperties(obj block.ObjectPointer) (block.Properties, error) {
+	var err error
+	defer reportMetrics("GetProperties", time.Now(), nil, &err)
+	var props block.Properties
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return props, err
+	}
+	resp, err := a.getBlobProperties(qualifiedKey)
+	if err != nil {
+		return props, err
+	}
+	// the access tier of the blob is its storage class
+	if tier := resp.Header.Get("x-ms-access-tier"); tier != "" {
+		props.StorageClass = &tier
+	}
+	return props, nil
+}
+
+func (a *Adapter) getBlobProperties(qualifiedKey block.QualifiedKey) (*http.Response, error) {
+	resp, err := a.client.do(a.ctx, http.MethodHead, qualifiedKey.StorageNamespace, qualifiedKey.Key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get blob %s properties: %w", qualifiedKey.Key, err)
+	}
+	return resp, resp.Body.Close()
+}
+
+func (a *Adapter) Remove(obj block.ObjectPointer) error {
+	var err error
+	defer reportMetrics("Remove", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.do(a.ctx, http.MethodDelete, qualifiedKey.StorageNamespace, qualifiedKey.Key, nil, nil, nil, 0)
+	if err != nil {
+		return fmt.Errorf("delete blob %s: %w", qualifiedKey.Key, err)
+	}
+	return resp.Body.Close()
+}
+
+// CreateMultiPartUpload returns a new upload ID.  Nothing is written until the parts are
+// uploaded.
+func (a *Adapter) CreateMultiPartUpload(obj block.ObjectPointer, _ *http.Request, _ block.CreateMultiPartUploadOpts) (string, error) {
+	var err error
+	defer reportMetrics("CreateMultiPartUpload", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return "", err
+	}
+	uid := uuid.New()
+	uploadID := a.uploadIDTranslator.SetUploadID(hex.EncodeToString(uid[:]))
+	a.log().WithFields(logging.Fields{
+		"upload_id":     uploadID,
+		"qualified_ns":  qualifiedKey.StorageNamespace,
+		"qualified_key": qualifiedKey.Key,
+		"key":           obj.Identifier,
+	}).Debug("created multipart upload")
+	return uploadID, nil
+}
+
+// blockID returns the ID of the block of a part.  All the block IDs of a blob have the same
+// length.
+func blockID(uploadID string, partNumber int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s-%05d", uploadID, partNumber)))
+}
+
+// UploadPart stages the part as a block of the target blob, and returns its MD5 as the part
+// ETag.
+func (a *Adapter) UploadPart(obj block.ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int64) (string, error) {
+	var err error
+	defer reportMetrics("UploadPart", time.Now(), &sizeBytes, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return "", err
+	}
+	uploadID = a.uploadIDTranslator.TranslateUploadID(uploadID)
+	md5Read := block.NewHashingReader(reader, block.HashFunctionMD5)
+	query := url.Values{
+		"comp":    []string{"block"},
+		"blockid": []string{blockID(uploadID, partNumber)},
+	}
+	resp, err := a.client.do(a.ctx, http.MethodPut, qualifiedKey.StorageNamespace, qualifiedKey.Key, query, nil, md5Read, sizeBytes)
+	if err != nil {
+		return "", fmt.Errorf("put block %d of %s: %w", partNumber, qualifiedKey.Key, err)
+	}
+	if err = resp.Body.Close(); err != nil {
+		return "", err
+	}
+	return "\"" + hex.EncodeToString(md5Read.Md5.Sum(nil)) + "\"", nil
+}
+
+// AbortMultiPartUpload does nothing: the service discards uncommitted blocks after a week.
+func (a *Adapter) AbortMultiPartUpload(_ block.ObjectPointer, uploadID string) error {
+	a.uploadIDTranslator.RemoveUploadID(uploadID)
+	return nil
+}
+
+type blockList struct {
+	XMLName xml.Name `xml:"BlockList"`
+	Latest  []string `xml:"Latest"`
+}
+
+// CompleteMultiPartUpload commits the blocks of the listed parts as the content of the target
+// blob.  The ETag of the blob follows the ETags of S3 multipart uploads.
+func (a *Adapter) CompleteMultiPartUpload(obj block.ObjectPointer, uploadID string, multipartList *block.MultipartUploadCompletion) (*string, int64, error) {
+	var err error
+	defer reportMetrics("CompleteMultiPartUpload", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return nil, 0, err
+	}
+	uploadID = a.uploadIDTranslator.TranslateUploadID(uploadID)
+	list := blockList{Latest: make([]string, len(multipartList.Part))}
+	for i, p := range multipartList.Part {
+		if p.PartNumber == nil {
+			return nil, 0, fmt.Errorf("invalid part at position %d: %w", i, ErrMissingPartNumber)
+		}
+		list.Latest[i] = blockID(uploadID, *p.PartNumber)
+	}
+	body, err := xml.Marshal(list)
+	if err != nil {
+		return nil, 0, err
+	}
+	query := url.Values{"comp": []string{"blocklist"}}
+	resp, err := a.client.do(a.ctx, http.MethodPut, qualifiedKey.StorageNamespace, qualifiedKey.Key, query, nil,
+		strings.NewReader(string(body)), int64(len(body)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("put block list of %s: %w", qualifiedKey.Key, err)
+	}
+	if err = resp.Body.Close(); err != nil {
+		return nil, 0, err
+	}
+	resp, err = a.getBlobProperties(qualifiedKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("blob %s size: %w", qualifiedKey.Key, err)
+	}
+	etag, err := computeETag(multipartList)
+	if err != nil {
+		return nil, 0, err
+	}
+	a.uploadIDTranslator.RemoveUploadID(uploadID)
+	a.log().WithFields(logging.Fields{
+		"upload_id":     uploadID,
+		"qualified_ns":  qualifiedKey.StorageNamespace,
+		"qualified_key": qualifiedKey.Key,
+		"key":           obj.Identifier,
+	}).Debug("completed multipart upload")
+	return &etag, size, nil
+}
+
+func (a *Adapter) ValidateConfiguration(_ string) error {
+	return nil
+}
+
+func (a *Adapter) GenerateInventory(_ context.Context, _ logging.Logger, _ string, _ bool) (block.Inventory, error) {
+	return nil, fmt.Errorf("inventory %w", ErrNotImplemented)
+}
+
+func (a *Adapter) BlockstoreType() string {
+	return BlockstoreType
+}
+
+// computeETag returns the ETag S3 gives a multipart upload: the MD5 of the MD5s of its parts,
+// followed by the number of parts.
+func computeETag(multipartList *block.MultipartUploadCompletion) (string, error) {
+	var sums []byte
+	for i, p := range multipartList.Part {
+		if p.ETag == nil {
+			return "", fmt.Errorf("invalid part at position %d: %w", i, ErrMissingPartETag)
+		}
+		sum, err := hex.DecodeString(strings.Trim(*p.ETag, "\""))
+		if err != nil {
+			return "", fmt.Errorf("invalid part at position %d: %w", i, err)
+		}
+		sums = append(sums, sum...)
+	}
+	etag := md5.Sum(sums) //nolint:gosec
+	return hex.EncodeToString(etag[:]) + "-" + strconv.Itoa(len(multipartList.Part)), nil
+}
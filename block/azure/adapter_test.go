@@ -0,0 +1,190 @@
+package azure_test
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/azure"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+const (
+	testAccount = "account"
+	testKey     = "a2V5" // "key"
+)
+
+// blobService serves the Blob service requests the adapter makes, on blobs held in memory
+type blobService struct {
+	mu     sync.Mutex
+	blobs  map[string][]byte
+	blocks map[string][]byte
+}
+
+func newBlobService() *blobService {
+	return &blobService{
+		blobs:  make(map[string][]byte),
+		blocks: make(map[string][]byte),
+	}
+}
+
+func (s *blobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey "+testAccount+":") || r.Header.Get("x-ms-date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	path := r.URL.Path
+	body, _ := ioutil.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "block":
+		s.blocks[path+"#"+r.URL.Query().Get("blockid")] = body
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "blocklist":
+		var list struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.Unmarshal(body, &list); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data []byte
+		for _, id := range list.Latest {
+			data = append(data, s.blocks[path+"#"+id]...)
+		}
+		s.blobs[path] = data
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.blobs[path] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		data, ok := s.blobs[path]
+		if !ok {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			delete(s.blobs, path)
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodHead:
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.Header().Set("x-ms-access-tier", "Hot")
+		default:
+			var start, end int
+			if _, err := fmt.Sscanf(r.Header.Get("x-ms-range"), "bytes=%d-%d", &start, &end); err == nil {
+				data = data[start : end+1]
+			}
+			_, _ = w.Write(data)
+		}
+	}
+}
+
+func testAdapter(t *testing.T) *azure.Adapter {
+	t.Helper()
+	server := httptest.NewServer(newBlobService())
+	t.Cleanup(server.Close)
+	client, err := azure.NewClient(server.Client(), testAccount, testKey, server.URL+"/"+testAccount)
+	testutil.MustDo(t, "new client", err)
+	return azure.NewAdapter(client)
+}
+
+func readAll(t *testing.T, what string, body io.ReadCloser, err error) string {
+	t.Helper()
+	testutil.MustDo(t, what, err)
+	defer func() { _ = body.Close() }()
+	data, err := ioutil.ReadAll(body)
+	testutil.MustDo(t, what+" read", err)
+	return string(data)
+}
+
+func TestAdapter(t *testing.T) {
+	adapter := testAdapter(t)
+	obj := block.ObjectPointer{StorageNamespace: "wasb://container/prefix", Identifier: "dir/file 1"}
+	const data = "hello azure"
+	testutil.MustDo(t, "put", adapter.Put(obj, int64(len(data)), strings.NewReader(data), block.PutOpts{}))
+
+	body, err := adapter.Get(obj, int64(len(data)))
+	if got := readAll(t, "get", body, err); got != data {
+		t.Errorf("Get() got %q, expected %q", got, data)
+	}
+	body, err = adapter.GetRange(obj, 6, 10)
+	if got := readAll(t, "get range", body, err); got != "azure" {
+		t.Errorf("GetRange() got %q, expected %q", got, "azure")
+	}
+	props, err := adapter.GetProperties(obj)
+	testutil.MustDo(t, "get properties", err)
+	if props.StorageClass == nil || *props.StorageClass != "Hot" {
+		t.Errorf("GetProperties() storage class %v, expected Hot", props.StorageClass)
+	}
+
+	testutil.MustDo(t, "remove", adapter.Remove(obj))
+	if _, err := adapter.Get(obj, 0); !errors.Is(err, azure.ErrNotFound) {
+		t.Errorf("Get() removed blob err=%v, expected %v", err, azure.ErrNotFound)
+	}
+
+	_, err = adapter.Get(block.ObjectPointer{StorageNamespace: "gs://bucket", Identifier: "file"}, 0)
+	if !errors.Is(err, block.ErrInvalidNamespace) {
+		t.Errorf("Get() from gs err=%v, expected %v", err, block.ErrInvalidNamespace)
+	}
+}
+
+func TestAdapter_MultipartUpload(t *testing.T) {
+	adapter := testAdapter(t)
+	obj := block.ObjectPointer{StorageNamespace: "wasb://container", Identifier: "multipart"}
+	uploadID, err := adapter.CreateMultiPartUpload(obj, nil, block.CreateMultiPartUploadOpts{})
+	testutil.MustDo(t, "create multipart upload", err)
+
+	parts := []string{"first part,", "second part"}
+	var completion block.MultipartUploadCompletion
+	// upload the parts out of order
+	for i := len(parts) - 1; i >= 0; i-- {
+		etag, err := adapter.UploadPart(obj, int64(len(parts[i])), strings.NewReader(parts[i]), uploadID, int64(i+1))
+		testutil.MustDo(t, "upload part", err)
+		completion.Part = append([]*s3.CompletedPart{{ETag: aws.String(etag), PartNumber: aws.Int64(int64(i + 1))}}, completion.Part...)
+	}
+	etag, size, err := adapter.CompleteMultiPartUpload(obj, uploadID, &completion)
+	testutil.MustDo(t, "complete multipart upload", err)
+	data := strings.Join(parts, "")
+	if size != int64(len(data)) {
+		t.Errorf("CompleteMultiPartUpload() size %d, expected %d", size, len(data))
+	}
+	// md5("first part,") and md5("second part") joined, hashed, and the number of parts
+	const expectedETag = "9051a2eb44b6b3aa39a093f16fc87279-2"
+	if etag == nil || *etag != expectedETag {
+		t.Errorf("CompleteMultiPartUpload() etag %v, expected %s", etag, expectedETag)
+	}
+	body, err := adapter.Get(obj, size)
+	if got := readAll(t, "get", body, err); got != data {
+		t.Errorf("Get() got %q, expected %q", got, data)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := azure.NewClient(nil, testAccount, "", ""); !errors.Is(err, azure.ErrMissingCredentials) {
+		t.Errorf("NewClient() without key err=%v, expected %v", err, azure.ErrMissingCredentials)
+	}
+	if _, err := azure.NewClient(nil, testAccount, "not base64!", ""); err == nil {
+		t.Error("NewClient() with invalid key expected an error")
+	}
+	if _, err := azure.NewClient(nil, testAccount, base64.StdEncoding.EncodeToString([]byte("key")), ""); err != nil {
+		t.Errorf("NewClient() err=%s", err)
+	}
+}
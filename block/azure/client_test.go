@@ -0,0 +1,39 @@
+package azure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClient_Signature(t *testing.T) {
+	client, err := NewClient(nil, "account", base64.StdEncoding.EncodeToString([]byte("key")), "")
+	if err != nil {
+		t.Fatalf("NewClient() err=%s", err)
+	}
+	u := client.blobURL("container", "dir/file 1", url.Values{"comp": []string{"block"}, "blockid": []string{"YQ=="}})
+	if u.String() != "https://account.blob.core.windows.net/container/dir/file%201?blockid=YQ%3D%3D&comp=block" {
+		t.Fatalf("blobURL() got %s", u)
+	}
+	req, err := http.NewRequest(http.MethodPut, u.String(), strings.NewReader("data"))
+	if err != nil {
+		t.Fatalf("NewRequest() err=%s", err)
+	}
+	req.Header.Set("x-ms-version", serviceVersion)
+	req.Header.Set("x-ms-date", "Thu, 15 Oct 2020 10:00:00 GMT")
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+
+	stringToSign := "PUT\n\n\n4\n\n\n\n\n\n\n\n\n" +
+		"x-ms-blob-type:BlockBlob\nx-ms-date:Thu, 15 Oct 2020 10:00:00 GMT\nx-ms-version:" + serviceVersion + "\n" +
+		"/account/container/dir/file%201\nblockid:YQ==\ncomp:block"
+	mac := hmac.New(sha256.New, []byte("key"))
+	_, _ = mac.Write([]byte(stringToSign))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if got := client.signature(req); got != expected {
+		t.Errorf("signature() got %s, expected %s", got, expected)
+	}
+}
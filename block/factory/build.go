@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"cloud.google.com/go/storage"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	log "github.com/sirupsen/logrus"
 	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/azure"
 	"github.com/treeverse/lakefs/block/gs"
 	"github.com/treeverse/lakefs/block/local"
 	"github.com/treeverse/lakefs/block/mem"
@@ -55,9 +57,15 @@ func BuildBlockAdapter(c *config.Config) (block.Adapter, error) {
 			return nil, err
 		}
 		return buildGSAdapter(p)
+	case azure.BlockstoreType:
+		p, err := c.GetBlockAdapterAzureParams()
+		if err != nil {
+			return nil, err
+		}
+		return buildAzureAdapter(p)
 	default:
 		return nil, fmt.Errorf("%w '%s' please choose one of %s",
-			ErrInvalidBlockStoreType, blockstore, []string{local.BlockstoreType, s3a.BlockstoreType, mem.BlockstoreType, transient.BlockstoreType, gs.BlockstoreType})
+			ErrInvalidBlockStoreType, blockstore, []string{local.BlockstoreType, s3a.BlockstoreType, mem.BlockstoreType, transient.BlockstoreType, gs.BlockstoreType, azure.BlockstoreType})
 	}
 }
 
@@ -109,3 +117,14 @@ func buildGSAdapter(params params.GS) (*gs.Adapter, error) {
 	log.WithField("type", "gs").Info("initialized blockstore adapter")
 	return adapter, nil
 }
+
+func buildAzureAdapter(params params.Azure) (*azure.Adapter, error) {
+	client, err := azure.NewClient(&http.Client{Timeout: params.TryTimeout},
+		params.StorageAccount, params.StorageAccessKey, params.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	adapter := azure.NewAdapter(client)
+	logging.Default().WithField("type", "azure").Info("initialized blockstore adapter")
+	return adapter, nil
+}
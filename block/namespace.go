@@ -14,6 +14,7 @@ const (
 	StorageTypeLocal
 	StorageTypeS3
 	StorageTypeGS
+	StorageTypeAzure
 )
 
 var (
@@ -37,6 +38,8 @@ func GetStorageType(namespaceURL *url.URL) (StorageType, error) {
 		return StorageTypeLocal, nil
 	case "gs":
 		return StorageTypeGS, nil
+	case "wasb":
+		return StorageTypeAzure, nil
 	default:
 		return st, fmt.Errorf("%s: %w", namespaceURL.Scheme, ErrInvalidNamespace)
 	}
@@ -93,3 +96,26 @@ func ResolveNamespace(defaultNamespace, key string) (QualifiedKey, error) {
 		Key:              formatPathWithNamespace("", parsedKey.Path),
 	}, nil
 }
+
+// NamespacesOverlap returns true if one storage namespace is the same as, or contained in, the
+// other.  Objects written under overlapping namespaces may collide.
+func NamespacesOverlap(a, b string) bool {
+	a = strings.TrimSuffix(a, "/") + "/"
+	b = strings.TrimSuffix(b, "/") + "/"
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// IsKeyInNamespace returns true if key resolves to an object inside storageNamespace: a relative
+// key, or a fully qualified key under the namespace.  Keys with ".." path elements are never
+// inside the namespace.
+func IsKeyInNamespace(storageNamespace, key string) bool {
+	for _, elem := range strings.Split(key, "/") {
+		if elem == ".." {
+			return false
+		}
+	}
+	if IsResolvableKey(key) {
+		return true
+	}
+	return strings.HasPrefix(key, strings.TrimSuffix(storageNamespace, "/")+"/")
+}
@@ -95,3 +95,51 @@ func TestResolveNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespacesOverlap(t *testing.T) {
+	cases := []struct {
+		A        string
+		B        string
+		Expected bool
+	}{
+		{A: "s3://foo", B: "s3://foo", Expected: true},
+		{A: "s3://foo", B: "s3://foo/", Expected: true},
+		{A: "s3://foo", B: "s3://foo/bar", Expected: true},
+		{A: "s3://foo/bar/", B: "s3://foo", Expected: true},
+		{A: "s3://foo", B: "s3://foobar", Expected: false},
+		{A: "s3://foo/bar", B: "s3://foo/baz", Expected: false},
+		{A: "s3://foo", B: "gs://foo", Expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.A+" "+c.B, func(t *testing.T) {
+			if got := block.NamespacesOverlap(c.A, c.B); got != c.Expected {
+				t.Fatalf("NamespacesOverlap(%s, %s) = %t, expected %t", c.A, c.B, got, c.Expected)
+			}
+		})
+	}
+}
+
+func TestIsKeyInNamespace(t *testing.T) {
+	cases := []struct {
+		Key      string
+		Expected bool
+	}{
+		{Key: "key", Expected: true},
+		{Key: "dir/key", Expected: true},
+		{Key: "s3://foo/bar/key", Expected: true},
+		{Key: "s3://foo/bar/dir/key", Expected: true},
+		{Key: "s3://foo/barbaz/key", Expected: false},
+		{Key: "s3://foo/key", Expected: false},
+		{Key: "s3://other/bar/key", Expected: false},
+		{Key: "/bar/key", Expected: false},
+		{Key: "../key", Expected: false},
+		{Key: "s3://foo/bar/../key", Expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.Key, func(t *testing.T) {
+			if got := block.IsKeyInNamespace("s3://foo/bar", c.Key); got != c.Expected {
+				t.Fatalf("IsKeyInNamespace(s3://foo/bar, %s) = %t, expected %t", c.Key, got, c.Expected)
+			}
+		})
+	}
+}
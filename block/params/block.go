@@ -22,3 +22,10 @@ type GS struct {
 	CredentialsFile string
 	CredentialsJSON string
 }
+
+type Azure struct {
+	StorageAccount   string
+	StorageAccessKey string
+	Endpoint         string
+	TryTimeout       time.Duration
+}
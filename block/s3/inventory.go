@@ -32,8 +32,13 @@ type inventoryFile struct {
 	Key string `json:"key"` // an s3 key for an inventory list file
 }
 
-func (a *Adapter) GenerateInventory(ctx context.Context, logger logging.Logger, manifestURL string, shouldSort bool) (block.Inventory, error) {
-	return GenerateInventory(logger, manifestURL, a.s3, inventorys3.NewReader(ctx, a.s3, logger), shouldSort)
+// GenerateInventory returns the inventory of an S3 inventory manifest, or of the objects under
+// a prefix if inventoryURL is not a manifest URL
+func (a *Adapter) GenerateInventory(ctx context.Context, logger logging.Logger, inventoryURL string, shouldSort bool) (block.Inventory, error) {
+	if !IsManifestURL(inventoryURL) {
+		return GenerateListingInventory(ctx, logger, inventoryURL, a.s3)
+	}
+	return GenerateInventory(logger, inventoryURL, a.s3, inventorys3.NewReader(ctx, a.s3, logger), shouldSort)
 }
 
 func GenerateInventory(logger logging.Logger, manifestURL string, s3 s3iface.S3API, inventoryReader inventorys3.IReader, shouldSort bool) (block.Inventory, error) {
@@ -0,0 +1,132 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const manifestFilename = "manifest.json"
+
+// IsManifestURL returns true if inventoryURL points to an S3 inventory manifest, rather than
+// to a prefix to list
+func IsManifestURL(inventoryURL string) bool {
+	return strings.HasSuffix(inventoryURL, "/"+manifestFilename)
+}
+
+// ListingInventory is an Inventory of the objects under an S3 prefix, read by listing the bucket.
+// Use it to import buckets that have no S3 inventory configured.
+type ListingInventory struct {
+	ctx    context.Context
+	svc    s3iface.S3API
+	url    string
+	bucket string
+	prefix string
+	logger logging.Logger
+}
+
+func GenerateListingInventory(ctx context.Context, logger logging.Logger, prefixURL string, svc s3iface.S3API) (block.Inventory, error) {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	u, err := url.Parse(prefixURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", block.ErrInvalidNamespace, prefixURL)
+	}
+	return &ListingInventory{
+		ctx:    ctx,
+		svc:    svc,
+		url:    prefixURL,
+		bucket: u.Host,
+		prefix: strings.TrimPrefix(u.Path, "/"),
+		logger: logger,
+	}, nil
+}
+
+func (inv *ListingInventory) Iterator() block.InventoryIterator {
+	return &ListingIterator{
+		ListingInventory: inv,
+		hasMore:          true,
+		progress:         cmdutils.NewProgress(fmt.Sprintf("Objects listed from %s", inv.url), 0),
+	}
+}
+
+func (inv *ListingInventory) SourceName() string {
+	return inv.bucket
+}
+
+func (inv *ListingInventory) InventoryURL() string {
+	return inv.url
+}
+
+// ListingIterator reads objects in key order, one listing page at a time
+type ListingIterator struct {
+	*ListingInventory
+	buffer            []*s3.Object
+	continuationToken *string
+	hasMore           bool
+	val               *block.InventoryObject
+	err               error
+	progress          *cmdutils.Progress
+}
+
+func (it *ListingIterator) Next() bool {
+	for len(it.buffer) == 0 {
+		if !it.hasMore || it.err != nil {
+			return false
+		}
+		it.fillBuffer()
+	}
+	obj := it.buffer[0]
+	it.buffer = it.buffer[1:]
+	key := aws.StringValue(obj.Key)
+	it.val = &block.InventoryObject{
+		Bucket:          it.bucket,
+		Key:             key,
+		Size:            aws.Int64Value(obj.Size),
+		LastModified:    aws.TimeValue(obj.LastModified),
+		Checksum:        strings.Trim(aws.StringValue(obj.ETag), `"`),
+		PhysicalAddress: "s3://" + it.bucket + "/" + key,
+	}
+	it.progress.Incr()
+	return true
+}
+
+func (it *ListingIterator) fillBuffer() {
+	output, err := it.svc.ListObjectsV2WithContext(it.ctx, &s3.ListObjectsV2Input{
+		Bucket:            aws.String(it.bucket),
+		Prefix:            aws.String(it.prefix),
+		ContinuationToken: it.continuationToken,
+	})
+	if err != nil {
+		it.err = fmt.Errorf("list s3://%s/%s: %w", it.bucket, it.prefix, err)
+		return
+	}
+	it.buffer = output.Contents
+	it.continuationToken = output.NextContinuationToken
+	it.hasMore = aws.BoolValue(output.IsTruncated)
+	it.progress.SetTotal(it.progress.Total() + int64(len(output.Contents)))
+}
+
+func (it *ListingIterator) Err() error {
+	return it.err
+}
+
+func (it *ListingIterator) Get() *block.InventoryObject {
+	return it.val
+}
+
+func (it *ListingIterator) Progress() []*cmdutils.Progress {
+	return []*cmdutils.Progress{it.progress}
+}
@@ -0,0 +1,104 @@
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	s3sdk "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/treeverse/lakefs/block/s3"
+)
+
+var errListFailed = errors.New("list failed")
+
+type mockListingClient struct {
+	s3iface.S3API
+	keys     []string
+	pageSize int
+	failAt   int
+	calls    int
+}
+
+func (m *mockListingClient) ListObjectsV2WithContext(_ aws.Context, input *s3sdk.ListObjectsV2Input, _ ...request.Option) (*s3sdk.ListObjectsV2Output, error) {
+	m.calls++
+	if m.calls == m.failAt {
+		return nil, errListFailed
+	}
+	start := 0
+	if input.ContinuationToken != nil {
+		_, _ = fmt.Sscan(*input.ContinuationToken, &start)
+	}
+	var contents []*s3sdk.Object
+	i := start
+	for ; i < len(m.keys) && len(contents) < m.pageSize; i++ {
+		if !strings.HasPrefix(m.keys[i], aws.StringValue(input.Prefix)) {
+			continue
+		}
+		contents = append(contents, &s3sdk.Object{
+			Key:  aws.String(m.keys[i]),
+			Size: aws.Int64(int64(i)),
+			ETag: aws.String(`"etag` + m.keys[i] + `"`),
+		})
+	}
+	output := &s3sdk.ListObjectsV2Output{Contents: contents, IsTruncated: aws.Bool(i < len(m.keys))}
+	if i < len(m.keys) {
+		output.NextContinuationToken = aws.String(fmt.Sprint(i))
+	}
+	return output, nil
+}
+
+func TestListingIterator(t *testing.T) {
+	keys := []string{"data/a", "data/b", "data/c", "data/d", "data/e", "other/f"}
+	tests := []struct {
+		name         string
+		url          string
+		failAt       int
+		expectedKeys []string
+		expectedErr  error
+	}{
+		{name: "prefix", url: "s3://bucket/data/", expectedKeys: []string{"data/a", "data/b", "data/c", "data/d", "data/e"}},
+		{name: "bucket", url: "s3://bucket", expectedKeys: keys},
+		{name: "error", url: "s3://bucket/data/", failAt: 2, expectedErr: errListFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockListingClient{keys: keys, pageSize: 2, failAt: tt.failAt}
+			inv, err := s3.GenerateListingInventory(context.Background(), nil, tt.url, svc)
+			if err != nil {
+				t.Fatalf("GenerateListingInventory() error = %s", err)
+			}
+			if inv.SourceName() != "bucket" || inv.InventoryURL() != tt.url {
+				t.Fatalf("inventory source = %s, url = %s", inv.SourceName(), inv.InventoryURL())
+			}
+			it := inv.Iterator()
+			var gotKeys []string
+			for it.Next() {
+				obj := it.Get()
+				if obj.PhysicalAddress != "s3://bucket/"+obj.Key || obj.Checksum != "etag"+obj.Key {
+					t.Errorf("object %s address = %s, checksum = %s", obj.Key, obj.PhysicalAddress, obj.Checksum)
+				}
+				gotKeys = append(gotKeys, obj.Key)
+			}
+			if !errors.Is(it.Err(), tt.expectedErr) {
+				t.Fatalf("iterator error = %v, expected %v", it.Err(), tt.expectedErr)
+			}
+			if tt.expectedErr == nil && fmt.Sprint(gotKeys) != fmt.Sprint(tt.expectedKeys) {
+				t.Fatalf("iterator keys = %v, expected %v", gotKeys, tt.expectedKeys)
+			}
+		})
+	}
+}
+
+func TestIsManifestURL(t *testing.T) {
+	if !s3.IsManifestURL("s3://bucket/inventory/2020-10-01T00-00Z/manifest.json") {
+		t.Error("IsManifestURL() = false for a manifest")
+	}
+	if s3.IsManifestURL("s3://bucket/data/") {
+		t.Error("IsManifestURL() = true for a prefix")
+	}
+}
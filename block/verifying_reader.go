@@ -0,0 +1,166 @@
+package block
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var ErrIntegrity = errors.New("object integrity check failed")
+
+var checksumVerificationsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "block_checksum_verifications",
+		Help: "Objects read with checksum verification, by result",
+	},
+	[]string{"result"})
+
+// IntegrityError reports an object whose content does not match the checksum it was stored with
+type IntegrityError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("%s: expected checksum %s, got %s", ErrIntegrity, e.Expected, e.Actual)
+}
+
+func (e *IntegrityError) Unwrap() error {
+	return ErrIntegrity
+}
+
+// VerifyingReader computes the MD5 of the data read through it, and fails the read that reaches EOF with an
+// *IntegrityError if it does not match the expected checksum.  The last byte read is held back until the
+// checksum is verified, and is not returned on a mismatch, so the data is never passed on complete - a
+// response copied from the reader is cut short if the content does not match.
+type VerifyingReader struct {
+	reader   io.ReadCloser
+	md5      hash.Hash
+	expected string
+	held     byte
+	hasHeld  bool
+	done     bool
+}
+
+// NewVerifyingReader wraps reader to verify its content against checksum, the hex MD5 of the object.  Checksums
+// that are not a plain MD5 (e.g. multipart upload ETags, "<md5>-<parts>") cannot be recomputed from the content, so
+// reader is returned as is.
+func NewVerifyingReader(reader io.ReadCloser, checksum string) io.ReadCloser {
+	if !IsVerifiableChecksum(checksum) {
+		checksumVerificationsCounter.WithLabelValues("skipped").Inc()
+		return reader
+	}
+	return &VerifyingReader{
+		reader:   reader,
+		md5:      md5.New(), //nolint:gosec
+		expected: checksum,
+	}
+}
+
+// IsVerifiableChecksum returns true if checksum is a hex MD5 that can be compared to the MD5 of the content
+func IsVerifiableChecksum(checksum string) bool {
+	if len(checksum) != hex.EncodedLen(md5.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(checksum)
+	return err == nil
+}
+
+func (r *VerifyingReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.done {
+		// the content was verified, pass on the byte held back
+		if !r.hasHeld {
+			return 0, io.EOF
+		}
+		p[0] = r.held
+		r.hasHeld = false
+		return 1, io.EOF
+	}
+	n := 0
+	if r.hasHeld {
+		p[0] = r.held
+		n = 1
+	}
+	var err error
+	if n < len(p) {
+		var m int
+		m, err = r.reader.Read(p[n:])
+		_, _ = r.md5.Write(p[n : n+m])
+		n += m
+		r.hasHeld = n > 0
+		if r.hasHeld {
+			n--
+			r.held = p[n]
+		}
+	} else {
+		// p only has room for the held byte, read ahead a byte to hold back instead
+		var ahead [1]byte
+		var m int
+		m, err = r.reader.Read(ahead[:])
+		_, _ = r.md5.Write(ahead[:m])
+		if m > 0 {
+			r.held = ahead[0]
+		} else {
+			n = 0
+		}
+	}
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	r.done = true
+	actual := hex.EncodeToString(r.md5.Sum(nil))
+	if actual != r.expected {
+		checksumVerificationsCounter.WithLabelValues("mismatch").Inc()
+		r.hasHeld = false
+		return n, &IntegrityError{Expected: r.expected, Actual: actual}
+	}
+	checksumVerificationsCounter.WithLabelValues("ok").Inc()
+	if r.hasHeld && n < len(p) {
+		p[n] = r.held
+		n++
+		r.hasHeld = false
+	}
+	if r.hasHeld {
+		return n, nil
+	}
+	return n, io.EOF
+}
+
+func (r *VerifyingReader) Close() error {
+	return r.reader.Close()
+}
+
+// abortOnIntegrityErrorReader aborts the HTTP handler reading it on an integrity error
+type abortOnIntegrityErrorReader struct {
+	io.ReadCloser
+	onError func(err error)
+}
+
+// NewAbortOnIntegrityErrorReader wraps reader, the body of an HTTP response, to abort the response on a read
+// that fails with ErrIntegrity: onError is called with the error, and the handler panics with
+// http.ErrAbortHandler so the server closes the connection without completing the response.  Use it when the
+// body is copied by code that does not handle read errors, after the response headers were sent.
+func NewAbortOnIntegrityErrorReader(reader io.ReadCloser, onError func(err error)) io.ReadCloser {
+	return &abortOnIntegrityErrorReader{ReadCloser: reader, onError: onError}
+}
+
+func (r *abortOnIntegrityErrorReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, ErrIntegrity) {
+		if r.onError != nil {
+			r.onError(err)
+		}
+		panic(http.ErrAbortHandler)
+	}
+	return n, err
+}
@@ -0,0 +1,98 @@
+package block_test
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/treeverse/lakefs/block"
+)
+
+func TestVerifyingReader(t *testing.T) {
+	const data = "hello world"
+	sum := md5.Sum([]byte(data)) //nolint:gosec
+	checksum := hex.EncodeToString(sum[:])
+
+	cases := []struct {
+		Name         string
+		Data         string
+		Checksum     string
+		ExpectedData string
+		ExpectedErr  error
+	}{
+		{Name: "match", Data: data, Checksum: checksum, ExpectedData: data},
+		// the last byte is not passed on when the content does not match
+		{Name: "mismatch", Data: data + "!", Checksum: checksum, ExpectedData: data, ExpectedErr: block.ErrIntegrity},
+		{Name: "empty_mismatch", Data: "", Checksum: checksum, ExpectedErr: block.ErrIntegrity},
+		{Name: "multipart_etag_skipped", Data: data, Checksum: checksum + "-2", ExpectedData: data},
+		{Name: "empty_checksum_skipped", Data: data, Checksum: "", ExpectedData: data},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			reader := block.NewVerifyingReader(ioutil.NopCloser(strings.NewReader(tt.Data)), tt.Checksum)
+			b, err := ioutil.ReadAll(reader)
+			if !errors.Is(err, tt.ExpectedErr) {
+				t.Fatalf("ReadAll() err=%v, expected=%v", err, tt.ExpectedErr)
+			}
+			if string(b) != tt.ExpectedData {
+				t.Fatalf("ReadAll() data=%q, expected=%q", b, tt.ExpectedData)
+			}
+			var integrityErr *block.IntegrityError
+			if errors.As(err, &integrityErr) && integrityErr.Expected != tt.Checksum {
+				t.Fatalf("IntegrityError expected checksum=%s, expected=%s", integrityErr.Expected, tt.Checksum)
+			}
+			if err := reader.Close(); err != nil {
+				t.Fatalf("Close() err=%v", err)
+			}
+		})
+		t.Run(tt.Name+"_one_byte", func(t *testing.T) {
+			reader := block.NewVerifyingReader(ioutil.NopCloser(strings.NewReader(tt.Data)), tt.Checksum)
+			b, err := ioutil.ReadAll(iotest.OneByteReader(reader))
+			if !errors.Is(err, tt.ExpectedErr) {
+				t.Fatalf("ReadAll() err=%v, expected=%v", err, tt.ExpectedErr)
+			}
+			if string(b) != tt.ExpectedData {
+				t.Fatalf("ReadAll() data=%q, expected=%q", b, tt.ExpectedData)
+			}
+		})
+	}
+}
+
+func TestAbortOnIntegrityErrorReader(t *testing.T) {
+	// larger than the server response buffer, so the headers and part of the body are sent
+	data := strings.Repeat("0123456789", 10000)
+	sum := md5.Sum([]byte(data)) //nolint:gosec
+	checksum := hex.EncodeToString(sum[:])
+	stored := data[:len(data)-1] + "x"
+
+	handlerErr := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader := block.NewVerifyingReader(ioutil.NopCloser(strings.NewReader(stored)), checksum)
+		reader = block.NewAbortOnIntegrityErrorReader(reader, func(err error) { handlerErr <- err })
+		w.Header().Set("Content-Length", strconv.Itoa(len(stored)))
+		_, _ = io.Copy(w, reader)
+	}))
+	defer server.Close()
+
+	// the client fails to read the response, whether or not its headers were sent
+	resp, err := http.Get(server.URL) //nolint:noctx
+	if err == nil {
+		defer func() { _ = resp.Body.Close() }()
+		var b []byte
+		b, err = ioutil.ReadAll(resp.Body)
+		if err == nil {
+			t.Fatalf("ReadAll() of a corrupted object read %d bytes with no error", len(b))
+		}
+	}
+	if err := <-handlerErr; !errors.Is(err, block.ErrIntegrity) {
+		t.Fatalf("onError err=%v, expected %s", err, block.ErrIntegrity)
+	}
+}
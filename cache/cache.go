@@ -3,9 +3,11 @@ package cache
 import (
 	"errors"
 	"math/rand"
+	"sync/atomic"
 	"time"
 
 	lru "github.com/hnlq715/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type JitterFn func() time.Duration
@@ -13,6 +15,13 @@ type SetFn func() (v interface{}, err error)
 
 type Cache interface {
 	GetOrSet(k interface{}, setFn SetFn) (v interface{}, err error)
+	Remove(k interface{})
+}
+
+// Stats counts the lookups served by a cache
+type Stats struct {
+	Hits   int64
+	Misses int64
 }
 
 type GetSetCache struct {
@@ -20,26 +29,38 @@ type GetSetCache struct {
 	locker     *ChanLocker
 	jitterFn   JitterFn
 	baseExpiry time.Duration
+	hits       int64
+	misses     int64
+	hitCount   prometheus.Counter
+	missCount  prometheus.Counter
 }
 
 var (
 	ErrCacheItemNotFound = errors.New("cache item not found")
 )
 
-func NewCache(size int, expiry time.Duration, jitterFn JitterFn) *GetSetCache {
+// NewCache returns an LRU cache holding up to size items.  Lookups are counted under name in the
+// cache_access metric.
+func NewCache(name string, size int, expiry time.Duration, jitterFn JitterFn) *GetSetCache {
 	c, _ := lru.New(size)
 	return &GetSetCache{
 		lru:        c,
 		locker:     NewChanLocker(),
 		jitterFn:   jitterFn,
 		baseExpiry: expiry,
+		hitCount:   cacheAccessCounter.WithLabelValues(name, "hit"),
+		missCount:  cacheAccessCounter.WithLabelValues(name, "miss"),
 	}
 }
 
 func (c *GetSetCache) GetOrSet(k interface{}, setFn SetFn) (v interface{}, err error) {
 	if v, ok := c.lru.Get(k); ok {
+		atomic.AddInt64(&c.hits, 1)
+		c.hitCount.Inc()
 		return v, nil
 	}
+	atomic.AddInt64(&c.misses, 1)
+	c.missCount.Inc()
 	acquired := c.locker.Lock(k, func() {
 		v, err = setFn()
 		if err != nil {
@@ -61,6 +82,20 @@ func (c *GetSetCache) GetOrSet(k interface{}, setFn SetFn) (v interface{}, err e
 	return nil, ErrCacheItemNotFound
 }
 
+// Remove removes k from the cache, so the next lookup calls setFn
+func (c *GetSetCache) Remove(k interface{}) {
+	c.lru.Remove(k)
+}
+
+// Stats returns the number of lookups found in the cache and the number that called setFn or
+// waited for another caller to set the value
+func (c *GetSetCache) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+	}
+}
+
 func NewJitterFn(jitter time.Duration) JitterFn {
 	return func() time.Duration {
 		n := rand.Intn(int(jitter)) //nolint:gosec
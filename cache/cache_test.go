@@ -0,0 +1,33 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/cache"
+)
+
+func TestGetSetCache_Stats(t *testing.T) {
+	c := cache.NewCache("test", 10, time.Minute, func() time.Duration { return 0 })
+	calls := 0
+	setFn := func() (interface{}, error) {
+		calls++
+		return "value", nil
+	}
+	for i := 0; i < 3; i++ {
+		v, err := c.GetOrSet("key", setFn)
+		if err != nil {
+			t.Fatalf("GetOrSet() error = %s", err)
+		}
+		if v != "value" {
+			t.Fatalf("GetOrSet() = %v, expected 'value'", v)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("GetOrSet() called setFn %d times, expected 1", calls)
+	}
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Stats() = %+v, expected 2 hits and 1 miss", stats)
+	}
+}
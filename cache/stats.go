@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var cacheAccessCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cache_access",
+		Help: "Cache lookups by cache name and result (hit or miss)",
+	},
+	[]string{"cache", "result"},
+)
@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+const branchExpiryPolicyConfigKey = "branchExpiryPolicy"
+
+// BranchExpiryPolicy selects the branches of a repository that ExpireBranches deletes: branches
+// whose name matches one of Patterns (path.Match syntax, e.g. "tmp-*") and whose last commit is
+// older than OlderThanDays.  Branches named in Exempt are never expired.
+type BranchExpiryPolicy struct {
+	Patterns      []string `json:"patterns"`
+	OlderThanDays int      `json:"older_than_days"`
+	Exempt        []string `json:"exempt,omitempty"`
+	Description   string   `json:"description,omitempty"`
+}
+
+// BranchExpiryPolicyWithCreationTime is a stored BranchExpiryPolicy
+type BranchExpiryPolicyWithCreationTime struct {
+	BranchExpiryPolicy
+	CreatedAt time.Time
+}
+
+// ExpiredBranch is a branch deleted (or, on a dry run, that would be deleted) by ExpireBranches
+type ExpiredBranch struct {
+	Name            string
+	CommitReference string
+	CommitDate      time.Time
+}
+
+// Validate checks the policy has patterns, that they are well formed, and a positive age
+func (p *BranchExpiryPolicy) Validate() error {
+	if len(p.Patterns) == 0 {
+		return fmt.Errorf("%w: patterns", ErrInvalidValue)
+	}
+	for _, pattern := range p.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: pattern '%s'", ErrInvalidValue, pattern)
+		}
+	}
+	if p.OlderThanDays < 1 {
+		return fmt.Errorf("%w: older_than_days", ErrInvalidValue)
+	}
+	return nil
+}
+
+// Matches reports whether branch is selected by the policy name patterns and not exempt
+func (p *BranchExpiryPolicy) Matches(branch string) bool {
+	for _, exempt := range p.Exempt {
+		if exempt == branch {
+			return false
+		}
+	}
+	for _, pattern := range p.Patterns {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package catalog
+
+import "testing"
+
+func TestBranchExpiryPolicy_Matches(t *testing.T) {
+	policy := &BranchExpiryPolicy{
+		Patterns:      []string{"tmp-*", "ci-?"},
+		OlderThanDays: 1,
+		Exempt:        []string{"tmp-keep"},
+	}
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "tmp-1", want: true},
+		{branch: "tmp-", want: true},
+		{branch: "ci-1", want: true},
+		{branch: "ci-12", want: false},
+		{branch: "tmp-keep", want: false},
+		{branch: "master", want: false},
+		{branch: "feature-tmp-1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if got := policy.Matches(tt.branch); got != tt.want {
+				t.Errorf("Matches(%s) = %t, expected %t", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
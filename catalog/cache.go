@@ -14,6 +14,8 @@ type Cache interface {
 	Repository(repository string, setFn GetRepositoryFn) (*Repository, error)
 	RepositoryID(repository string, setFn GetRepositoryIDFn) (int, error)
 	BranchID(repository string, branch string, setFn GetBranchIDFn) (int64, error)
+	RemoveBranchID(repository string, branch string)
+	RemoveRepository(repository string)
 }
 
 type LRUCache struct {
@@ -25,9 +27,9 @@ type LRUCache struct {
 func NewLRUCache(size int, expiry, jitter time.Duration) *LRUCache {
 	jitterFn := cache.NewJitterFn(jitter)
 	return &LRUCache{
-		repository:   cache.NewCache(size, expiry, jitterFn),
-		repositoryID: cache.NewCache(size, expiry, jitterFn),
-		branchID:     cache.NewCache(size, expiry, jitterFn),
+		repository:   cache.NewCache("catalog_repository", size, expiry, jitterFn),
+		repositoryID: cache.NewCache("catalog_repository_id", size, expiry, jitterFn),
+		branchID:     cache.NewCache("catalog_branch_id", size, expiry, jitterFn),
 	}
 }
 
@@ -52,7 +54,7 @@ func (c *LRUCache) RepositoryID(repository string, setFn GetRepositoryIDFn) (int
 }
 
 func (c *LRUCache) BranchID(repository string, branch string, setFn GetBranchIDFn) (int64, error) {
-	key := repository + "/" + branch
+	key := branchIDKey(repository, branch)
 	v, err := c.branchID.GetOrSet(key, func() (interface{}, error) {
 		return setFn(repository, branch)
 	})
@@ -62,6 +64,19 @@ func (c *LRUCache) BranchID(repository string, branch string, setFn GetBranchIDF
 	return v.(int64), nil
 }
 
+func (c *LRUCache) RemoveBranchID(repository string, branch string) {
+	c.branchID.Remove(branchIDKey(repository, branch))
+}
+
+func (c *LRUCache) RemoveRepository(repository string) {
+	c.repository.Remove(repository)
+	c.repositoryID.Remove(repository)
+}
+
+func branchIDKey(repository string, branch string) string {
+	return repository + "/" + branch
+}
+
 type DummyCache struct{}
 
 func (c *DummyCache) Repository(repository string, setFn GetRepositoryFn) (*Repository, error) {
@@ -75,3 +90,7 @@ func (c *DummyCache) RepositoryID(repository string, setFn GetRepositoryIDFn) (i
 func (c *DummyCache) BranchID(repository string, branch string, setFn GetBranchIDFn) (int64, error) {
 	return setFn(repository, branch)
 }
+
+func (c *DummyCache) RemoveBranchID(repository string, branch string) {}
+
+func (c *DummyCache) RemoveRepository(repository string) {}
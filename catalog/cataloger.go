@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -59,18 +61,39 @@ type ExpireResult struct {
 
 type RepositoryCataloger interface {
 	CreateRepository(ctx context.Context, repository string, storageNamespace string, branch string) error
+	ForkRepository(ctx context.Context, sourceRepository, repository string) error
+	ExportReplication(ctx context.Context, repository string, after int64, w io.Writer) (int64, error)
+	ImportReplication(ctx context.Context, repository string, r io.Reader) (int64, error)
+	GetReplicationCheckpoint(ctx context.Context, repository string) (int64, error)
+	DumpRepository(ctx context.Context, repository string, w io.Writer) error
+	RestoreRepository(ctx context.Context, repository, storageNamespace string, r io.Reader) error
 	GetRepository(ctx context.Context, repository string) (*Repository, error)
 	DeleteRepository(ctx context.Context, repository string) error
-	ListRepositories(ctx context.Context, limit int, after string) ([]*Repository, bool, error)
+	UpdateRepository(ctx context.Context, repository string, params UpdateRepositoryParams) error
+	ListRepositories(ctx context.Context, params ListRepositoriesParams, limit int, after string) ([]*Repository, bool, error)
+	GetEvents(ctx context.Context, repository string, after int64, limit int) ([]*RepositoryEvent, bool, error)
+	DeleteEvents(ctx context.Context, repository string, createdBefore time.Time) (int, error)
+	CheckRepository(ctx context.Context, repository string) ([]*ConsistencyIssue, error)
+	GetProtectedPaths(ctx context.Context, repository string) ([]*ProtectedPathRule, error)
+	SetProtectedPaths(ctx context.Context, repository string, rules []*ProtectedPathRule) error
+	GetRepositoryQuotas(ctx context.Context, repository string) (*RepositoryQuotas, error)
+	SetRepositoryQuotas(ctx context.Context, repository string, quotas *RepositoryQuotas) error
+	GetRepositoryUsage(ctx context.Context, repository string) (*RepositoryUsage, error)
+	UpgradeRepositoryConfigs(ctx context.Context) (int, error)
 }
 
 type BranchCataloger interface {
-	CreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*CommitLog, error)
-	DeleteBranch(ctx context.Context, repository, branch string) error
+	CreateBranch(ctx context.Context, repository, branch string, sourceRef string) (*CommitLog, error)
+	DeleteBranch(ctx context.Context, repository, branch string) (string, error)
 	ListBranches(ctx context.Context, repository string, prefix string, limit int, after string) ([]*Branch, bool, error)
 	BranchExists(ctx context.Context, repository string, branch string) (bool, error)
 	GetBranchReference(ctx context.Context, repository, branch string) (string, error)
+	GetBranch(ctx context.Context, repository, branch string) (*BranchInfo, error)
 	ResetBranch(ctx context.Context, repository, branch string) error
+	ResetBranchToCommit(ctx context.Context, repository, branch, reference string) error
+	GetBranchExpiryPolicy(ctx context.Context, repository string) (*BranchExpiryPolicyWithCreationTime, error)
+	SetBranchExpiryPolicy(ctx context.Context, repository string, policy *BranchExpiryPolicy) error
+	ExpireBranches(ctx context.Context, repository string, dryRun bool) ([]*ExpiredBranch, error)
 }
 
 var ErrExpired = errors.New("expired from storage")
@@ -94,22 +117,44 @@ type GetEntryParams struct {
 
 type CreateEntryParams struct {
 	Dedup DedupParams
+	// IfAbsent fails the create with ErrEntryAlreadyExists if the branch already has an entry
+	// at the path, uncommitted or committed.
+	IfAbsent bool
 }
 
 type EntryCataloger interface {
 	// GetEntry returns the current entry for path in repository branch reference.  Returns
 	// the entry with ExpiredError if it has expired from underlying storage.
 	GetEntry(ctx context.Context, repository, reference string, path string, params GetEntryParams) (*Entry, error)
+	StatEntry(ctx context.Context, repository, reference string, path string) (*EntryStat, error)
+	// Blame returns the commit that introduced the current version of the entry for path in
+	// repository reference.
+	Blame(ctx context.Context, repository, reference string, path string) (*CommitLog, error)
 	CreateEntry(ctx context.Context, repository, branch string, entry Entry, params CreateEntryParams) error
 	CreateEntries(ctx context.Context, repository, branch string, entries []Entry) error
 	DeleteEntry(ctx context.Context, repository, branch string, path string) error
+	DeleteEntries(ctx context.Context, repository, branch string, paths []string) error
+	RenameEntry(ctx context.Context, repository, branch string, sourcePath, destinationPath string) error
+	CopyEntry(ctx context.Context, repository, sourceReference, sourcePath, destinationBranch, destinationPath string) (*Entry, error)
+	CopyPath(ctx context.Context, sourceRepository, sourceReference, sourcePath, repository, branch, destinationPath string) (int, error)
 	ListEntries(ctx context.Context, repository, reference string, prefix, after string, delimiter string, limit int) ([]*Entry, bool, error)
+	WalkEntries(ctx context.Context, repository, reference string, prefix string, fn WalkEntriesFunc) error
+	SearchEntries(ctx context.Context, repository, reference string, params SearchEntriesParams, after string, limit int) ([]*Entry, bool, error)
+	ListWorkspace(ctx context.Context, repository, branch string, limit int, after string) ([]*WorkspaceEntry, bool, error)
+	GetWorkspaceExpiryPolicy(ctx context.Context, repository string) (*WorkspaceExpiryPolicyWithCreationTime, error)
+	SetWorkspaceExpiryPolicy(ctx context.Context, repository string, policy *WorkspaceExpiryPolicy) error
+	ExpireWorkspace(ctx context.Context, repository string, dryRun bool) ([]*ExpiredWorkspace, error)
 	ResetEntry(ctx context.Context, repository, branch string, path string) error
 	ResetEntries(ctx context.Context, repository, branch string, prefix string) error
+	ResetPath(ctx context.Context, repository, branch string, path string) error
+	RevertPath(ctx context.Context, repository, branch, reference, path string) error
 
 	// QueryEntriesToExpire returns ExpiryRows iterating over all objects to expire on
 	// repositoryName according to policy.
 	QueryEntriesToExpire(ctx context.Context, repositoryName string, policy *Policy) (ExpiryRows, error)
+	// GetExpiryReport reports the entries, objects and bytes that expiring repository
+	// according to policy would remove, and the commits they belong to.  Nothing expires.
+	GetExpiryReport(ctx context.Context, repository string, policy *Policy) (*ExpiryReport, error)
 	// MarkEntriesExpired marks all entries identified by expire as expired.  It is a batch operation.
 	MarkEntriesExpired(ctx context.Context, repositoryName string, expireResults []*ExpireResult) error
 	// MarkObjectsForDeletion marks objects in catalog_object_dedup as "deleting" if all
@@ -125,6 +170,9 @@ type EntryCataloger interface {
 	// expired objects.  It also removes the "deleting" mark from those objects that have an
 	// entry _not_ marked as expiring and therefore were not on the returned rows.
 	DeleteOrUnmarkObjectsForDeletion(ctx context.Context, repositoryName string) (StringRows, error)
+	// FindUnreferencedAddresses returns the fully qualified object addresses, of addresses,
+	// that no entry, multipart upload or dedup record of any repository points to.
+	FindUnreferencedAddresses(ctx context.Context, addresses []string) ([]string, error)
 
 	DedupReportChannel() chan *DedupReport
 }
@@ -133,22 +181,45 @@ type MultipartUpdateCataloger interface {
 	CreateMultipartUpload(ctx context.Context, repository, uploadID, path, physicalAddress string, creationTime time.Time) error
 	GetMultipartUpload(ctx context.Context, repository, uploadID string) (*MultipartUpload, error)
 	DeleteMultipartUpload(ctx context.Context, repository, uploadID string) error
+	ListMultipartUploads(ctx context.Context, repository string, createdBefore time.Time, limit int, after string) ([]*MultipartUpload, bool, error)
+	CreateMultipartUploadPart(ctx context.Context, repository, uploadID string, part MultipartUploadPart) error
+	ListMultipartUploadParts(ctx context.Context, repository, uploadID string, limit int, after int) ([]*MultipartUploadPart, bool, error)
 }
 
 type Committer interface {
-	Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata) (*CommitLog, error)
+	Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata, opts ...CommitOption) (*CommitLog, error)
 	GetCommit(ctx context.Context, repository, reference string) (*CommitLog, error)
-	ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int) ([]*CommitLog, bool, error)
+	GetCommitAt(ctx context.Context, repository, branch string, ts time.Time) (*CommitLog, error)
+	ResolveReference(ctx context.Context, repository, reference string) (string, error)
+	Snapshot(ctx context.Context, repository, reference string) (string, error)
+	ListCommits(ctx context.Context, repository, reference string, fromReference string, limit int, opts ...ListCommitsOption) ([]*CommitLog, bool, error)
+	ListPathCommits(ctx context.Context, repository, reference string, path string, fromReference string, limit int) ([]*CommitLog, bool, error)
+	SearchCommits(ctx context.Context, repository string, params SearchCommitsParams, limit int, after string) ([]*CommitLog, bool, error)
 	RollbackCommit(ctx context.Context, repository, reference string) error
 }
 
+type TagCataloger interface {
+	CreateTag(ctx context.Context, repository, tagName, reference, tagger, message string, metadata Metadata) (*Tag, error)
+	GetTag(ctx context.Context, repository, tagName string) (*Tag, error)
+	ListTags(ctx context.Context, repository string, limit int, after string) ([]*Tag, bool, error)
+	DeleteTag(ctx context.Context, repository, tagName string) error
+}
+
 type Differ interface {
-	Diff(ctx context.Context, repository, leftBranch string, rightBranch string, limit int, after string) (Differences, bool, error)
+	Diff(ctx context.Context, repository, leftReference string, rightReference string, limit int, after string) (Differences, bool, error)
 	DiffUncommitted(ctx context.Context, repository, branch string, limit int, after string) (Differences, bool, error)
 }
 
 type Merger interface {
-	Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata) (*MergeResult, error)
+	Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata, opts ...MergeOpt) (*MergeResult, error)
+	ListMergeConflicts(ctx context.Context, repository, sourceBranch, destinationBranch string, limit int, after string) ([]*MergeConflict, bool, error)
+	MergeBase(ctx context.Context, repository, leftReference, rightReference string) (*CommitLog, error)
+	CherryPick(ctx context.Context, sourceRepository, reference, repository, branch string) (int, error)
+	Rebase(ctx context.Context, repository, branch, onto, committer string) (*RebaseResult, error)
+}
+
+type RepositoryTransactor interface {
+	Transact(ctx context.Context, repository string, branches []string, fn RepositoryTxFunc) error
 }
 
 type Cataloger interface {
@@ -156,9 +227,11 @@ type Cataloger interface {
 	BranchCataloger
 	EntryCataloger
 	Committer
+	TagCataloger
 	MultipartUpdateCataloger
 	Differ
 	Merger
+	RepositoryTransactor
 	io.Closer
 }
 
@@ -188,6 +261,7 @@ type cataloger struct {
 	dedupReportEnabled   bool
 	dedupReportCh        chan *DedupReport
 	readEntryRequestChan chan *readRequest
+	hooks                hooks
 }
 
 type CatalogerOption func(*cataloger)
@@ -234,6 +308,7 @@ func WithParams(p params.Catalog) CatalogerOption {
 			c.Cache.Jitter = p.Cache.Jitter
 		}
 		c.Cache.Enabled = p.Cache.Enabled
+		c.Quotas = p.Quotas
 	}
 }
 
@@ -284,14 +359,28 @@ func (c *cataloger) startReadOrchestrator() {
 	go c.readEntriesBatchOrchestrator()
 }
 
+// txOpts returns the options of a transaction of the calling cataloger operation, which is
+// traced as "catalog.<operation>".
 func (c *cataloger) txOpts(ctx context.Context, opts ...db.TxOpt) []db.TxOpt {
 	o := []db.TxOpt{
 		db.WithContext(ctx),
-		db.WithLogger(c.log),
+		db.WithLogger(c.log.WithContext(ctx)),
+		db.WithOperation("catalog." + callerName()),
 	}
 	return append(o, opts...)
 }
 
+// callerName returns the unqualified name of the function that called the caller of callerName
+func callerName() string {
+	pcs := make([]uintptr, 1)
+	// skip runtime.Callers, callerName and its caller
+	if runtime.Callers(3, pcs) == 0 {
+		return "unknown"
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	return frame.Function[strings.LastIndex(frame.Function, ".")+1:]
+}
+
 func (c *cataloger) Close() error {
 	if c != nil {
 		close(c.dedupCh)
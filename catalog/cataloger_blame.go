@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// blameMaxMerges bounds the number of merges Blame follows back to the source of an entry
+const blameMaxMerges = 1000
+
+// Blame returns the commit that introduced the current version of the entry at path in
+// reference.  Merges copy entries into the destination branch, so when the entry was written by
+// a merge commit and the merge source has the same object, the merge source commit is followed
+// back to the commit that wrote the object.  Returns ErrEntryUncommitted if the entry is not
+// committed yet.
+func (c *cataloger) Blame(ctx context.Context, repository, reference string, path string) (*CommitLog, error) {
+	stat, err := c.StatEntry(ctx, repository, reference, path)
+	if err != nil {
+		return nil, err
+	}
+	if stat.CommitReference == "" {
+		return nil, ErrEntryUncommitted
+	}
+	commit, err := c.GetCommit(ctx, repository, stat.CommitReference)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < blameMaxMerges && commit.IsMerge(); i++ {
+		source, err := c.StatEntry(ctx, repository, commit.MergeSource(), path)
+		if errors.Is(err, db.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if source.CommitReference == "" || source.PhysicalAddress != stat.PhysicalAddress {
+			break
+		}
+		commit, err = c.GetCommit(ctx, repository, source.CommitReference)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return commit, nil
+}
@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Blame(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "alice", nil)
+	testutil.MustDo(t, "commit1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	commit2, err := c.Commit(ctx, repository, "branch1", "commit2", "bob", nil)
+	testutil.MustDo(t, "commit2", err)
+	_, err = c.Merge(ctx, repository, "branch1", "master", "carol", "merge branch1", nil)
+	testutil.MustDo(t, "merge branch1 to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "v2")
+	commit3, err := c.Commit(ctx, repository, "master", "commit3", "dave", nil)
+	testutil.MustDo(t, "commit3", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+
+	tests := []struct {
+		name       string
+		reference  string
+		path       string
+		wantCommit string
+		wantErr    error
+	}{
+		{name: "committed", reference: "master", path: "file1", wantCommit: commit3.Reference},
+		{name: "previous version", reference: commit1.Reference, path: "file1", wantCommit: commit1.Reference},
+		{name: "through merge", reference: "master", path: "file2", wantCommit: commit2.Reference},
+		{name: "uncommitted", reference: "master", path: "file3", wantErr: ErrEntryUncommitted},
+		{name: "not found", reference: "branch1", path: "file3", wantErr: db.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Blame(ctx, repository, tt.reference, tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Blame() err = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Reference != tt.wantCommit {
+				t.Errorf("Blame() commit = %s (%s), expected %s", got.Reference, got.Message, tt.wantCommit)
+			}
+		})
+	}
+}
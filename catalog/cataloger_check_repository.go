@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	ConsistencyIssueMissingDefaultBranch  = "missing_default_branch"
+	ConsistencyIssueBranchWithoutCommit   = "branch_without_commit"
+	ConsistencyIssueMissingLineageBranch  = "missing_lineage_branch"
+	ConsistencyIssueMissingPreviousCommit = "missing_previous_commit"
+	ConsistencyIssueMissingSourceCommit   = "missing_source_commit"
+	ConsistencyIssueMissingEntryCommit    = "missing_entry_commit"
+
+	// CheckRepositoryMaxIssues is the maximum number of issues of each type reported by
+	// CheckRepository
+	CheckRepositoryMaxIssues = 1000
+)
+
+// ConsistencyIssue is a dangling reference found in the repository metadata.  Reference is set
+// when the issue is found on a specific commit.
+type ConsistencyIssue struct {
+	Type      string
+	Branch    string
+	Reference string
+}
+
+// consistencyChecks are queries selecting (branch, commit_id) of each issue found in a
+// repository with id $1.  commit_id is 0 for issues of a branch.
+var consistencyChecks = []struct {
+	issueType string
+	query     string
+}{
+	{
+		issueType: ConsistencyIssueMissingDefaultBranch,
+		query: `SELECT '' AS branch, 0 AS commit_id FROM catalog_repositories r
+			WHERE r.id = $1 AND NOT EXISTS (SELECT 1 FROM catalog_branches b WHERE b.id = r.default_branch AND b.repository_id = r.id)`,
+	},
+	{
+		issueType: ConsistencyIssueBranchWithoutCommit,
+		query: `SELECT b.name AS branch, 0 AS commit_id FROM catalog_branches b
+			WHERE b.repository_id = $1 AND NOT EXISTS (SELECT 1 FROM catalog_commits c WHERE c.branch_id = b.id)`,
+	},
+	{
+		issueType: ConsistencyIssueMissingLineageBranch,
+		query: `SELECT b.name AS branch, 0 AS commit_id FROM catalog_branches b
+			WHERE b.repository_id = $1 AND EXISTS (
+				SELECT 1 FROM unnest(b.lineage) AS l(id) WHERE NOT EXISTS (SELECT 1 FROM catalog_branches p WHERE p.id = l.id))`,
+	},
+	{
+		issueType: ConsistencyIssueMissingPreviousCommit,
+		query: `SELECT b.name AS branch, c.commit_id FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE b.repository_id = $1 AND c.previous_commit_id <> 0 AND NOT EXISTS (
+				SELECT 1 FROM catalog_commits p WHERE p.branch_id = c.branch_id AND p.commit_id = c.previous_commit_id)`,
+	},
+	{
+		// a merged child branch may be deleted, so only commits from the parent branch must
+		// have a source commit
+		issueType: ConsistencyIssueMissingSourceCommit,
+		query: `SELECT b.name AS branch, c.commit_id FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE b.repository_id = $1 AND c.merge_type = 'from_parent' AND NOT EXISTS (
+				SELECT 1 FROM catalog_commits s WHERE s.branch_id = c.merge_source_branch AND s.commit_id = c.merge_source_commit)`,
+	},
+	{
+		issueType: ConsistencyIssueMissingEntryCommit,
+		query: `SELECT b.name AS branch, e.min_commit AS commit_id
+			FROM (SELECT DISTINCT branch_id, min_commit FROM catalog_entries
+				WHERE min_commit <> 0 AND branch_id IN (SELECT id FROM catalog_branches WHERE repository_id = $1)) e
+			JOIN catalog_branches b ON b.id = e.branch_id
+			WHERE NOT EXISTS (SELECT 1 FROM catalog_commits c WHERE c.branch_id = e.branch_id AND c.commit_id = e.min_commit)`,
+	},
+}
+
+// CheckRepository verifies that the repository default branch, branch lineage, commit parents
+// and committed entries all reference existing branches and commits.  It returns the issues
+// found, an empty result means the metadata is consistent.
+func (c *cataloger) CheckRepository(ctx context.Context, repository string) ([]*ConsistencyIssue, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := getRepositoryID(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var issues []*ConsistencyIssue
+		for _, check := range consistencyChecks {
+			var rows []struct {
+				Branch   string   `db:"branch"`
+				CommitID CommitID `db:"commit_id"`
+			}
+			if err := tx.Select(&rows, check.query+` LIMIT $2`, repoID, CheckRepositoryMaxIssues); err != nil {
+				return nil, fmt.Errorf("check %s: %w", check.issueType, err)
+			}
+			for _, row := range rows {
+				issue := &ConsistencyIssue{Type: check.issueType, Branch: row.Branch}
+				if row.CommitID != 0 {
+					issue.Reference = MakeReference(row.Branch, row.CommitID)
+				}
+				issues = append(issues, issue)
+			}
+		}
+		return issues, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*ConsistencyIssue), nil
+}
@@ -0,0 +1,77 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
+)
+
+func TestCataloger_CheckRepository(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	if err != nil {
+		t.Fatal("commit:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	issues, err := c.CheckRepository(ctx, repository)
+	if err != nil {
+		t.Fatal("CheckRepository:", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("CheckRepository() found %d issues on a consistent repository, expected none: %+v", len(issues), issues[0])
+	}
+
+	// remove the commit of file1 from master, leaving its entry and the branch1 creation commit
+	// dangling
+	ref, err := ParseRef(commitLog.Reference)
+	if err != nil {
+		t.Fatal("parse reference:", err)
+	}
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`DELETE FROM catalog_commits c USING catalog_branches b, catalog_repositories r
+			WHERE c.branch_id = b.id AND b.repository_id = r.id AND r.name = $1 AND b.name = $2 AND c.commit_id = $3`,
+			repository, ref.Branch, ref.CommitID)
+	})
+	if err != nil {
+		t.Fatal("delete commit:", err)
+	}
+
+	issues, err = c.CheckRepository(ctx, repository)
+	if err != nil {
+		t.Fatal("CheckRepository:", err)
+	}
+	found := make(map[string]*ConsistencyIssue)
+	for _, issue := range issues {
+		found[issue.Type] = issue
+	}
+	for _, expected := range []ConsistencyIssue{
+		{Type: ConsistencyIssueMissingSourceCommit, Branch: "branch1"},
+		{Type: ConsistencyIssueMissingEntryCommit, Branch: "master", Reference: commitLog.Reference},
+	} {
+		issue, ok := found[expected.Type]
+		if !ok {
+			t.Errorf("CheckRepository() missing %s issue, got %+v", expected.Type, issues)
+			continue
+		}
+		if issue.Branch != expected.Branch || (expected.Reference != "" && issue.Reference != expected.Reference) {
+			t.Errorf("CheckRepository() %s issue = %+v, expected %+v", expected.Type, issue, expected)
+		}
+	}
+
+	_, err = c.CheckRepository(ctx, "no-repo")
+	if !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("CheckRepository() on unknown repository error = %v, expected %s", err, db.ErrNotFound)
+	}
+}
@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// CherryPick applies the changes of the commit reference points to in sourceRepository to branch
+// of repository, as uncommitted changes: entries added or changed by the commit are copied, and
+// entries it deleted are deleted.  The repositories may differ, objects are shared as CopyPath
+// does.  Returns the number of changes applied.
+func (c *cataloger) CherryPick(ctx context.Context, sourceRepository, reference, repository, branch string) (int, error) {
+	reference, err := c.ResolveReference(ctx, sourceRepository, reference)
+	if err != nil {
+		return 0, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "sourceRepository", IsValid: ValidateRepositoryName(sourceRepository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return 0, err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return 0, err
+	}
+	if ref.CommitID == UncommittedID {
+		return 0, fmt.Errorf("%w: uncommitted reference", ErrInvalidValue)
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		sourceBranchID, err := c.getBranchIDCache(tx, sourceRepository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		commitID := ref.CommitID
+		if commitID == CommittedID {
+			commitID, err = getLastCommitIDByBranchID(tx, sourceBranchID)
+			if err != nil {
+				return nil, fmt.Errorf("last commit: %w", err)
+			}
+		}
+		var previousCommitID CommitID
+		err = tx.Get(&previousCommitID, `SELECT previous_commit_id FROM catalog_commits WHERE branch_id=$1 AND commit_id=$2`,
+			sourceBranchID, commitID)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrCommitNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		qualify, err := qualifyAddressFunc(tx, sourceRepository, repository)
+		if err != nil {
+			return nil, err
+		}
+
+		// the commit writes entries with its id, and closes the entries it replaced or deleted
+		// with the id of the previous commit.  Deleting an entry of the branch lineage writes a
+		// tombstone.
+		var changed []Entry
+		err = tx.Select(&changed, `SELECT path, physical_address, creation_date, size, checksum, content_type, metadata, is_expired
+			FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2 AND max_commit >= min_commit
+			ORDER BY path`, sourceBranchID, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("changed entries: %w", err)
+		}
+		var deletedPaths []string
+		err = tx.Select(&deletedPaths, `SELECT path FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2 AND max_commit < min_commit
+			UNION
+			SELECT e.path FROM catalog_entries e WHERE e.branch_id=$1 AND e.max_commit=$3 AND $3 > 0
+				AND NOT EXISTS (SELECT 1 FROM catalog_entries n WHERE n.branch_id=$1 AND n.path=e.path AND n.min_commit=$2)
+			ORDER BY path`, sourceBranchID, commitID, previousCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("deleted entries: %w", err)
+		}
+
+		changedPaths := make([]string, len(changed))
+		for i := range changed {
+			if changed[i].Expired {
+				return nil, fmt.Errorf("%s: %w", changed[i].Path, ErrExpired)
+			}
+			changedPaths[i] = changed[i].Path
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, append(changedPaths, deletedPaths...)...); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		for i := range changed {
+			entry := &changed[i]
+			entry.PhysicalAddress = qualify(entry.PhysicalAddress)
+			entry.CreationDate = now
+			if _, err := insertEntry(tx, branchID, entry); err != nil {
+				return nil, err
+			}
+		}
+		if len(deletedPaths) > 0 {
+			lineage, err := getLineage(tx, branchID, UncommittedID)
+			if err != nil {
+				return nil, fmt.Errorf("get lineage: %w", err)
+			}
+			batchSize := c.BatchWrite.EntriesInsertSize
+			for i := 0; i < len(deletedPaths); i += batchSize {
+				j := i + batchSize
+				if j > len(deletedPaths) {
+					j = len(deletedPaths)
+				}
+				if err := deleteEntries(tx, branchID, lineage, deletedPaths[i:j]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvents(tx, repository, EventTypeObjectStaged, branch, changedPaths); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvents(tx, repository, EventTypeObjectDeleted, branch, deletedPaths); err != nil {
+			return nil, err
+		}
+		return len(changedPaths) + len(deletedPaths), nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.(int), nil
+}
@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_CherryPick(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	source := testCatalogerRepo(t, ctx, c, "source", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "changed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "deleted", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "untouched", nil, "")
+	_, err := c.Commit(ctx, source, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+	testCatalogerBranch(t, ctx, c, source, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "branch1", "changed", nil, "seed2")
+	testCatalogerCreateEntry(t, ctx, c, source, "branch1", "added", nil, "")
+	testutil.MustDo(t, "delete lineage entry", c.DeleteEntry(ctx, source, "branch1", "deleted"))
+	c2, err := c.Commit(ctx, source, "branch1", "c2", "tester", nil)
+	testutil.MustDo(t, "commit c2", err)
+
+	repository := testCatalogerRepo(t, ctx, c, "consumer", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "changed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "deleted", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "consumer c1", "tester", nil)
+	testutil.MustDo(t, "commit consumer c1", err)
+
+	changes, err := c.CherryPick(ctx, source, c2.Reference, repository, "master")
+	testutil.MustDo(t, "cherry-pick", err)
+	if changes != 3 {
+		t.Errorf("CherryPick() applied %d changes, expected 3", changes)
+	}
+	differences, _, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	expected := Differences{
+		{Path: "added", Type: DifferenceTypeAdded},
+		{Path: "changed", Type: DifferenceTypeChanged},
+		{Path: "deleted", Type: DifferenceTypeRemoved},
+	}
+	if len(differences) != len(expected) {
+		t.Fatalf("uncommitted changes after CherryPick %+v, expected %+v", differences, expected)
+	}
+	for i := range expected {
+		if differences[i].Path != expected[i].Path || differences[i].Type != expected[i].Type {
+			t.Errorf("uncommitted change %d: %s %s, expected %s %s", i,
+				differences[i].Path, differences[i].Type, expected[i].Path, expected[i].Type)
+		}
+	}
+}
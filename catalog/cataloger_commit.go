@@ -9,7 +9,43 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata) (*CommitLog, error) {
+type CommitOptions struct {
+	// AllowEmpty creates the commit even if there are no changes to commit.  Used to record
+	// message and metadata only.
+	AllowEmpty bool
+	// ExpectedCommit, if set, is the commit reference the branch is expected to point to.  The
+	// commit fails with ErrBranchHeadMoved if another commit was made on the branch since.
+	ExpectedCommit string
+	// ExpectedVersion, if set, is the version the branch is expected to be at.  The commit
+	// fails with ErrBranchConcurrentUpdate if the branch was written since.
+	ExpectedVersion int64
+}
+
+type CommitOption func(*CommitOptions)
+
+func WithAllowEmpty(b bool) CommitOption {
+	return func(o *CommitOptions) {
+		o.AllowEmpty = b
+	}
+}
+
+func WithExpectedCommit(reference string) CommitOption {
+	return func(o *CommitOptions) {
+		o.ExpectedCommit = reference
+	}
+}
+
+func WithExpectedVersion(version int64) CommitOption {
+	return func(o *CommitOptions) {
+		o.ExpectedVersion = version
+	}
+}
+
+func (c *cataloger) Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata, opts ...CommitOption) (*CommitLog, error) {
+	var options CommitOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	if err := Validate(ValidateFields{
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
 		{Name: "message", IsValid: ValidateCommitMessage(message)},
@@ -18,68 +54,106 @@ func (c *cataloger) Commit(ctx context.Context, repository, branch string, messa
 		return nil, err
 	}
 
+	preCommit, err := c.runPreCommit(ctx, &PendingCommit{
+		Repository: repository,
+		Branch:     branch,
+		Committer:  committer,
+		Message:    message,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
 		if err != nil {
 			return nil, fmt.Errorf("get branch id: %w", err)
 		}
-
-		lastCommitID, err := getLastCommitIDByBranchID(tx, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("last commit id: %w", err)
-		}
-
-		committedAffected, err := commitUpdateCommittedEntriesWithMaxCommit(tx, branchID, lastCommitID)
-		if err != nil {
-			return nil, fmt.Errorf("update commit entries: %w", err)
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
 		}
-
-		_, err = commitDeleteUncommittedTombstones(tx, branchID, lastCommitID)
-		if err != nil {
-			return nil, fmt.Errorf("delete uncommitted tombstones: %w", err)
+		if err := preCommit.verify(tx, branchID); err != nil {
+			return nil, err
 		}
-
-		// uncommitted to committed entries
-		commitID, err := getNextCommitID(tx)
+		commitLog, err := commitBranch(tx, branchID, branch, message, committer, metadata, options)
 		if err != nil {
-			return nil, fmt.Errorf("next commit id: %w", err)
+			return nil, err
 		}
+		return commitLog, insertRepositoryEvent(tx, repository, EventTypeCommitCreated, branch, "", commitLog.Reference)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	commitLog := res.(*CommitLog)
+	c.hooks.runPostCommit(ctx, repository, branch, commitLog)
+	return commitLog, nil
+}
 
-		// commit entries (include the tombstones)
-		affectedNew, err := commitEntries(tx, branchID, commitID)
+func commitBranch(tx db.Tx, branchID int64, branch string, message string, committer string, metadata Metadata, options CommitOptions) (*CommitLog, error) {
+	lastCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("last commit id: %w", err)
+	}
+	if options.ExpectedCommit != "" {
+		expectedRef, err := ParseRef(options.ExpectedCommit)
 		if err != nil {
-			return nil, fmt.Errorf("commit entries: %w", err)
+			return nil, fmt.Errorf("expected commit: %w", err)
 		}
-		if (affectedNew + committedAffected) == 0 {
-			return nil, ErrNothingToCommit
+		if expectedRef.Branch != branch || expectedRef.CommitID != lastCommitID {
+			return nil, fmt.Errorf("%w: expected %s, branch is at %s", ErrBranchHeadMoved,
+				options.ExpectedCommit, MakeReference(branch, lastCommitID))
 		}
+	}
+	if _, err := updateBranchVersion(tx, branchID, options.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
-		// insert commit record
-		var creationDate time.Time
-		if err = tx.Get(&creationDate,
-			`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
-			VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)
-			RETURNING creation_date`,
-			branchID, commitID, committer, message, metadata, RelationTypeNone, lastCommitID,
-		); err != nil {
-			return nil, err
-		}
-		reference := MakeReference(branch, commitID)
-		parentReference := MakeReference(branch, lastCommitID)
-		commitLog := &CommitLog{
-			Committer:    committer,
-			Message:      message,
-			CreationDate: creationDate,
-			Metadata:     metadata,
-			Reference:    reference,
-			Parents:      []string{parentReference},
-		}
-		return commitLog, nil
-	}, c.txOpts(ctx)...)
+	committedAffected, err := commitUpdateCommittedEntriesWithMaxCommit(tx, branchID, lastCommitID)
 	if err != nil {
+		return nil, fmt.Errorf("update commit entries: %w", err)
+	}
+
+	_, err = commitDeleteUncommittedTombstones(tx, branchID, lastCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("delete uncommitted tombstones: %w", err)
+	}
+
+	// uncommitted to committed entries
+	commitID, err := getNextCommitID(tx)
+	if err != nil {
+		return nil, fmt.Errorf("next commit id: %w", err)
+	}
+
+	// commit entries (include the tombstones)
+	affectedNew, err := commitEntries(tx, branchID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("commit entries: %w", err)
+	}
+	if !options.AllowEmpty && (affectedNew+committedAffected) == 0 {
+		return nil, ErrNothingToCommit
+	}
+
+	// insert commit record
+	var creationDate time.Time
+	if err = tx.Get(&creationDate,
+		`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+		VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)
+		RETURNING creation_date`,
+		branchID, commitID, committer, message, metadata, RelationTypeNone, lastCommitID,
+	); err != nil {
 		return nil, err
 	}
-	return res.(*CommitLog), nil
+	reference := MakeReference(branch, commitID)
+	parentReference := MakeReference(branch, lastCommitID)
+	commitLog := &CommitLog{
+		Committer:    committer,
+		Message:      message,
+		CreationDate: creationDate,
+		Metadata:     metadata,
+		Reference:    reference,
+		Parents:      []string{parentReference},
+	}
+	return commitLog, nil
 }
 
 func commitUpdateCommittedEntriesWithMaxCommit(tx sqlx.Execer, branchID int64, commitID CommitID) (int64, error) {
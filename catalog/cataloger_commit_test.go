@@ -130,6 +130,60 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 		}
 	})
 
+	t.Run("allow empty", func(t *testing.T) {
+		repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+		meta := Metadata{"validated": "true"}
+		commitLog, err := c.Commit(ctx, repository, "master", "validation passed", "tester1", meta, WithAllowEmpty(true))
+		testutil.MustDo(t, "commit with allow empty", err)
+		got, err := c.GetCommit(ctx, repository, commitLog.Reference)
+		testutil.MustDo(t, "get empty commit", err)
+		if got.Message != "validation passed" || got.Metadata["validated"] != "true" {
+			t.Fatalf("Empty commit got message '%s', metadata %s - expected the ones we committed", got.Message, got.Metadata)
+		}
+	})
+
+	t.Run("expected commit", func(t *testing.T) {
+		repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+		head, err := c.GetCommit(ctx, repository, "master:HEAD")
+		testutil.MustDo(t, "get branch head", err)
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+		commitLog, err := c.Commit(ctx, repository, "master", "first", "tester1", nil, WithExpectedCommit(head.Reference))
+		testutil.MustDo(t, "commit with expected head", err)
+
+		// a writer that read the previous head fails to commit
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file2", nil, "")
+		_, err = c.Commit(ctx, repository, "master", "second", "tester2", nil, WithExpectedCommit(head.Reference))
+		if !errors.Is(err, ErrBranchHeadMoved) {
+			t.Fatalf("Commit() with old expected commit error = %v, expected %s", err, ErrBranchHeadMoved)
+		}
+		_, err = c.Commit(ctx, repository, "master", "second", "tester2", nil, WithExpectedCommit(commitLog.Reference))
+		testutil.MustDo(t, "commit with current expected head", err)
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+		branch, err := c.GetBranch(ctx, repository, "master")
+		testutil.MustDo(t, "get branch", err)
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+		_, err = c.Commit(ctx, repository, "master", "first", "tester1", nil, WithExpectedVersion(branch.Version))
+		testutil.MustDo(t, "commit with expected version", err)
+
+		// a writer that read the previous version fails to commit, also after a reset
+		testutil.MustDo(t, "reset branch", c.ResetBranch(ctx, repository, "master"))
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file2", nil, "")
+		_, err = c.Commit(ctx, repository, "master", "second", "tester2", nil, WithExpectedVersion(branch.Version))
+		if !errors.Is(err, ErrBranchConcurrentUpdate) {
+			t.Fatalf("Commit() with old expected version error = %v, expected %s", err, ErrBranchConcurrentUpdate)
+		}
+		current, err := c.GetBranch(ctx, repository, "master")
+		testutil.MustDo(t, "get branch", err)
+		if current.Version != branch.Version+2 {
+			t.Fatalf("Branch version = %d, expected %d", current.Version, branch.Version+2)
+		}
+		_, err = c.Commit(ctx, repository, "master", "second", "tester2", nil, WithExpectedVersion(current.Version))
+		testutil.MustDo(t, "commit with current expected version", err)
+	})
+
 	t.Run("same file more than once", func(t *testing.T) {
 		repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 		var previousCommitID CommitID
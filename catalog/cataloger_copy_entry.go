@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// CopyEntry creates an entry at destinationPath on destinationBranch pointing to the object of
+// the entry at sourcePath in sourceReference.  Only the entry is copied, the object data is
+// shared between the source and the destination.  Returns the new entry.
+func (c *cataloger) CopyEntry(ctx context.Context, repository, sourceReference, sourcePath, destinationBranch, destinationPath string) (*Entry, error) {
+	sourceReference, err := c.ResolveReference(ctx, repository, sourceReference)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "sourceReference", IsValid: ValidateReference(sourceReference)},
+		{Name: "sourcePath", IsValid: ValidatePath(sourcePath)},
+		{Name: "destinationBranch", IsValid: ValidateBranchName(destinationBranch)},
+		{Name: "destinationPath", IsValid: ValidatePath(destinationPath)},
+	}); err != nil {
+		return nil, err
+	}
+	ref, err := ParseRef(sourceReference)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		sourceBranchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, destinationBranch, destinationPath); err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, sourceBranchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		sql, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired").
+			FromSelect(sqEntriesLineage(sourceBranchID, ref.CommitID, lineage), "entries").
+			Where(sq.Eq{"path": sourcePath, "is_deleted": false}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entry Entry
+		if err := tx.Get(&entry, sql, args...); errors.Is(err, db.ErrNotFound) {
+			return nil, ErrEntryNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		if entry.Expired {
+			return nil, ErrExpired
+		}
+
+		destinationBranchID, err := c.getBranchIDCache(tx, repository, destinationBranch)
+		if err != nil {
+			return nil, err
+		}
+		entry.Path = destinationPath
+		entry.CreationDate = time.Now()
+		if _, err := insertEntry(tx, destinationBranchID, &entry); err != nil {
+			return nil, err
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvent(tx, repository, EventTypeObjectStaged, destinationBranch, destinationPath, ""); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Entry), nil
+}
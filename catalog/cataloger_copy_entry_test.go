@@ -0,0 +1,82 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+func TestCataloger_CopyEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	if err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "data/file1",
+		Checksum:        "ff",
+		PhysicalAddress: "/addr1",
+		Size:            1,
+		Metadata:        Metadata{"k": "v"},
+	}, CreateEntryParams{}); err != nil {
+		t.Fatal("create entry for copy entry test:", err)
+	}
+	commitLog, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	if err != nil {
+		t.Fatal("commit for copy entry test:", err)
+	}
+	if err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "data/file1",
+		Checksum:        "ee",
+		PhysicalAddress: "/addr2",
+		Size:            2,
+	}, CreateEntryParams{}); err != nil {
+		t.Fatal("create entry for copy entry test:", err)
+	}
+
+	tests := []struct {
+		name              string
+		sourceReference   string
+		sourcePath        string
+		destinationBranch string
+		destinationPath   string
+		wantAddress       string
+		wantErr           error
+	}{
+		{name: "same branch", sourceReference: "master", sourcePath: "data/file1", destinationBranch: "master", destinationPath: "data/copy1", wantAddress: "/addr2"},
+		{name: "committed", sourceReference: "master:HEAD", sourcePath: "data/file1", destinationBranch: "branch1", destinationPath: "data/copy1", wantAddress: "/addr1"},
+		{name: "commit", sourceReference: commitLog.Reference, sourcePath: "data/file1", destinationBranch: "branch1", destinationPath: "data/copy2", wantAddress: "/addr1"},
+		{name: "other branch", sourceReference: "master", sourcePath: "data/file1", destinationBranch: "branch1", destinationPath: "data/file1", wantAddress: "/addr2"},
+		{name: "not found", sourceReference: "master", sourcePath: "data/file2", destinationBranch: "master", destinationPath: "data/copy2", wantErr: ErrEntryNotFound},
+		{name: "no branch", sourceReference: "master", sourcePath: "data/file1", destinationBranch: "branch2", destinationPath: "data/copy2", wantErr: db.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.CopyEntry(ctx, repository, tt.sourceReference, tt.sourcePath, tt.destinationBranch, tt.destinationPath)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CopyEntry() error = %s, expected %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CopyEntry() error = %s, expected no error", err)
+			}
+			if got.Path != tt.destinationPath || got.PhysicalAddress != tt.wantAddress {
+				t.Errorf("CopyEntry() entry = %s -> %s, expected %s -> %s", got.Path, got.PhysicalAddress, tt.destinationPath, tt.wantAddress)
+			}
+			ent, err := c.GetEntry(ctx, repository, tt.destinationBranch, tt.destinationPath, GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry() destination %s error = %s", tt.destinationPath, err)
+			}
+			if ent.PhysicalAddress != tt.wantAddress {
+				t.Errorf("GetEntry() destination address = %s, expected %s", ent.PhysicalAddress, tt.wantAddress)
+			}
+			if _, err := c.GetEntry(ctx, repository, tt.sourceReference, tt.sourcePath, GetEntryParams{}); err != nil {
+				t.Errorf("GetEntry() source %s error = %s, expected source to remain", tt.sourcePath, err)
+			}
+		})
+	}
+}
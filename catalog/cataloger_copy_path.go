@@ -0,0 +1,153 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/db"
+)
+
+// CopyPath copies the entries at sourcePath, or under it when sourcePath is a directory, in
+// sourceReference of sourceRepository to destinationPath on branch of repository, as uncommitted
+// changes.  The repositories may differ: objects are not copied, entries copied from another
+// storage namespace point to the objects in the source namespace by their full address.
+// Returns the number of entries copied.  The copied paths are authorized by the CopyAuthorizer of
+// ctx, if it has one.
+func (c *cataloger) CopyPath(ctx context.Context, sourceRepository, sourceReference, sourcePath, repository, branch, destinationPath string) (int, error) {
+	sourceReference, err := c.ResolveReference(ctx, sourceRepository, sourceReference)
+	if err != nil {
+		return 0, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "sourceRepository", IsValid: ValidateRepositoryName(sourceRepository)},
+		{Name: "sourceReference", IsValid: ValidateReference(sourceReference)},
+		{Name: "sourcePath", IsValid: ValidatePath(sourcePath)},
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "destinationPath", IsValid: ValidatePath(destinationPath)},
+	}); err != nil {
+		return 0, err
+	}
+	ref, err := ParseRef(sourceReference)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		sourceBranchID, err := c.getBranchIDCache(tx, sourceRepository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		qualify, err := qualifyAddressFunc(tx, sourceRepository, repository)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := selectPathEntries(tx, sourceBranchID, ref.CommitID, sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("source entries: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil, ErrEntryNotFound
+		}
+		sourcePaths := make([]string, len(entries))
+		paths := make([]string, len(entries))
+		for i := range entries {
+			if entries[i].Expired {
+				return nil, fmt.Errorf("%s: %w", entries[i].Path, ErrExpired)
+			}
+			sourcePaths[i] = entries[i].Path
+			paths[i] = copyDestinationPath(sourcePath, destinationPath, entries[i].Path)
+		}
+		if authorize, ok := ctx.Value(copyAuthorizerContextKey{}).(CopyAuthorizer); ok {
+			if err := authorize(sourcePaths, paths); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, paths...); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		for i := range entries {
+			entry := &entries[i]
+			entry.Path = paths[i]
+			entry.PhysicalAddress = qualify(entry.PhysicalAddress)
+			entry.CreationDate = now
+			if _, err := insertEntry(tx, branchID, entry); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvents(tx, repository, EventTypeObjectStaged, branch, paths); err != nil {
+			return nil, err
+		}
+		return len(entries), nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.(int), nil
+}
+
+type copyAuthorizerContextKey struct{}
+
+// CopyAuthorizer authorizes reading the entries at sourcePaths and writing them to
+// destinationPaths
+type CopyAuthorizer func(sourcePaths, destinationPaths []string) error
+
+// WithCopyAuthorizer returns a context that makes CopyPath pass the paths it copies to authorize
+// before copying them
+func WithCopyAuthorizer(ctx context.Context, authorize CopyAuthorizer) context.Context {
+	return context.WithValue(ctx, copyAuthorizerContextKey{}, authorize)
+}
+
+// copyDestinationPath returns the path to copy path to: destinationPath for sourcePath itself,
+// and the same path under destinationPath for the entries under sourcePath, regardless of
+// trailing delimiters
+func copyDestinationPath(sourcePath, destinationPath, path string) string {
+	if path == sourcePath {
+		return destinationPath
+	}
+	sourceDir := strings.TrimSuffix(sourcePath, DefaultPathDelimiter) + DefaultPathDelimiter
+	destinationDir := strings.TrimSuffix(destinationPath, DefaultPathDelimiter) + DefaultPathDelimiter
+	return destinationDir + strings.TrimPrefix(path, sourceDir)
+}
+
+// qualifyAddressFunc returns a function that maps a physical address of an entry of
+// sourceRepository to an address of the same object from repository.  Addresses relative to the
+// storage namespace of the source are made full addresses when the namespaces differ.
+func qualifyAddressFunc(tx db.Tx, sourceRepository, repository string) (func(string) string, error) {
+	source, err := getRepository(tx, sourceRepository)
+	if err != nil {
+		return nil, fmt.Errorf("source repository: %w", err)
+	}
+	destination, err := getRepository(tx, repository)
+	if err != nil {
+		return nil, err
+	}
+	if source.StorageNamespace == destination.StorageNamespace {
+		return func(address string) string { return address }, nil
+	}
+	return func(address string) string {
+		return qualifyPhysicalAddress(source.StorageNamespace, address)
+	}, nil
+}
+
+// qualifyPhysicalAddress returns the full address of an object at address in storageNamespace
+func qualifyPhysicalAddress(storageNamespace, address string) string {
+	if !block.IsResolvableKey(address) {
+		return address
+	}
+	return strings.TrimSuffix(storageNamespace, "/") + "/" + strings.TrimPrefix(address, "/")
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_CopyPath(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	source := testCatalogerRepo(t, ctx, c, "source", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "data/public/file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "data/public/sub/file2", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "data/publicity", nil, "")
+	commitLog, err := c.Commit(ctx, source, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+
+	repository := "consumer-" + testCatalogerUniqueID()
+	testutil.MustDo(t, "create repository", c.CreateRepository(ctx, repository, "s3://consumer-bucket", "master"))
+
+	copied, err := c.CopyPath(ctx, source, commitLog.Reference, "data/public", repository, "master", "public")
+	testutil.MustDo(t, "copy path", err)
+	if copied != 2 {
+		t.Errorf("CopyPath() copied %d entries, expected 2", copied)
+	}
+	entries, _, err := c.ListEntries(ctx, repository, "master", "", "", "", -1)
+	testutil.MustDo(t, "list entries", err)
+	expected := []struct{ path, address string }{
+		{path: "public/file1", address: "s3://" + source + "/" + testCreateEntryCalcChecksum("data/public/file1", "")},
+		{path: "public/sub/file2", address: "s3://" + source + "/" + testCreateEntryCalcChecksum("data/public/sub/file2", "")},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("ListEntries() got %d entries, expected %d", len(entries), len(expected))
+	}
+	for i, e := range expected {
+		if entries[i].Path != e.path || entries[i].PhysicalAddress != e.address {
+			t.Errorf("entry %d: %s at %s, expected %s at %s", i, entries[i].Path, entries[i].PhysicalAddress, e.path, e.address)
+		}
+	}
+
+	// copy within the repository keeps the relative address
+	_, err = c.CopyPath(ctx, source, "master", "data/publicity", source, "master", "copy")
+	testutil.MustDo(t, "copy object", err)
+	entry, err := c.GetEntry(ctx, source, "master", "copy", GetEntryParams{})
+	testutil.MustDo(t, "get copied entry", err)
+	if entry.PhysicalAddress != testCreateEntryCalcChecksum("data/publicity", "") {
+		t.Errorf("copied entry address %s, expected the source address", entry.PhysicalAddress)
+	}
+
+	// a source directory with a trailing delimiter copies under the destination directory
+	_, err = c.CopyPath(ctx, source, commitLog.Reference, "data/public/", repository, "master", "pub")
+	testutil.MustDo(t, "copy directory", err)
+	if _, err := c.GetEntry(ctx, repository, "master", "pub/sub/file2", GetEntryParams{}); err != nil {
+		t.Errorf("get entry copied from directory: %s", err)
+	}
+
+	// the copied paths are authorized
+	errDenied := errors.New("denied")
+	var authorized []string
+	authCtx := WithCopyAuthorizer(ctx, func(sourcePaths, destinationPaths []string) error {
+		authorized = append(sourcePaths, destinationPaths...)
+		return errDenied
+	})
+	_, err = c.CopyPath(authCtx, source, commitLog.Reference, "data/public", repository, "master", "denied")
+	if !errors.Is(err, errDenied) {
+		t.Errorf("CopyPath() with denying authorizer err=%v, expected %s", err, errDenied)
+	}
+	expectedAuthorized := []string{"data/public/file1", "data/public/sub/file2", "denied/file1", "denied/sub/file2"}
+	if diff := deep.Equal(authorized, expectedAuthorized); diff != nil {
+		t.Errorf("authorized paths diff %s", diff)
+	}
+
+	_, err = c.CopyPath(ctx, source, "master", "missing", repository, "master", "missing")
+	if !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("CopyPath() of missing path err=%v, expected %s", err, ErrEntryNotFound)
+	}
+}
+
+func TestCopyDestinationPath(t *testing.T) {
+	cases := []struct {
+		sourcePath      string
+		destinationPath string
+		path            string
+		expected        string
+	}{
+		{sourcePath: "a", destinationPath: "b", path: "a", expected: "b"},
+		{sourcePath: "a", destinationPath: "b", path: "a/x", expected: "b/x"},
+		{sourcePath: "a/", destinationPath: "b", path: "a/x", expected: "b/x"},
+		{sourcePath: "a", destinationPath: "b/", path: "a/x", expected: "b/x"},
+		{sourcePath: "a/", destinationPath: "b/", path: "a/sub/x", expected: "b/sub/x"},
+	}
+	for _, tt := range cases {
+		if got := copyDestinationPath(tt.sourcePath, tt.destinationPath, tt.path); got != tt.expected {
+			t.Errorf("copyDestinationPath(%s, %s, %s) = %s, expected %s", tt.sourcePath, tt.destinationPath, tt.path, got, tt.expected)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,14 +13,26 @@ const (
 	createBranchCommitMessageFormat = "Branch '%s' created, source branch '%s'"
 )
 
-func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*CommitLog, error) {
+// CreateBranch creates branch from sourceRef.  The source reference can be a branch name, in
+// which case the branch is created from its last commit, or a commit reference.  Creating a
+// branch copies no entries - the new branch reads the source entries through its lineage.
+func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string, sourceRef string) (*CommitLog, error) {
+	sourceRef, err := c.ResolveReference(ctx, repository, sourceRef)
+	if err != nil {
+		return nil, err
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
-		{Name: "sourceBranch", IsValid: ValidateBranchName(sourceBranch)},
+		{Name: "sourceRef", IsValid: ValidateReference(sourceRef)},
 	}); err != nil {
 		return nil, err
 	}
+	ref, err := ParseRef(sourceRef)
+	if err != nil {
+		return nil, err
+	}
+	sourceBranch := ref.Branch
 
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		_, err := tx.Exec("LOCK TABLE catalog_branches IN SHARE UPDATE EXCLUSIVE MODE")
@@ -31,6 +44,9 @@ func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string,
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 
 		// get source branch id and
 		var sourceBranchID int
@@ -39,6 +55,21 @@ func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string,
 			return nil, fmt.Errorf("source branch id: %w", err)
 		}
 
+		// source commit - specific commit or the last commit on the source branch
+		var sourceCommitID CommitID
+		if ref.CommitID > 0 {
+			err = tx.Get(&sourceCommitID, `SELECT commit_id FROM catalog_commits WHERE branch_id=$1 AND commit_id=$2`,
+				sourceBranchID, ref.CommitID)
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, ErrCommitNotFound
+			}
+		} else {
+			err = tx.Get(&sourceCommitID, `SELECT MAX(commit_id) FROM catalog_commits WHERE branch_id=$1`, sourceBranchID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("source commit id: %w", err)
+		}
+
 		// next id for branch
 		var branchID int64
 		if err := tx.Get(&branchID, `SELECT nextval('catalog_branches_id_seq')`); err != nil {
@@ -61,15 +92,27 @@ func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string,
 		err = tx.Get(&insertReturns, `INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
 			creation_date,merge_source_branch,merge_type,lineage_commits,merge_source_commit)
 			VALUES ($1,nextval('catalog_commit_id_seq'),0,$2,$3,transaction_timestamp(),$4,'from_parent',
-				(select (select max(commit_id) from catalog_commits where branch_id=$4) ||
+				(select $5::bigint ||
 					(select distinct on (branch_id) lineage_commits from catalog_commits
-						where branch_id=$4 and merge_type='from_parent' order by branch_id,commit_id desc))
-						,(select max(commit_id) from catalog_commits where branch_id=$4 ))
+						where branch_id=$4 and merge_type='from_parent' and commit_id <= $5 order by branch_id,commit_id desc))
+						,$5)
 			RETURNING commit_id,merge_source_commit,transaction_timestamp()`,
-			branchID, CatalogerCommitter, commitMsg, sourceBranchID)
+			branchID, CatalogerCommitter, commitMsg, sourceBranchID, sourceCommitID)
 		if err != nil {
 			return nil, fmt.Errorf("insert commit: %w", err)
 		}
+		if err := c.checkBranchQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		// the entries of a deleted branch are counted again once a branch is created from it
+		if isDeletedBranchName(sourceBranch) {
+			if err := recountRepositoryTrackedUsage(tx, repoID); err != nil {
+				return nil, fmt.Errorf("usage: %w", err)
+			}
+			if err := c.checkObjectsQuota(tx, repository); err != nil {
+				return nil, err
+			}
+		}
 		reference := MakeReference(branch, insertReturns.CommitID)
 		parentReference := MakeReference(sourceBranch, insertReturns.MergeSourceCommit)
 
@@ -80,6 +123,9 @@ func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string,
 			Reference:    reference,
 			Parents:      []string{parentReference},
 		}
+		if err := insertRepositoryEvent(tx, repository, EventTypeBranchCreated, branch, "", reference); err != nil {
+			return nil, err
+		}
 		return commitLog, nil
 	}, c.txOpts(ctx)...)
 	if err != nil {
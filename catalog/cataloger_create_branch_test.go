@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -127,3 +128,30 @@ func TestCataloger_CreateBranch_Parallel(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCataloger_CreateBranch_OfCommit(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repo := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repo, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repo, "master", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit file1", err)
+	testCatalogerCreateEntry(t, ctx, c, repo, "master", "file2", nil, "")
+	_, err = c.Commit(ctx, repo, "master", "commit file2", "tester", nil)
+	testutil.MustDo(t, "commit file2", err)
+
+	commitLog, err := c.CreateBranch(ctx, repo, "branch1", commit1.Reference)
+	testutil.MustDo(t, "create branch from commit", err)
+	if len(commitLog.Parents) != 1 || commitLog.Parents[0] != commit1.Reference {
+		t.Errorf("CreateBranch parents = %v, expected %s", commitLog.Parents, commit1.Reference)
+	}
+	testCatalogerGetEntry(t, ctx, c, repo, "branch1", "file1", true)
+	testCatalogerGetEntry(t, ctx, c, repo, "branch1", "file2", false)
+
+	// unknown commit
+	_, err = c.CreateBranch(ctx, repo, "branch2", MakeReference("master", 1000))
+	if !errors.Is(err, ErrCommitNotFound) {
+		t.Errorf("CreateBranch from unknown commit err = %v, expected %s", err, ErrCommitNotFound)
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // CreateEntries add multiple entries into the catalog, this process doesn't pass through de-dup mechanism.
-//   It is mainly used by import mass entries into the catalog.
+//   It is mainly used by import mass entries into the catalog.  Physical addresses must be inside the
+//   storage namespace of the repository, unless ctx is set by WithExternalAddresses.
 func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string, entries []Entry) error {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
@@ -49,11 +50,24 @@ func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		paths := make([]string, len(entriesToInsert))
+		for i, entry := range entriesToInsert {
+			paths[i] = entry.Path
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, paths...); err != nil {
+			return nil, err
+		}
+		if err := c.checkAddressesInNamespace(ctx, tx, repository, entriesToInsert...); err != nil {
+			return nil, err
+		}
 		// single insert per batch
 		entriesInsertSize := c.BatchWrite.EntriesInsertSize
 		for i := 0; i < len(entriesToInsert); i += entriesInsertSize {
 			sqInsert := psql.Insert("catalog_entries").
-				Columns("branch_id", "path", "physical_address", "checksum", "size", "metadata", "creation_date", "is_expired")
+				Columns("branch_id", "path", "physical_address", "checksum", "size", "metadata", "creation_date", "is_expired", "content_type")
 			j := i + entriesInsertSize
 			if j > len(entriesToInsert) {
 				j = len(entriesToInsert)
@@ -65,10 +79,10 @@ func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string
 					dbTime.Valid = true
 				}
 				sqInsert = sqInsert.Values(branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata,
-					sq.Expr("COALESCE(?,NOW())", dbTime), entry.Expired)
+					sq.Expr("COALESCE(?,NOW())", dbTime), entry.Expired, entry.ContentType)
 			}
 			query, args, err := sqInsert.Suffix(`ON CONFLICT (branch_id,path,min_commit)
-DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, max_commit=catalog_max_commit_id()`).
+DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, content_type=EXCLUDED.content_type, max_commit=catalog_max_commit_id()`).
 				ToSql()
 			if err != nil {
 				return nil, fmt.Errorf("build query: %w", err)
@@ -78,7 +92,10 @@ DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.chec
 				return nil, err
 			}
 		}
-		return nil, nil
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		return nil, insertRepositoryEvents(tx, repository, EventTypeObjectStaged, branch, paths)
 	}, c.txOpts(ctx)...)
 	return err
 }
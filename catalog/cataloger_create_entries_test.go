@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/treeverse/lakefs/testutil"
@@ -144,3 +145,28 @@ func TestCataloger_CreateEntries(t *testing.T) {
 		})
 	}
 }
+
+func TestCataloger_CreateEntries_ExternalAddress(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repo := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	entries := []Entry{
+		{Path: "inside", Checksum: "aa", PhysicalAddress: "s3://" + repo + "/inside", Size: 1},
+		{Path: "outside", Checksum: "bb", PhysicalAddress: "s3://other-bucket/outside", Size: 2},
+	}
+	err := c.CreateEntries(ctx, repo, "master", entries)
+	if !errors.Is(err, ErrAddressOutsideNamespace) {
+		t.Fatalf("CreateEntries() of an external address err=%v, expected %s", err, ErrAddressOutsideNamespace)
+	}
+	testCatalogerGetEntry(t, ctx, c, repo, "master", "inside", false)
+
+	testutil.MustDo(t, "create entries with external addresses",
+		c.CreateEntries(WithExternalAddresses(ctx), repo, "master", entries))
+	ent, err := c.GetEntry(ctx, repo, "master", "outside", GetEntryParams{})
+	testutil.MustDo(t, "get external entry", err)
+	if ent.PhysicalAddress != "s3://other-bucket/outside" {
+		t.Errorf("external entry address = %s, expected s3://other-bucket/outside", ent.PhysicalAddress)
+	}
+}
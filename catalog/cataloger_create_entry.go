@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/treeverse/lakefs/db"
 )
 
@@ -22,7 +23,29 @@ func (c *cataloger) CreateEntry(ctx context.Context, repository, branch string,
 		if err != nil {
 			return nil, err
 		}
-		return insertEntry(tx, branchID, &entry)
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, entry.Path); err != nil {
+			return nil, err
+		}
+		if params.IfAbsent {
+			exists, err := entryExists(tx, branchID, entry.Path)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, ErrEntryAlreadyExists
+			}
+		}
+		id, err := insertEntry(tx, branchID, &entry)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		return id, insertRepositoryEvent(tx, repository, EventTypeObjectStaged, branch, entry.Path, "")
 	}, c.txOpts(ctx)...)
 	if err != nil {
 		return err
@@ -41,6 +64,29 @@ func (c *cataloger) CreateEntry(ctx context.Context, repository, branch string,
 	return nil
 }
 
+// entryExists checks if branch has an entry at path, including entries of the branch lineage
+func entryExists(tx db.Tx, branchID int64, path string) (bool, error) {
+	lineage, err := getLineage(tx, branchID, UncommittedID)
+	if err != nil {
+		return false, fmt.Errorf("get lineage: %w", err)
+	}
+	query, args, err := psql.
+		Select("1").
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		Where(sq.Eq{"path": path, "is_deleted": false}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build sql: %w", err)
+	}
+	var exists bool
+	if err := tx.Get(&exists, query, args...); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
 func insertEntry(tx db.Tx, branchID int64, entry *Entry) (string, error) {
 	var (
 		ctid   string
@@ -52,12 +98,12 @@ func insertEntry(tx db.Tx, branchID int64, entry *Entry) (string, error) {
 		dbTime.Time = entry.CreationDate
 		dbTime.Valid = true
 	}
-	err := tx.Get(&ctid, `INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,creation_date,is_expired)
-                        VALUES ($1,$2,$3,$4,$5,$6, COALESCE($7, NOW()), $8)
+	err := tx.Get(&ctid, `INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,creation_date,is_expired,content_type)
+                        VALUES ($1,$2,$3,$4,$5,$6, COALESCE($7, NOW()), $8, $9)
 			ON CONFLICT (branch_id,path,min_commit)
-			DO UPDATE SET physical_address=$3, checksum=$4, size=$5, metadata=$6, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, max_commit=catalog_max_commit_id()
+			DO UPDATE SET physical_address=$3, checksum=$4, size=$5, metadata=$6, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, content_type=$9, max_commit=catalog_max_commit_id()
 			RETURNING ctid`,
-		branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata, dbTime, entry.Expired)
+		branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata, dbTime, entry.Expired, entry.ContentType)
 	if err != nil {
 		return "", fmt.Errorf("insert entry: %w", err)
 	}
@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -235,6 +236,56 @@ func TestCataloger_CreateEntry_Dedup(t *testing.T) {
 	}
 }
 
+func TestCataloger_CreateEntry_IfAbsent(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/committed", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "commit", "tester", nil); err != nil {
+		t.Fatal("commit for create entry test:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/uncommitted", nil, "")
+	if err := c.DeleteEntry(ctx, repository, "branch1", "/committed"); err != nil {
+		t.Fatal("delete entry for create entry test:", err)
+	}
+
+	tests := []struct {
+		name    string
+		branch  string
+		path    string
+		wantErr error
+	}{
+		{name: "new", branch: "branch1", path: "/new"},
+		{name: "uncommitted", branch: "branch1", path: "/uncommitted", wantErr: ErrEntryAlreadyExists},
+		{name: "committed", branch: "master", path: "/committed", wantErr: ErrEntryAlreadyExists},
+		{name: "deleted", branch: "branch1", path: "/committed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CreateEntry(ctx, repository, tt.branch, Entry{
+				Path:            tt.path,
+				PhysicalAddress: "/addr" + tt.path,
+				Checksum:        "ff",
+				Size:            1,
+			}, CreateEntryParams{IfAbsent: true})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateEntry() error = %v, expected %v", err, tt.wantErr)
+			}
+			ent, err := c.GetEntry(ctx, repository, tt.branch, tt.path, GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry() error = %s", err)
+			}
+			if tt.wantErr == nil && ent.PhysicalAddress != "/addr"+tt.path {
+				t.Errorf("GetEntry() address = %s, expected the created entry", ent.PhysicalAddress)
+			}
+			if tt.wantErr != nil && ent.PhysicalAddress == "/addr"+tt.path {
+				t.Errorf("GetEntry() address = %s, expected the existing entry", ent.PhysicalAddress)
+			}
+		})
+	}
+}
+
 func randomFilepath(basename string) string {
 	var sb strings.Builder
 	depth := rand.Intn(10)
@@ -256,3 +307,37 @@ func BenchmarkCataloger_CreateEntry(b *testing.B) {
 		testCatalogerCreateEntry(b, ctx, c, repo, "master", entPath, nil, "")
 	}
 }
+
+func TestCataloger_CreateEntry_ContentType(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	const contentType = "application/json"
+	err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "/file.json",
+		PhysicalAddress: "/addr/file.json",
+		Checksum:        "ff",
+		ContentType:     contentType,
+		Size:            2,
+	}, CreateEntryParams{})
+	if err != nil {
+		t.Fatal("CreateEntry()", err)
+	}
+	if _, err := c.Commit(ctx, repository, "master", "commit", "tester", nil); err != nil {
+		t.Fatal("commit for create entry test:", err)
+	}
+	ent, err := c.GetEntry(ctx, repository, "master", "/file.json", GetEntryParams{})
+	if err != nil {
+		t.Fatalf("GetEntry() error = %s", err)
+	}
+	if ent.ContentType != contentType {
+		t.Errorf("GetEntry() content type = %s, expected %s", ent.ContentType, contentType)
+	}
+	entries, _, err := c.ListEntries(ctx, repository, "master", "", "", "", -1)
+	if err != nil {
+		t.Fatalf("ListEntries() error = %s", err)
+	}
+	if len(entries) != 1 || entries[0].ContentType != contentType {
+		t.Errorf("ListEntries() = %+v, expected a single entry with content type %s", entries, contentType)
+	}
+}
@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// MultipartUploadMaxParts is the highest part number a multipart upload accepts
+const MultipartUploadMaxParts = 10000
+
+// CreateMultipartUploadPart records a part uploaded to a multipart upload.  Uploading the same
+// part number again replaces the previous part.
+func (c *cataloger) CreateMultipartUploadPart(ctx context.Context, repository, uploadID string, part MultipartUploadPart) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "uploadID", IsValid: ValidateUploadID(uploadID)},
+		{Name: "partNumber", IsValid: ValidatePartNumber(part.PartNumber)},
+	}); err != nil {
+		return err
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec(`INSERT INTO catalog_multipart_upload_parts (upload_id,part_number,etag,size,creation_date)
+			SELECT upload_id, $3, $4, $5, NOW()
+			FROM catalog_multipart_uploads
+			WHERE repository_id = $1 AND upload_id = $2
+			ON CONFLICT (upload_id,part_number)
+			DO UPDATE SET etag=EXCLUDED.etag, size=EXCLUDED.size, creation_date=EXCLUDED.creation_date`,
+			repoID, uploadID, part.PartNumber, part.ETag, part.Size)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected != 1 {
+			return nil, ErrMultipartUploadNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCataloger_CreateMultipartUploadPart(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.CreateMultipartUpload(ctx, repository, "upload1", "/path", "/file", time.Now()); err != nil {
+		t.Fatal("create multipart upload:", err)
+	}
+
+	tests := []struct {
+		name     string
+		uploadID string
+		part     MultipartUploadPart
+		wantErr  error
+	}{
+		{name: "first", uploadID: "upload1", part: MultipartUploadPart{PartNumber: 1, ETag: "etag1", Size: 10}},
+		{name: "replace", uploadID: "upload1", part: MultipartUploadPart{PartNumber: 1, ETag: "etag2", Size: 20}},
+		{name: "unknown upload", uploadID: "upload2", part: MultipartUploadPart{PartNumber: 1, ETag: "etag1", Size: 10}, wantErr: ErrMultipartUploadNotFound},
+		{name: "invalid part number", uploadID: "upload1", part: MultipartUploadPart{PartNumber: 0, ETag: "etag1", Size: 10}, wantErr: ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.CreateMultipartUploadPart(ctx, repository, tt.uploadID, tt.part)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateMultipartUploadPart() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			parts, _, err := c.ListMultipartUploadParts(ctx, repository, tt.uploadID, -1, 0)
+			if err != nil {
+				t.Fatalf("ListMultipartUploadParts() error = %s", err)
+			}
+			if len(parts) != 1 || parts[0].ETag != tt.part.ETag || parts[0].Size != tt.part.Size {
+				t.Errorf("ListMultipartUploadParts() = %+v, expected a single part %+v", parts, tt.part)
+			}
+		})
+	}
+}
@@ -11,6 +11,9 @@ const (
 	createRepositoryCommitMessage = "Repository created"
 )
 
+// CreateRepository creates a repository with an initial commit on its default branch.  The
+// storage namespace of the repository may not overlap the storage namespace of another
+// repository, so their objects can't collide.
 func (c *cataloger) CreateRepository(ctx context.Context, repository string, storageNamespace string, branch string) error {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
@@ -21,6 +24,10 @@ func (c *cataloger) CreateRepository(ctx context.Context, repository string, sto
 	}
 
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkStorageNamespaceOverlap(tx, storageNamespace); err != nil {
+			return nil, err
+		}
+
 		// next id for branch
 		var branchID int64
 		if err := tx.Get(&branchID, `SELECT nextval('catalog_branches_id_seq')`); err != nil {
@@ -55,6 +62,9 @@ func (c *cataloger) CreateRepository(ctx context.Context, repository string, sto
 		if err != nil {
 			return nil, fmt.Errorf("insert commit: %w", err)
 		}
+		if err := c.checkRepositoriesQuota(tx); err != nil {
+			return nil, err
+		}
 		return repoID, nil
 	}, c.txOpts(ctx)...)
 	return err
@@ -29,6 +29,24 @@ func TestCataloger_CreateRepo(t *testing.T) {
 			wantErr: false,
 			asErr:   nil,
 		},
+		{
+			name:    "same storage",
+			args:    args{name: "repo2", storage: "s3://bucket1/", branch: "master"},
+			wantErr: true,
+			asErr:   ErrStorageNamespaceOverlap,
+		},
+		{
+			name:    "nested storage",
+			args:    args{name: "repo2", storage: "s3://bucket1/nested", branch: "master"},
+			wantErr: true,
+			asErr:   ErrStorageNamespaceOverlap,
+		},
+		{
+			name:    "sibling storage",
+			args:    args{name: "repo2", storage: "s3://bucket10", branch: "master"},
+			wantErr: false,
+			asErr:   nil,
+		},
 		{
 			name:    "unknown branch",
 			args:    args{name: "repo3", storage: "s3://bucket3", branch: ""},
@@ -55,7 +73,7 @@ func TestCataloger_CreateRepo(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CreateRepository() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if err != nil && tt.asErr != nil && !errors.As(err, &tt.asErr) {
+			if err != nil && tt.asErr != nil && !errors.Is(err, tt.asErr) {
 				t.Fatalf("CreateRepository() error = %v, expected as %v", err, tt.asErr)
 			}
 			if err != nil {
@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/ident"
+)
+
+// CreateTag names the commit that reference points to.  A branch is tagged at its last commit.
+// The tag ID is the content address of the tag computed with the hash algorithm of the
+// repository, so a tag is verifiable by recomputing it.
+func (c *cataloger) CreateTag(ctx context.Context, repository, tagName, reference, tagger, message string, metadata Metadata) (*Tag, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "tag", IsValid: ValidateTagName(tagName)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "tagger", IsValid: ValidateCommitter(tagger)},
+	}); err != nil {
+		return nil, err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		commitID := ref.CommitID
+		if commitID <= UncommittedID {
+			commitID, err = getLastCommitIDByBranchID(tx, branchID)
+		} else {
+			err = tx.Get(&commitID, `SELECT commit_id FROM catalog_commits WHERE branch_id=$1 AND commit_id=$2`, branchID, commitID)
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrCommitNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tagged commit: %w", err)
+		}
+
+		tag := &Tag{
+			Repository:      repository,
+			Name:            tagName,
+			CommitReference: MakeReference(ref.Branch, commitID),
+			Tagger:          tagger,
+			Message:         message,
+			Metadata:        metadata,
+			// the database keeps microseconds - truncate so the stored tag hashes to its ID
+			CreationDate: time.Now().UTC().Truncate(time.Microsecond),
+		}
+		var algorithm ident.Algorithm
+		if err := tx.Get(&algorithm, `SELECT hash_algorithm FROM catalog_repositories WHERE id=$1`, repoID); err != nil {
+			return nil, fmt.Errorf("hash algorithm: %w", err)
+		}
+		tag.ID, err = ident.Address(algorithm, tag)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec(`INSERT INTO catalog_tags (repository_id, name, id, branch_id, commit_id, tagger, message, metadata, creation_date)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT DO NOTHING`,
+			repoID, tag.Name, tag.ID, branchID, commitID, tag.Tagger, tag.Message, tag.Metadata, tag.CreationDate)
+		if err != nil {
+			return nil, fmt.Errorf("insert tag: %w", err)
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected == 0 {
+			return nil, ErrTagAlreadyExists
+		}
+		return tag, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Tag), nil
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/ident"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_CreateTag(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "tester", nil)
+	testutil.MustDo(t, "commit1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	commit2, err := c.Commit(ctx, repository, "branch1", "commit2", "tester", nil)
+	testutil.MustDo(t, "commit2", err)
+
+	tag, err := c.CreateTag(ctx, repository, "v1.0", "master", "tagger", "release", Metadata{"k": "v"})
+	testutil.MustDo(t, "create tag", err)
+	if tag.CommitReference != commit1.Reference {
+		t.Fatalf("CreateTag() on branch tagged %s, expected %s", tag.CommitReference, commit1.Reference)
+	}
+	got, err := c.GetTag(ctx, repository, "v1.0")
+	testutil.MustDo(t, "get tag", err)
+	if !got.CreationDate.Equal(tag.CreationDate) {
+		t.Fatalf("GetTag() creation date %s, expected %s", got.CreationDate, tag.CreationDate)
+	}
+	got.CreationDate = tag.CreationDate
+	if diff := deep.Equal(got, tag); diff != nil {
+		t.Fatal("GetTag() diff:", diff)
+	}
+	// the stored tag hashes to its ID
+	if id := ident.ContentAddress(got); id != got.ID {
+		t.Fatalf("GetTag() content address %s, expected ID %s", id, got.ID)
+	}
+
+	_, err = c.CreateTag(ctx, repository, "v1.0", commit2.Reference, "tagger", "again", nil)
+	if !errors.Is(err, ErrTagAlreadyExists) {
+		t.Fatalf("CreateTag() existing tag err=%v, expected %s", err, ErrTagAlreadyExists)
+	}
+	_, err = c.CreateTag(ctx, repository, "bad/name", "master", "tagger", "", nil)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("CreateTag() invalid name err=%v, expected %s", err, ErrInvalidValue)
+	}
+	_, err = c.CreateTag(ctx, repository, "v0", MakeReference("master", 999999), "tagger", "", nil)
+	if !errors.Is(err, ErrCommitNotFound) {
+		t.Fatalf("CreateTag() missing commit err=%v, expected %s", err, ErrCommitNotFound)
+	}
+	tag2, err := c.CreateTag(ctx, repository, "v2.0", commit2.Reference, "tagger", "", nil)
+	testutil.MustDo(t, "create tag on commit", err)
+	if tag2.CommitReference != commit2.Reference {
+		t.Fatalf("CreateTag() on commit tagged %s, expected %s", tag2.CommitReference, commit2.Reference)
+	}
+
+	tags, hasMore, err := c.ListTags(ctx, repository, 1, "")
+	testutil.MustDo(t, "list tags", err)
+	if len(tags) != 1 || tags[0].Name != "v1.0" || !hasMore {
+		t.Fatalf("ListTags() first page got %d tags, more=%t, expected v1.0 and more", len(tags), hasMore)
+	}
+	tags, hasMore, err = c.ListTags(ctx, repository, 1, "v1.0")
+	testutil.MustDo(t, "list tags", err)
+	if len(tags) != 1 || tags[0].Name != "v2.0" || hasMore {
+		t.Fatalf("ListTags() second page got %d tags, more=%t, expected v2.0 and no more", len(tags), hasMore)
+	}
+
+	// a branch with tagged commits is not deleted
+	_, err = c.DeleteBranch(ctx, repository, "branch1")
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Fatalf("DeleteBranch() with tagged commit err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+	testutil.MustDo(t, "delete tag", c.DeleteTag(ctx, repository, "v2.0"))
+	_, err = c.DeleteBranch(ctx, repository, "branch1")
+	testutil.MustDo(t, "delete branch", err)
+	_, err = c.GetTag(ctx, repository, "v2.0")
+	if !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("GetTag() deleted tag err=%v, expected %s", err, ErrTagNotFound)
+	}
+	if err := c.DeleteTag(ctx, repository, "v2.0"); !errors.Is(err, ErrTagNotFound) {
+		t.Fatalf("DeleteTag() deleted tag err=%v, expected %s", err, ErrTagNotFound)
+	}
+}
+
+func TestCataloger_CreateTag_HashAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	v1, err := c.CreateTag(ctx, repository, "v1", "master", "tagger", "", nil)
+	testutil.MustDo(t, "create tag", err)
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{HashAlgorithm: ident.AlgorithmBLAKE2b})
+	testutil.MustDo(t, "set repository hash algorithm", err)
+	v2, err := c.CreateTag(ctx, repository, "v2", "master", "tagger", "", nil)
+	testutil.MustDo(t, "create tag", err)
+
+	// tags keep the algorithm they were created with
+	for _, tt := range []struct {
+		tag       *Tag
+		algorithm ident.Algorithm
+	}{{tag: v1, algorithm: ident.AlgorithmSHA256}, {tag: v2, algorithm: ident.AlgorithmBLAKE2b}} {
+		got, err := c.GetTag(ctx, repository, tt.tag.Name)
+		testutil.MustDo(t, "get tag", err)
+		if algorithm := ident.AddressAlgorithm(got.ID); algorithm != tt.algorithm {
+			t.Errorf("tag %s ID %s hashed with %s, expected %s", got.Name, got.ID, algorithm, tt.algorithm)
+		}
+		if ok, err := ident.Verify(got.ID, got); err != nil || !ok {
+			t.Errorf("tag %s does not hash to its ID %s: %v", got.Name, got.ID, err)
+		}
+	}
+}
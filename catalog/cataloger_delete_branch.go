@@ -3,63 +3,148 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) DeleteBranch(ctx context.Context, repository, branch string) error {
+// deletedBranchPrefix starts the name a deleted branch is renamed to.  Branch names cannot start
+// with it, so the commits of a deleted branch stay reachable by reference while its name is free
+// for a new branch.
+const deletedBranchPrefix = "~"
+
+func deletedBranchName(branchID int64) string {
+	return deletedBranchPrefix + strconv.FormatInt(branchID, 10)
+}
+
+func isDeletedBranchName(name string) bool {
+	return strings.HasPrefix(name, deletedBranchPrefix)
+}
+
+// DeleteBranch deletes branch and returns the reference of the commit it was at, which is also
+// recorded by the branch_deleted event.  The default branch, a branch named by the Branches of
+// a protected path rule, and a branch that was branched from or has tagged commits cannot be
+// deleted.  Uncommitted changes are dropped, but the commits of the branch are kept:
+// CreateBranch from the returned reference re-creates the branch.
+func (c *cataloger) DeleteBranch(ctx context.Context, repository, branch string) (string, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
 	}); err != nil {
-		return err
+		return "", err
 	}
 
-	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
 		if err != nil {
 			return nil, err
 		}
-
-		// default branch doesn't have parents
-		var legacyCount int
-		err = tx.Get(&legacyCount, `SELECT array_length(lineage,1) FROM catalog_branches WHERE id=$1`, branchID)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
 			return nil, err
 		}
-		if legacyCount == 0 {
-			return nil, fmt.Errorf("delete default branch: %w", ErrOperationNotPermitted)
-		}
+		return deleteBranch(tx, repository, branch, branchID)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return "", err
+	}
+	c.cache.RemoveBranchID(repository, branch)
+	return res.(string), nil
+}
 
-		// check we don't have branch depends on us by count lineage records we are part of
-		var childBranches int
-		err = tx.Get(&childBranches, `SELECT count(*) FROM catalog_branches b 
-			JOIN catalog_branches b2 ON b.repository_id = b2.repository_id AND b2.id=$1
-			WHERE $1=ANY(b.lineage)`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("dependent check: %w", err)
-		}
-		if childBranches > 0 {
-			return nil, fmt.Errorf("branch has dependent branch: %w", ErrOperationNotPermitted)
-		}
+// deleteBranch deletes a branch that is not the default branch, that is not named by protected
+// path rules and that no other branch was created from.  The caller locks the branch.  The
+// branch is renamed to its deleted branch name with its committed entries and commits, and
+// the returned reference of its last commit refers to it by that name.  Its entries are not
+// counted toward the repository usage and its commits are not searched, unless a branch is
+// created from it again; retention rules match it by the name it had.
+func deleteBranch(tx db.Tx, repository, branch string, branchID int64) (string, error) {
+	// default branch doesn't have parents
+	var legacyCount int
+	err := tx.Get(&legacyCount, `SELECT array_length(lineage,1) FROM catalog_branches WHERE id=$1`, branchID)
+	if err != nil {
+		return "", err
+	}
+	if legacyCount == 0 {
+		return "", fmt.Errorf("delete default branch: %w", ErrOperationNotPermitted)
+	}
+	var isDefaultBranch bool
+	err = tx.Get(&isDefaultBranch, `SELECT EXISTS (SELECT 1 FROM catalog_repositories WHERE default_branch=$1)`, branchID)
+	if err != nil {
+		return "", err
+	}
+	if isDefaultBranch {
+		return "", fmt.Errorf("delete default branch: %w", ErrOperationNotPermitted)
+	}
 
-		// delete branch entries
-		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("delete entries: %w", err)
+	// protected path rules naming the branch would silently apply to a new branch of that name
+	repoID, err := getRepositoryID(tx, repository)
+	if err != nil {
+		return "", err
+	}
+	rules, err := getProtectedPaths(tx, repoID)
+	if err != nil {
+		return "", err
+	}
+	for _, rule := range rules {
+		if rule.NamesBranch(branch) {
+			return "", fmt.Errorf("branch is named by protected path %s: %w", rule.Pattern, ErrOperationNotPermitted)
 		}
+	}
 
-		// delete branch
-		res, err := tx.Exec(`DELETE FROM catalog_branches WHERE id=$1`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("delete branch: %w", err)
-		}
-		if affected, err := res.RowsAffected(); err != nil {
-			return nil, err
-		} else if affected != 1 {
-			return nil, ErrBranchNotFound
-		}
-		return nil, nil
-	}, c.txOpts(ctx)...)
-	return err
+	// check we don't have branch depends on us by count lineage records we are part of
+	var childBranches int
+	err = tx.Get(&childBranches, `SELECT count(*) FROM catalog_branches b 
+		JOIN catalog_branches b2 ON b.repository_id = b2.repository_id AND b2.id=$1
+		WHERE $1=ANY(b.lineage) AND b.name NOT LIKE '`+deletedBranchPrefix+`%'`, branchID)
+	if err != nil {
+		return "", fmt.Errorf("dependent check: %w", err)
+	}
+	if childBranches > 0 {
+		return "", fmt.Errorf("branch has dependent branch: %w", ErrOperationNotPermitted)
+	}
+
+	// a tag is addressed by its content, which holds the reference of its commit - renaming the
+	// branch would change it
+	var tagged bool
+	err = tx.Get(&tagged, `SELECT EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id=$1)`, branchID)
+	if err != nil {
+		return "", fmt.Errorf("tags check: %w", err)
+	}
+	if tagged {
+		return "", fmt.Errorf("branch has tagged commits: %w", ErrOperationNotPermitted)
+	}
+
+	headCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+	if err != nil {
+		return "", fmt.Errorf("last commit: %w", err)
+	}
+
+	// drop uncommitted changes
+	_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=0`, branchID)
+	if err != nil {
+		return "", fmt.Errorf("delete entries: %w", err)
+	}
+
+	// free the branch name, keeping its commits
+	deletedName := deletedBranchName(branchID)
+	res, err := tx.Exec(`UPDATE catalog_branches SET name=$2, deleted_name=$3 WHERE id=$1`, branchID, deletedName, branch)
+	if err != nil {
+		return "", fmt.Errorf("delete branch: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if affected != 1 {
+		return "", ErrBranchNotFound
+	}
+	// the committed entries of the branch are no longer counted, nor those of deleted branches
+	// it was created from
+	if err := recountRepositoryTrackedUsage(tx, repoID); err != nil {
+		return "", fmt.Errorf("usage: %w", err)
+	}
+	reference := MakeReference(deletedName, headCommitID)
+	if err := insertRepositoryEvent(tx, repository, EventTypeBranchDeleted, branch, "", reference); err != nil {
+		return "", err
+	}
+	return reference, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
 )
 
 func TestCataloger_DeleteBranch(t *testing.T) {
@@ -75,7 +76,7 @@ func TestCataloger_DeleteBranch(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := c.DeleteBranch(ctx, tt.args.repository, tt.args.branch)
+			_, err := c.DeleteBranch(ctx, tt.args.repository, tt.args.branch)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("DeleteBranch() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -106,13 +107,76 @@ func TestCataloger_DeleteBranchTwice(t *testing.T) {
 	// delete twice (checking double delete) in reverse order
 	for i := numBranches; i > 0; i-- {
 		branchName := fmt.Sprintf("branch%d", i)
-		err := c.DeleteBranch(ctx, repo, branchName)
+		_, err := c.DeleteBranch(ctx, repo, branchName)
 		if err != nil {
 			t.Fatal("Expected delete to succeed on", branchName, err)
 		}
-		err = c.DeleteBranch(ctx, repo, branchName)
+		_, err = c.DeleteBranch(ctx, repo, branchName)
 		if err == nil {
 			t.Fatal("Expected delete to fail on", branchName, err)
 		}
 	}
 }
+
+func TestCataloger_DeleteBranch_GuardRails(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "release", "master")
+	testCatalogerBranch(t, ctx, c, repository, "feature", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "feature", "file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "feature", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	testutil.MustDo(t, "set protected paths", c.SetProtectedPaths(ctx, repository, []*ProtectedPathRule{
+		{Pattern: "**", Branches: []string{"rel*"}},
+		{Pattern: "prod/**"},
+	}))
+
+	if _, err := c.DeleteBranch(ctx, repository, "master"); !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("DeleteBranch() of default branch err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+	if _, err := c.DeleteBranch(ctx, repository, "release"); !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("DeleteBranch() of protected branch err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+	// a rule without branches does not name the branch
+	head, err := c.DeleteBranch(ctx, repository, "feature")
+	testutil.MustDo(t, "delete branch", err)
+	headRef, err := ParseRef(head)
+	testutil.MustDo(t, "parse deleted head", err)
+	commitRef, err := ParseRef(commitLog.Reference)
+	testutil.MustDo(t, "parse commit reference", err)
+	if headRef.CommitID != commitRef.CommitID {
+		t.Errorf("DeleteBranch() returned %s, expected the head commit %d", head, commitRef.CommitID)
+	}
+}
+
+func TestCataloger_DeleteBranch_Recreate(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "feature", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "feature", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "feature", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "feature", "file2", nil, "")
+
+	head, err := c.DeleteBranch(ctx, repository, "feature")
+	testutil.MustDo(t, "delete branch", err)
+	branches, _, err := c.ListBranches(ctx, repository, "", -1, "")
+	testutil.MustDo(t, "list branches", err)
+	if len(branches) != 1 || branches[0].Name != "master" {
+		t.Fatalf("ListBranches() after delete got %+v, expected only master", branches)
+	}
+	if _, err := c.GetCommit(ctx, repository, head); err != nil {
+		t.Fatalf("GetCommit() of deleted head err=%s, expected the commit", err)
+	}
+
+	// the name is free, and the returned reference re-creates the branch with its commits
+	_, err = c.CreateBranch(ctx, repository, "feature", head)
+	testutil.MustDo(t, "re-create branch", err)
+	testCatalogerGetEntry(t, ctx, c, repository, "feature", "file1", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "feature", "file2", false)
+	if _, err := c.DeleteBranch(ctx, repository, "feature"); err != nil {
+		t.Fatalf("DeleteBranch() of re-created branch err=%s", err)
+	}
+}
@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// DeleteEntries deletes multiple entries from branch in a single transaction.  Paths that are
+// not found are ignored.
+func (c *cataloger) DeleteEntries(ctx context.Context, repository, branch string, paths []string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return err
+	}
+
+	// nothing to do in case we don't have paths
+	if len(paths) == 0 {
+		return nil
+	}
+	for i, p := range paths {
+		if !IsNonEmptyString(p) {
+			return fmt.Errorf("path at pos %d: %w", i, ErrInvalidValue)
+		}
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, paths...); err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, UncommittedID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		// delete and tombstone per batch
+		batchSize := c.BatchWrite.EntriesInsertSize
+		for i := 0; i < len(paths); i += batchSize {
+			j := i + batchSize
+			if j > len(paths) {
+				j = len(paths)
+			}
+			if err := deleteEntries(tx, branchID, lineage, paths[i:j]); err != nil {
+				return nil, err
+			}
+		}
+		return nil, insertRepositoryEvents(tx, repository, EventTypeObjectDeleted, branch, paths)
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// deleteEntries deletes the uncommitted entries of paths and adds a tombstone to each path
+// that has a committed entry, same as deleteEntry does for a single path.
+func deleteEntries(tx db.Tx, branchID int64, lineage []lineageCommit, paths []string) error {
+	query, args, err := psql.
+		Delete("catalog_entries").
+		Where(sq.Eq{"branch_id": branchID, "path": paths, "min_commit": 0}).
+		Where("max_commit = catalog_max_commit_id()").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete sql: %w", err)
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("uncommitted: %w", err)
+	}
+
+	committedQuery := psql.
+		Select().
+		Column(sq.Expr("?::bigint", branchID)).
+		Columns("path", "''", "''", "0", "'{}'", "0", "0").
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		Where(sq.Eq{"path": paths, "is_deleted": false, "is_committed": true})
+	query, args, err = psql.
+		Insert("catalog_entries").
+		Columns("branch_id", "path", "physical_address", "checksum", "size", "metadata", "min_commit", "max_commit").
+		Select(committedQuery).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build tombstone sql: %w", err)
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("tombstone: %w", err)
+	}
+	return nil
+}
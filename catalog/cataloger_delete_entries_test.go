@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCataloger_DeleteEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	// committed on master, file0..file4
+	for i := 0; i < 5; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", fmt.Sprintf("/file%d", i), nil, "")
+	}
+	if _, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil); err != nil {
+		t.Fatal("commit for delete entries test:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	// uncommitted on branch1, file5..file9
+	for i := 5; i < 10; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", fmt.Sprintf("/file%d", i), nil, "")
+	}
+
+	paths := []string{"/file1", "/file3", "/file5", "/file7", "/no-such-file"}
+	if err := c.DeleteEntries(ctx, repository, "branch1", paths); err != nil {
+		t.Fatalf("DeleteEntries() error = %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		p := fmt.Sprintf("/file%d", i)
+		_, err := c.GetEntry(ctx, repository, "branch1", p, GetEntryParams{})
+		deleted := i == 1 || i == 3 || i == 5 || i == 7
+		if deleted && !errors.Is(err, ErrEntryNotFound) {
+			t.Errorf("GetEntry() %s error = %v, expected %s", p, err, ErrEntryNotFound)
+		}
+		if !deleted && err != nil {
+			t.Errorf("GetEntry() %s error = %s, expected entry", p, err)
+		}
+	}
+	// parent branch is not affected
+	for _, p := range []string{"/file1", "/file3"} {
+		if _, err := c.GetEntry(ctx, repository, "master", p, GetEntryParams{}); err != nil {
+			t.Errorf("GetEntry() master %s error = %s, expected entry", p, err)
+		}
+	}
+	// tombstones of committed entries are part of the next commit
+	if _, err := c.Commit(ctx, repository, "branch1", "commit deletes", "tester", nil); err != nil {
+		t.Fatal("commit deletes:", err)
+	}
+	for _, p := range []string{"/file1", "/file3"} {
+		if _, err := c.GetEntry(ctx, repository, "branch1:HEAD", p, GetEntryParams{}); !errors.Is(err, ErrEntryNotFound) {
+			t.Errorf("GetEntry() committed %s error = %v, expected %s", p, err, ErrEntryNotFound)
+		}
+	}
+
+	if err := c.DeleteEntries(ctx, repository, "branch1", []string{"/file0", ""}); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("DeleteEntries() with empty path error = %v, expected %s", err, ErrInvalidValue)
+	}
+}
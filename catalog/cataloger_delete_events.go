@@ -0,0 +1,45 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// DeleteEvents removes the events of repository recorded before createdBefore, and returns the
+// number of events removed.  Events are removed in batches, each in its own transaction.
+func (c *cataloger) DeleteEvents(ctx context.Context, repository string, createdBefore time.Time) (int, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for {
+		res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+			repoID, err := c.getRepositoryIDCache(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			result, err := tx.Exec(`DELETE FROM catalog_repository_events
+				WHERE repository_id = $1 AND seq IN (
+					SELECT seq FROM catalog_repository_events
+					WHERE repository_id = $1 AND creation_date < $2
+					LIMIT $3)`,
+				repoID, createdBefore, eventsDeleteBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			return result.RowsAffected()
+		}, c.txOpts(ctx)...)
+		if err != nil {
+			return deleted, err
+		}
+		batch := int(res.(int64))
+		deleted += batch
+		if batch < eventsDeleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
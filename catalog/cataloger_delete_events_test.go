@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DeleteEvents(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+
+	// age the events so far
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`UPDATE catalog_repository_events SET creation_date = now() - interval '40 days'`)
+	})
+	testutil.MustDo(t, "age events", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+
+	deleted, err := c.DeleteEvents(ctx, repository, time.Now().Add(-30*24*time.Hour))
+	testutil.MustDo(t, "delete events", err)
+	if deleted != 2 {
+		t.Errorf("DeleteEvents() deleted %d events, expected 2", deleted)
+	}
+	events, _, err := c.GetEvents(ctx, repository, 0, -1)
+	testutil.MustDo(t, "get events", err)
+	if len(events) != 1 || events[0].Path != "file3" {
+		t.Errorf("GetEvents() after DeleteEvents = %+v, expected the file3 event", events)
+	}
+}
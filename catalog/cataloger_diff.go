@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/treeverse/lakefs/db"
@@ -16,32 +17,65 @@ const (
 	diffResultsTableName = "catalog_diff_results"
 )
 
-func (c *cataloger) Diff(ctx context.Context, repository string, leftBranch string, rightBranch string, limit int, after string) (Differences, bool, error) {
+// Diff lists the differences between leftReference and rightReference.  Diff between two branches
+// compares the branches by their relation, the same way a merge does.  Diff that includes a commit
+// reference compares the two snapshots.
+func (c *cataloger) Diff(ctx context.Context, repository string, leftReference string, rightReference string, limit int, after string) (Differences, bool, error) {
+	leftReference, err := c.ResolveReference(ctx, repository, leftReference)
+	if err != nil {
+		return nil, false, fmt.Errorf("left reference: %w", err)
+	}
+	rightReference, err = c.ResolveReference(ctx, repository, rightReference)
+	if err != nil {
+		return nil, false, fmt.Errorf("right reference: %w", err)
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
-		{Name: "leftBranch", IsValid: ValidateBranchName(leftBranch)},
-		{Name: "rightBranch", IsValid: ValidateBranchName(rightBranch)},
+		{Name: "leftReference", IsValid: ValidateReference(leftReference)},
+		{Name: "rightReference", IsValid: ValidateReference(rightReference)},
 	}); err != nil {
 		return nil, false, err
 	}
+	leftRef, err := ParseRef(leftReference)
+	if err != nil {
+		return nil, false, fmt.Errorf("left reference: %w", err)
+	}
+	rightRef, err := ParseRef(rightReference)
+	if err != nil {
+		return nil, false, fmt.Errorf("right reference: %w", err)
+	}
 
 	if limit < 0 || limit > DiffMaxLimit {
 		limit = DiffMaxLimit
 	}
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		leftID, err := c.getBranchIDCache(tx, repository, leftBranch)
+		leftID, err := c.getBranchIDCache(tx, repository, leftRef.Branch)
 		if err != nil {
 			return nil, fmt.Errorf("left branch: %w", err)
 		}
-		rightID, err := c.getBranchIDCache(tx, repository, rightBranch)
+		rightID, err := c.getBranchIDCache(tx, repository, rightRef.Branch)
 		if err != nil {
 			return nil, fmt.Errorf("right branch: %w", err)
 		}
-		err = c.doDiff(tx, leftID, rightID)
+		// diff by relation compares the committed left branch with the right branch
+		leftCommit, rightCommit := leftRef.CommitID, rightRef.CommitID
+		if leftCommit == UncommittedID && rightCommit == UncommittedID {
+			leftCommit = CommittedID
+			err = c.doDiff(tx, leftID, rightID)
+		} else {
+			err = c.diffSnapshots(tx, leftID, leftCommit, rightID, rightCommit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		differences, err := getDiffDifferences(tx, limit+1, after)
 		if err != nil {
 			return nil, err
 		}
-		return getDiffDifferences(tx, limit+1, after)
+		if err := setDifferencesSizeDelta(tx, differences, leftID, leftCommit, rightID, rightCommit); err != nil {
+			return nil, err
+		}
+		return differences, nil
 	}, c.txOpts(ctx)...)
 	if err != nil {
 		return nil, false, err
@@ -130,14 +164,18 @@ func (c *cataloger) diffFromParent(tx db.Tx, parentID, childID int64) error {
 	return nil
 }
 
+// getDiffDifferences returns up to limit differences after path from the diff results, all of
+// them if limit is negative
 func getDiffDifferences(tx db.Tx, limit int, after string) (Differences, error) {
 	var result Differences
-	query, args, err := psql.Select("diff_type", "path").
+	q := psql.Select("diff_type", "path").
 		From(diffResultsTableName).
 		Where(sq.Gt{"path": after}).
-		OrderBy("path").
-		Limit(uint64(limit)).
-		ToSql()
+		OrderBy("path")
+	if limit >= 0 {
+		q = q.Limit(uint64(limit))
+	}
+	query, args, err := q.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("format diff results query: %w", err)
 	}
@@ -148,6 +186,62 @@ func getDiffDifferences(tx db.Tx, limit int, after string) (Differences, error)
 	return result, nil
 }
 
+// setDifferencesSizeDelta sets the size delta of differences between the entries visible on
+// leftID at leftCommit and the entries visible on rightID at rightCommit
+func setDifferencesSizeDelta(tx db.Tx, differences Differences, leftID int64, leftCommit CommitID, rightID int64, rightCommit CommitID) error {
+	for start := 0; start < len(differences); start += DiffMaxLimit {
+		end := start + DiffMaxLimit
+		if end > len(differences) {
+			end = len(differences)
+		}
+		chunk := differences[start:end]
+		paths := make([]string, len(chunk))
+		for i := range chunk {
+			paths[i] = chunk[i].Path
+		}
+		leftSizes, err := getEntriesSize(tx, leftID, leftCommit, paths)
+		if err != nil {
+			return fmt.Errorf("left sizes: %w", err)
+		}
+		rightSizes, err := getEntriesSize(tx, rightID, rightCommit, paths)
+		if err != nil {
+			return fmt.Errorf("right sizes: %w", err)
+		}
+		for i := range chunk {
+			chunk[i].SizeDelta = leftSizes[chunk[i].Path] - rightSizes[chunk[i].Path]
+		}
+	}
+	return nil
+}
+
+// getEntriesSize returns the size of the entries for paths visible on branchID at commitID.
+// Paths without an entry are missing from the result.
+func getEntriesSize(tx db.Tx, branchID int64, commitID CommitID, paths []string) (map[string]int64, error) {
+	lineage, err := getLineage(tx, branchID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	query, args, err := psql.Select("path", "size").
+		FromSelect(sqEntriesLineage(branchID, commitID, lineage), "entries").
+		Where(sq.Eq{"path": paths, "is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var entries []struct {
+		Path string `db:"path"`
+		Size int64  `db:"size"`
+	}
+	if err := tx.Select(&entries, query, args...); err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(entries))
+	for _, ent := range entries {
+		sizes[ent.Path] = ent.Size
+	}
+	return sizes, nil
+}
+
 func (c *cataloger) diffFromChild(tx db.Tx, childID, parentID int64) error {
 	// read last merge commit numbers from commit table
 	// if it is the first child-to-parent commit, than those commit numbers are calculated as follows:
@@ -222,3 +316,81 @@ func (c *cataloger) diffNonDirect(_ db.Tx, leftID, rightID int64) error {
 	}).Debug("Diff not direct - feature not supported")
 	return ErrFeatureNotSupported
 }
+
+// diffSnapshots compares the entries visible on the left branch at leftCommit with the entries
+// visible on the right branch at rightCommit.  Entries found only on the left are added, entries
+// found only on the right are removed and entries found on both with a different checksum are
+// changed.
+func (c *cataloger) diffSnapshots(tx db.Tx, leftID int64, leftCommit CommitID, rightID int64, rightCommit CommitID) error {
+	leftLineage, err := getLineage(tx, leftID, leftCommit)
+	if err != nil {
+		return fmt.Errorf("left lineage failed: %w", err)
+	}
+	rightLineage, err := getLineage(tx, rightID, rightCommit)
+	if err != nil {
+		return fmt.Errorf("right lineage failed: %w", err)
+	}
+	leftQ := sqSnapshotEntries(leftID, leftCommit, leftLineage)
+	rightQ := sqSnapshotEntries(rightID, rightCommit, rightLineage)
+	// two commits of the same branch with the same lineage differ only by the entries the branch
+	// changed between these commits - limit the comparison to their paths
+	if leftID == rightID && leftCommit > UncommittedID && rightCommit > UncommittedID &&
+		isLineageEqual(leftLineage, rightLineage) {
+		fromCommit, toCommit := leftCommit, rightCommit
+		if fromCommit > toCommit {
+			fromCommit, toCommit = toCommit, fromCommit
+		}
+		changedPaths := sqBranchChangedPaths(leftID, fromCommit, toCommit)
+		leftQ = leftQ.Where(changedPaths)
+		rightQ = rightQ.Where(changedPaths)
+	}
+	diffSQL, args, err := sq.Select(
+		"CASE WHEN r.path IS NULL THEN "+strconv.Itoa(int(DifferenceTypeAdded))+
+			" WHEN l.path IS NULL THEN "+strconv.Itoa(int(DifferenceTypeRemoved))+
+			" ELSE "+strconv.Itoa(int(DifferenceTypeChanged))+" END AS diff_type",
+		"COALESCE(l.path, r.path) AS path").
+		FromSelect(leftQ, "l").
+		JoinClause(rightQ.Prefix("FULL OUTER JOIN (").Suffix(") AS r ON l.path = r.path")).
+		Where("l.path IS NULL OR r.path IS NULL OR l.checksum <> r.checksum").
+		Prefix("CREATE TEMP TABLE " + diffResultsTableName + " ON COMMIT DROP AS").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("diff snapshots sql: %w", err)
+	}
+	if _, err := tx.Exec(diffSQL, args...); err != nil {
+		return fmt.Errorf("exec diff snapshots: %w", err)
+	}
+	return nil
+}
+
+// sqSnapshotEntries selects the path and checksum of the entries visible on branchID at commitID.
+// Entries of the branch itself that were deleted by a later commit are part of the snapshot.
+func sqSnapshotEntries(branchID int64, commitID CommitID, lineage []lineageCommit) sq.SelectBuilder {
+	q := sq.Select("path", "checksum").
+		FromSelect(sqEntriesLineageV(branchID, commitID, lineage), "s")
+	if commitID == UncommittedID || commitID == CommittedID {
+		return q.Where("NOT is_deleted")
+	}
+	return q.Where("CASE WHEN source_branch = ? THEN max_commit >= ? ELSE NOT is_deleted END", branchID, commitID)
+}
+
+// sqBranchChangedPaths matches the paths of the branch entries created or deleted by commits after
+// fromCommit up to toCommit
+func sqBranchChangedPaths(branchID int64, fromCommit, toCommit CommitID) sq.Sqlizer {
+	return sq.Expr(`path IN (SELECT path FROM catalog_entries WHERE branch_id = ?
+			AND (min_commit > ? AND min_commit <= ? OR max_commit >= ? AND max_commit < ?))`,
+		branchID, fromCommit, toCommit, fromCommit, toCommit)
+}
+
+func isLineageEqual(a, b []lineageCommit) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -178,3 +178,169 @@ func TestCataloger_Diff_FromChildThreeBranches(t *testing.T) {
 		{Path: "/file8"},
 	})
 }
+
+func TestCataloger_Diff_Commits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	for i := 0; i < 3; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
+	}
+	firstCommit, err := c.Commit(ctx, repository, "master", "first", "tester", nil)
+	testutil.MustDo(t, "first commit", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "changed")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "master", "/file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file3", nil, "")
+	secondCommit, err := c.Commit(ctx, repository, "master", "second", "tester", nil)
+	testutil.MustDo(t, "second commit", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file4", nil, "")
+
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		want  Differences
+	}{
+		{
+			name:  "second to first",
+			left:  secondCommit.Reference,
+			right: firstCommit.Reference,
+			want: Differences{
+				{Type: DifferenceTypeChanged, Path: "/file1"},
+				{Type: DifferenceTypeRemoved, Path: "/file2"},
+				{Type: DifferenceTypeAdded, Path: "/file3"},
+			},
+		},
+		{
+			name:  "first to second",
+			left:  firstCommit.Reference,
+			right: secondCommit.Reference,
+			want: Differences{
+				{Type: DifferenceTypeChanged, Path: "/file1"},
+				{Type: DifferenceTypeAdded, Path: "/file2"},
+				{Type: DifferenceTypeRemoved, Path: "/file3"},
+			},
+		},
+		{
+			name:  "workspace to second",
+			left:  "master",
+			right: secondCommit.Reference,
+			want: Differences{
+				{Type: DifferenceTypeAdded, Path: "/file4"},
+			},
+		},
+		{
+			name:  "same commit",
+			left:  firstCommit.Reference,
+			right: firstCommit.Reference,
+			want:  Differences{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasMore, err := c.Diff(ctx, repository, tt.left, tt.right, -1, "")
+			testutil.MustDo(t, "diff", err)
+			if hasMore {
+				t.Error("Diff() hasMore should be false")
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Diff() got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCataloger_Diff_CommitsHistory(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	// commit history on master and a child branch, each commit adds a file and changes the first one
+	var masterCommits []*CommitLog
+	for i := 0; i < 3; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, strconv.Itoa(i))
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/master"+strconv.Itoa(i), nil, "")
+		commitLog, err := c.Commit(ctx, repository, "master", "commit "+strconv.Itoa(i), "tester", nil)
+		testutil.MustDo(t, "commit to master", err)
+		masterCommits = append(masterCommits, commitLog)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/branch1", nil, "")
+	testutil.MustDo(t, "delete file on branch1", c.DeleteEntry(ctx, repository, "branch1", "/master1"))
+	branchCommit, err := c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		want  Differences
+	}{
+		{
+			name:  "skip commit",
+			left:  masterCommits[2].Reference,
+			right: masterCommits[0].Reference,
+			want: Differences{
+				{Type: DifferenceTypeChanged, Path: "/file0"},
+				{Type: DifferenceTypeAdded, Path: "/master1"},
+				{Type: DifferenceTypeAdded, Path: "/master2"},
+			},
+		},
+		{
+			name:  "branch to ancestor commit",
+			left:  branchCommit.Reference,
+			right: masterCommits[1].Reference,
+			want: Differences{
+				{Type: DifferenceTypeAdded, Path: "/branch1"},
+				{Type: DifferenceTypeChanged, Path: "/file0"},
+				{Type: DifferenceTypeRemoved, Path: "/master1"},
+				{Type: DifferenceTypeAdded, Path: "/master2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := c.Diff(ctx, repository, tt.left, tt.right, -1, "")
+			testutil.MustDo(t, "diff", err)
+			if !got.Equal(tt.want) {
+				t.Errorf("Diff() got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCataloger_Diff_SizeDelta(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file2", nil, "")
+	firstCommit, err := c.Commit(ctx, repository, "master", "first", "tester", nil)
+	testutil.MustDo(t, "first commit", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file1", nil, "changed")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "branch1", "/file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file3", nil, "")
+	branchCommit, err := c.Commit(ctx, repository, "branch1", "second", "tester", nil)
+	testutil.MustDo(t, "branch commit", err)
+
+	want := Differences{
+		{Type: DifferenceTypeChanged, Path: "/file1", SizeDelta: testCreateEntryCalcSize("/file1", "changed") - testCreateEntryCalcSize("/file1", "")},
+		{Type: DifferenceTypeRemoved, Path: "/file2", SizeDelta: -testCreateEntryCalcSize("/file2", "")},
+		{Type: DifferenceTypeAdded, Path: "/file3", SizeDelta: testCreateEntryCalcSize("/file3", "")},
+	}
+	for _, refs := range [][2]string{
+		{branchCommit.Reference, firstCommit.Reference},
+		{"branch1", "master"},
+	} {
+		got, _, err := c.Diff(ctx, repository, refs[0], refs[1], -1, "")
+		testutil.MustDo(t, "diff", err)
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("Diff(%s, %s) %s", refs[0], refs[1], diff)
+		}
+	}
+}
@@ -9,6 +9,11 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
+// DiffUncommitted returns the uncommitted changes of branch relative to its last commit - what
+// committing the branch would contain.  Staged paths missing from the commit are
+// DifferenceTypeAdded, uncommitted deletes are DifferenceTypeRemoved, and staged paths that
+// overwrite a committed path are DifferenceTypeChanged.  The size delta is the staged size minus
+// the committed size.
 func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch string, limit int, after string) (Differences, bool, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
@@ -25,33 +30,7 @@ func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch stri
 		if err != nil {
 			return nil, err
 		}
-
-		lineage, err := getLineage(tx, branchID, CommittedID)
-		if err != nil {
-			return nil, fmt.Errorf("get lineage: %w", err)
-		}
-
-		q := psql.Select("CASE WHEN e.max_commit=0 THEN 1 WHEN v.path IS NOT NULL THEN 2 ELSE 0 END AS diff_type", "e.path").
-			FromSelect(sqEntriesV(UncommittedID), "e").
-			JoinClause(
-				sqEntriesLineageV(branchID, CommittedID, lineage).
-					Prefix("LEFT JOIN (").Suffix(") AS v ON v.path=e.path")).
-			Where(sq.And{
-				sq.Eq{"e.branch_id": branchID, "e.is_committed": false},
-				sq.Gt{"e.path": after},
-			}).
-			Limit(uint64(limit + 1)).
-			OrderBy("path")
-		sql, args, err := q.ToSql()
-		if err != nil {
-			return nil, fmt.Errorf("build sql: %w", err)
-		}
-
-		var result Differences
-		if err := tx.Select(&result, sql, args...); err != nil {
-			return nil, err
-		}
-		return result, nil
+		return diffUncommitted(tx, branchID, limit+1, after)
 	}, c.txOpts(ctx, db.ReadOnly())...)
 	if err != nil {
 		return nil, false, err
@@ -60,3 +39,37 @@ func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch stri
 	hasMore := paginateSlice(&differences, limit)
 	return differences, hasMore, nil
 }
+
+// diffUncommitted returns up to limit uncommitted differences of branchID after path, all of
+// them if limit is negative
+func diffUncommitted(tx db.Tx, branchID int64, limit int, after string) (Differences, error) {
+	lineage, err := getLineage(tx, branchID, CommittedID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+
+	q := psql.Select("CASE WHEN e.max_commit=0 THEN 1 WHEN v.path IS NOT NULL THEN 2 ELSE 0 END AS diff_type", "e.path",
+		"CASE WHEN e.max_commit=0 THEN 0 ELSE e.size END - CASE WHEN v.is_deleted THEN 0 ELSE COALESCE(v.size,0) END AS size_delta").
+		FromSelect(sqEntriesV(UncommittedID), "e").
+		JoinClause(
+			sqEntriesLineageV(branchID, CommittedID, lineage).
+				Prefix("LEFT JOIN (").Suffix(") AS v ON v.path=e.path")).
+		Where(sq.And{
+			sq.Eq{"e.branch_id": branchID, "e.is_committed": false},
+			sq.Gt{"e.path": after},
+		}).
+		OrderBy("path")
+	if limit >= 0 {
+		q = q.Limit(uint64(limit))
+	}
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+
+	var result Differences
+	if err := tx.Select(&result, sql, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,15 @@
+package catalog
+
+import (
+	"context"
+	"io"
+)
+
+// DumpRepository writes a backup of repository to w: its branches and tags, and all its commits and
+// committed entries.  The backup is a replication stream from the first commit, which ends with the
+// digest of the stream.  Uncommitted changes and repository settings are not included, and objects
+// are not copied.
+func (c *cataloger) DumpRepository(ctx context.Context, repository string, w io.Writer) error {
+	_, err := c.ExportReplication(ctx, repository, 0, w)
+	return err
+}
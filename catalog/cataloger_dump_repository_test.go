@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/ident"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DumpRestoreRepository(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	source := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "file2", nil, "")
+	m1, err := c.Commit(ctx, source, "master", "m1", "tester", nil)
+	testutil.MustDo(t, "commit m1", err)
+	testCatalogerBranch(t, ctx, c, source, "branch1", "master")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, source, "branch1", "file2"))
+	testCatalogerCreateEntry(t, ctx, c, source, "branch1", "file3", nil, "")
+	_, err = c.Commit(ctx, source, "branch1", "b1", "tester", nil)
+	testutil.MustDo(t, "commit b1", err)
+	_, err = c.CreateTag(ctx, source, "v1", m1.Reference, "tester", "v1", Metadata{"k": "v"})
+	testutil.MustDo(t, "create tag", err)
+	// tags hashed with another algorithm are verified with theirs
+	err = c.UpdateRepository(ctx, source, UpdateRepositoryParams{HashAlgorithm: ident.AlgorithmBLAKE2b})
+	testutil.MustDo(t, "set hash algorithm", err)
+	_, err = c.CreateTag(ctx, source, "v2", "branch1", "tester", "v2", nil)
+	testutil.MustDo(t, "create tag", err)
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "uncommitted", nil, "")
+
+	var dump bytes.Buffer
+	testutil.MustDo(t, "dump", c.DumpRepository(ctx, source, &dump))
+
+	restored := testCatalogerUniqueID() + "-restored"
+	testutil.MustDo(t, "restore", c.RestoreRepository(ctx, restored, "", bytes.NewReader(dump.Bytes())))
+	repo, err := c.GetRepository(ctx, restored)
+	testutil.MustDo(t, "get restored", err)
+	if repo.ReadOnly || repo.DefaultBranch != "master" || repo.HashAlgorithm != ident.AlgorithmBLAKE2b {
+		t.Errorf("restored repository %+v, expected writable with default branch master and blake2b", repo)
+	}
+	for _, branch := range []string{"master", "branch1"} {
+		committed := MakeReference(branch, CommittedID)
+		sourceEntries, _, err := c.ListEntries(ctx, source, committed, "", "", "", -1)
+		testutil.MustDo(t, "list source entries", err)
+		restoredEntries, _, err := c.ListEntries(ctx, restored, branch, "", "", "", -1)
+		testutil.MustDo(t, "list restored entries", err)
+		if len(restoredEntries) != len(sourceEntries) {
+			t.Fatalf("restored %s has %d entries, expected %d", branch, len(restoredEntries), len(sourceEntries))
+		}
+		for i := range sourceEntries {
+			if restoredEntries[i].Path != sourceEntries[i].Path || restoredEntries[i].PhysicalAddress != sourceEntries[i].PhysicalAddress {
+				t.Errorf("restored %s entry %d: %s at %s, expected %s at %s", branch, i,
+					restoredEntries[i].Path, restoredEntries[i].PhysicalAddress, sourceEntries[i].Path, sourceEntries[i].PhysicalAddress)
+			}
+		}
+	}
+	tag, err := c.GetTag(ctx, restored, "v1")
+	testutil.MustDo(t, "get restored tag", err)
+	if tag.CommitReference != m1.Reference {
+		t.Errorf("restored tag points to %s, expected %s", tag.CommitReference, m1.Reference)
+	}
+
+	if err := c.RestoreRepository(ctx, restored, "", bytes.NewReader(dump.Bytes())); !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("RestoreRepository() to existing repository err=%v, expected %s", err, db.ErrAlreadyExists)
+	}
+	tampered := strings.Replace(dump.String(), `"message":"b1"`, `"message":"changed"`, 1)
+	if err := c.RestoreRepository(ctx, restored+"-tampered", "", strings.NewReader(tampered)); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("RestoreRepository() of tampered backup err=%v, expected %s", err, ErrDigestMismatch)
+	}
+	if _, err := c.GetRepository(ctx, restored+"-tampered"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("tampered backup restored, err=%v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ExpireBranches deletes the branches of repository selected by its branch expiry policy, and
+// returns them.  Branches with uncommitted changes, the default branch and branches that other
+// branches were created from are never expired.  On a dry run nothing is deleted, and the
+// branches that would be deleted are returned.
+func (c *cataloger) ExpireBranches(ctx context.Context, repository string, dryRun bool) ([]*ExpiredBranch, error) {
+	policy, err := c.GetBranchExpiryPolicy(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := c.listStaleBranches(ctx, repository, &policy.BranchExpiryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return candidates, nil
+	}
+	expired := make([]*ExpiredBranch, 0, len(candidates))
+	for _, candidate := range candidates {
+		deleted, err := c.expireBranch(ctx, repository, candidate)
+		if err != nil {
+			return expired, err
+		}
+		if deleted {
+			expired = append(expired, candidate)
+		}
+	}
+	return expired, nil
+}
+
+func (c *cataloger) listStaleBranches(ctx context.Context, repository string, policy *BranchExpiryPolicy) ([]*ExpiredBranch, error) {
+	before := time.Now().AddDate(0, 0, -policy.OlderThanDays)
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var branches []struct {
+			Name         string    `db:"name"`
+			CommitID     CommitID  `db:"commit_id"`
+			CreationDate time.Time `db:"creation_date"`
+		}
+		err = tx.Select(&branches, `SELECT b.name, c.commit_id, c.creation_date
+			FROM catalog_branches b
+			JOIN catalog_repositories r ON r.id = b.repository_id
+			JOIN LATERAL (
+				SELECT commit_id, creation_date FROM catalog_commits
+				WHERE branch_id = b.id ORDER BY commit_id DESC LIMIT 1
+			) c ON true
+			WHERE b.repository_id = $1 AND b.id <> r.default_branch AND c.creation_date < $2
+				AND NOT EXISTS (SELECT 1 FROM catalog_entries e WHERE e.branch_id = b.id AND e.min_commit = 0)
+				AND b.name NOT LIKE '`+deletedBranchPrefix+`%'
+				AND NOT EXISTS (SELECT 1 FROM catalog_branches ch WHERE ch.repository_id = b.repository_id AND b.id = ANY(ch.lineage)
+					AND ch.name NOT LIKE '`+deletedBranchPrefix+`%')
+				AND NOT EXISTS (SELECT 1 FROM catalog_tags t WHERE t.branch_id = b.id)
+			ORDER BY b.name`, repoID, before)
+		if err != nil {
+			return nil, err
+		}
+		var stale []*ExpiredBranch
+		for _, b := range branches {
+			if !policy.Matches(b.Name) {
+				continue
+			}
+			stale = append(stale, &ExpiredBranch{
+				Name:            b.Name,
+				CommitReference: MakeReference(b.Name, b.CommitID),
+				CommitDate:      b.CreationDate,
+			})
+		}
+		return stale, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*ExpiredBranch), nil
+}
+
+// expireBranch deletes branch if it did not change since it was listed as stale, and reports
+// whether it was deleted.  The CommitReference of a deleted branch is updated to the reference
+// DeleteBranch returns, from which the branch can be re-created.
+func (c *cataloger) expireBranch(ctx context.Context, repository string, branch *ExpiredBranch) (bool, error) {
+	ref, err := ParseRef(branch.CommitReference)
+	if err != nil {
+		return false, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := getBranchID(tx, repository, branch.Name, LockTypeUpdate)
+		if errors.Is(err, db.ErrNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		var unchanged bool
+		err = tx.Get(&unchanged, `SELECT
+				NOT EXISTS (SELECT 1 FROM catalog_commits WHERE branch_id = $1 AND commit_id > $2)
+				AND NOT EXISTS (SELECT 1 FROM catalog_entries WHERE branch_id = $1 AND min_commit = 0)`,
+			branchID, ref.CommitID)
+		if err != nil {
+			return nil, err
+		}
+		if !unchanged {
+			return "", nil
+		}
+		reference, err := deleteBranch(tx, repository, branch.Name, branchID)
+		if errors.Is(err, ErrOperationNotPermitted) {
+			// a branch was created from it, a commit of it was tagged, or a protected path rule
+			// names it
+			return "", nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return reference, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return false, err
+	}
+	reference := res.(string)
+	if reference == "" {
+		return false, nil
+	}
+	c.cache.RemoveBranchID(repository, branch.Name)
+	branch.CommitReference = reference
+	return true, nil
+}
@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
+)
+
+func TestCataloger_ExpireBranches(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	if _, err := c.ExpireBranches(ctx, repository, true); !errors.Is(err, ErrBranchExpiryPolicyNotFound) {
+		t.Fatalf("ExpireBranches() without a policy error = %v, expected %v", err, ErrBranchExpiryPolicyNotFound)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+		t.Fatal("commit:", err)
+	}
+	for _, branch := range []string{"tmp-old", "tmp-keep", "tmp-dirty", "tmp-parent", "other-old"} {
+		testCatalogerBranch(t, ctx, c, repository, branch, "master")
+	}
+	testCatalogerBranch(t, ctx, c, repository, "child", "tmp-parent")
+
+	// age all the commits so far, then add changes that keep branches from expiring
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`UPDATE catalog_commits c SET creation_date = now() - interval '30 days'
+			FROM catalog_branches b, catalog_repositories r
+			WHERE c.branch_id = b.id AND b.repository_id = r.id AND r.name = $1`, repository)
+	})
+	if err != nil {
+		t.Fatal("age commits:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "tmp-new", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "tmp-dirty", "file2", nil, "")
+
+	err = c.SetBranchExpiryPolicy(ctx, repository, &BranchExpiryPolicy{
+		Patterns:      []string{"tmp-*"},
+		OlderThanDays: 7,
+		Exempt:        []string{"tmp-keep"},
+	})
+	if err != nil {
+		t.Fatal("SetBranchExpiryPolicy:", err)
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		expired, err := c.ExpireBranches(ctx, repository, dryRun)
+		if err != nil {
+			t.Fatalf("ExpireBranches(dryRun=%t): %s", dryRun, err)
+		}
+		names := make([]string, len(expired))
+		for i, branch := range expired {
+			names[i] = branch.Name
+		}
+		if diff := deep.Equal(names, []string{"tmp-old"}); diff != nil {
+			t.Fatalf("ExpireBranches(dryRun=%t) diff: %s", dryRun, diff)
+		}
+		exists, err := c.BranchExists(ctx, repository, "tmp-old")
+		if err != nil {
+			t.Fatal("BranchExists:", err)
+		}
+		if exists != dryRun {
+			t.Fatalf("ExpireBranches(dryRun=%t) left branch exists=%t", dryRun, exists)
+		}
+	}
+
+	for _, branch := range []string{"master", "tmp-keep", "tmp-dirty", "tmp-parent", "other-old", "child", "tmp-new"} {
+		exists, err := c.BranchExists(ctx, repository, branch)
+		if err != nil {
+			t.Fatal("BranchExists:", err)
+		}
+		if !exists {
+			t.Errorf("Branch %s was expired", branch)
+		}
+	}
+}
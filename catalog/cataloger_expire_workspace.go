@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ExpireWorkspace discards the uncommitted entries of all branches of repository that were staged
+// before the age set by its workspace expiry policy, as ResetEntry would.  It returns the number
+// and size of the entries discarded on each branch.  On a dry run nothing is discarded, and the
+// entries that would be discarded are counted.  Objects of discarded entries are not removed from
+// the underlying storage.
+func (c *cataloger) ExpireWorkspace(ctx context.Context, repository string, dryRun bool) ([]*ExpiredWorkspace, error) {
+	policy, err := c.GetWorkspaceExpiryPolicy(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	before := time.Now().AddDate(0, 0, -policy.OlderThanDays)
+	opts := c.txOpts(ctx)
+	if dryRun {
+		opts = c.txOpts(ctx, db.ReadOnly())
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		entries := `SELECT e.branch_id, e.size, e.max_commit FROM catalog_entries e
+			JOIN catalog_branches b ON b.id = e.branch_id
+			WHERE b.repository_id = $1 AND e.min_commit = 0 AND e.creation_date < $2`
+		if !dryRun {
+			if err := checkRepositoryWritable(tx, repository); err != nil {
+				return nil, err
+			}
+			entries = `DELETE FROM catalog_entries e USING catalog_branches b
+				WHERE b.id = e.branch_id AND b.repository_id = $1 AND e.min_commit = 0 AND e.creation_date < $2
+				RETURNING e.branch_id, e.size, e.max_commit`
+		}
+		var expired []*ExpiredWorkspace
+		err = tx.Select(&expired, fmt.Sprintf(`WITH expired AS (%s)
+			SELECT b.name AS branch, count(*) AS entries, coalesce(sum(x.size) FILTER (WHERE x.max_commit <> 0), 0) AS bytes
+			FROM expired x JOIN catalog_branches b ON b.id = x.branch_id
+			GROUP BY b.name
+			ORDER BY b.name`, entries), repoID, before)
+		if err != nil {
+			return nil, err
+		}
+		return expired, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	expired := res.([]*ExpiredWorkspace)
+	if !dryRun {
+		for _, workspace := range expired {
+			workspaceExpiredEntriesCounter.WithLabelValues(repository).Add(float64(workspace.Entries))
+			workspaceExpiredBytesCounter.WithLabelValues(repository).Add(float64(workspace.Bytes))
+		}
+	}
+	return expired, nil
+}
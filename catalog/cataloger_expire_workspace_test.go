@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
+)
+
+func TestCataloger_ExpireWorkspace(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	if _, err := c.ExpireWorkspace(ctx, repository, true); !errors.Is(err, ErrWorkspaceExpiryPolicyNotFound) {
+		t.Fatalf("ExpireWorkspace() without a policy error = %v, expected %v", err, ErrWorkspaceExpiryPolicyNotFound)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "commit file0", "tester", nil); err != nil {
+		t.Fatal("commit:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	for _, entry := range []struct {
+		branch string
+		path   string
+		size   int64
+	}{
+		{branch: "master", path: "old1", size: 10},
+		{branch: "master", path: "new1", size: 100},
+		{branch: "branch1", path: "old2", size: 20},
+	} {
+		err := c.CreateEntry(ctx, repository, entry.branch, Entry{
+			Path:            entry.path,
+			Checksum:        "cc",
+			PhysicalAddress: "/addr/" + entry.path,
+			Size:            entry.size,
+		}, CreateEntryParams{})
+		if err != nil {
+			t.Fatalf("create entry %s: %s", entry.path, err)
+		}
+	}
+	if err := c.DeleteEntry(ctx, repository, "master", "file0"); err != nil {
+		t.Fatal("delete entry:", err)
+	}
+
+	// stage the old entries and the deletion of file0 in the past
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`UPDATE catalog_entries e SET creation_date = now() - interval '30 days'
+			FROM catalog_branches b, catalog_repositories r
+			WHERE e.branch_id = b.id AND b.repository_id = r.id AND r.name = $1
+				AND e.min_commit = 0 AND e.path IN ('old1', 'old2', 'file0')`, repository)
+	})
+	if err != nil {
+		t.Fatal("age entries:", err)
+	}
+
+	if err := c.SetWorkspaceExpiryPolicy(ctx, repository, &WorkspaceExpiryPolicy{OlderThanDays: 7}); err != nil {
+		t.Fatal("SetWorkspaceExpiryPolicy:", err)
+	}
+	expected := []*ExpiredWorkspace{
+		{Branch: "branch1", Entries: 1, Bytes: 20},
+		{Branch: "master", Entries: 2, Bytes: 10},
+	}
+	for _, dryRun := range []bool{true, false} {
+		expired, err := c.ExpireWorkspace(ctx, repository, dryRun)
+		if err != nil {
+			t.Fatalf("ExpireWorkspace(dryRun=%t): %s", dryRun, err)
+		}
+		if diff := deep.Equal(expired, expected); diff != nil {
+			t.Fatalf("ExpireWorkspace(dryRun=%t) diff: %s", dryRun, diff)
+		}
+	}
+
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "old1", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "old2", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "new1", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "file0", true)
+
+	expired, err := c.ExpireWorkspace(ctx, repository, false)
+	if err != nil {
+		t.Fatal("ExpireWorkspace:", err)
+	}
+	if len(expired) != 0 {
+		t.Fatalf("ExpireWorkspace() after expiry returned %d branches, expected none", len(expired))
+	}
+}
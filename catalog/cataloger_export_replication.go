@@ -0,0 +1,243 @@
+package catalog
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/treeverse/lakefs/db"
+)
+
+// ExportReplication writes to w the replication stream of repository: the changes of the commits
+// with an ID greater than after, where 0 starts from the first commit.  Returns the checkpoint -
+// the ID of the last commit in the stream, to pass as after to the next export.  The stream ends
+// with its digest.  Only committed data is replicated, and a deleted branch is replicated only
+// while a branch was created from it.
+func (c *cataloger) ExportReplication(ctx context.Context, repository string, after int64, w io.Writer) (int64, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "after", IsValid: func() bool { return after >= 0 }},
+	}); err != nil {
+		return 0, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := getRepositoryID(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		// commits lock their branch before they take an ID from the commit sequence - wait for
+		// the commits in progress, so no commit with an ID up to the checkpoint is missed.
+		// nothing is written before the lock is taken, so a retry does not repeat output.
+		if _, err := tx.Exec(`SELECT 1 FROM catalog_branches WHERE repository_id=$1 FOR SHARE`, repoID); err != nil {
+			return nil, fmt.Errorf("lock branches: %w", err)
+		}
+		repo, err := getRepository(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		// the last commit may be gone with its deleted branch, the checkpoint never moves back
+		var checkpoint CommitID
+		if err := tx.Get(&checkpoint, `SELECT GREATEST(MAX(c.commit_id), $2::bigint) FROM catalog_commits c
+				JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE b.repository_id = $1`, repoID, after); err != nil {
+			return nil, fmt.Errorf("checkpoint: %w", err)
+		}
+
+		h := sha256.New()
+		enc := json.NewEncoder(io.MultiWriter(w, h))
+		if err := enc.Encode(replicationHeader{
+			Version:          ReplicationStreamVersion,
+			Repository:       repository,
+			StorageNamespace: repo.StorageNamespace,
+			DefaultBranch:    repo.DefaultBranch,
+			After:            CommitID(after),
+			Checkpoint:       checkpoint,
+			HashAlgorithm:    repo.HashAlgorithm,
+		}); err != nil {
+			return nil, fmt.Errorf("write header: %w", err)
+		}
+		if err := exportReplicationBranches(tx, enc, repoID); err != nil {
+			return nil, err
+		}
+		if err := exportReplicationCommits(tx, enc, repoID, CommitID(after), checkpoint); err != nil {
+			return nil, err
+		}
+		if err := exportReplicationEntries(tx, enc, repoID, CommitID(after), checkpoint); err != nil {
+			return nil, err
+		}
+		if err := exportReplicationTags(tx, enc, repoID); err != nil {
+			return nil, err
+		}
+		if err := json.NewEncoder(w).Encode(replicationRecord{
+			Type:   replicationRecordDigest,
+			Digest: hex.EncodeToString(h.Sum(nil)),
+		}); err != nil {
+			return nil, fmt.Errorf("write digest: %w", err)
+		}
+		return int64(checkpoint), nil
+	}, c.txOpts(ctx, db.WithIsolationLevel(sql.LevelRepeatableRead))...)
+	if err != nil {
+		return 0, err
+	}
+	return res.(int64), nil
+}
+
+func exportReplicationBranches(tx db.Tx, enc *json.Encoder, repoID int) error {
+	rows, err := tx.Query(`SELECT b.name,
+			ARRAY_TO_STRING(ARRAY(SELECT lb.name FROM unnest(b.lineage) WITH ORDINALITY AS l(id, n)
+				JOIN catalog_branches lb ON lb.id = l.id ORDER BY l.n), ',') AS lineage,
+			COALESCE((SELECT MIN(c.commit_id) FROM catalog_commits c WHERE c.branch_id = b.id), 0) AS first_commit
+		FROM catalog_branches b
+		WHERE b.repository_id = $1 AND catalog_branch_in_use(b.id)
+		ORDER BY b.name`, repoID)
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var row struct {
+			Name        string   `db:"name"`
+			Lineage     string   `db:"lineage"`
+			FirstCommit CommitID `db:"first_commit"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("scan branch: %w", err)
+		}
+		if err := enc.Encode(replicationRecord{Type: replicationRecordBranch, Branch: &replicationBranch{
+			Name:        row.Name,
+			Lineage:     splitList(row.Lineage),
+			FirstCommit: row.FirstCommit,
+		}}); err != nil {
+			return fmt.Errorf("write branch %s: %w", row.Name, err)
+		}
+	}
+	return rows.Err()
+}
+
+func exportReplicationCommits(tx db.Tx, enc *json.Encoder, repoID int, after, checkpoint CommitID) error {
+	rows, err := tx.Query(`SELECT b.name AS branch, c.commit_id, c.previous_commit_id,
+			COALESCE(c.committer,'') AS committer, COALESCE(c.message,'') AS message, c.creation_date, c.metadata,
+			c.merge_type::text AS merge_type, COALESCE(mb.name,'') AS merge_source_branch,
+			COALESCE(c.merge_source_commit,0) AS merge_source_commit,
+			ARRAY_TO_STRING(c.lineage_commits, ',') AS lineage_commits, c.squashed
+		FROM catalog_commits c
+			JOIN catalog_branches b ON b.id = c.branch_id
+			LEFT JOIN catalog_branches mb ON mb.id = c.merge_source_branch
+		WHERE b.repository_id = $1 AND c.commit_id > $2 AND c.commit_id <= $3 AND catalog_branch_in_use(b.id)
+		ORDER BY c.commit_id`, repoID, after, checkpoint)
+	if err != nil {
+		return fmt.Errorf("list commits: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var row struct {
+			Branch            string    `db:"branch"`
+			CommitID          CommitID  `db:"commit_id"`
+			PreviousCommitID  CommitID  `db:"previous_commit_id"`
+			Committer         string    `db:"committer"`
+			Message           string    `db:"message"`
+			CreationDate      time.Time `db:"creation_date"`
+			Metadata          Metadata  `db:"metadata"`
+			MergeType         string    `db:"merge_type"`
+			MergeSourceBranch string    `db:"merge_source_branch"`
+			MergeSourceCommit CommitID  `db:"merge_source_commit"`
+			LineageCommits    string    `db:"lineage_commits"`
+			Squashed          bool      `db:"squashed"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("scan commit: %w", err)
+		}
+		lineageCommits, err := parseCommitIDs(row.LineageCommits)
+		if err != nil {
+			return fmt.Errorf("commit %d lineage: %w", row.CommitID, err)
+		}
+		if err := enc.Encode(replicationRecord{Type: replicationRecordCommit, Commit: &replicationCommit{
+			Branch:            row.Branch,
+			CommitID:          row.CommitID,
+			PreviousCommitID:  row.PreviousCommitID,
+			Committer:         row.Committer,
+			Message:           row.Message,
+			CreationDate:      row.CreationDate,
+			Metadata:          row.Metadata,
+			MergeType:         row.MergeType,
+			MergeSourceBranch: row.MergeSourceBranch,
+			MergeSourceCommit: row.MergeSourceCommit,
+			LineageCommits:    lineageCommits,
+			Squashed:          row.Squashed,
+		}}); err != nil {
+			return fmt.Errorf("write commit %d: %w", row.CommitID, err)
+		}
+	}
+	return rows.Err()
+}
+
+// exportReplicationEntries writes the entries committed after the previous export, and closes the
+// entries of previous exports that were replaced or deleted since.  A commit sets the max commit
+// of the entries it replaces to the previous commit of its branch.
+func exportReplicationEntries(tx db.Tx, enc *json.Encoder, repoID int, after, checkpoint CommitID) error {
+	rows, err := tx.Query(`SELECT b.name AS branch, e.path, COALESCE(e.physical_address,'') AS physical_address, e.creation_date, e.size, e.checksum,
+			e.content_type, e.metadata, e.min_commit, e.max_commit
+		FROM catalog_entries e JOIN catalog_branches b ON b.id = e.branch_id
+		WHERE b.repository_id = $1 AND e.min_commit > $2 AND e.min_commit <= $3 AND catalog_branch_in_use(b.id)
+		ORDER BY b.name, e.path, e.min_commit`, repoID, after, checkpoint)
+	if err != nil {
+		return fmt.Errorf("list entries: %w", err)
+	}
+	if err := encodeReplicationEntries(rows, enc, replicationRecordEntry); err != nil {
+		return err
+	}
+	rows, err = tx.Query(`SELECT b.name AS branch, e.path, e.min_commit, e.max_commit
+		FROM catalog_entries e JOIN catalog_branches b ON b.id = e.branch_id
+		WHERE b.repository_id = $1 AND e.min_commit > 0 AND e.min_commit <= $2 AND catalog_branch_in_use(b.id)
+			AND e.max_commit >= e.min_commit AND e.max_commit < catalog_max_commit_id()
+			AND EXISTS (SELECT 1 FROM catalog_commits c
+				WHERE c.branch_id = e.branch_id AND c.previous_commit_id = e.max_commit
+					AND c.commit_id > $2 AND c.commit_id <= $3)
+		ORDER BY b.name, e.path, e.min_commit`, repoID, after, checkpoint)
+	if err != nil {
+		return fmt.Errorf("list closed entries: %w", err)
+	}
+	return encodeReplicationEntries(rows, enc, replicationRecordEntryClosed)
+}
+
+func encodeReplicationEntries(rows *sqlx.Rows, enc *json.Encoder, recordType string) error {
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var ent replicationEntry
+		if err := rows.StructScan(&ent); err != nil {
+			return fmt.Errorf("scan entry: %w", err)
+		}
+		if err := enc.Encode(replicationRecord{Type: recordType, Entry: &ent}); err != nil {
+			return fmt.Errorf("write entry %s: %w", ent.Path, err)
+		}
+	}
+	return rows.Err()
+}
+
+func exportReplicationTags(tx db.Tx, enc *json.Encoder, repoID int) error {
+	rows, err := tx.Query(`SELECT t.name, t.id, b.name AS branch, t.commit_id, t.tagger, t.message, t.metadata, t.creation_date
+		FROM catalog_tags t JOIN catalog_branches b ON b.id = t.branch_id
+		WHERE t.repository_id = $1
+		ORDER BY t.name`, repoID)
+	if err != nil {
+		return fmt.Errorf("list tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var tag replicationTag
+		if err := rows.StructScan(&tag); err != nil {
+			return fmt.Errorf("scan tag: %w", err)
+		}
+		if err := enc.Encode(replicationRecord{Type: replicationRecordTag, Tag: &tag}); err != nil {
+			return fmt.Errorf("write tag %s: %w", tag.Name, err)
+		}
+	}
+	return rows.Err()
+}
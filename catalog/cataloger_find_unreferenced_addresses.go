@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// FindUnreferencedAddresses returns the addresses, of the fully qualified object addresses given
+// (e.g. "s3://bucket/prefix/key"), that no entry, multipart upload or dedup record of any
+// repository points to.  Entries of every branch and commit count, including deleted and expired
+// entries, and entries copied from another repository that point to the object by its qualified
+// address.
+func (c *cataloger) FindUnreferencedAddresses(ctx context.Context, addresses []string) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	values := make([]string, len(addresses))
+	args := make([]interface{}, len(addresses))
+	for i, address := range addresses {
+		values[i] = fmt.Sprintf("($%d::varchar)", i+1)
+		args[i] = address
+	}
+	// relative addresses are matched through the storage namespace of their repository, so the
+	// physical address index serves both lookups
+	query := `WITH addresses AS (SELECT address FROM (VALUES ` + strings.Join(values, ",") + `) AS a(address)),
+			namespaces AS (SELECT id, regexp_replace(storage_namespace, '/$', '') || '/' AS prefix FROM catalog_repositories)
+		SELECT a.address FROM addresses a
+		WHERE NOT EXISTS (SELECT 1 FROM catalog_entries e WHERE e.physical_address = a.address)
+			AND NOT EXISTS (SELECT 1 FROM namespaces n
+				JOIN catalog_branches b ON b.repository_id = n.id
+				JOIN catalog_entries e ON e.branch_id = b.id
+				WHERE left(a.address, length(n.prefix)) = n.prefix
+					AND e.physical_address = substr(a.address, length(n.prefix) + 1))
+			AND NOT EXISTS (SELECT 1 FROM catalog_multipart_uploads m JOIN catalog_repositories r ON r.id = m.repository_id
+				WHERE catalog_qualified_address(r.storage_namespace, m.physical_address) = a.address)
+			AND NOT EXISTS (SELECT 1 FROM catalog_object_dedup d JOIN catalog_repositories r ON r.id = d.repository_id
+				WHERE catalog_qualified_address(r.storage_namespace, d.physical_address) = a.address)
+		ORDER BY a.address`
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var unreferenced []string
+		if err := tx.Select(&unreferenced, query, args...); err != nil {
+			return nil, fmt.Errorf("select unreferenced addresses: %w", err)
+		}
+		return unreferenced, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]string), nil
+}
@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_FindUnreferencedAddresses(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	other := testCatalogerRepo(t, ctx, c, "other", "master")
+	namespace := "s3://" + repository
+	for _, path := range []string{"a", "copied"} {
+		err := c.CreateEntry(ctx, repository, "master", Entry{Path: path, PhysicalAddress: "addr-" + path, Checksum: "ff", Size: 1}, CreateEntryParams{})
+		testutil.MustDo(t, "create entry "+path, err)
+	}
+	// copied is only referenced by its full address from the other repository
+	_, err := c.CopyPath(ctx, repository, "master", "copied", other, "master", "copied")
+	testutil.MustDo(t, "copy path", err)
+	testutil.MustDo(t, "delete copied", c.DeleteEntry(ctx, repository, "master", "copied"))
+	err = c.CreateMultipartUpload(ctx, repository, "upload1", "uploading", "addr-uploading", time.Now())
+	testutil.MustDo(t, "create multipart upload", err)
+
+	got, err := c.FindUnreferencedAddresses(ctx, []string{
+		namespace + "/addr-a",
+		namespace + "/addr-copied",
+		namespace + "/addr-uploading",
+		namespace + "/addr-orphan",
+		"s3://" + other + "/addr-a",
+	})
+	testutil.MustDo(t, "find unreferenced addresses", err)
+	expected := []string{namespace + "/addr-orphan", "s3://" + other + "/addr-a"}
+	sort.Strings(expected)
+	if diff := deep.Equal(got, expected); diff != nil {
+		t.Errorf("FindUnreferencedAddresses() got %v, diff %s", got, diff)
+	}
+
+	got, err = c.FindUnreferencedAddresses(ctx, nil)
+	testutil.MustDo(t, "find no addresses", err)
+	if len(got) != 0 {
+		t.Errorf("FindUnreferencedAddresses() of no addresses got %v", got)
+	}
+}
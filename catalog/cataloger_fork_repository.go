@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ForkRepository creates repository as a fork of sourceRepository: the fork starts with the
+// branches, commits, tags and uncommitted changes of the source, and then changes independently.
+// Objects are not copied - the fork uses the storage namespace of the source and its entries
+// point to the same objects.  Commits keep their IDs, so commit references of the source are
+// valid in the fork.  Repository settings and the change feed are not forked.
+func (c *cataloger) ForkRepository(ctx context.Context, sourceRepository, repository string) error {
+	if err := Validate(ValidateFields{
+		{Name: "sourceRepository", IsValid: ValidateRepositoryName(sourceRepository)},
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		source, err := getRepository(tx, sourceRepository)
+		if err != nil {
+			return nil, fmt.Errorf("source repository: %w", err)
+		}
+		sourceRepoID, err := getRepositoryID(tx, sourceRepository)
+		if err != nil {
+			return nil, fmt.Errorf("source repository: %w", err)
+		}
+		// lock the source branches, so the fork is a consistent copy, and map each of them to the
+		// id of its fork
+		if _, err := tx.Exec(`SELECT 1 FROM catalog_branches WHERE repository_id=$1 FOR SHARE`, sourceRepoID); err != nil {
+			return nil, fmt.Errorf("lock source branches: %w", err)
+		}
+		if _, err := tx.Exec(`CREATE TEMPORARY TABLE fork_branch_map ON COMMIT DROP AS
+			SELECT id AS source_id, nextval('catalog_branches_id_seq') AS id
+			FROM catalog_branches WHERE repository_id=$1`, sourceRepoID); err != nil {
+			return nil, fmt.Errorf("map branches: %w", err)
+		}
+
+		var repoID int64
+		if err := tx.Get(&repoID, `SELECT nextval('catalog_repositories_id_seq')`); err != nil {
+			return nil, fmt.Errorf("next repository id: %w", err)
+		}
+		if _, err := tx.Exec(`SET CONSTRAINTS catalog_repositories_branches_id_fk DEFERRED`); err != nil {
+			return nil, fmt.Errorf("set constraints: %w", err)
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories (id,name,storage_namespace,creation_date,default_branch,hash_algorithm)
+			SELECT $1::integer, $2, $3, transaction_timestamp(), m.id, r.hash_algorithm
+			FROM catalog_repositories r JOIN fork_branch_map m ON m.source_id = r.default_branch
+			WHERE r.id = $4`,
+			repoID, repository, source.StorageNamespace, sourceRepoID)
+		if db.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("repository %s: %w", repository, db.ErrAlreadyExists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("insert repository: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_branches (repository_id,id,name,lineage)
+			SELECT $1::integer, m.id, b.name,
+				ARRAY(SELECT lm.id FROM unnest(b.lineage) WITH ORDINALITY AS l(source_id, n)
+					JOIN fork_branch_map lm ON lm.source_id = l.source_id ORDER BY l.n)
+			FROM catalog_branches b JOIN fork_branch_map m ON m.source_id = b.id`,
+			repoID); err != nil {
+			return nil, fmt.Errorf("fork branches: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
+				creation_date,metadata,merge_source_branch,merge_source_commit,merge_type,lineage_commits,squashed)
+			SELECT m.id, c.commit_id, c.previous_commit_id, c.committer, c.message,
+				c.creation_date, c.metadata, ms.id, c.merge_source_commit, c.merge_type, c.lineage_commits, c.squashed
+			FROM catalog_commits c JOIN fork_branch_map m ON m.source_id = c.branch_id
+				LEFT JOIN fork_branch_map ms ON ms.source_id = c.merge_source_branch`); err != nil {
+			return nil, fmt.Errorf("fork commits: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,
+				metadata,min_commit,max_commit,is_expired,content_type)
+			SELECT m.id, e.path, e.physical_address, e.creation_date, e.size, e.checksum,
+				e.metadata, e.min_commit, e.max_commit, e.is_expired, e.content_type
+			FROM catalog_entries e JOIN fork_branch_map m ON m.source_id = e.branch_id`); err != nil {
+			return nil, fmt.Errorf("fork entries: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_tags (repository_id,name,id,branch_id,commit_id,tagger,message,metadata,creation_date)
+			SELECT $1::integer, t.name, t.id, m.id, t.commit_id, t.tagger, t.message, t.metadata, t.creation_date
+			FROM catalog_tags t JOIN fork_branch_map m ON m.source_id = t.branch_id`,
+			repoID); err != nil {
+			return nil, fmt.Errorf("fork tags: %w", err)
+		}
+		if err := c.checkRepositoriesQuota(tx); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ForkRepository(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	source := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "file1", nil, "")
+	m1, err := c.Commit(ctx, source, "master", "m1", "tester", nil)
+	testutil.MustDo(t, "commit m1", err)
+	testCatalogerBranch(t, ctx, c, source, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, source, "branch1", "file2", nil, "")
+	_, err = c.Commit(ctx, source, "branch1", "b1", "tester", nil)
+	testutil.MustDo(t, "commit b1", err)
+	_, err = c.Merge(ctx, source, "branch1", "master", "tester", "merge branch1", nil)
+	testutil.MustDo(t, "merge branch1", err)
+	_, err = c.CreateTag(ctx, source, "v1", m1.Reference, "tester", "v1", nil)
+	testutil.MustDo(t, "create tag", err)
+	testCatalogerCreateEntry(t, ctx, c, source, "branch1", "uncommitted", nil, "")
+
+	fork := testCatalogerUniqueID() + "-fork"
+	testutil.MustDo(t, "fork", c.ForkRepository(ctx, source, fork))
+
+	sourceRepo, err := c.GetRepository(ctx, source)
+	testutil.MustDo(t, "get source", err)
+	forkRepo, err := c.GetRepository(ctx, fork)
+	testutil.MustDo(t, "get fork", err)
+	if forkRepo.StorageNamespace != sourceRepo.StorageNamespace || forkRepo.DefaultBranch != "master" {
+		t.Errorf("forked repository %+v, expected the storage namespace and default branch of %+v", forkRepo, sourceRepo)
+	}
+	for _, branch := range []string{"master", "branch1"} {
+		sourceEntries, _, err := c.ListEntries(ctx, source, branch, "", "", "", -1)
+		testutil.MustDo(t, "list source entries", err)
+		forkEntries, _, err := c.ListEntries(ctx, fork, branch, "", "", "", -1)
+		testutil.MustDo(t, "list fork entries", err)
+		if len(forkEntries) != len(sourceEntries) {
+			t.Fatalf("fork %s has %d entries, expected %d", branch, len(forkEntries), len(sourceEntries))
+		}
+		for i := range sourceEntries {
+			if forkEntries[i].Path != sourceEntries[i].Path || forkEntries[i].PhysicalAddress != sourceEntries[i].PhysicalAddress {
+				t.Errorf("fork %s entry %d: %s at %s, expected %s at %s", branch, i,
+					forkEntries[i].Path, forkEntries[i].PhysicalAddress, sourceEntries[i].Path, sourceEntries[i].PhysicalAddress)
+			}
+		}
+		sourceCommits, _, err := c.ListCommits(ctx, source, branch, "", -1)
+		testutil.MustDo(t, "list source commits", err)
+		forkCommits, _, err := c.ListCommits(ctx, fork, branch, "", -1)
+		testutil.MustDo(t, "list fork commits", err)
+		if len(forkCommits) != len(sourceCommits) || forkCommits[0].Reference != sourceCommits[0].Reference {
+			t.Errorf("fork %s log has %d commits, head %s, expected %d commits, head %s", branch,
+				len(forkCommits), forkCommits[0].Reference, len(sourceCommits), sourceCommits[0].Reference)
+		}
+	}
+	tag, err := c.GetTag(ctx, fork, "v1")
+	testutil.MustDo(t, "get forked tag", err)
+	if tag.CommitReference != m1.Reference {
+		t.Errorf("forked tag points to %s, expected %s", tag.CommitReference, m1.Reference)
+	}
+
+	// the fork changes independently
+	testCatalogerCreateEntry(t, ctx, c, fork, "master", "fork-only", nil, "")
+	_, err = c.Commit(ctx, fork, "master", "fork commit", "tester", nil)
+	testutil.MustDo(t, "commit to fork", err)
+	if _, err := c.GetEntry(ctx, source, "master", "fork-only", GetEntryParams{}); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("fork entry found on source, err=%v", err)
+	}
+
+	if err := c.ForkRepository(ctx, source, fork); !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("ForkRepository() to existing repository err=%v, expected %s", err, db.ErrAlreadyExists)
+	}
+}
@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetBranch returns the branch last commit and whether the branch has uncommitted changes,
+// without reading the branch entries.
+func (c *cataloger) GetBranch(ctx context.Context, repository, branch string) (*BranchInfo, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+
+		var state struct {
+			CommitID     CommitID  `db:"commit_id"`
+			CreationDate time.Time `db:"creation_date"`
+			Dirty        bool      `db:"dirty"`
+			Version      int64     `db:"version"`
+		}
+		err = tx.Get(&state, `SELECT c.commit_id, c.creation_date,
+				EXISTS (SELECT 1 FROM catalog_entries WHERE branch_id=$1 AND min_commit=0) AS dirty,
+				(SELECT version FROM catalog_branches WHERE id=$1) AS version
+			FROM catalog_commits c WHERE c.branch_id=$1
+			ORDER BY c.commit_id DESC LIMIT 1`, branchID)
+		if err != nil {
+			return nil, err
+		}
+		return &BranchInfo{
+			Repository:      repository,
+			Name:            branch,
+			CommitReference: MakeReference(branch, state.CommitID),
+			CommitDate:      state.CreationDate,
+			Dirty:           state.Dirty,
+			Version:         state.Version,
+		}, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*BranchInfo), nil
+}
@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetBranchExpiryPolicy returns the branch expiry policy of repository, or
+// ErrBranchExpiryPolicyNotFound if none was set.
+func (c *cataloger) GetBranchExpiryPolicy(ctx context.Context, repository string) (*BranchExpiryPolicyWithCreationTime, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var policy BranchExpiryPolicyWithCreationTime
+		policy.CreatedAt, err = getRepositoryConfig(tx, repoID, branchExpiryPolicyConfigKey, &policy.BranchExpiryPolicy)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrBranchExpiryPolicyNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &policy, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*BranchExpiryPolicyWithCreationTime), nil
+}
@@ -2,9 +2,11 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
+	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/testutil"
 )
 
@@ -80,3 +82,39 @@ func TestCataloger_GetBranch(t *testing.T) {
 		})
 	}
 }
+
+func TestCataloger_GetBranchInfo(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	initialReference, err := c.GetBranchReference(ctx, repository, "master")
+	testutil.MustDo(t, "get branch reference", err)
+
+	branch, err := c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get new branch", err)
+	if branch.CommitReference != initialReference || branch.Dirty {
+		t.Errorf("GetBranch() = %+v, expected reference %s with clean workspace", branch, initialReference)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	branch, err = c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get dirty branch", err)
+	if branch.CommitReference != initialReference || !branch.Dirty {
+		t.Errorf("GetBranch() = %+v, expected reference %s with dirty workspace", branch, initialReference)
+	}
+
+	commitLog, err := c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	branch, err = c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get committed branch", err)
+	if branch.CommitReference != commitLog.Reference || branch.Dirty || !branch.CommitDate.Equal(commitLog.CreationDate) {
+		t.Errorf("GetBranch() = %+v, expected reference %s with clean workspace", branch, commitLog.Reference)
+	}
+
+	_, err = c.GetBranch(ctx, repository, "no-branch")
+	if !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("GetBranch() unknown branch err = %v, expected not found", err)
+	}
+}
@@ -6,7 +6,13 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
+// GetCommit returns the commit pointed by reference.  A branch committed reference ("branch:HEAD")
+// returns the last commit of the branch.  Reference expressions are resolved by ResolveReference.
 func (c *cataloger) GetCommit(ctx context.Context, repository, reference string) (*CommitLog, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "reference", IsValid: ValidateReference(reference)},
@@ -22,13 +28,20 @@ func (c *cataloger) GetCommit(ctx context.Context, repository, reference string)
 		if err != nil {
 			return nil, err
 		}
+		commitID := ref.CommitID
+		if commitID == CommittedID {
+			commitID, err = getLastCommitIDByBranchID(tx, branchID)
+			if err != nil {
+				return nil, err
+			}
+		}
 		query := `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
-			COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit
+			COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,c.squashed
 			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id 
 				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
 			WHERE b.id=$1 AND c.commit_id=$2`
 		var rawCommit commitLogRaw
-		if err := tx.Get(&rawCommit, query, branchID, ref.CommitID); err != nil {
+		if err := tx.Get(&rawCommit, query, branchID, commitID); err != nil {
 			return nil, err
 		}
 		commit := convertRawCommit(&rawCommit)
@@ -48,7 +61,8 @@ func convertRawCommit(raw *commitLogRaw) *CommitLog {
 		CreationDate: raw.CreationDate,
 		Metadata:     raw.Metadata,
 	}
-	if raw.MergeSourceBranchName != "" && raw.MergeSourceCommit > 0 {
+	// squashed merge commits are not part of the source branch history
+	if raw.MergeSourceBranchName != "" && raw.MergeSourceCommit > 0 && !raw.Squashed {
 		reference := MakeReference(raw.MergeSourceBranchName, raw.MergeSourceCommit)
 		c.Parents = append(c.Parents, reference)
 	}
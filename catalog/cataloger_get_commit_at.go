@@ -0,0 +1,50 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetCommitAt returns the last commit on branch that was created at or before ts - the commit
+// that represents the branch state at that time.
+func (c *cataloger) GetCommitAt(ctx context.Context, repository, branch string, ts time.Time) (*CommitLog, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		return getCommitAt(tx, branchID, ts)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*CommitLog), nil
+}
+
+func getCommitAt(tx db.Tx, branchID int64, ts time.Time) (*CommitLog, error) {
+	query := `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
+			COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,c.squashed
+			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
+			WHERE b.id=$1 AND c.creation_date <= $2
+			ORDER BY c.commit_id DESC
+			LIMIT 1`
+	var rawCommit commitLogRaw
+	err := tx.Get(&rawCommit, query, branchID, ts)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, ErrCommitNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertRawCommit(&rawCommit), nil
+}
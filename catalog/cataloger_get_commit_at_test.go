@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetCommitAt(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "tester", nil)
+	testutil.MustDo(t, "commit1", err)
+	time.Sleep(10 * time.Millisecond)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file2", nil, "")
+	commit2, err := c.Commit(ctx, repository, "master", "commit2", "tester", nil)
+	testutil.MustDo(t, "commit2", err)
+
+	tests := []struct {
+		name    string
+		ts      time.Time
+		want    string
+		wantErr error
+	}{
+		{name: "before repository", ts: commit1.CreationDate.Add(-time.Hour), wantErr: ErrCommitNotFound},
+		{name: "first commit", ts: commit1.CreationDate, want: commit1.Reference},
+		{name: "between commits", ts: commit2.CreationDate.Add(-time.Nanosecond), want: commit1.Reference},
+		{name: "last commit", ts: commit2.CreationDate, want: commit2.Reference},
+		{name: "future", ts: commit2.CreationDate.Add(time.Hour), want: commit2.Reference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetCommitAt(ctx, repository, "master", tt.ts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetCommitAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Reference != tt.want {
+				t.Errorf("GetCommitAt() reference = %s, want %s", got.Reference, tt.want)
+			}
+		})
+	}
+
+	// resolve using a timestamp reference
+	got, err := c.GetCommit(ctx, repository, "master@{"+commit2.CreationDate.Add(time.Second).Format(time.RFC3339)+"}")
+	testutil.MustDo(t, "get commit by timestamp reference", err)
+	if got.Reference != commit2.Reference {
+		t.Errorf("GetCommit() by timestamp reference = %s, want %s", got.Reference, commit2.Reference)
+	}
+}
@@ -78,6 +78,19 @@ func TestCataloger_GetCommit(t *testing.T) {
 			want:      nil,
 			wantErr:   true,
 		},
+		{
+			name:      "branch head",
+			reference: "master" + CommittedSuffix,
+			want: &CommitLog{
+				Reference:    "~KJ8Wd1Rs96a",
+				Committer:    "tester1",
+				Message:      "Commit1",
+				CreationDate: time.Now(),
+				Metadata:     Metadata{"k1": "v1"},
+				Parents:      []string{"~KJ8Wd1Rs96Z"},
+			},
+			wantErr: false,
+		},
 		{
 			name:      "branch",
 			reference: "master",
@@ -12,6 +12,10 @@ import (
 const useEntryReadBatched = true
 
 func (c *cataloger) GetEntry(ctx context.Context, repository, reference string, path string, params GetEntryParams) (*Entry, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "reference", IsValid: ValidateReference(reference)},
@@ -75,7 +79,7 @@ func (c *cataloger) getEntryMaybeExpired(ctx context.Context, repository string,
 		}
 
 		sql, args, err := psql.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired").
 			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
 			Where(sq.Eq{"path": path, "is_deleted": false}).
 			ToSql()
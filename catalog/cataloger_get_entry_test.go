@@ -2,7 +2,10 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
 )
 
 func TestCataloger_GetEntry(t *testing.T) {
@@ -107,6 +110,41 @@ func TestCataloger_GetEntry(t *testing.T) {
 	}
 }
 
+func TestCataloger_GetEntry_Expired(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := setupReadEntryData(t, ctx, c)
+
+	expireResults, err := readEntriesToExpire(t, ctx, c, repository, &Policy{Rules: []Rule{{
+		Enabled:      true,
+		FilterPrefix: "master//file1",
+		Expiration:   Expiration{All: makeHours(0)},
+	}}})
+	testutil.MustDo(t, "read entries to expire", err)
+	testutil.MustDo(t, "mark entries expired", c.MarkEntriesExpired(ctx, repository, expireResults))
+
+	for _, reference := range []string{"master", "master:HEAD"} {
+		t.Run(reference, func(t *testing.T) {
+			entry, err := c.GetEntry(ctx, repository, reference, "/file1", GetEntryParams{})
+			if !errors.Is(err, ErrExpired) {
+				t.Fatalf("GetEntry() of expired entry err=%v, expected %s", err, ErrExpired)
+			}
+			if entry == nil || entry.Path != "/file1" || !entry.Expired {
+				t.Errorf("GetEntry() of expired entry got %+v, expected expired /file1", entry)
+			}
+			entry, err = c.GetEntry(ctx, repository, reference, "/file1", GetEntryParams{ReturnExpired: true})
+			testutil.MustDo(t, "get expired entry", err)
+			if !entry.Expired {
+				t.Errorf("GetEntry() with ReturnExpired got %+v, expected expired entry", entry)
+			}
+			if _, err := c.GetEntry(ctx, repository, reference, "/file2", GetEntryParams{}); err != nil {
+				t.Errorf("GetEntry() of entry that did not expire: %s", err)
+			}
+		})
+	}
+}
+
 func setupReadEntryData(t *testing.T, ctx context.Context, c Cataloger) string {
 	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 	if err := c.CreateEntry(ctx, repository, "master", Entry{
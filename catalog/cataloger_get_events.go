@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const GetEventsMaxLimit = 1000
+
+// GetEvents returns the repository change feed: events after the event numbered 'after' (0 for
+// the start of the feed), ordered by the transaction that recorded them.  Passing the last
+// returned sequence number as 'after' resumes the feed.  Events are numbered by a global
+// sequence, so the numbers of a repository are not contiguous, and are returned only once every
+// transaction started before theirs completed - a transaction still in progress may record
+// events with lower numbers.
+func (c *cataloger) GetEvents(ctx context.Context, repository string, after int64, limit int) ([]*RepositoryEvent, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > GetEventsMaxLimit {
+		limit = GetEventsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var events []*RepositoryEvent
+		if err := tx.Select(&events, `
+			SELECT seq, event_type, branch, path, reference, creation_date
+			FROM catalog_repository_events
+			WHERE repository_id = $1
+				AND (txid, seq) > ((SELECT COALESCE(MAX(txid), 0) FROM catalog_repository_events WHERE repository_id = $1 AND seq = $2), $2)
+				AND txid < txid_snapshot_xmin(txid_current_snapshot())
+			ORDER BY txid, seq
+			LIMIT $3`,
+			repoID, after, limit+1); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	events := res.([]*RepositoryEvent)
+	hasMore := paginateSlice(&events, limit)
+	return events, hasMore, nil
+}
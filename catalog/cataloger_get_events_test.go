@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+func TestCataloger_GetEvents(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	if err != nil {
+		t.Fatal("commit:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	if err := c.DeleteEntry(ctx, repository, "branch1", "file1"); err != nil {
+		t.Fatal("delete entry:", err)
+	}
+	deletedHead, err := c.DeleteBranch(ctx, repository, "branch1")
+	if err != nil {
+		t.Fatal("delete branch:", err)
+	}
+
+	want := []RepositoryEvent{
+		{Seq: 1, Type: EventTypeObjectStaged, Branch: "master", Path: "file1"},
+		{Seq: 2, Type: EventTypeCommitCreated, Branch: "master", Reference: commitLog.Reference},
+		{Seq: 3, Type: EventTypeBranchCreated, Branch: "branch1", Reference: commitLog.Reference},
+		{Seq: 4, Type: EventTypeObjectDeleted, Branch: "branch1", Path: "file1"},
+		{Seq: 5, Type: EventTypeBranchDeleted, Branch: "branch1", Reference: deletedHead},
+	}
+	tests := []struct {
+		name       string
+		repository string
+		after      int64
+		limit      int
+		want       []RepositoryEvent
+		wantMore   bool
+		wantErr    error
+	}{
+		{name: "all", repository: repository, limit: -1, want: want},
+		{name: "limit", repository: repository, limit: 2, want: want[:2], wantMore: true},
+		{name: "after", repository: repository, after: 2, limit: 2, want: want[2:4], wantMore: true},
+		{name: "last", repository: repository, after: 4, limit: 2, want: want[4:]},
+		{name: "end", repository: repository, after: 5, limit: 2},
+		{name: "unknown repository", repository: "no-repo", limit: -1, wantErr: db.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.GetEvents(ctx, tt.repository, tt.after, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetEvents() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetEvents() got %d events, expected %d", len(got), len(tt.want))
+			}
+			for i, ev := range got {
+				if ev.CreationDate.IsZero() {
+					t.Errorf("GetEvents() event %d has no creation date", ev.Seq)
+				}
+				ev.CreationDate = tt.want[i].CreationDate
+				if fmt.Sprint(*ev) != fmt.Sprint(tt.want[i]) {
+					t.Errorf("GetEvents() event %d = %+v, expected %+v", i, *ev, tt.want[i])
+				}
+			}
+			if gotMore != tt.wantMore {
+				t.Errorf("GetEvents() hasMore = %t, expected %t", gotMore, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestCataloger_GetEvents_Batch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	const numEntries = 5
+	entries := make([]Entry, numEntries)
+	paths := make([]string, numEntries)
+	for i := range entries {
+		paths[i] = fmt.Sprintf("file%d", i)
+		entries[i] = Entry{Path: paths[i], PhysicalAddress: "addr" + paths[i], Checksum: "checksum", Size: int64(i)}
+	}
+	if err := c.CreateEntries(ctx, repository, "master", entries); err != nil {
+		t.Fatal("create entries:", err)
+	}
+	if err := c.DeleteEntries(ctx, repository, "master", paths); err != nil {
+		t.Fatal("delete entries:", err)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "last", nil, "")
+
+	events, hasMore, err := c.GetEvents(ctx, repository, 0, -1)
+	if err != nil {
+		t.Fatal("GetEvents:", err)
+	}
+	if hasMore {
+		t.Error("GetEvents() hasMore = true, expected false")
+	}
+	const expectedEvents = 2*numEntries + 1
+	if len(events) != expectedEvents {
+		t.Fatalf("GetEvents() got %d events, expected %d", len(events), expectedEvents)
+	}
+	for i, ev := range events {
+		expectedType := EventTypeObjectStaged
+		expectedPath := "last"
+		switch {
+		case i < numEntries:
+			expectedPath = paths[i]
+		case i < 2*numEntries:
+			expectedType = EventTypeObjectDeleted
+			expectedPath = paths[i-numEntries]
+		}
+		if ev.Seq != int64(i+1) || ev.Type != expectedType || ev.Path != expectedPath {
+			t.Errorf("GetEvents() event %d = %+v, expected seq %d %s %s", i, *ev, i+1, expectedType, expectedPath)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ExpiryReport describes what expiring repository entries according to a retention policy would
+// reclaim, without expiring anything
+type ExpiryReport struct {
+	// Entries is the number of entries that would expire, and LogicalBytes their total size
+	Entries      int64
+	LogicalBytes int64
+	// Objects is the number of objects that would be deleted from the underlying storage:
+	// objects all of whose entries expire, and objects no entry references any more.  Objects
+	// already marked for deletion, or whose entries all expired before, are not counted.
+	// PhysicalBytes is their total size, counting each object once.  The size of an
+	// unreferenced object is unknown, they are counted separately in UnreferencedObjects.
+	Objects             int64
+	UnreferencedObjects int64
+	PhysicalBytes       int64
+	// Commits are the commits whose entries expire, ordered by branch and commit
+	Commits []*ExpiryReportCommit
+}
+
+// ExpiryReportCommit holds the entries that would expire from a single commit.  Expiring
+// uncommitted entries are reported under their branch with an empty Reference.
+type ExpiryReportCommit struct {
+	Branch       string    `db:"branch"`
+	Reference    string    `db:"-"`
+	CommitID     CommitID  `db:"min_commit"`
+	Message      string    `db:"message"`
+	CreationDate time.Time `db:"creation_date"`
+	Entries      int64     `db:"entries"`
+	LogicalBytes int64     `db:"logical_bytes"`
+}
+
+// GetExpiryReport reports the entries and objects that expiring repository according to policy
+// would remove.  It is a dry run of expiry, and changes nothing.
+func (c *cataloger) GetExpiryReport(ctx context.Context, repository string, policy *Policy) (*ExpiryReport, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "policy", IsValid: func() bool { return policy != nil }},
+	}); err != nil {
+		return nil, err
+	}
+	expiryQuery, args, err := buildExpiryQuery(repository, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := getRepositoryID(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`CREATE TEMPORARY TABLE temp_expiry_report (
+				physical_address text, branch text NOT NULL, branch_id bigint NOT NULL, path text NOT NULL, min_commit bigint NOT NULL)
+			ON COMMIT DROP`); err != nil {
+			return nil, fmt.Errorf("creating temporary expiry table: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT INTO temp_expiry_report (physical_address, branch, branch_id, path, min_commit) `+expiryQuery,
+			args...); err != nil {
+			return nil, fmt.Errorf("querying entries to expire: %w", err)
+		}
+		// entries expired by a previous run are already invisible, and are not reported again
+		if _, err := tx.Exec(`DELETE FROM temp_expiry_report t USING catalog_entries e
+			WHERE e.branch_id = t.branch_id AND e.path = t.path AND e.min_commit = t.min_commit AND e.is_expired`); err != nil {
+			return nil, fmt.Errorf("removing expired entries: %w", err)
+		}
+
+		var commits []*ExpiryReportCommit
+		if err := tx.Select(&commits, `SELECT t.branch, t.min_commit,
+				COALESCE(c.message,'') AS message, COALESCE(c.creation_date, 'epoch') AS creation_date,
+				COUNT(*) AS entries, COALESCE(SUM(e.size),0) AS logical_bytes
+			FROM temp_expiry_report t
+				JOIN catalog_entries e ON e.branch_id = t.branch_id AND e.path = t.path AND e.min_commit = t.min_commit
+				LEFT JOIN catalog_commits c ON c.branch_id = t.branch_id AND c.commit_id = t.min_commit
+			GROUP BY t.branch, t.min_commit, c.message, c.creation_date
+			ORDER BY t.branch, t.min_commit`); err != nil {
+			return nil, fmt.Errorf("summarize commits: %w", err)
+		}
+		report := &ExpiryReport{Commits: commits}
+		for _, commit := range commits {
+			if commit.CommitID != UncommittedID {
+				commit.Reference = MakeReference(commit.Branch, commit.CommitID)
+			}
+			report.Entries += commit.Entries
+			report.LogicalBytes += commit.LogicalBytes
+		}
+
+		// the objects of the repository that only expiring or expired entries reference, and
+		// at least one expiring entry or none at all.  Objects marked for deletion are already
+		// reclaimed.
+		var objects struct {
+			Objects             int64 `db:"objects"`
+			UnreferencedObjects int64 `db:"unreferenced_objects"`
+			PhysicalBytes       int64 `db:"physical_bytes"`
+		}
+		if err := tx.Get(&objects, `SELECT COUNT(*) AS objects,
+				COUNT(*) FILTER (WHERE s.size IS NULL) AS unreferenced_objects,
+				COALESCE(SUM(s.size),0) AS physical_bytes
+			FROM catalog_object_dedup d
+				JOIN catalog_repositories r ON r.id = d.repository_id
+				LEFT JOIN LATERAL (SELECT e.size FROM catalog_entries e
+					WHERE `+objectEntriesCondition+` LIMIT 1) s ON true
+			WHERE d.repository_id = $1 AND NOT d.deleting
+				AND NOT EXISTS (SELECT 1 FROM catalog_entries e
+					WHERE `+objectEntriesCondition+` AND NOT e.is_expired
+						AND NOT EXISTS (SELECT 1 FROM temp_expiry_report t
+							WHERE t.branch_id = e.branch_id AND t.path = e.path AND t.min_commit = e.min_commit))
+				AND (s.size IS NULL OR EXISTS (SELECT 1 FROM temp_expiry_report t JOIN catalog_entries e
+					ON t.branch_id = e.branch_id AND t.path = e.path AND t.min_commit = e.min_commit
+					WHERE `+objectEntriesCondition+`))`,
+			repoID); err != nil {
+			return nil, fmt.Errorf("summarize objects: %w", err)
+		}
+		report.Objects = objects.Objects
+		report.UnreferencedObjects = objects.UnreferencedObjects
+		report.PhysicalBytes = objects.PhysicalBytes
+		return report, nil
+	}, c.txOpts(ctx, db.WithIsolationLevel(sql.LevelRepeatableRead))...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ExpiryReport), nil
+}
@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetExpiryReport(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	makeDedup := func(id int) CreateEntryParams {
+		return CreateEntryParams{
+			Dedup: DedupParams{
+				ID:               fmt.Sprintf("%08x", id),
+				StorageNamespace: "foo",
+			},
+		}
+	}
+	entries := []struct {
+		branch string
+		entry  Entry
+		params CreateEntryParams
+	}{
+		{branch: "master", entry: Entry{Path: "old/1", PhysicalAddress: "old-1", Checksum: "aa", Size: 10, CreationDate: time.Now().Add(-20 * time.Hour)}, params: makeDedup(111)},
+		{branch: "master", entry: Entry{Path: "new/1", PhysicalAddress: "new-1", Checksum: "bb", Size: 5}, params: makeDedup(222)},
+		{branch: "master", entry: Entry{Path: "gone/1", PhysicalAddress: "gone-1", Checksum: "dd", Size: 3, CreationDate: time.Now().Add(-20 * time.Hour)}, params: makeDedup(444)},
+		{branch: "branch1", entry: Entry{Path: "reset/1", PhysicalAddress: "reset-me", Checksum: "cc", Size: 7}, params: makeDedup(333)},
+	}
+	for _, e := range entries {
+		if err := c.CreateEntry(ctx, repository, e.branch, e.entry, e.params); err != nil {
+			t.Fatalf("failed to set up entry %v: %s", e, err)
+		}
+	}
+	for i := range entries {
+		select {
+		case <-c.DedupReportChannel():
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for dedup report %v", i)
+		}
+	}
+	commitLog, err := c.Commit(ctx, repository, "master", "commit old", "tester", nil)
+	testutil.MustDo(t, "commit master", err)
+	// drop the only entry that references reset-me
+	testutil.MustDo(t, "reset branch1", c.ResetBranch(ctx, repository, "branch1"))
+
+	policy := &Policy{Rules: []Rule{{
+		Enabled:      true,
+		FilterPrefix: "master/old/",
+		Expiration:   Expiration{All: makeHours(10)},
+	}}}
+	report, err := c.GetExpiryReport(ctx, repository, policy)
+	testutil.MustDo(t, "get expiry report", err)
+	if len(report.Commits) != 1 {
+		t.Fatalf("GetExpiryReport() reported %d commits, expected 1", len(report.Commits))
+	}
+	report.Commits[0].CreationDate = time.Time{}
+	expected := &ExpiryReport{
+		Entries:             1,
+		LogicalBytes:        10,
+		Objects:             2,
+		UnreferencedObjects: 1,
+		PhysicalBytes:       10,
+		Commits: []*ExpiryReportCommit{{
+			Branch:       "master",
+			Reference:    commitLog.Reference,
+			CommitID:     report.Commits[0].CommitID,
+			Message:      "commit old",
+			Entries:      1,
+			LogicalBytes: 10,
+		}},
+	}
+	if diffs := deep.Equal(report, expected); diffs != nil {
+		t.Errorf("GetExpiryReport() diff %s", diffs)
+	}
+
+	// a dry run expires nothing
+	if _, err := c.GetEntry(ctx, repository, "master", "old/1", GetEntryParams{}); err != nil {
+		t.Errorf("get entry after expiry report: %s", err)
+	}
+
+	// objects whose entries all expired before are not reported
+	expireResults, err := readEntriesToExpire(t, ctx, c, repository, &Policy{Rules: []Rule{{
+		Enabled:      true,
+		FilterPrefix: "master/gone/",
+		Expiration:   Expiration{All: makeHours(10)},
+	}}})
+	testutil.MustDo(t, "read entries to expire", err)
+	testutil.MustDo(t, "mark entries expired", c.MarkEntriesExpired(ctx, repository, expireResults))
+	report, err = c.GetExpiryReport(ctx, repository, policy)
+	testutil.MustDo(t, "get expiry report after expiry", err)
+	if report.Objects != 2 || report.UnreferencedObjects != 1 || report.PhysicalBytes != 10 {
+		t.Errorf("GetExpiryReport() after expiry objects=%d unreferenced=%d bytes=%d, expected 2, 1, 10",
+			report.Objects, report.UnreferencedObjects, report.PhysicalBytes)
+	}
+
+	// objects marked for deletion are not reported
+	_, err = c.MarkObjectsForDeletion(ctx, repository)
+	testutil.MustDo(t, "mark objects for deletion", err)
+	report, err = c.GetExpiryReport(ctx, repository, policy)
+	testutil.MustDo(t, "get expiry report after marking objects", err)
+	if report.Objects != 1 || report.UnreferencedObjects != 0 || report.PhysicalBytes != 10 {
+		t.Errorf("GetExpiryReport() after marking objects objects=%d unreferenced=%d bytes=%d, expected 1, 0, 10",
+			report.Objects, report.UnreferencedObjects, report.PhysicalBytes)
+	}
+}
@@ -0,0 +1,39 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetReplicationCheckpoint returns the checkpoint of the last replication stream applied to the
+// replica repository, the commit to export the next stream after.  Returns
+// ErrOperationNotPermitted if repository is not a replica.
+func (c *cataloger) GetReplicationCheckpoint(ctx context.Context, repository string) (int64, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return 0, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := getRepositoryID(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var checkpoint int64
+		_, err = getRepositoryConfig(tx, repoID, replicationCheckpointConfigKey, &checkpoint)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%w: repository %s is not a replica", ErrOperationNotPermitted, repository)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return checkpoint, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return 0, err
+	}
+	return res.(int64), nil
+}
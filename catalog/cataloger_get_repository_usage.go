@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetRepositoryUsage returns the branches and objects of repository, as counted against its quotas
+func (c *cataloger) GetRepositoryUsage(ctx context.Context, repository string) (*RepositoryUsage, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var usage RepositoryUsage
+		usage.Branches, err = getRepositoryBranchesUsage(tx, repoID)
+		if err != nil {
+			return nil, fmt.Errorf("count branches: %w", err)
+		}
+		usage.Objects, usage.SizeBytes, err = getRepositoryObjectsUsage(tx, repoID)
+		if err != nil {
+			return nil, fmt.Errorf("count objects: %w", err)
+		}
+		return &usage, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*RepositoryUsage), nil
+}
@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) GetTag(ctx context.Context, repository, tagName string) (*Tag, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "tag", IsValid: ValidateTagName(tagName)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		query, args, err := sqTags(repoID).Where(sq.Eq{"t.name": tagName}).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var raw tagRaw
+		err = tx.Get(&raw, query, args...)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrTagNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return raw.toTag(), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Tag), nil
+}
@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// GetWorkspaceExpiryPolicy returns the workspace expiry policy of repository, or
+// ErrWorkspaceExpiryPolicyNotFound if none was set.
+func (c *cataloger) GetWorkspaceExpiryPolicy(ctx context.Context, repository string) (*WorkspaceExpiryPolicyWithCreationTime, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var policy WorkspaceExpiryPolicyWithCreationTime
+		policy.CreatedAt, err = getRepositoryConfig(tx, repoID, workspaceExpiryPolicyConfigKey, &policy.WorkspaceExpiryPolicy)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrWorkspaceExpiryPolicyNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &policy, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*WorkspaceExpiryPolicyWithCreationTime), nil
+}
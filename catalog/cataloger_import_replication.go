@@ -0,0 +1,298 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ImportReplication applies a replication stream written by ExportReplication to repository, the
+// replica.  The first stream, exported from commit 0, creates the replica as a read-only
+// repository with the storage namespace of the primary.  Each following stream must start at the
+// checkpoint of the stream applied before it.  Commits keep their IDs, so references are valid on
+// both repositories.  Returns the checkpoint of the replica.  A replica that was made writable is
+// promoted, and does not accept streams anymore.
+func (c *cataloger) ImportReplication(ctx context.Context, repository string, r io.Reader) (int64, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return 0, err
+	}
+	rr, err := newReplicationReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	// the stream is applied while it is read, so the transaction cannot be retried
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		// the default branch of the replica is set once its branches are in place
+		if _, err := tx.Exec(`SET CONSTRAINTS catalog_repositories_branches_id_fk DEFERRED`); err != nil {
+			return nil, fmt.Errorf("set constraints: %w", err)
+		}
+		repoID, err := c.replicaRepositoryID(tx, repository, rr.Header)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyReplicationStream(tx, repoID, rr, nil); err != nil {
+			return nil, err
+		}
+		return nil, setRepositoryConfig(tx, repoID, replicationCheckpointConfigKey, rr.Header.Checkpoint,
+			"replicated from "+rr.Header.Repository)
+	}, c.txOpts(ctx, db.WithMaxAttempts(1))...)
+	if err != nil {
+		return 0, err
+	}
+	return int64(rr.Header.Checkpoint), nil
+}
+
+// applyReplicationStream makes the branches and tags of the repository match the stream read by
+// rr, and adds the commits and entries of the stream.  Each record is passed to prepare, when set,
+// and applied as it is read.  The branches lead the stream and are applied together, before the
+// records that reference them.  Fails when the digest of the stream does not match, so the
+// transaction is rolled back.
+func applyReplicationStream(tx db.Tx, repoID int, rr *replicationReader, prepare func(rec *replicationRecord) error) error {
+	// tags are replaced by the stream, and are removed first as they reference the commits of
+	// deleted branches
+	if _, err := tx.Exec(`DELETE FROM catalog_tags WHERE repository_id = $1`, repoID); err != nil {
+		return fmt.Errorf("delete tags: %w", err)
+	}
+	var branches []*replicationBranch
+	var branchIDs map[string]int64
+	for {
+		rec, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if prepare != nil {
+			if err := prepare(rec); err != nil {
+				return err
+			}
+		}
+		if rec.Type == replicationRecordBranch {
+			if branchIDs != nil {
+				return fmt.Errorf("%w: branch %s after the branches of the stream", ErrInvalidValue, rec.Branch.Name)
+			}
+			branches = append(branches, rec.Branch)
+			continue
+		}
+		if branchIDs == nil {
+			branchIDs, err = importReplicationBranches(tx, repoID, branches)
+			if err != nil {
+				return err
+			}
+		}
+		if err := importReplicationRecord(tx, repoID, branchIDs, rec); err != nil {
+			return err
+		}
+	}
+	if branchIDs == nil {
+		var err error
+		branchIDs, err = importReplicationBranches(tx, repoID, branches)
+		if err != nil {
+			return err
+		}
+	}
+
+	defaultBranchID, ok := branchIDs[rr.Header.DefaultBranch]
+	if !ok {
+		return fmt.Errorf("default branch %s: %w", rr.Header.DefaultBranch, ErrBranchNotFound)
+	}
+	if _, err := tx.Exec(`UPDATE catalog_repositories SET default_branch = $2 WHERE id = $1`, repoID, defaultBranchID); err != nil {
+		return fmt.Errorf("update default branch: %w", err)
+	}
+	// later commits, such as the commits of a promoted replica, must not reuse the IDs of the stream
+	if _, err := tx.Exec(`SELECT setval('catalog_commit_id_seq', $1::bigint) FROM catalog_commit_id_seq WHERE last_value < $1::bigint`,
+		rr.Header.Checkpoint); err != nil {
+		return fmt.Errorf("advance commit sequence: %w", err)
+	}
+	return nil
+}
+
+// importReplicationRecord applies a commit, entry or tag record of the stream
+func importReplicationRecord(tx db.Tx, repoID int, branchIDs map[string]int64, rec *replicationRecord) error {
+	switch rec.Type {
+	case replicationRecordCommit:
+		return importReplicationCommit(tx, branchIDs, rec.Commit)
+	case replicationRecordEntry:
+		return importReplicationEntry(tx, branchIDs, rec.Entry)
+	case replicationRecordEntryClosed:
+		return closeReplicationEntry(tx, branchIDs, rec.Entry)
+	case replicationRecordTag:
+		return importReplicationTag(tx, repoID, branchIDs, rec.Tag)
+	default:
+		return fmt.Errorf("%w: record type %s", ErrInvalidValue, rec.Type)
+	}
+}
+
+// replicaRepositoryID returns the ID of the replica repository after verifying the stream starts
+// at its checkpoint, creating the replica for a stream that starts at commit 0
+func (c *cataloger) replicaRepositoryID(tx db.Tx, repository string, header replicationHeader) (int, error) {
+	repoID, err := getRepositoryID(tx, repository)
+	if errors.Is(err, db.ErrNotFound) {
+		if header.After != 0 {
+			return 0, fmt.Errorf("%w: replica %s does not exist, stream starts after commit %d",
+				ErrReplicationCheckpoint, repository, header.After)
+		}
+		if err := tx.Get(&repoID, `INSERT INTO catalog_repositories (id,name,storage_namespace,creation_date,default_branch,read_only,hash_algorithm)
+			VALUES (nextval('catalog_repositories_id_seq'), $1, $2, transaction_timestamp(), 0, true, $3)
+			RETURNING id`,
+			repository, header.StorageNamespace, header.hashAlgorithm()); err != nil {
+			return 0, fmt.Errorf("insert repository: %w", err)
+		}
+		if err := c.checkRepositoriesQuota(tx); err != nil {
+			return 0, err
+		}
+		return repoID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var checkpoint CommitID
+	_, err = getRepositoryConfig(tx, repoID, replicationCheckpointConfigKey, &checkpoint)
+	if errors.Is(err, db.ErrNotFound) {
+		return 0, fmt.Errorf("%w: repository %s is not a replica", ErrOperationNotPermitted, repository)
+	}
+	if err != nil {
+		return 0, err
+	}
+	var readOnly bool
+	if err := tx.Get(&readOnly, `SELECT read_only FROM catalog_repositories WHERE id = $1 FOR UPDATE`, repoID); err != nil {
+		return 0, err
+	}
+	if !readOnly {
+		return 0, fmt.Errorf("%w: replica %s was promoted", ErrOperationNotPermitted, repository)
+	}
+	if checkpoint != header.After {
+		return 0, fmt.Errorf("%w: replica %s is at commit %d, stream starts after commit %d",
+			ErrReplicationCheckpoint, repository, checkpoint, header.After)
+	}
+	return repoID, nil
+}
+
+// importReplicationBranches makes the branches of the replica match the branches of the stream,
+// and returns the replica branch ID of each branch name.  A branch that was deleted and created
+// again on the primary is deleted and created again on the replica, with its history.
+func importReplicationBranches(tx db.Tx, repoID int, branches []*replicationBranch) (map[string]int64, error) {
+	var existing []struct {
+		ID          int64    `db:"id"`
+		Name        string   `db:"name"`
+		FirstCommit CommitID `db:"first_commit"`
+	}
+	if err := tx.Select(&existing, `SELECT b.id, b.name,
+			COALESCE((SELECT MIN(c.commit_id) FROM catalog_commits c WHERE c.branch_id = b.id), 0) AS first_commit
+		FROM catalog_branches b WHERE b.repository_id = $1`, repoID); err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	wanted := make(map[string]*replicationBranch, len(branches))
+	for _, branch := range branches {
+		wanted[branch.Name] = branch
+	}
+	branchIDs := make(map[string]int64, len(branches))
+	for _, b := range existing {
+		if branch, ok := wanted[b.Name]; ok && branch.FirstCommit == b.FirstCommit {
+			branchIDs[b.Name] = b.ID
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM catalog_branches WHERE id = $1`, b.ID); err != nil {
+			return nil, fmt.Errorf("delete branch %s: %w", b.Name, err)
+		}
+	}
+	for _, branch := range branches {
+		if _, ok := branchIDs[branch.Name]; ok {
+			continue
+		}
+		var id int64
+		if err := tx.Get(&id, `INSERT INTO catalog_branches (repository_id,name) VALUES ($1,$2) RETURNING id`,
+			repoID, branch.Name); err != nil {
+			return nil, fmt.Errorf("insert branch %s: %w", branch.Name, err)
+		}
+		branchIDs[branch.Name] = id
+	}
+	for _, branch := range branches {
+		lineage := make([]string, len(branch.Lineage))
+		for i, name := range branch.Lineage {
+			id, ok := branchIDs[name]
+			if !ok {
+				return nil, fmt.Errorf("branch %s lineage %s: %w", branch.Name, name, ErrBranchNotFound)
+			}
+			lineage[i] = strconv.FormatInt(id, 10)
+		}
+		if _, err := tx.Exec(`UPDATE catalog_branches SET lineage = string_to_array($2,',')::bigint[] WHERE id = $1`,
+			branchIDs[branch.Name], strings.Join(lineage, ",")); err != nil {
+			return nil, fmt.Errorf("update branch %s lineage: %w", branch.Name, err)
+		}
+	}
+	return branchIDs, nil
+}
+
+func importReplicationCommit(tx db.Tx, branchIDs map[string]int64, commit *replicationCommit) error {
+	branchID, ok := branchIDs[commit.Branch]
+	if !ok {
+		return fmt.Errorf("commit %d: branch %s: %w", commit.CommitID, commit.Branch, ErrBranchNotFound)
+	}
+	// the merge source branch may have been deleted on the primary
+	var mergeSourceBranch interface{}
+	if id, ok := branchIDs[commit.MergeSourceBranch]; ok {
+		mergeSourceBranch = id
+	}
+	if _, err := tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
+			creation_date,metadata,merge_type,merge_source_branch,merge_source_commit,lineage_commits,squashed)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8::catalog_merge_type,$9,NULLIF($10::bigint,0),string_to_array($11,',')::bigint[],$12)
+		ON CONFLICT (branch_id,commit_id) DO NOTHING`,
+		branchID, commit.CommitID, commit.PreviousCommitID, commit.Committer, commit.Message,
+		commit.CreationDate, commit.Metadata, commit.MergeType, mergeSourceBranch, commit.MergeSourceCommit,
+		formatCommitIDs(commit.LineageCommits), commit.Squashed); err != nil {
+		return fmt.Errorf("insert commit %d: %w", commit.CommitID, err)
+	}
+	return nil
+}
+
+func importReplicationEntry(tx db.Tx, branchIDs map[string]int64, ent *replicationEntry) error {
+	branchID, ok := branchIDs[ent.Branch]
+	if !ok {
+		return fmt.Errorf("entry %s: branch %s: %w", ent.Path, ent.Branch, ErrBranchNotFound)
+	}
+	if _, err := tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,
+			content_type,metadata,min_commit,max_commit)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+		ON CONFLICT (branch_id,path,min_commit) DO UPDATE SET max_commit = EXCLUDED.max_commit`,
+		branchID, ent.Path, ent.PhysicalAddress, ent.CreationDate, ent.Size, ent.Checksum,
+		ent.ContentType, ent.Metadata, ent.MinCommit, ent.MaxCommit); err != nil {
+		return fmt.Errorf("insert entry %s: %w", ent.Path, err)
+	}
+	return nil
+}
+
+// closeReplicationEntry sets the max commit of an entry replicated by a previous stream
+func closeReplicationEntry(tx db.Tx, branchIDs map[string]int64, ent *replicationEntry) error {
+	branchID, ok := branchIDs[ent.Branch]
+	if !ok {
+		return fmt.Errorf("entry %s: branch %s: %w", ent.Path, ent.Branch, ErrBranchNotFound)
+	}
+	if _, err := tx.Exec(`UPDATE catalog_entries SET max_commit = $4 WHERE branch_id = $1 AND path = $2 AND min_commit = $3`,
+		branchID, ent.Path, ent.MinCommit, ent.MaxCommit); err != nil {
+		return fmt.Errorf("close entry %s: %w", ent.Path, err)
+	}
+	return nil
+}
+
+func importReplicationTag(tx db.Tx, repoID int, branchIDs map[string]int64, tag *replicationTag) error {
+	branchID, ok := branchIDs[tag.Branch]
+	if !ok {
+		return fmt.Errorf("tag %s: branch %s: %w", tag.Name, tag.Branch, ErrBranchNotFound)
+	}
+	if _, err := tx.Exec(`INSERT INTO catalog_tags (repository_id,name,id,branch_id,commit_id,tagger,message,metadata,creation_date)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+		repoID, tag.Name, tag.ID, branchID, tag.CommitID, tag.Tagger, tag.Message, tag.Metadata, tag.CreationDate); err != nil {
+		return fmt.Errorf("insert tag %s: %w", tag.Name, err)
+	}
+	return nil
+}
@@ -26,7 +26,7 @@ func (c *cataloger) ListBranches(ctx context.Context, repository string, prefix
 
 		query := `SELECT $2 AS repository, name
 			FROM catalog_branches
-			WHERE repository_id = $1 AND name like $3 AND name > $4
+			WHERE repository_id = $1 AND name like $3 AND name > $4 AND name NOT LIKE '` + deletedBranchPrefix + `%'
 			ORDER BY name
 			LIMIT $5`
 		var branches []*Branch
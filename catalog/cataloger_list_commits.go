@@ -3,21 +3,76 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/treeverse/lakefs/db"
 )
 
 const ListCommitsMaxLimit = 10000
 
-func (c *cataloger) ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int) ([]*CommitLog, bool, error) {
+// ListCommitsOptions filters the commits returned by ListCommits.  Empty fields match all
+// commits.
+type ListCommitsOptions struct {
+	// Prefix matches commits that created, overwrote or deleted an entry under the path prefix
+	Prefix string
+	// Committer matches commits by the committer
+	Committer string
+	// Since matches commits created at or after the time
+	Since time.Time
+	// Until matches commits created before the time
+	Until time.Time
+}
+
+type ListCommitsOption func(*ListCommitsOptions)
+
+func WithPathPrefix(prefix string) ListCommitsOption {
+	return func(o *ListCommitsOptions) {
+		o.Prefix = prefix
+	}
+}
+
+func WithCommitter(committer string) ListCommitsOption {
+	return func(o *ListCommitsOptions) {
+		o.Committer = committer
+	}
+}
+
+func WithTimeRange(since, until time.Time) ListCommitsOption {
+	return func(o *ListCommitsOptions) {
+		o.Since = since
+		o.Until = until
+	}
+}
+
+// ListCommits lists the commit log of reference, newest first.  Listing the log of a branch starts
+// from its last commit, while listing the log of a commit reference starts from that commit.  The
+// log includes the history of merged commits.  Commit IDs are allocated in order, so a commit is
+// always listed before its parents.
+// fromReference is the commit reference the listing continues after.  Filters set by opts are
+// applied by the query, so a filtered log does not scan the commits it does not return.
+func (c *cataloger) ListCommits(ctx context.Context, repository, reference string, fromReference string, limit int, opts ...ListCommitsOption) ([]*CommitLog, bool, error) {
+	var options ListCommitsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
-		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
 		{Name: "fromReference", IsValid: ValidateOptionalString(fromReference, IsValidReference)},
 	}); err != nil {
 		return nil, false, err
 	}
-	ref, err := ParseRef(fromReference)
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, false, err
+	}
+	afterRef, err := ParseRef(fromReference)
 	if err != nil {
 		return nil, false, err
 	}
@@ -27,10 +82,13 @@ func (c *cataloger) ListCommits(ctx context.Context, repository, branch string,
 	// we start from the newest to the oldest
 	fromCommitID := MaxCommitID
 	if ref.CommitID > 0 {
-		fromCommitID = ref.CommitID
+		fromCommitID = ref.CommitID + 1
+	}
+	if afterRef.CommitID > 0 && afterRef.CommitID < fromCommitID {
+		fromCommitID = afterRef.CommitID
 	}
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
 		if err != nil {
 			return nil, err
 		}
@@ -39,24 +97,25 @@ func (c *cataloger) ListCommits(ctx context.Context, repository, branch string,
 			return nil, fmt.Errorf("get lineage: %w", err)
 		}
 		lineageAsValuesTable := getLineageAsValues(lineage, branchID, fromCommitID)
+		args := []interface{}{fromCommitID}
 		cte := `WITH RECURSIVE lineage_graph AS (
     select branch_id,commit_id from ` + lineageAsValuesTable + `
 	union all
 	select * from (Select distinct on (c.branch_id,c.merge_source_branch) merge_source_branch,merge_source_commit from catalog_commits c 
-	join lineage_graph l on l.branch_id = c.branch_id and c.merge_type='from_child'  and c.merge_source_commit < l.commit_id
+	join lineage_graph l on l.branch_id = c.branch_id and c.merge_type='from_child' and not c.squashed and c.merge_source_commit < l.commit_id
 	order by c.branch_id,c.merge_source_branch,c.commit_id desc )t)
 `
 		query := cte + `SELECT b_name.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
-				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit
+				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,c.squashed
 			FROM catalog_commits c JOIN lineage_graph l  ON  c.branch_id = l.branch_id and c.commit_id <= l.commit_id
 				JOIN catalog_branches b_name ON c.branch_id = b_name.id
 				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
-			WHERE c.commit_id < $1
+			WHERE c.commit_id < $1` + listCommitsFilter(options, &args) + `
 			ORDER BY c.commit_id DESC
-			LIMIT $2`
+			LIMIT ` + addArg(&args, limit+1)
 
 		var rawCommits []*commitLogRaw
-		if err := tx.Select(&rawCommits, query, fromCommitID, limit+1); err != nil {
+		if err := tx.Select(&rawCommits, query, args...); err != nil {
 			return nil, err
 		}
 		commits := convertRawCommits(rawCommits)
@@ -71,6 +130,34 @@ func (c *cataloger) ListCommits(ctx context.Context, repository, branch string,
 	return commits, hasMore, err
 }
 
+// listCommitsFilter returns the conditions on commits "c" that apply options, adding their
+// arguments to args.  A commit touched the prefix if it committed an entry under the prefix
+// (min_commit) or closed one (max_commit is set to the previous commit of the branch).
+func listCommitsFilter(options ListCommitsOptions, args *[]interface{}) string {
+	var sb strings.Builder
+	if options.Prefix != "" {
+		sb.WriteString(` AND EXISTS (SELECT 1 FROM catalog_entries e
+				WHERE e.branch_id = c.branch_id AND e.path LIKE ` + addArg(args, db.Prefix(options.Prefix)) + `
+					AND (e.min_commit = c.commit_id OR e.max_commit = c.previous_commit_id))`)
+	}
+	if options.Committer != "" {
+		sb.WriteString(" AND c.committer = " + addArg(args, options.Committer))
+	}
+	if !options.Since.IsZero() {
+		sb.WriteString(" AND c.creation_date >= " + addArg(args, options.Since))
+	}
+	if !options.Until.IsZero() {
+		sb.WriteString(" AND c.creation_date < " + addArg(args, options.Until))
+	}
+	return sb.String()
+}
+
+// addArg appends v to args and returns its positional placeholder
+func addArg(args *[]interface{}, v interface{}) string {
+	*args = append(*args, v)
+	return "$" + strconv.Itoa(len(*args))
+}
+
 func convertRawCommits(rawCommits []*commitLogRaw) []*CommitLog {
 	commits := make([]*CommitLog, len(rawCommits))
 	for i, commit := range rawCommits {
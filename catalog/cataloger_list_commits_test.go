@@ -26,7 +26,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 
 	type args struct {
 		repository    string
-		branch        string
+		reference     string
 		fromReference string
 		limit         int
 	}
@@ -41,7 +41,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "all",
 			args: args{
 				repository:    repository,
-				branch:        "master",
+				reference:     "master",
 				fromReference: "",
 				limit:         -1,
 			},
@@ -58,7 +58,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "just 2",
 			args: args{
 				repository:    repository,
-				branch:        "master",
+				reference:     "master",
 				fromReference: "",
 				limit:         2,
 			},
@@ -73,7 +73,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "get last commit",
 			args: args{
 				repository:    repository,
-				branch:        "master",
+				reference:     "master",
 				fromReference: commits[0].Reference,
 				limit:         1,
 			},
@@ -87,7 +87,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "center",
 			args: args{
 				repository:    repository,
-				branch:        "master",
+				reference:     "master",
 				fromReference: commits[2].Reference,
 				limit:         1,
 			},
@@ -97,11 +97,41 @@ func TestCataloger_ListCommits(t *testing.T) {
 			wantMore: true,
 			wantErr:  false,
 		},
+		{
+			name: "from commit",
+			args: args{
+				repository:    repository,
+				reference:     commits[1].Reference,
+				fromReference: "",
+				limit:         -1,
+			},
+			want: []*CommitLog{
+				{Reference: commits[1].Reference, Committer: "tester", Message: "commit2 on branch master", Metadata: Metadata{}, Parents: []string{"~KJ8Wd1Rs96Z"}},
+				{Reference: commits[0].Reference, Committer: "tester", Message: "commit1 on branch master", Metadata: Metadata{}, Parents: []string{"~KJ8Wd1Rs96Y"}},
+				{Reference: initialCommitReference, Committer: CatalogerCommitter, Message: createRepositoryCommitMessage, Metadata: Metadata{}},
+			},
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name: "from commit after",
+			args: args{
+				repository:    repository,
+				reference:     commits[1].Reference,
+				fromReference: commits[1].Reference,
+				limit:         1,
+			},
+			want: []*CommitLog{
+				{Reference: commits[0].Reference, Committer: "tester", Message: "commit1 on branch master", Metadata: Metadata{}, Parents: []string{"~KJ8Wd1Rs96Y"}},
+			},
+			wantMore: true,
+			wantErr:  false,
+		},
 		{
 			name: "unknown repository",
 			args: args{
 				repository:    "no_repo",
-				branch:        "master",
+				reference:     "master",
 				fromReference: "",
 				limit:         -1,
 			},
@@ -113,7 +143,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "no repository",
 			args: args{
 				repository:    "",
-				branch:        "master",
+				reference:     "master",
 				fromReference: "",
 				limit:         -1,
 			},
@@ -125,7 +155,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 			name: "no branch",
 			args: args{
 				repository:    repository,
-				branch:        "",
+				reference:     "",
 				fromReference: "",
 				limit:         -1,
 			},
@@ -136,7 +166,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotMore, err := c.ListCommits(ctx, tt.args.repository, tt.args.branch, tt.args.fromReference, tt.args.limit)
+			got, gotMore, err := c.ListCommits(ctx, tt.args.repository, tt.args.reference, tt.args.fromReference, tt.args.limit)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("ListCommits() error = %s, wantErr %t", err, tt.wantErr)
 			}
@@ -463,3 +493,69 @@ func TestCataloger_ListCommits_Order(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCataloger_ListCommits_Filter(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "data/a", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "add data", "alice", nil)
+	testutil.MustDo(t, "commit add data", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "logs/a", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "add logs", "bob", nil)
+	testutil.MustDo(t, "commit add logs", err)
+	testutil.MustDo(t, "delete data/a", c.DeleteEntry(ctx, repository, "master", "data/a"))
+	deleteCommit, err := c.Commit(ctx, repository, "master", "delete data", "bob", nil)
+	testutil.MustDo(t, "commit delete data", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "data/b", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "add data on branch", "alice", nil)
+	testutil.MustDo(t, "commit add data on branch", err)
+
+	tests := []struct {
+		name string
+		opts []ListCommitsOption
+		want []string
+	}{
+		{
+			name: "prefix",
+			opts: []ListCommitsOption{WithPathPrefix("data/")},
+			want: []string{"add data on branch", "delete data", "add data"},
+		},
+		{
+			name: "committer",
+			opts: []ListCommitsOption{WithCommitter("bob")},
+			want: []string{"delete data", "add logs"},
+		},
+		{
+			name: "prefix and committer",
+			opts: []ListCommitsOption{WithPathPrefix("data/"), WithCommitter("alice")},
+			want: []string{"add data on branch", "add data"},
+		},
+		{
+			name: "until",
+			opts: []ListCommitsOption{WithPathPrefix("data/"), WithTimeRange(time.Time{}, deleteCommit.CreationDate)},
+			want: []string{"add data"},
+		},
+		{
+			name: "since",
+			opts: []ListCommitsOption{WithPathPrefix("data/"), WithTimeRange(deleteCommit.CreationDate, time.Time{})},
+			want: []string{"add data on branch", "delete data"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, _, err := c.ListCommits(ctx, repository, "branch1", "", -1, tt.opts...)
+			testutil.MustDo(t, "list commits", err)
+			messages := make([]string, len(commits))
+			for i, commit := range commits {
+				messages[i] = commit.Message
+			}
+			if diff := deep.Equal(messages, tt.want); diff != nil {
+				t.Error("ListCommits() filtered", diff)
+			}
+		})
+	}
+}
@@ -16,6 +16,10 @@ const (
 )
 
 func (c *cataloger) ListEntries(ctx context.Context, repository, reference string, prefix, after string, delimiter string, limit int) ([]*Entry, bool, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "reference", IsValid: ValidateReference(reference)},
@@ -33,13 +37,10 @@ func (c *cataloger) ListEntries(ctx context.Context, repository, reference strin
 	}
 
 	var res interface{}
-	switch delimiter {
-	case "":
+	if delimiter == "" {
 		res, err = c.listEntries(ctx, repository, ref, prefix, after, limit)
-	case DefaultPathDelimiter:
+	} else {
 		res, err = c.listEntriesByLevel(ctx, repository, ref, prefix, after, delimiter, limit)
-	default:
-		err = ErrUnsupportedDelimiter
 	}
 	if err != nil {
 		return nil, false, err
@@ -62,7 +63,7 @@ func (c *cataloger) listEntries(ctx context.Context, repository string, ref *Ref
 			return nil, fmt.Errorf("get lineage: %w", err)
 		}
 		entriesSQL, args, err := psql.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata").
 			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
 			// Listing also shows expired objects!
 			Where(sq.And{sq.Like{"path": likePath}, sq.Eq{"is_deleted": false}, sq.Gt{"path": after}}).
@@ -145,7 +146,9 @@ func loopByLevel(tx db.Tx, prefix, after, delimiter string, limit, branchBatchSi
 		listAfter = prefix
 		exactFirst = true
 	} else {
-		if strings.HasSuffix(after, delimiter) {
+		// continue after the entries of a common prefix.  The listed prefix itself can be a
+		// directory marker entry, listed before the entries under it.
+		if after != prefix && strings.HasSuffix(after, delimiter) {
 			after += DirectoryTermination
 		}
 		listAfter = after
@@ -222,7 +225,7 @@ func processSinglePrefix(response []resultRow, delimiter string, branchPriorityM
 		if checkPathNotDeleted(pathResults) { // minimal path was found
 			pos := strings.Index(p, delimiter)
 			if pos > -1 {
-				p = p[:pos+1]
+				p = p[:pos+len(delimiter)]
 			}
 			resultPaths = append(resultPaths, p)
 			if pos > -1 || len(resultPaths) >= limit {
@@ -360,7 +363,7 @@ func loadEntriesIntoMarkerList(markerList []string, tx db.Tx, branchID int64, co
 	var previousInRun string
 	var run entryRun
 	for i, p := range markerList {
-		// terminating by '/'(slash) character is an indication of a directory
+		// terminating by the delimiter is an indication of a directory
 		if strings.HasSuffix(p, delimiter) {
 			// its absence indicates a leaf entry that has to be read from DB
 			if inRun {
@@ -389,7 +392,7 @@ func loadEntriesIntoMarkerList(markerList []string, tx db.Tx, branchID int64, co
 	entriesReader := sqEntriesLineageV(branchID, commitID, lineage)
 	for _, r := range entryRuns {
 		entriesSQL, args, err := sq.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata").
 			Where("NOT is_deleted AND path between ? and ?", prefix+r.startEntryRun, prefix+r.endEntryRun).
 			FromSelect(entriesReader, "e").
 			PlaceholderFormat(sq.Dollar).
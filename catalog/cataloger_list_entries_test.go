@@ -601,6 +601,41 @@ func TestCataloger_ListEntries_ByLevelAfter(t *testing.T) {
 	}
 }
 
+func TestCataloger_ListEntries_DirectoryMarkers(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repo := testCatalogerRepo(t, ctx, c, "repo", "master")
+	// zero-byte folder markers, as created by Hadoop or the S3 console
+	for _, p := range []string{"empty/", "dir/", "dir/file1", "dir/file2", "dir/sub/"} {
+		testCatalogerCreateEntry(t, ctx, c, repo, "master", p, nil, "")
+	}
+
+	tests := []struct {
+		name      string
+		prefix    string
+		after     string
+		delimiter string
+		limit     int
+		want      []string
+	}{
+		{name: "root", delimiter: DefaultPathDelimiter, limit: -1, want: []string{"dir/", "empty/"}},
+		{name: "empty directory", prefix: "empty/", delimiter: DefaultPathDelimiter, limit: -1, want: []string{"empty/"}},
+		{name: "directory", prefix: "dir/", delimiter: DefaultPathDelimiter, limit: -1, want: []string{"dir/", "dir/file1", "dir/file2", "dir/sub/"}},
+		{name: "after marker", prefix: "dir/", after: "dir/", delimiter: DefaultPathDelimiter, limit: -1, want: []string{"dir/file1", "dir/file2", "dir/sub/"}},
+		{name: "first page", prefix: "dir/", delimiter: DefaultPathDelimiter, limit: 1, want: []string{"dir/"}},
+		{name: "recursive", prefix: "dir/", limit: -1, want: []string{"dir/", "dir/file1", "dir/file2", "dir/sub/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := c.ListEntries(ctx, repo, "master", tt.prefix, tt.after, tt.delimiter, tt.limit)
+			testutil.MustDo(t, "ListEntries", err)
+			if diff := deep.Equal(extractEntriesPaths(got), tt.want); diff != nil {
+				t.Fatal("ListEntries", diff)
+			}
+		})
+	}
+}
+
 func TestCataloger_ListEntries_Uncommitted(t *testing.T) {
 	ctx := context.Background()
 	c := testCataloger(t)
@@ -827,3 +862,42 @@ func testListEntriesCreateEntries(t *testing.T, ctx context.Context, c Cataloger
 		testutil.MustDo(t, msg, err)
 	}
 }
+
+func TestCataloger_ListEntries_Delimiter(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+
+	repo := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, p := range []string{"a.b.c", "a.b.d", "a.e", "a/x", "f", "x::w", "x::y::z"} {
+		testCatalogerCreateEntry(t, ctx, c, repo, "master", p, nil, "")
+	}
+
+	tests := []struct {
+		name      string
+		prefix    string
+		delimiter string
+		want      []string
+	}{
+		{name: "dot", prefix: "a.", delimiter: ".", want: []string{"a.b.", "a.e"}},
+		{name: "partial prefix", prefix: "a", delimiter: ".", want: []string{"a.", "a/x"}},
+		{name: "root", prefix: "", delimiter: ".", want: []string{"a.", "a/x", "f", "x::w", "x::y::z"}},
+		{name: "multiple characters", prefix: "x::", delimiter: "::", want: []string{"x::w", "x::y::"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.ListEntries(ctx, repo, "master", tt.prefix, "", tt.delimiter, -1)
+			testutil.MustDo(t, "list entries", err)
+			if diff := deep.Equal(extractEntriesPaths(got), tt.want); diff != nil {
+				t.Fatal("ListEntries", diff)
+			}
+			if gotMore {
+				t.Fatal("ListEntries got more should be false")
+			}
+			for _, res := range got {
+				if strings.HasSuffix(res.Path, tt.delimiter) != res.CommonLevel {
+					t.Errorf("ListEntries entry %s common level = %t", res.Path, res.CommonLevel)
+				}
+			}
+		})
+	}
+}
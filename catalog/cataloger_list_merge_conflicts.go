@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const MergeConflictsMaxLimit = 1000
+
+// ListMergeConflicts lists the conflicts found by the last failed merge of sourceBranch into
+// destinationBranch, ordered by path.  Conflicts are removed once the branches merge successfully.
+func (c *cataloger) ListMergeConflicts(ctx context.Context, repository, sourceBranch, destinationBranch string, limit int, after string) ([]*MergeConflict, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "sourceBranch", IsValid: ValidateBranchName(sourceBranch)},
+		{Name: "destinationBranch", IsValid: ValidateBranchName(destinationBranch)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > MergeConflictsMaxLimit {
+		limit = MergeConflictsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		sourceID, err := c.getBranchIDCache(tx, repository, sourceBranch)
+		if err != nil {
+			return nil, fmt.Errorf("source branch: %w", err)
+		}
+		destinationID, err := c.getBranchIDCache(tx, repository, destinationBranch)
+		if err != nil {
+			return nil, fmt.Errorf("destination branch: %w", err)
+		}
+		var conflicts []*MergeConflict
+		err = tx.Select(&conflicts, `SELECT path, conflict_type
+			FROM catalog_merge_conflicts
+			WHERE source_branch_id = $1 AND destination_branch_id = $2 AND path > $3
+			ORDER BY path
+			LIMIT $4`, sourceID, destinationID, after, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		return conflicts, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	conflicts := res.([]*MergeConflict)
+	hasMore := paginateSlice(&conflicts, limit)
+	return conflicts, hasMore, nil
+}
@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ListMergeConflicts(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, p := range []string{"file0", "file1", "file2", "file3"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "master")
+	testutil.MustDo(t, "delete file1 on master", c.DeleteEntry(ctx, repository, "master", "file1"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "master")
+	_, err = c.Commit(ctx, repository, "master", "change master", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file0", nil, "branch1")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "branch1")
+	testutil.MustDo(t, "delete file2 on branch1", c.DeleteEntry(ctx, repository, "branch1", "file2"))
+	_, err = c.Commit(ctx, repository, "branch1", "change branch1", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	if !errors.Is(err, ErrConflictFound) {
+		t.Fatalf("Merge err=%v, expected %v", err, ErrConflictFound)
+	}
+	expectedConflicts := []*MergeConflict{
+		{Path: "file0", Type: ConflictTypeBothModified},
+		{Path: "file1", Type: ConflictTypeDeletedBySource},
+		{Path: "file2", Type: ConflictTypeDeletedByDestination},
+	}
+	if diff := deep.Equal(res.Conflicts, expectedConflicts); diff != nil {
+		t.Fatal("Merge conflicts diff:", diff)
+	}
+
+	conflicts, hasMore, err := c.ListMergeConflicts(ctx, repository, "master", "branch1", 2, "")
+	testutil.MustDo(t, "list merge conflicts", err)
+	if diff := deep.Equal(conflicts, expectedConflicts[:2]); diff != nil {
+		t.Fatal("List merge conflicts diff:", diff)
+	}
+	if !hasMore {
+		t.Fatal("List merge conflicts expected more conflicts")
+	}
+	conflicts, hasMore, err = c.ListMergeConflicts(ctx, repository, "master", "branch1", 2, "file1")
+	testutil.MustDo(t, "list merge conflicts after file1", err)
+	if diff := deep.Equal(conflicts, expectedConflicts[2:]); diff != nil {
+		t.Fatal("List merge conflicts after file1 diff:", diff)
+	}
+	if hasMore {
+		t.Fatal("List merge conflicts after file1 expected no more conflicts")
+	}
+
+	// conflicts are kept per source and destination
+	conflicts, _, err = c.ListMergeConflicts(ctx, repository, "branch1", "master", -1, "")
+	testutil.MustDo(t, "list merge conflicts of branch1 into master", err)
+	if len(conflicts) != 0 {
+		t.Fatalf("List merge conflicts of branch1 into master %v, expected none", conflicts)
+	}
+
+	// a successful merge removes the conflicts
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, WithStrategy(MergeStrategyDestWins))
+	testutil.MustDo(t, "merge master into branch1 keeping branch1", err)
+	conflicts, _, err = c.ListMergeConflicts(ctx, repository, "master", "branch1", -1, "")
+	testutil.MustDo(t, "list merge conflicts after merge", err)
+	if len(conflicts) != 0 {
+		t.Fatalf("List merge conflicts after merge %v, expected none", conflicts)
+	}
+}
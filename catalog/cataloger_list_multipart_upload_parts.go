@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ListMultipartUploadParts lists the parts recorded for a multipart upload, ordered by part
+// number, starting after the part number 'after'.
+func (c *cataloger) ListMultipartUploadParts(ctx context.Context, repository, uploadID string, limit int, after int) ([]*MultipartUploadPart, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "uploadID", IsValid: ValidateUploadID(uploadID)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > MultipartUploadMaxParts {
+		limit = MultipartUploadMaxParts
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var exists bool
+		if err := tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM catalog_multipart_uploads WHERE repository_id = $1 AND upload_id = $2)`,
+			repoID, uploadID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrMultipartUploadNotFound
+		}
+		var parts []*MultipartUploadPart
+		if err := tx.Select(&parts, `
+			SELECT part_number, etag, size, creation_date
+			FROM catalog_multipart_upload_parts
+			WHERE upload_id = $1 AND part_number > $2
+			ORDER BY part_number
+			LIMIT $3`,
+			uploadID, after, limit+1); err != nil {
+			return nil, err
+		}
+		return parts, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	parts := res.([]*MultipartUploadPart)
+	hasMore := paginateSlice(&parts, limit)
+	return parts, hasMore, nil
+}
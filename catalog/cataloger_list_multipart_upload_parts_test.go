@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCataloger_ListMultipartUploadParts(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.CreateMultipartUpload(ctx, repository, "upload1", "/path", "/file", time.Now()); err != nil {
+		t.Fatal("create multipart upload:", err)
+	}
+	for _, partNumber := range []int{3, 1, 2} {
+		part := MultipartUploadPart{PartNumber: partNumber, ETag: fmt.Sprintf("etag%d", partNumber), Size: int64(partNumber)}
+		if err := c.CreateMultipartUploadPart(ctx, repository, "upload1", part); err != nil {
+			t.Fatalf("create multipart upload part %d: %s", partNumber, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		uploadID string
+		limit    int
+		after    int
+		want     []int
+		wantMore bool
+		wantErr  error
+	}{
+		{name: "all", uploadID: "upload1", limit: -1, want: []int{1, 2, 3}},
+		{name: "limit", uploadID: "upload1", limit: 2, want: []int{1, 2}, wantMore: true},
+		{name: "after", uploadID: "upload1", limit: 2, after: 2, want: []int{3}},
+		{name: "unknown upload", uploadID: "upload2", limit: -1, wantErr: ErrMultipartUploadNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.ListMultipartUploadParts(ctx, repository, tt.uploadID, tt.limit, tt.after)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListMultipartUploadParts() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			gotNumbers := make([]int, 0, len(got))
+			for _, p := range got {
+				if p.ETag != fmt.Sprintf("etag%d", p.PartNumber) {
+					t.Errorf("ListMultipartUploadParts() part %d etag = %s", p.PartNumber, p.ETag)
+				}
+				gotNumbers = append(gotNumbers, p.PartNumber)
+			}
+			if fmt.Sprint(gotNumbers) != fmt.Sprint(tt.want) {
+				t.Fatalf("ListMultipartUploadParts() got %v, expected %v", gotNumbers, tt.want)
+			}
+			if gotMore != tt.wantMore {
+				t.Errorf("ListMultipartUploadParts() hasMore = %t, expected %t", gotMore, tt.wantMore)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListMultipartUploadsMaxLimit = 10000
+
+// ListMultipartUploads lists the multipart uploads of a repository that were created before
+// createdBefore, ordered by upload id.  Uploads that were never completed or aborted leave their
+// parts on the underlying storage, listing the old ones lets a cleanup job abort them.
+func (c *cataloger) ListMultipartUploads(ctx context.Context, repository string, createdBefore time.Time, limit int, after string) ([]*MultipartUpload, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListMultipartUploadsMaxLimit {
+		limit = ListMultipartUploadsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var uploads []*MultipartUpload
+		if err := tx.Select(&uploads, `
+			SELECT $2 AS repository, upload_id, path, creation_date, physical_address
+			FROM catalog_multipart_uploads
+			WHERE repository_id = $1 AND creation_date < $3 AND upload_id > $4
+			ORDER BY upload_id
+			LIMIT $5`,
+			repoID, repository, createdBefore, after, limit+1); err != nil {
+			return nil, err
+		}
+		return uploads, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	uploads := res.([]*MultipartUpload)
+	hasMore := paginateSlice(&uploads, limit)
+	return uploads, hasMore, nil
+}
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCataloger_ListMultipartUploads(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	now := time.Now().Round(time.Second) // round in order to remove the monotonic clock
+	uploads := []struct {
+		uploadID string
+		age      time.Duration
+	}{
+		{uploadID: "upload1", age: 72 * time.Hour},
+		{uploadID: "upload2", age: time.Minute},
+		{uploadID: "upload3", age: 48 * time.Hour},
+		{uploadID: "upload4", age: 96 * time.Hour},
+	}
+	for _, u := range uploads {
+		if err := c.CreateMultipartUpload(ctx, repository, u.uploadID, "/path/"+u.uploadID, "/file/"+u.uploadID, now.Add(-u.age)); err != nil {
+			t.Fatalf("create multipart upload %s: %s", u.uploadID, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		createdBefore time.Time
+		limit         int
+		after         string
+		want          []string
+		wantMore      bool
+	}{
+		{name: "all", createdBefore: now, limit: -1, want: []string{"upload1", "upload2", "upload3", "upload4"}},
+		{name: "older than a day", createdBefore: now.Add(-24 * time.Hour), limit: -1, want: []string{"upload1", "upload3", "upload4"}},
+		{name: "limit", createdBefore: now.Add(-24 * time.Hour), limit: 2, want: []string{"upload1", "upload3"}, wantMore: true},
+		{name: "after", createdBefore: now.Add(-24 * time.Hour), limit: 2, after: "upload3", want: []string{"upload4"}},
+		{name: "none", createdBefore: now.Add(-100 * time.Hour), limit: -1, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.ListMultipartUploads(ctx, repository, tt.createdBefore, tt.limit, tt.after)
+			if err != nil {
+				t.Fatalf("ListMultipartUploads() error = %s", err)
+			}
+			gotIDs := make([]string, 0, len(got))
+			for _, u := range got {
+				if u.Repository != repository {
+					t.Errorf("ListMultipartUploads() upload %s repository = %s, expected %s", u.UploadID, u.Repository, repository)
+				}
+				gotIDs = append(gotIDs, u.UploadID)
+			}
+			if len(gotIDs) != len(tt.want) {
+				t.Fatalf("ListMultipartUploads() got %v, expected %v", gotIDs, tt.want)
+			}
+			for i := range gotIDs {
+				if gotIDs[i] != tt.want[i] {
+					t.Fatalf("ListMultipartUploads() got %v, expected %v", gotIDs, tt.want)
+				}
+			}
+			if gotMore != tt.wantMore {
+				t.Errorf("ListMultipartUploads() hasMore = %t, expected %t", gotMore, tt.wantMore)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ListPathCommits lists the commits, newest first, that changed path in the history of reference.
+// A commit changed the path when it created, overwrote or deleted the path entry.  fromReference is
+// the commit reference the listing continues after.
+func (c *cataloger) ListPathCommits(ctx context.Context, repository, reference string, path string, fromReference string, limit int) ([]*CommitLog, bool, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "path", IsValid: ValidatePath(path)},
+		{Name: "fromReference", IsValid: ValidateOptionalString(fromReference, IsValidReference)},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, false, err
+	}
+	afterRef, err := ParseRef(fromReference)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListCommitsMaxLimit {
+		limit = ListCommitsMaxLimit
+	}
+	fromCommitID := MaxCommitID
+	if ref.CommitID > 0 {
+		fromCommitID = ref.CommitID
+	}
+	beforeCommitID := fromCommitID + 1
+	if afterRef.CommitID > 0 && afterRef.CommitID < beforeCommitID {
+		beforeCommitID = afterRef.CommitID
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, fromCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		lineageAsValuesTable := getLineageAsValues(lineage, branchID, fromCommitID)
+		query := `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
+				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,c.squashed
+			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
+			WHERE (c.branch_id, c.commit_id) IN (
+					SELECT e.branch_id, e.min_commit FROM catalog_entries e
+						JOIN ` + lineageAsValuesTable + ` ON e.branch_id = l.branch_id AND e.min_commit <= l.commit_id
+					WHERE e.path = $1 AND e.min_commit > 0)
+				AND c.commit_id < $2
+			ORDER BY c.commit_id DESC
+			LIMIT $3`
+		var rawCommits []*commitLogRaw
+		if err := tx.Select(&rawCommits, query, path, beforeCommitID, limit+1); err != nil {
+			return nil, err
+		}
+		return convertRawCommits(rawCommits), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	commits := res.([]*CommitLog)
+	hasMore := paginateSlice(&commits, limit)
+	return commits, hasMore, nil
+}
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ListPathCommits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	commit := func(branch, message string) string {
+		commitLog, err := c.Commit(ctx, repository, branch, message, "tester", nil)
+		testutil.MustDo(t, message, err)
+		return commitLog.Reference
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/a", nil, "1")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/b", nil, "1")
+	commit1 := commit("master", "add a and b")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/b", nil, "2")
+	commit2 := commit("master", "change b")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/a", nil, "3")
+	commit3 := commit("branch1", "change a on branch1")
+	testutil.MustDo(t, "delete a", c.DeleteEntry(ctx, repository, "master", "/a"))
+	commit4 := commit("master", "delete a")
+
+	tests := []struct {
+		name          string
+		reference     string
+		path          string
+		fromReference string
+		limit         int
+		want          []string
+		wantMore      bool
+	}{
+		{name: "branch", reference: "branch1", path: "/a", limit: -1, want: []string{commit3, commit1}},
+		{name: "parent", reference: "master", path: "/a", limit: -1, want: []string{commit4, commit1}},
+		{name: "inherited", reference: "branch1", path: "/b", limit: -1, want: []string{commit2, commit1}},
+		{name: "commit", reference: commit1, path: "/b", limit: -1, want: []string{commit1}},
+		{name: "limit", reference: "branch1", path: "/a", limit: 1, want: []string{commit3}, wantMore: true},
+		{name: "after", reference: "branch1", path: "/a", fromReference: commit3, limit: 1, want: []string{commit1}},
+		{name: "unknown path", reference: "master", path: "/c", limit: -1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.ListPathCommits(ctx, repository, tt.reference, tt.path, tt.fromReference, tt.limit)
+			testutil.MustDo(t, "list path commits", err)
+			var gotRefs []string
+			for _, commitLog := range got {
+				gotRefs = append(gotRefs, commitLog.Reference)
+			}
+			if len(gotRefs) != len(tt.want) {
+				t.Fatalf("ListPathCommits() got %v, expected %v", gotRefs, tt.want)
+			}
+			for i := range gotRefs {
+				if gotRefs[i] != tt.want[i] {
+					t.Fatalf("ListPathCommits() got %v, expected %v", gotRefs, tt.want)
+				}
+			}
+			if gotMore != tt.wantMore {
+				t.Errorf("ListPathCommits() hasMore = %t, expected %t", gotMore, tt.wantMore)
+			}
+		})
+	}
+}
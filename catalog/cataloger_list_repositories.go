@@ -3,23 +3,58 @@ package catalog
 import (
 	"context"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/treeverse/lakefs/db"
 )
 
 const ListRepositoriesMaxLimit = 10000
 
-func (c *cataloger) ListRepositories(ctx context.Context, limit int, after string) ([]*Repository, bool, error) {
+type RepositoriesOrder int
+
+const (
+	RepositoriesOrderByName RepositoriesOrder = iota
+	RepositoriesOrderByCreationDate
+)
+
+// ListRepositoriesParams filters and sorts the repositories returned by ListRepositories
+type ListRepositoriesParams struct {
+	// Prefix lists only repositories whose name starts with the prefix
+	Prefix string
+	// Labels lists only repositories labeled with all the labels and their values
+	Labels Metadata
+	// OrderBy sets the listing order.  Repositories created at the same time are sorted by name.
+	OrderBy RepositoriesOrder
+}
+
+func (c *cataloger) ListRepositories(ctx context.Context, params ListRepositoriesParams, limit int, after string) ([]*Repository, bool, error) {
 	if limit < 0 || limit > ListRepositoriesMaxLimit {
 		limit = ListRepositoriesMaxLimit
 	}
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		query := `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date
-			FROM catalog_repositories r JOIN catalog_branches b ON r.default_branch = b.id 
-			WHERE r.name > $1
-			ORDER BY r.name
-			LIMIT $2`
+		q := psql.Select("r.name", "r.storage_namespace", "b.name as default_branch", "r.creation_date", "r.read_only", "r.labels", "r.hash_algorithm").
+			From("catalog_repositories r").
+			Join("catalog_branches b ON r.default_branch = b.id")
+		if params.Prefix != "" {
+			q = q.Where(sq.Like{"r.name": db.Prefix(params.Prefix)})
+		}
+		if len(params.Labels) > 0 {
+			q = q.Where("r.labels @> ?::jsonb", params.Labels)
+		}
+		switch params.OrderBy {
+		case RepositoriesOrderByCreationDate:
+			if after != "" {
+				q = q.Where(`(r.creation_date,r.name) > (SELECT creation_date,name FROM catalog_repositories WHERE name = ?)`, after)
+			}
+			q = q.OrderBy("r.creation_date", "r.name")
+		default:
+			q = q.Where(sq.Gt{"r.name": after}).OrderBy("r.name")
+		}
+		query, args, err := q.Limit(uint64(limit) + 1).ToSql()
+		if err != nil {
+			return nil, err
+		}
 		var repos []*Repository
-		if err := tx.Select(&repos, query, after, limit+1); err != nil {
+		if err := tx.Select(&repos, query, args...); err != nil {
 			return nil, err
 		}
 		return repos, nil
@@ -19,11 +19,19 @@ func TestCataloger_ListRepositories(t *testing.T) {
 		if err != nil {
 			t.Fatal("create repository for testing failed", err)
 		}
+		labels := Metadata{"team": fmt.Sprintf("team%d", i%2)}
+		if i == 1 {
+			labels["env"] = "prod"
+		}
+		if err := c.UpdateRepository(ctx, repoName, UpdateRepositoryParams{Labels: labels}); err != nil {
+			t.Fatal("label repository for testing failed", err)
+		}
 	}
 
 	type args struct {
-		limit int
-		after string
+		params ListRepositoriesParams
+		limit  int
+		after  string
 	}
 	tests := []struct {
 		name     string
@@ -53,6 +61,48 @@ func TestCataloger_ListRepositories(t *testing.T) {
 			wantMore: false,
 			wantErr:  false,
 		},
+		{
+			name:     "prefix",
+			args:     args{params: ListRepositoriesParams{Prefix: "repo2"}, limit: -1},
+			want:     []string{"repo2"},
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name:     "label",
+			args:     args{params: ListRepositoriesParams{Labels: Metadata{"team": "team1"}}, limit: -1},
+			want:     []string{"repo1", "repo3"},
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name:     "all labels",
+			args:     args{params: ListRepositoriesParams{Labels: Metadata{"team": "team1", "env": "prod"}}, limit: -1},
+			want:     []string{"repo1"},
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name:     "label and prefix",
+			args:     args{params: ListRepositoriesParams{Prefix: "repo2", Labels: Metadata{"team": "team1"}}, limit: -1},
+			want:     nil,
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name:     "by creation date",
+			args:     args{params: ListRepositoriesParams{OrderBy: RepositoriesOrderByCreationDate}, limit: -1},
+			want:     []string{"repo3", "repo2", "repo1"},
+			wantMore: false,
+			wantErr:  false,
+		},
+		{
+			name:     "by creation date after",
+			args:     args{params: ListRepositoriesParams{OrderBy: RepositoriesOrderByCreationDate}, limit: 1, after: "repo3"},
+			want:     []string{"repo2"},
+			wantMore: true,
+			wantErr:  false,
+		},
 		{
 			name:     "nothing to be found",
 			args:     args{limit: 0, after: "repoX"},
@@ -63,7 +113,7 @@ func TestCataloger_ListRepositories(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotMore, err := c.ListRepositories(ctx, tt.args.limit, tt.args.after)
+			got, gotMore, err := c.ListRepositories(ctx, tt.args.params, tt.args.limit, tt.args.after)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListRepositories() error = %v, wantErr %v", err, tt.wantErr)
 				return
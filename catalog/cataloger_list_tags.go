@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListTagsMaxLimit = 1000
+
+// ListTags lists the tags of repository by name, starting after the tag named after
+func (c *cataloger) ListTags(ctx context.Context, repository string, limit int, after string) ([]*Tag, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListTagsMaxLimit {
+		limit = ListTagsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		query, args, err := sqTags(repoID).
+			Where(sq.Gt{"t.name": after}).
+			OrderBy("t.name").
+			Limit(uint64(limit) + 1).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var raws []*tagRaw
+		if err := tx.Select(&raws, query, args...); err != nil {
+			return nil, err
+		}
+		tags := make([]*Tag, len(raws))
+		for i, raw := range raws {
+			tags[i] = raw.toTag()
+		}
+		return tags, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	tags := res.([]*Tag)
+	hasMore := paginateSlice(&tags, limit)
+	return tags, hasMore, nil
+}
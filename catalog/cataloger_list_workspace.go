@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListWorkspaceMaxLimit = 1000
+
+// ListWorkspace returns the uncommitted entries of branch, including tombstones of uncommitted
+// deletes.
+func (c *cataloger) ListWorkspace(ctx context.Context, repository, branch string, limit int, after string) ([]*WorkspaceEntry, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListWorkspaceMaxLimit {
+		limit = ListWorkspaceMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		sql, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired", "is_tombstone").
+			FromSelect(sqEntriesV(UncommittedID), "e").
+			Where(sq.And{
+				sq.Eq{"branch_id": branchID, "is_committed": false},
+				sq.Gt{"path": after},
+			}).
+			OrderBy("path").
+			Limit(uint64(limit) + 1).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entries []*WorkspaceEntry
+		if err := tx.Select(&entries, sql, args...); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*WorkspaceEntry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ListWorkspace(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed2", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "added", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed2", nil, "changed")
+	testutil.MustDo(t, "delete committed1", c.DeleteEntry(ctx, repository, "master", "committed1"))
+
+	entries, hasMore, err := c.ListWorkspace(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "list workspace", err)
+	if hasMore {
+		t.Error("ListWorkspace() has more, expected all entries")
+	}
+	expected := []struct {
+		path      string
+		tombstone bool
+	}{
+		{path: "added"},
+		{path: "committed1", tombstone: true},
+		{path: "committed2"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("ListWorkspace() got %d entries, expected %d", len(entries), len(expected))
+	}
+	for i, exp := range expected {
+		if entries[i].Path != exp.path || entries[i].Tombstone != exp.tombstone {
+			t.Errorf("ListWorkspace() entry %d = %s (tombstone %t), expected %s (tombstone %t)",
+				i, entries[i].Path, entries[i].Tombstone, exp.path, exp.tombstone)
+		}
+	}
+
+	entries, hasMore, err = c.ListWorkspace(ctx, repository, "master", 1, "added")
+	testutil.MustDo(t, "list workspace after", err)
+	if !hasMore || len(entries) != 1 || entries[0].Path != "committed1" {
+		t.Errorf("ListWorkspace() after 'added' got %d entries, more %t, expected committed1 with more", len(entries), hasMore)
+	}
+}
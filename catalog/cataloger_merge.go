@@ -6,12 +6,85 @@ import (
 	"fmt"
 	"strconv"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/logging"
 )
 
-func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata) (*MergeResult, error) {
+const (
+	// MetadataKeySquashedFrom is the commit metadata key of a squash merge holding the last source
+	// commit merged before - the squashed commits are the source commits after it
+	MetadataKeySquashedFrom = "squashed_from"
+	// MetadataKeySquashedTo is the commit metadata key of a squash merge holding the last squashed
+	// source commit
+	MetadataKeySquashedTo = "squashed_to"
+)
+
+// MergeStrategy selects how Merge resolves conflicts
+type MergeStrategy string
+
+const (
+	// MergeStrategyNone fails merges with conflicts
+	MergeStrategyNone MergeStrategy = ""
+	// MergeStrategySourceWins resolves conflicts by taking the source branch version
+	MergeStrategySourceWins MergeStrategy = "source-wins"
+	// MergeStrategyDestWins resolves conflicts by keeping the destination branch version
+	MergeStrategyDestWins MergeStrategy = "dest-wins"
+)
+
+type mergeOptions struct {
+	squash          bool
+	strategy        MergeStrategy
+	dryRun          bool
+	expectedVersion int64
+}
+
+type MergeOpt func(o *mergeOptions)
+
+// WithSquash merges a child branch into its parent as a single commit: the log of the parent
+// does not include the squashed commits of the child, and the squashed range is recorded in the
+// merge commit metadata.
+func WithSquash() MergeOpt {
+	return func(o *mergeOptions) {
+		o.squash = true
+	}
+}
+
+// WithStrategy resolves merge conflicts by strategy.  Conflicts with uncommitted changes on the
+// destination branch are never resolved by taking the source version.
+func WithStrategy(strategy MergeStrategy) MergeOpt {
+	return func(o *mergeOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithDryRun computes the merge result - the summary of the changes and the conflicts - without
+// merging or keeping the conflicts.  Pre-merge hooks are not run and the result has no reference.
+func WithDryRun() MergeOpt {
+	return func(o *mergeOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithExpectedDestinationVersion fails the merge with ErrBranchConcurrentUpdate unless the
+// destination branch is at version.
+func WithExpectedDestinationVersion(version int64) MergeOpt {
+	return func(o *mergeOptions) {
+		o.expectedVersion = version
+	}
+}
+
+func IsValidMergeStrategy(strategy MergeStrategy) bool {
+	switch strategy {
+	case MergeStrategyNone, MergeStrategySourceWins, MergeStrategyDestWins:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata, opts ...MergeOpt) (*MergeResult, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "leftBranch", IsValid: ValidateBranchName(leftBranch)},
@@ -20,33 +93,66 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 	}); err != nil {
 		return nil, err
 	}
+	var options mergeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := Validate(ValidateFields{
+		{Name: "strategy", IsValid: func() bool { return IsValidMergeStrategy(options.strategy) }},
+	}); err != nil {
+		return nil, err
+	}
 
+	if message == "" {
+		message = formatMergeMessage(leftBranch, rightBranch)
+	}
+	var preMerge *preMergeCheck
+	if !options.dryRun {
+		var err error
+		preMerge, err = c.runPreMerge(ctx, options, &PendingMerge{
+			Repository:        repository,
+			SourceBranch:      leftBranch,
+			DestinationBranch: rightBranch,
+			Committer:         committer,
+			Message:           message,
+			Metadata:          metadata,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lockType := LockTypeUpdate
+	if options.dryRun {
+		lockType = LockTypeNone
+	}
 	mergeResult := &MergeResult{}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		leftID, err := getBranchID(tx, repository, leftBranch, LockTypeUpdate)
+		leftID, err := getBranchID(tx, repository, leftBranch, lockType)
 		if err != nil {
 			return nil, fmt.Errorf("left branch: %w", err)
 		}
-		rightID, err := getBranchID(tx, repository, rightBranch, LockTypeUpdate)
+		rightID, err := getBranchID(tx, repository, rightBranch, lockType)
 		if err != nil {
 			return nil, fmt.Errorf("right branch: %w", err)
 		}
-		relation, err := getBranchesRelationType(tx, leftID, rightID)
-		if err != nil {
-			return nil, fmt.Errorf("branch relation: %w", err)
-		}
-
-		err = c.doDiffByRelation(tx, relation, leftID, rightID)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
 			return nil, err
 		}
-		mergeResult.Summary, err = c.getDiffSummary(tx)
+		var relation RelationType
+		relation, mergeResult.Summary, err = c.diffMerge(tx, leftID, rightID, options)
 		if err != nil {
 			return nil, err
 		}
-		// check for conflicts
+		// keep the conflicts and commit - the merge fails with ErrConflictFound after the transaction
 		if mergeResult.Summary[DifferenceTypeConflict] > 0 {
-			return nil, ErrConflictFound
+			if !options.dryRun {
+				if err := saveMergeConflicts(tx, leftID, rightID); err != nil {
+					return nil, err
+				}
+			}
+			mergeResult.Conflicts, err = getMergeConflicts(tx)
+			return nil, err
 		}
 		// check for changes
 		var total int
@@ -62,18 +168,151 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 				return nil, ErrNoDifferenceWasFound
 			}
 		}
+		if options.dryRun {
+			return nil, nil
+		}
+		if err := preMerge.verify(tx, leftID, rightID); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, rightID, options.expectedVersion); err != nil {
+			return nil, err
+		}
 
-		if message == "" {
-			message = formatMergeMessage(leftBranch, rightBranch)
+		var commitID CommitID
+		if options.squash {
+			commitID, err = c.squashFromChild(tx, leftBranch, leftID, rightID, committer, message, metadata)
+		} else {
+			commitID, err = c.doMergeByRelation(tx, relation, leftID, rightID, committer, message, metadata)
 		}
-		commitID, err := c.doMergeByRelation(tx, relation, leftID, rightID, committer, message, metadata)
 		if err != nil {
 			return nil, err
 		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`DELETE FROM catalog_merge_conflicts WHERE source_branch_id = $1 AND destination_branch_id = $2`,
+			leftID, rightID); err != nil {
+			return nil, fmt.Errorf("delete merge conflicts: %w", err)
+		}
 		mergeResult.Reference = MakeReference(rightBranch, commitID)
-		return nil, nil
+		return nil, insertRepositoryEvent(tx, repository, EventTypeCommitCreated, rightBranch, "", mergeResult.Reference)
 	}, c.txOpts(ctx)...)
-	return mergeResult, err
+	if err != nil {
+		return mergeResult, err
+	}
+	if mergeResult.Summary[DifferenceTypeConflict] > 0 {
+		mergesCounter.WithLabelValues("conflict").Inc()
+		return mergeResult, ErrConflictFound
+	}
+	if options.dryRun {
+		mergesCounter.WithLabelValues("dry_run").Inc()
+		return mergeResult, nil
+	}
+	mergesCounter.WithLabelValues("merged").Inc()
+	var changes int
+	for _, count := range mergeResult.Summary {
+		changes += count
+	}
+	mergeChangesHistogram.Observe(float64(changes))
+	if len(c.hooks.postMerge) > 0 {
+		commitLog, err := c.GetCommit(ctx, repository, mergeResult.Reference)
+		if err != nil {
+			c.log.WithContext(ctx).WithError(err).WithField("reference", mergeResult.Reference).Error("Failed to read merge commit for post-merge hooks")
+		} else {
+			c.hooks.runPostMerge(ctx, repository, leftBranch, rightBranch, commitLog)
+		}
+	}
+	return mergeResult, nil
+}
+
+// diffMerge computes the merge diff results of merging leftID into rightID, with the conflicts
+// resolved by the merge strategy, and returns the branches relation and the results summary
+func (c *cataloger) diffMerge(tx db.Tx, leftID, rightID int64, options mergeOptions) (RelationType, map[DifferenceType]int, error) {
+	relation, err := getBranchesRelationType(tx, leftID, rightID)
+	if err != nil {
+		return "", nil, fmt.Errorf("branch relation: %w", err)
+	}
+	if options.squash && relation != RelationTypeFromChild {
+		return "", nil, fmt.Errorf("squash merge into a branch that is not the source parent: %w", ErrOperationNotPermitted)
+	}
+	if err := c.doDiffByRelation(tx, relation, leftID, rightID); err != nil {
+		return "", nil, err
+	}
+	if err := resolveConflicts(tx, options.strategy); err != nil {
+		return "", nil, err
+	}
+	summary, err := c.getDiffSummary(tx)
+	if err != nil {
+		return "", nil, err
+	}
+	return relation, summary, nil
+}
+
+// resolveConflicts updates the conflicts found by the merge diff to the differences of the
+// version selected by strategy
+func resolveConflicts(tx db.Tx, strategy MergeStrategy) error {
+	var err error
+	switch strategy {
+	case MergeStrategySourceWins:
+		_, err = tx.Exec(`UPDATE `+diffResultsTableName+` SET diff_type = source_diff_type, entry_ctid = source_entry_ctid
+			WHERE diff_type = $1 AND NOT dest_uncommitted`, DifferenceTypeConflict)
+	case MergeStrategyDestWins:
+		_, err = tx.Exec(`DELETE FROM `+diffResultsTableName+` WHERE diff_type = $1`, DifferenceTypeConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("resolve conflicts: %w", err)
+	}
+	return nil
+}
+
+// sqMergeConflicts selects the path and ConflictType of the conflicts found by the merge diff
+func sqMergeConflicts(columns ...string) sq.SelectBuilder {
+	conflictType := sq.Case().
+		When(sq.Eq{"source_diff_type": DifferenceTypeRemoved}, sq.Expr("?", ConflictTypeDeletedBySource)).
+		When("dest_deleted", sq.Expr("?", ConflictTypeDeletedByDestination)).
+		Else(sq.Expr("?", ConflictTypeBothModified))
+	return psql.Select(columns...).
+		Column("path").
+		Column(sq.Alias(conflictType, "conflict_type")).
+		From(diffResultsTableName).
+		Where(sq.Eq{"diff_type": DifferenceTypeConflict})
+}
+
+// getMergeConflicts returns the first MergeConflictsMaxLimit conflicts found by the merge diff
+func getMergeConflicts(tx db.Tx) ([]*MergeConflict, error) {
+	query, args, err := sqMergeConflicts().
+		OrderBy("path").
+		Limit(MergeConflictsMaxLimit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("format merge conflicts query: %w", err)
+	}
+	var conflicts []*MergeConflict
+	if err := tx.Select(&conflicts, query, args...); err != nil {
+		return nil, fmt.Errorf("select merge conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+// saveMergeConflicts replaces the merge conflicts kept for the branches with the conflicts found
+// by the merge diff
+func saveMergeConflicts(tx db.Tx, leftID, rightID int64) error {
+	_, err := tx.Exec(`DELETE FROM catalog_merge_conflicts WHERE source_branch_id = $1 AND destination_branch_id = $2`,
+		leftID, rightID)
+	if err != nil {
+		return fmt.Errorf("delete merge conflicts: %w", err)
+	}
+	query, args, err := psql.Insert("catalog_merge_conflicts").
+		Columns("source_branch_id", "destination_branch_id", "path", "conflict_type").
+		Select(sqMergeConflicts(strconv.FormatInt(leftID, 10), strconv.FormatInt(rightID, 10))).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("format insert merge conflicts: %w", err)
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("insert merge conflicts: %w", err)
+	}
+	return nil
 }
 
 // hasCommitDifferences - Checks if the current commit id of target or source branch advanced since last merge
@@ -140,8 +379,8 @@ func (c *cataloger) mergeFromParent(tx db.Tx, previousMaxCommitID, nextCommitID
 	}
 
 	// DifferenceTypeChanged - create entries into this commit based on parent branch
-	_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,metadata,min_commit)
-				SELECT $1,path,physical_address,creation_date,size,checksum,metadata,$2 AS min_commit
+	_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,content_type,metadata,min_commit)
+				SELECT $1,path,physical_address,creation_date,size,checksum,content_type,metadata,$2 AS min_commit
 				FROM catalog_entries e
 				WHERE e.ctid IN (SELECT d.entry_ctid FROM `+diffResultsTableName+` d WHERE d.diff_type=$3 
  				-- the or condition - diff will see an entry as new if it is deleted in child. but merge still need to copy it
@@ -188,8 +427,8 @@ func (c *cataloger) mergeFromChild(tx db.Tx, previousMaxCommitID, nextCommitID C
 	}
 
 	// DifferenceTypeChanged or DifferenceTypeAdded - create entries into this commit based on parent branch
-	_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,metadata,min_commit)
-				SELECT $1,path,physical_address,creation_date,size,checksum,metadata,$2 AS min_commit
+	_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,content_type,metadata,min_commit)
+				SELECT $1,path,physical_address,creation_date,size,checksum,content_type,metadata,$2 AS min_commit
 				FROM catalog_entries e
 				WHERE e.ctid IN (SELECT entry_ctid FROM `+diffResultsTableName+` WHERE diff_type IN ($3,$4))`,
 		parentID, nextCommitID, DifferenceTypeAdded, DifferenceTypeChanged)
@@ -215,6 +454,41 @@ func (c *cataloger) mergeFromChild(tx db.Tx, previousMaxCommitID, nextCommitID C
 	return err
 }
 
+// squashFromChild merges child into parent as a squashed merge commit
+func (c *cataloger) squashFromChild(tx db.Tx, childBranch string, childID, parentID int64, committer string, msg string, metadata Metadata) (CommitID, error) {
+	// the squashed range starts after the child commit merged last into the parent, or after the
+	// child branch creation commit
+	var squashedFrom CommitID
+	err := tx.Get(&squashedFrom, `SELECT COALESCE(
+			(SELECT max(merge_source_commit) FROM catalog_commits
+				WHERE branch_id = $2 AND merge_type = 'from_child' AND merge_source_branch = $1),
+			(SELECT min(commit_id) FROM catalog_commits WHERE branch_id = $1))`,
+		childID, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("squash range: %w", err)
+	}
+	childLastCommitID, err := getLastCommitIDByBranchID(tx, childID)
+	if err != nil {
+		return 0, err
+	}
+	squashMetadata := make(Metadata, len(metadata)+2)
+	for k, v := range metadata {
+		squashMetadata[k] = v
+	}
+	squashMetadata[MetadataKeySquashedFrom] = MakeReference(childBranch, squashedFrom)
+	squashMetadata[MetadataKeySquashedTo] = MakeReference(childBranch, childLastCommitID)
+
+	commitID, err := c.doMergeByRelation(tx, RelationTypeFromChild, childID, parentID, committer, msg, squashMetadata)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(`UPDATE catalog_commits SET squashed = true WHERE branch_id = $1 AND commit_id = $2`, parentID, commitID)
+	if err != nil {
+		return 0, fmt.Errorf("mark squashed: %w", err)
+	}
+	return commitID, nil
+}
+
 func (c *cataloger) mergeNonDirect(_ sqlx.Execer, previousMaxCommitID, nextCommitID CommitID, leftID, rightID int64, committer string, msg string, _ Metadata) error {
 	c.log.WithFields(logging.Fields{
 		"commit_id":      previousMaxCommitID,
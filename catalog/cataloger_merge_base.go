@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// sqlCommitAncestors is a recursive query term over the commits graph: each commit is joined with
+// its parents - the previous commit of its branch and the merge source commit.  Squashed merges
+// are not part of the source branch history, so their merge source is not a parent.
+const sqlCommitAncestors = `SELECT p.branch_id, p.commit_id FROM %[1]s a
+			JOIN catalog_commits c ON c.branch_id = a.branch_id AND c.commit_id = a.commit_id
+			CROSS JOIN LATERAL (VALUES
+				(c.branch_id, c.previous_commit_id),
+				(c.merge_source_branch, CASE WHEN c.squashed THEN 0 ELSE COALESCE(c.merge_source_commit, 0) END)
+			) AS p(branch_id, commit_id)
+			WHERE p.commit_id > 0`
+
+// MergeBase returns the best common ancestor of leftReference and rightReference: a commit
+// reachable from both that is not an ancestor of another such commit.  The history is a DAG that
+// can hold many merges in both directions, so more than one best common ancestor can exist - the
+// newest one is returned.  A branch reference stands for its last commit.
+func (c *cataloger) MergeBase(ctx context.Context, repository, leftReference, rightReference string) (*CommitLog, error) {
+	leftReference, err := c.ResolveReference(ctx, repository, leftReference)
+	if err != nil {
+		return nil, fmt.Errorf("left reference: %w", err)
+	}
+	rightReference, err = c.ResolveReference(ctx, repository, rightReference)
+	if err != nil {
+		return nil, fmt.Errorf("right reference: %w", err)
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftReference", IsValid: ValidateReference(leftReference)},
+		{Name: "rightReference", IsValid: ValidateReference(rightReference)},
+	}); err != nil {
+		return nil, err
+	}
+	leftRef, err := ParseRef(leftReference)
+	if err != nil {
+		return nil, fmt.Errorf("left reference: %w", err)
+	}
+	rightRef, err := ParseRef(rightReference)
+	if err != nil {
+		return nil, fmt.Errorf("right reference: %w", err)
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		leftID, leftCommitID, err := c.getRefCommit(tx, repository, leftRef)
+		if err != nil {
+			return nil, fmt.Errorf("left reference: %w", err)
+		}
+		rightID, rightCommitID, err := c.getRefCommit(tx, repository, rightRef)
+		if err != nil {
+			return nil, fmt.Errorf("right reference: %w", err)
+		}
+		query := `WITH RECURSIVE left_ancestors(branch_id, commit_id) AS (
+				VALUES ($1::bigint, $2::bigint)
+				UNION ` + fmt.Sprintf(sqlCommitAncestors, "left_ancestors") + `
+			), right_ancestors(branch_id, commit_id) AS (
+				VALUES ($3::bigint, $4::bigint)
+				UNION ` + fmt.Sprintf(sqlCommitAncestors, "right_ancestors") + `
+			)
+			SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
+				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,c.squashed
+			FROM catalog_commits c
+				JOIN left_ancestors l ON l.branch_id = c.branch_id AND l.commit_id = c.commit_id
+				JOIN right_ancestors r ON r.branch_id = c.branch_id AND r.commit_id = c.commit_id
+				JOIN catalog_branches b ON b.id = c.branch_id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
+			ORDER BY c.commit_id DESC
+			LIMIT 1`
+		var raw commitLogRaw
+		err = tx.Get(&raw, query, leftID, leftCommitID, rightID, rightCommitID)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrCommitNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return convertRawCommit(&raw), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*CommitLog), nil
+}
+
+// getRefCommit returns the branch ID and commit ID of ref, the last commit of the branch if ref
+// is a branch
+func (c *cataloger) getRefCommit(tx db.Tx, repository string, ref *Ref) (int64, CommitID, error) {
+	branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if err != nil {
+		return 0, 0, err
+	}
+	if ref.CommitID > 0 {
+		return branchID, ref.CommitID, nil
+	}
+	commitID, err := getLastCommitIDByBranchID(tx, branchID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return branchID, commitID, nil
+}
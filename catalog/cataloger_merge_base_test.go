@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_MergeBase(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "m1", nil, "")
+	m1, err := c.Commit(ctx, repository, "master", "m1", "tester", nil)
+	testutil.MustDo(t, "commit m1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "b1", nil, "")
+	b1, err := c.Commit(ctx, repository, "branch1", "b1", "tester", nil)
+	testutil.MustDo(t, "commit b1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "m2", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "m2", "tester", nil)
+	testutil.MustDo(t, "commit m2", err)
+
+	testMergeBase := func(t *testing.T, left, right, expected string) {
+		t.Helper()
+		got, err := c.MergeBase(ctx, repository, left, right)
+		testutil.MustDo(t, "merge base", err)
+		if got.Reference != expected {
+			t.Errorf("MergeBase(%s, %s) = %s (%s), expected %s", left, right, got.Reference, got.Message, expected)
+		}
+	}
+	testMergeBase(t, "branch1", "master", m1.Reference)
+	testMergeBase(t, "master", "branch1", m1.Reference)
+	testMergeBase(t, m1.Reference, m1.Reference, m1.Reference)
+
+	// merge to the parent makes the merged commit the base
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "merge branch1", nil)
+	testutil.MustDo(t, "merge branch1 to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "b2", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "b2", "tester", nil)
+	testutil.MustDo(t, "commit b2", err)
+	testMergeBase(t, "branch1", "master", b1.Reference)
+
+	// merge from the parent makes the parent commit the base
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "m3", nil, "")
+	m3, err := c.Commit(ctx, repository, "master", "m3", "tester", nil)
+	testutil.MustDo(t, "commit m3", err)
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "merge master", nil)
+	testutil.MustDo(t, "merge master to branch1", err)
+	testMergeBase(t, "branch1", "master", m3.Reference)
+	testMergeBase(t, "branch1", m1.Reference, m1.Reference)
+
+	// the log of a history with merges lists every commit before its parents
+	commits, _, err := c.ListCommits(ctx, repository, "branch1", "", -1)
+	testutil.MustDo(t, "list commits", err)
+	position := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		position[commit.Reference] = i
+	}
+	var merges int
+	for i, commit := range commits {
+		if commit.IsMerge() {
+			merges++
+		}
+		for _, parent := range commit.Parents {
+			if pos, ok := position[parent]; ok && pos <= i {
+				t.Errorf("ListCommits() lists %s (%s) before its child %s", parent, commits[pos].Message, commit.Message)
+			}
+		}
+	}
+	const expectedMerges = 2
+	if merges != expectedMerges {
+		t.Errorf("ListCommits() has %d merge commits, expected %d", merges, expectedMerges)
+	}
+}
@@ -1147,3 +1147,168 @@ func TestCataloger_MergeWitoutDiff(t *testing.T) {
 		t.Fatal("did not get 'nothing to commit' error")
 	}
 }
+
+func TestCataloger_Merge_Squash(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file0", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	branchLog, err := c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch1", err)
+
+	// squashing a parent into its child is not supported
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, WithSquash())
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Fatalf("Squash merge master into branch1 err=%v, expected %v", err, ErrOperationNotPermitted)
+	}
+
+	squashedFrom := branchLog.Reference
+	for round, files := range [][]string{{"file1", "file2"}, {"file3"}} {
+		var squashedTo string
+		for _, file := range files {
+			testCatalogerCreateEntry(t, ctx, c, repository, "branch1", file, nil, "")
+			commitLog, err := c.Commit(ctx, repository, "branch1", "commit "+file, "tester", nil)
+			testutil.MustDo(t, "commit to branch1", err)
+			squashedTo = commitLog.Reference
+		}
+		res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "squash", Metadata{"key": "value"}, WithSquash())
+		testutil.MustDo(t, "squash merge branch1 into master", err)
+		for _, file := range files {
+			testCatalogerGetEntry(t, ctx, c, repository, "master", file, true)
+		}
+
+		commitLog, err := c.GetCommit(ctx, repository, res.Reference)
+		testutil.MustDo(t, "get squash merge commit", err)
+		if diff := deep.Equal(commitLog.Metadata, Metadata{
+			"key":                   "value",
+			MetadataKeySquashedFrom: squashedFrom,
+			MetadataKeySquashedTo:   squashedTo,
+		}); diff != nil {
+			t.Fatalf("Round %d: squash merge commit metadata diff: %s", round, diff)
+		}
+		if len(commitLog.Parents) != 1 {
+			t.Fatalf("Round %d: squash merge commit parents %v, expected only the previous master commit", round, commitLog.Parents)
+		}
+		squashedFrom = squashedTo
+	}
+
+	// the master log holds the squash merges, but not the squashed commits
+	commits, _, err := c.ListCommits(ctx, repository, "master", "", -1)
+	testutil.MustDo(t, "list master commits", err)
+	var messages []string
+	for _, commit := range commits {
+		messages = append(messages, commit.Message)
+	}
+	if diff := deep.Equal(messages, []string{"squash", "squash", "commit file0", createRepositoryCommitMessage}); diff != nil {
+		t.Fatal("Master log messages diff:", diff)
+	}
+}
+
+func TestCataloger_Merge_Strategy(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file0", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	_, err = c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch1", err)
+
+	// conflicting change of file0 on both branches
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "master")
+	_, err = c.Commit(ctx, repository, "master", "change file0 on master", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file0", nil, "branch1")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "change file0 on branch1", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithStrategy("no-such-strategy"))
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Merge with unknown strategy err=%v, expected %v", err, ErrInvalidValue)
+	}
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	if !errors.Is(err, ErrConflictFound) {
+		t.Fatalf("Merge without strategy err=%v, expected %v", err, ErrConflictFound)
+	}
+
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithStrategy(MergeStrategyDestWins))
+	testutil.MustDo(t, "merge branch1 into master keeping master", err)
+	if diff := deep.Equal(res.Summary, map[DifferenceType]int{DifferenceTypeAdded: 1}); diff != nil {
+		t.Fatal("Merge dest-wins summary diff:", diff)
+	}
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "file0", Seed: "master"},
+		{Path: "file1"},
+	})
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file0", nil, "branch1-again")
+	_, err = c.Commit(ctx, repository, "branch1", "change file0 on branch1 again", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "master-again")
+	_, err = c.Commit(ctx, repository, "master", "change file0 on master again", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithStrategy(MergeStrategySourceWins))
+	testutil.MustDo(t, "merge branch1 into master taking branch1", err)
+	if diff := deep.Equal(res.Summary, map[DifferenceType]int{DifferenceTypeChanged: 1}); diff != nil {
+		t.Fatal("Merge source-wins summary diff:", diff)
+	}
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "file0", Seed: "branch1-again"},
+		{Path: "file1"},
+	})
+}
+
+func TestCataloger_Merge_DryRun(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file0", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithDryRun())
+	testutil.MustDo(t, "dry run merge branch1 into master", err)
+	if diff := deep.Equal(res.Summary, map[DifferenceType]int{DifferenceTypeAdded: 1}); diff != nil {
+		t.Fatal("Dry run merge summary diff:", diff)
+	}
+	if res.Reference != "" {
+		t.Fatalf("Dry run merge reference=%s, expected none", res.Reference)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "file1", false)
+
+	// conflicting change to file0 on both branches
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "master")
+	_, err = c.Commit(ctx, repository, "master", "change file0 on master", "tester", nil)
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file0", nil, "branch1")
+	_, err = c.Commit(ctx, repository, "branch1", "change file0 on branch1", "tester", nil)
+	testutil.MustDo(t, "commit to branch1", err)
+
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithDryRun())
+	if !errors.Is(err, ErrConflictFound) {
+		t.Fatalf("Dry run merge err=%v, expected %v", err, ErrConflictFound)
+	}
+	if diff := deep.Equal(res.Conflicts, []*MergeConflict{{Path: "file0", Type: ConflictTypeBothModified}}); diff != nil {
+		t.Fatal("Dry run merge conflicts diff:", diff)
+	}
+	conflicts, _, err := c.ListMergeConflicts(ctx, repository, "branch1", "master", -1, "")
+	testutil.MustDo(t, "list merge conflicts", err)
+	if len(conflicts) != 0 {
+		t.Fatalf("Dry run merge kept conflicts %v, expected none", conflicts)
+	}
+
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, WithDryRun(), WithStrategy(MergeStrategySourceWins))
+	testutil.MustDo(t, "dry run merge branch1 into master taking branch1", err)
+	if diff := deep.Equal(res.Summary, map[DifferenceType]int{DifferenceTypeAdded: 1, DifferenceTypeChanged: 1}); diff != nil {
+		t.Fatal("Dry run merge with strategy summary diff:", diff)
+	}
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{{Path: "file0", Seed: "master"}})
+}
@@ -0,0 +1,296 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// rebaseCommit is a commit of the branch being rebased, replayed by the rebase
+type rebaseCommit struct {
+	CommitID         CommitID `db:"commit_id"`
+	PreviousCommitID CommitID `db:"previous_commit_id"`
+	Committer        string   `db:"committer"`
+	Message          string   `db:"message"`
+	Metadata         Metadata `db:"metadata"`
+	MergeType        string   `db:"merge_type"`
+}
+
+// Rebase replays the commits of branch since it last merged from its parent branch onto, on top
+// of the current head of onto.  The first replayed commit brings in the changes of onto, as a
+// merge from the parent does, and the rest follow it with their committer, message and metadata.
+// The replayed commits replace the original commits, whose references are no longer valid.
+//
+// The branch must have no uncommitted changes, and its commits to replay must not be referenced
+// by merges, other branches or tags.  Changes of onto that conflict with the branch fail the
+// rebase with ErrConflictFound and leave the branch unchanged; the result then holds the
+// conflicts with the replayed commit that first changed each of their paths.  A branch with no
+// commits to replay is merged from onto with committer.
+func (c *cataloger) Rebase(ctx context.Context, repository, branch, onto, committer string) (*RebaseResult, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "onto", IsValid: ValidateBranchName(onto)},
+		{Name: "committer", IsValid: ValidateCommitter(committer)},
+	}); err != nil {
+		return nil, err
+	}
+
+	result := &RebaseResult{}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		*result = RebaseResult{}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		ontoID, err := getBranchID(tx, repository, onto, LockTypeShare)
+		if err != nil {
+			return nil, fmt.Errorf("onto branch: %w", err)
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		relation, err := getBranchesRelationType(tx, ontoID, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("branch relation: %w", err)
+		}
+		if relation != RelationTypeFromParent {
+			return nil, fmt.Errorf("rebase onto a branch that is not the branch parent: %w", ErrOperationNotPermitted)
+		}
+		commits, err := getRebaseCommits(tx, branchID)
+		if err != nil {
+			return nil, err
+		}
+		advanced, err := hasParentAdvanced(tx, ontoID, branchID)
+		if err != nil {
+			return nil, err
+		}
+		if !advanced {
+			return nil, ErrNoDifferenceWasFound
+		}
+
+		if err := c.doDiffByRelation(tx, relation, ontoID, branchID); err != nil {
+			return nil, err
+		}
+		summary, err := c.getDiffSummary(tx)
+		if err != nil {
+			return nil, err
+		}
+		if summary[DifferenceTypeConflict] > 0 {
+			result.Conflicts, err = getRebaseConflicts(tx, branch, branchID, commits)
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
+			return nil, err
+		}
+
+		if len(commits) == 0 {
+			commitID, err := c.doMergeByRelation(tx, relation, ontoID, branchID, committer, formatRebaseMessage(branch, onto), nil)
+			if err != nil {
+				return nil, err
+			}
+			result.Reference = MakeReference(branch, commitID)
+			return nil, insertRepositoryEvent(tx, repository, EventTypeCommitCreated, branch, "", result.Reference)
+		}
+
+		commitIDs, err := c.replayCommits(tx, ontoID, branchID, commits)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		for _, commitID := range commitIDs {
+			reference := MakeReference(branch, commitID)
+			result.Commits = append(result.Commits, reference)
+			if err := insertRepositoryEvent(tx, repository, EventTypeCommitCreated, branch, "", reference); err != nil {
+				return nil, err
+			}
+		}
+		result.Reference = result.Commits[len(result.Commits)-1]
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return result, err
+	}
+	if len(result.Conflicts) > 0 {
+		return result, ErrConflictFound
+	}
+	return result, nil
+}
+
+func formatRebaseMessage(branch, onto string) string {
+	return fmt.Sprintf("Rebase '%s' onto '%s'", branch, onto)
+}
+
+// getRebaseCommits returns the commits of the branch since its last merge from its parent, oldest
+// first, after verifying they can be replaced
+func getRebaseCommits(tx db.Tx, branchID int64) ([]*rebaseCommit, error) {
+	var uncommitted bool
+	if err := tx.Get(&uncommitted, `SELECT EXISTS (SELECT 1 FROM catalog_entries WHERE branch_id = $1 AND min_commit = 0)`,
+		branchID); err != nil {
+		return nil, fmt.Errorf("uncommitted check: %w", err)
+	}
+	if uncommitted {
+		return nil, fmt.Errorf("branch has uncommitted changes: %w", ErrOperationNotPermitted)
+	}
+	var lastParentMerge CommitID
+	if err := tx.Get(&lastParentMerge, `SELECT COALESCE(MAX(commit_id), 0) FROM catalog_commits WHERE branch_id = $1 AND merge_type = 'from_parent'`,
+		branchID); err != nil {
+		return nil, fmt.Errorf("last merge from parent: %w", err)
+	}
+	var commits []*rebaseCommit
+	if err := tx.Select(&commits, `SELECT commit_id, previous_commit_id, committer, message, metadata, merge_type
+		FROM catalog_commits WHERE branch_id = $1 AND commit_id > $2
+		ORDER BY commit_id`, branchID, lastParentMerge); err != nil {
+		return nil, fmt.Errorf("commits to replay: %w", err)
+	}
+	for _, commit := range commits {
+		if commit.MergeType != string(RelationTypeNone) {
+			return nil, fmt.Errorf("branch has merge commits to replay: %w", ErrOperationNotPermitted)
+		}
+	}
+	// merges, branches created from the branch and tags refer to commits by ID
+	var referenced bool
+	if err := tx.Get(&referenced, `SELECT
+			EXISTS (SELECT 1 FROM catalog_commits WHERE merge_source_branch = $1 AND merge_source_commit > $2) OR
+			EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id = $1 AND commit_id > $2)`,
+		branchID, lastParentMerge); err != nil {
+		return nil, fmt.Errorf("references check: %w", err)
+	}
+	if referenced {
+		return nil, fmt.Errorf("commits to replay are referenced by merges, branches or tags: %w", ErrOperationNotPermitted)
+	}
+	return commits, nil
+}
+
+// hasParentAdvanced checks if the parent branch has commits since the branch last merged from it
+func hasParentAdvanced(tx db.Tx, parentID, branchID int64) (bool, error) {
+	var advanced bool
+	err := tx.Get(&advanced, `SELECT COALESCE(MAX(merge_source_commit), 0) < (SELECT COALESCE(MAX(commit_id), 0) FROM catalog_commits WHERE branch_id = $2)
+		FROM catalog_commits WHERE branch_id = $1 AND merge_type = 'from_parent' AND merge_source_branch = $2`,
+		branchID, parentID)
+	if err != nil {
+		return false, fmt.Errorf("parent commits check: %w", err)
+	}
+	return advanced, nil
+}
+
+// getRebaseConflicts returns the conflicts found by the rebase diff, each with the replayed commit
+// that first changed its path, ordered by commit and path
+func getRebaseConflicts(tx db.Tx, branch string, branchID int64, commits []*rebaseCommit) ([]*RebaseConflict, error) {
+	mergeConflicts, err := getMergeConflicts(tx)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(mergeConflicts))
+	for i, conflict := range mergeConflicts {
+		paths[i] = conflict.Path
+	}
+	query, args, err := psql.Select("path", "min_commit", "max_commit").
+		From("catalog_entries").
+		Where(sq.Eq{"branch_id": branchID, "path": paths}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("format conflict entries query: %w", err)
+	}
+	var entries []struct {
+		Path      string   `db:"path"`
+		MinCommit CommitID `db:"min_commit"`
+		MaxCommit CommitID `db:"max_commit"`
+	}
+	if err := tx.Select(&entries, query, args...); err != nil {
+		return nil, fmt.Errorf("select conflict entries: %w", err)
+	}
+	// a commit changes the entries it creates, and the entries it deletes - those are closed at
+	// its previous commit
+	createdBy := make(map[CommitID]int, len(commits))
+	deletedBy := make(map[CommitID]int, len(commits))
+	for i, commit := range commits {
+		createdBy[commit.CommitID] = i
+		deletedBy[commit.PreviousCommitID] = i
+	}
+	firstChange := make(map[string]int, len(paths))
+	for _, ent := range entries {
+		i, ok := createdBy[ent.MinCommit]
+		if j, deleted := deletedBy[ent.MaxCommit]; deleted && (!ok || j < i) {
+			i, ok = j, true
+		}
+		if first, found := firstChange[ent.Path]; ok && (!found || i < first) {
+			firstChange[ent.Path] = i
+		}
+	}
+	conflicts := make([]*RebaseConflict, len(mergeConflicts))
+	for i, conflict := range mergeConflicts {
+		// the diff reports only paths changed since the last merge from the parent
+		commit := commits[firstChange[conflict.Path]]
+		conflicts[i] = &RebaseConflict{
+			Reference:     MakeReference(branch, commit.CommitID),
+			MergeConflict: *conflict,
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return firstChange[conflicts[i].Path] < firstChange[conflicts[j].Path]
+	})
+	return conflicts, nil
+}
+
+// replayCommits replaces commits of the branch with new commits on top of the head of the parent
+// branch, and returns their IDs.  Requires the diff results of merging the parent into the branch.
+func (c *cataloger) replayCommits(tx db.Tx, parentID, branchID int64, commits []*rebaseCommit) ([]CommitID, error) {
+	commitIDs := make([]CommitID, len(commits))
+	for i := range commits {
+		var err error
+		commitIDs[i], err = getNextCommitID(tx)
+		if err != nil {
+			return nil, fmt.Errorf("next commit id: %w", err)
+		}
+	}
+	lastCommitID := commits[len(commits)-1].CommitID
+
+	// the first commit merges from the parent: it takes the changes of the parent, and records its
+	// head as the new lineage of the branch
+	first := commits[0]
+	if err := c.mergeFromParent(tx, lastCommitID, commitIDs[0], parentID, branchID, first.Committer, first.Message, first.Metadata); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE catalog_commits SET previous_commit_id = $3 WHERE branch_id = $1 AND commit_id = $2`,
+		branchID, commitIDs[0], first.PreviousCommitID); err != nil {
+		return nil, fmt.Errorf("update first commit: %w", err)
+	}
+
+	// move the entries changed by each commit to its replayed commit.  Replayed IDs are greater
+	// than all the replaced IDs, so the moves do not overlap.
+	for i, commit := range commits {
+		if _, err := tx.Exec(`UPDATE catalog_entries SET min_commit = $3 WHERE branch_id = $1 AND min_commit = $2`,
+			branchID, commit.CommitID, commitIDs[i]); err != nil {
+			return nil, fmt.Errorf("replay created entries: %w", err)
+		}
+		// entries deleted by the next commit
+		if i < len(commits)-1 {
+			if _, err := tx.Exec(`UPDATE catalog_entries SET max_commit = $3 WHERE branch_id = $1 AND max_commit = $2`,
+				branchID, commit.CommitID, commitIDs[i]); err != nil {
+				return nil, fmt.Errorf("replay deleted entries: %w", err)
+			}
+		}
+	}
+
+	for i, commit := range commits {
+		if _, err := tx.Exec(`DELETE FROM catalog_commits WHERE branch_id = $1 AND commit_id = $2`,
+			branchID, commit.CommitID); err != nil {
+			return nil, fmt.Errorf("delete replaced commit: %w", err)
+		}
+		if i == 0 {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,creation_date,metadata,merge_type)
+			VALUES ($1,$2,$3,$4,$5,transaction_timestamp(),$6,$7)`,
+			branchID, commitIDs[i], commitIDs[i-1], commit.Committer, commit.Message, commit.Metadata, RelationTypeNone); err != nil {
+			return nil, fmt.Errorf("insert replayed commit: %w", err)
+		}
+	}
+	return commitIDs, nil
+}
@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Rebase(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "changed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "deleted", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "first", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "first commit", "tester1", Metadata{"k": "v"})
+	testutil.MustDo(t, "commit first", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "second", nil, "")
+	testutil.MustDo(t, "delete first", c.DeleteEntry(ctx, repository, "branch1", "first"))
+	_, err = c.Commit(ctx, repository, "branch1", "second commit", "tester2", nil)
+	testutil.MustDo(t, "commit second", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "changed", nil, "seed2")
+	testutil.MustDo(t, "delete on master", c.DeleteEntry(ctx, repository, "master", "deleted"))
+	_, err = c.Commit(ctx, repository, "master", "c2", "tester", nil)
+	testutil.MustDo(t, "commit c2", err)
+
+	res, err := c.Rebase(ctx, repository, "branch1", "master", "rebaser")
+	testutil.MustDo(t, "rebase", err)
+	if len(res.Commits) != 2 || res.Reference != res.Commits[1] {
+		t.Fatalf("Rebase() commits %v reference %s, expected 2 commits ending at the reference", res.Commits, res.Reference)
+	}
+
+	// the first replayed commit holds the changes of master and of the first commit
+	testCatalogerGetEntry(t, ctx, c, repository, res.Commits[0], "first", true)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Commits[0], "second", false)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Commits[0], "deleted", false)
+	ent, err := c.GetEntry(ctx, repository, res.Commits[0], "changed", GetEntryParams{})
+	testutil.MustDo(t, "get changed", err)
+	if expected := testCreateEntryCalcChecksum("changed", "seed2"); ent.Checksum != expected {
+		t.Errorf("changed checksum %s at first replayed commit, expected %s from master", ent.Checksum, expected)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, res.Reference, "first", false)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Reference, "second", true)
+
+	commits, _, err := c.ListCommits(ctx, repository, "branch1", "", 2)
+	testutil.MustDo(t, "list commits", err)
+	expected := []struct{ Reference, Committer, Message string }{
+		{Reference: res.Commits[1], Committer: "tester2", Message: "second commit"},
+		{Reference: res.Commits[0], Committer: "tester1", Message: "first commit"},
+	}
+	if len(commits) != len(expected) {
+		t.Fatalf("ListCommits() got %d commits, expected %d", len(commits), len(expected))
+	}
+	for i, commit := range commits {
+		if commit.Reference != expected[i].Reference || commit.Committer != expected[i].Committer || commit.Message != expected[i].Message {
+			t.Errorf("commit %d: %s by %s %q, expected %s by %s %q", i, commit.Reference, commit.Committer, commit.Message,
+				expected[i].Reference, expected[i].Committer, expected[i].Message)
+		}
+	}
+	if commits[1].Metadata["k"] != "v" {
+		t.Errorf("first replayed commit metadata %v, expected the original metadata", commits[1].Metadata)
+	}
+
+	_, err = c.Rebase(ctx, repository, "branch1", "master", "rebaser")
+	if !errors.Is(err, ErrNoDifferenceWasFound) {
+		t.Errorf("Rebase() again err=%v, expected %s", err, ErrNoDifferenceWasFound)
+	}
+}
+
+func TestCataloger_Rebase_Conflict(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "first", nil, "")
+	_, err := c.Commit(ctx, repository, "branch1", "first commit", "tester", nil)
+	testutil.MustDo(t, "commit first", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "second", nil, "")
+	second, err := c.Commit(ctx, repository, "branch1", "second commit", "tester", nil)
+	testutil.MustDo(t, "commit second", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "second", nil, "master")
+	_, err = c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+
+	res, err := c.Rebase(ctx, repository, "branch1", "master", "rebaser")
+	if !errors.Is(err, ErrConflictFound) {
+		t.Fatalf("Rebase() err=%v, expected %s", err, ErrConflictFound)
+	}
+	if len(res.Conflicts) != 1 {
+		t.Fatalf("Rebase() conflicts %v, expected one", res.Conflicts)
+	}
+	if conflict := res.Conflicts[0]; conflict.Path != "second" || conflict.Reference != second.Reference {
+		t.Errorf("Rebase() conflict on %s at %s, expected second at %s", conflict.Path, conflict.Reference, second.Reference)
+	}
+	// the branch is unchanged
+	commits, _, err := c.ListCommits(ctx, repository, "branch1", "", 1)
+	testutil.MustDo(t, "list commits", err)
+	if len(commits) != 1 || commits[0].Reference != second.Reference {
+		t.Errorf("branch head after failed rebase %v, expected %s", commits, second.Reference)
+	}
+}
+
+func TestCataloger_Rebase_NotPermitted(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+
+	t.Run("uncommitted", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "uncommitted", "master")
+		testCatalogerCreateEntry(t, ctx, c, repository, "uncommitted", "uncommitted", nil, "")
+		_, err := c.Rebase(ctx, repository, "uncommitted", "master", "rebaser")
+		if !errors.Is(err, ErrOperationNotPermitted) {
+			t.Errorf("Rebase() err=%v, expected %s", err, ErrOperationNotPermitted)
+		}
+	})
+	t.Run("referenced", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "referenced", "master")
+		testCatalogerCreateEntry(t, ctx, c, repository, "referenced", "referenced", nil, "")
+		_, err := c.Commit(ctx, repository, "referenced", "referenced commit", "tester", nil)
+		testutil.MustDo(t, "commit referenced", err)
+		testCatalogerBranch(t, ctx, c, repository, "child", "referenced")
+		_, err = c.Rebase(ctx, repository, "referenced", "master", "rebaser")
+		if !errors.Is(err, ErrOperationNotPermitted) {
+			t.Errorf("Rebase() err=%v, expected %s", err, ErrOperationNotPermitted)
+		}
+	})
+	t.Run("not_parent", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "other", "master")
+		_, err := c.Rebase(ctx, repository, "master", "other", "rebaser")
+		if !errors.Is(err, ErrOperationNotPermitted) {
+			t.Errorf("Rebase() err=%v, expected %s", err, ErrOperationNotPermitted)
+		}
+	})
+}
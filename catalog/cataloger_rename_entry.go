@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// RenameEntry moves the entry at sourcePath on branch to destinationPath.  The entry is written
+// to the destination and deleted from the source in a single transaction, so readers see either
+// the source or the destination, never both or neither.  An existing entry at destinationPath
+// is overwritten.  An expired source entry is not renamed, and fails with ErrExpired.
+func (c *cataloger) RenameEntry(ctx context.Context, repository, branch string, sourcePath, destinationPath string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "sourcePath", IsValid: ValidatePath(sourcePath)},
+		{Name: "destinationPath", IsValid: ValidatePath(destinationPath)},
+	}); err != nil {
+		return err
+	}
+	if sourcePath == destinationPath {
+		return fmt.Errorf("%w: destination is the source path", ErrInvalidValue)
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, sourcePath, destinationPath); err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, UncommittedID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		sql, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired").
+			FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+			Where(sq.Eq{"path": sourcePath, "is_deleted": false}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entry Entry
+		if err := tx.Get(&entry, sql, args...); errors.Is(err, db.ErrNotFound) {
+			return nil, ErrEntryNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		if entry.Expired {
+			return nil, ErrExpired
+		}
+		entry.Path = destinationPath
+		if _, err := insertEntry(tx, branchID, &entry); err != nil {
+			return nil, err
+		}
+		if err := deleteEntry(tx, branchID, sourcePath); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvent(tx, repository, EventTypeObjectStaged, branch, destinationPath, ""); err != nil {
+			return nil, err
+		}
+		return nil, insertRepositoryEvent(tx, repository, EventTypeObjectDeleted, branch, sourcePath, "")
+	}, c.txOpts(ctx)...)
+	return err
+}
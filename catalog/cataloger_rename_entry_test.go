@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_RenameEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	if err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "committed/file1",
+		Checksum:        "ff",
+		PhysicalAddress: "/addr1",
+		Size:            1,
+		Metadata:        Metadata{"k": "v"},
+	}, CreateEntryParams{}); err != nil {
+		t.Fatal("create entry for rename entry test:", err)
+	}
+	if err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "expired/file3",
+		Checksum:        "dd",
+		PhysicalAddress: "/addr3",
+		Size:            3,
+	}, CreateEntryParams{}); err != nil {
+		t.Fatal("create entry for rename entry test:", err)
+	}
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+		t.Fatal("commit for rename entry test:", err)
+	}
+	expireResults, err := readEntriesToExpire(t, ctx, c, repository, &Policy{Rules: []Rule{{
+		Enabled:      true,
+		FilterPrefix: "master/expired/",
+		Expiration:   Expiration{All: makeHours(0)},
+	}}})
+	testutil.MustDo(t, "read entries to expire", err)
+	testutil.MustDo(t, "mark entries expired", c.MarkEntriesExpired(ctx, repository, expireResults))
+	if err := c.CreateEntry(ctx, repository, "master", Entry{
+		Path:            "tmp/file2",
+		Checksum:        "ee",
+		PhysicalAddress: "/addr2",
+		Size:            2,
+	}, CreateEntryParams{}); err != nil {
+		t.Fatal("create entry for rename entry test:", err)
+	}
+
+	tests := []struct {
+		name            string
+		branch          string
+		sourcePath      string
+		destinationPath string
+		wantAddress     string
+		wantErr         error
+	}{
+		{name: "uncommitted", branch: "master", sourcePath: "tmp/file2", destinationPath: "data/file2", wantAddress: "/addr2"},
+		{name: "committed", branch: "master", sourcePath: "committed/file1", destinationPath: "data/file1", wantAddress: "/addr1"},
+		{name: "from parent", branch: "branch1", sourcePath: "committed/file1", destinationPath: "moved/file1", wantAddress: "/addr1"},
+		{name: "overwrite", branch: "master", sourcePath: "data/file2", destinationPath: "data/file1", wantAddress: "/addr2"},
+		{name: "not found", branch: "master", sourcePath: "tmp/file2", destinationPath: "data/file3", wantErr: ErrEntryNotFound},
+		{name: "same path", branch: "master", sourcePath: "data/file1", destinationPath: "data/file1", wantErr: ErrInvalidValue},
+		{name: "expired", branch: "master", sourcePath: "expired/file3", destinationPath: "data/file3", wantErr: ErrExpired},
+		{name: "no branch", branch: "branch2", sourcePath: "data/file1", destinationPath: "data/file4", wantErr: db.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.RenameEntry(ctx, repository, tt.branch, tt.sourcePath, tt.destinationPath)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RenameEntry() error = %s, expected %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenameEntry() error = %s, expected no error", err)
+			}
+			if _, err := c.GetEntry(ctx, repository, tt.branch, tt.sourcePath, GetEntryParams{}); !errors.Is(err, ErrEntryNotFound) {
+				t.Errorf("GetEntry() source %s error = %v, expected %s", tt.sourcePath, err, ErrEntryNotFound)
+			}
+			ent, err := c.GetEntry(ctx, repository, tt.branch, tt.destinationPath, GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry() destination %s error = %s", tt.destinationPath, err)
+			}
+			if ent.PhysicalAddress != tt.wantAddress {
+				t.Errorf("GetEntry() destination address = %s, expected %s", ent.PhysicalAddress, tt.wantAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Replication(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	primary := testCatalogerRepo(t, ctx, c, "repo", "master")
+	replica := testCatalogerUniqueID() + "-replica"
+	replicate := func(after int64) int64 {
+		t.Helper()
+		var buf bytes.Buffer
+		checkpoint, err := c.ExportReplication(ctx, primary, after, &buf)
+		testutil.MustDo(t, "export replication", err)
+		replicaCheckpoint, err := c.ImportReplication(ctx, replica, &buf)
+		testutil.MustDo(t, "import replication", err)
+		if replicaCheckpoint != checkpoint {
+			t.Fatalf("replica checkpoint %d, expected %d", replicaCheckpoint, checkpoint)
+		}
+		return checkpoint
+	}
+	verify := func(branches ...string) {
+		t.Helper()
+		for _, branch := range branches {
+			primaryEntries, _, err := c.ListEntries(ctx, primary, branch, "", "", "", -1)
+			testutil.MustDo(t, "list primary entries", err)
+			replicaEntries, _, err := c.ListEntries(ctx, replica, branch, "", "", "", -1)
+			testutil.MustDo(t, "list replica entries", err)
+			if len(replicaEntries) != len(primaryEntries) {
+				t.Fatalf("replica %s has %d entries, expected %d", branch, len(replicaEntries), len(primaryEntries))
+			}
+			for i := range primaryEntries {
+				if replicaEntries[i].Path != primaryEntries[i].Path || replicaEntries[i].Checksum != primaryEntries[i].Checksum {
+					t.Errorf("replica %s entry %d: %s (%s), expected %s (%s)", branch, i,
+						replicaEntries[i].Path, replicaEntries[i].Checksum, primaryEntries[i].Path, primaryEntries[i].Checksum)
+				}
+			}
+			primaryCommits, _, err := c.ListCommits(ctx, primary, branch, "", -1)
+			testutil.MustDo(t, "list primary commits", err)
+			replicaCommits, _, err := c.ListCommits(ctx, replica, branch, "", -1)
+			testutil.MustDo(t, "list replica commits", err)
+			if len(replicaCommits) != len(primaryCommits) || replicaCommits[0].Reference != primaryCommits[0].Reference {
+				t.Errorf("replica %s log has %d commits, head %s, expected %d commits, head %s", branch,
+					len(replicaCommits), replicaCommits[0].Reference, len(primaryCommits), primaryCommits[0].Reference)
+			}
+		}
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, primary, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, primary, "master", "file2", nil, "")
+	m1, err := c.Commit(ctx, primary, "master", "m1", "tester", nil)
+	testutil.MustDo(t, "commit m1", err)
+	_, err = c.CreateTag(ctx, primary, "v1", m1.Reference, "tester", "v1", nil)
+	testutil.MustDo(t, "create tag", err)
+	checkpoint := replicate(0)
+	verify("master")
+	replicaCheckpoint, err := c.GetReplicationCheckpoint(ctx, replica)
+	testutil.MustDo(t, "get replication checkpoint", err)
+	if replicaCheckpoint != checkpoint {
+		t.Errorf("GetReplicationCheckpoint() = %d, expected %d", replicaCheckpoint, checkpoint)
+	}
+	if _, err := c.GetReplicationCheckpoint(ctx, primary); !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("GetReplicationCheckpoint() of primary err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+	replicaRepo, err := c.GetRepository(ctx, replica)
+	testutil.MustDo(t, "get replica", err)
+	if !replicaRepo.ReadOnly {
+		t.Error("replica is not read-only")
+	}
+	tag, err := c.GetTag(ctx, replica, "v1")
+	testutil.MustDo(t, "get replicated tag", err)
+	if tag.CommitReference != m1.Reference {
+		t.Errorf("replicated tag points to %s, expected %s", tag.CommitReference, m1.Reference)
+	}
+
+	// changes after the checkpoint - replaced, deleted and new entries on a new branch
+	testCatalogerCreateEntry(t, ctx, c, primary, "master", "file1", nil, "seed1")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, primary, "master", "file2"))
+	_, err = c.Commit(ctx, primary, "master", "m2", "tester", nil)
+	testutil.MustDo(t, "commit m2", err)
+	testCatalogerBranch(t, ctx, c, primary, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, primary, "branch1", "file3", nil, "")
+	_, err = c.Commit(ctx, primary, "branch1", "b1", "tester", nil)
+	testutil.MustDo(t, "commit b1", err)
+	_, err = c.Merge(ctx, primary, "branch1", "master", "tester", "merge branch1", nil)
+	testutil.MustDo(t, "merge branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, primary, "master", "uncommitted", nil, "")
+	checkpoint = replicate(checkpoint)
+	if _, err := c.GetEntry(ctx, replica, "master", "uncommitted", GetEntryParams{}); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("uncommitted entry replicated, err=%v", err)
+	}
+	_, err = c.Commit(ctx, primary, "master", "m3", "tester", nil)
+	testutil.MustDo(t, "commit m3", err)
+	checkpoint = replicate(checkpoint)
+	verify("master", "branch1")
+
+	// streams must be applied in order
+	var buf bytes.Buffer
+	_, err = c.ExportReplication(ctx, primary, 0, &buf)
+	testutil.MustDo(t, "export replication", err)
+	if _, err := c.ImportReplication(ctx, replica, &buf); !errors.Is(err, ErrReplicationCheckpoint) {
+		t.Errorf("ImportReplication() of a stream before the checkpoint err=%v, expected %s", err, ErrReplicationCheckpoint)
+	}
+
+	// a stream is applied only when its digest matches
+	buf.Reset()
+	_, err = c.ExportReplication(ctx, primary, checkpoint, &buf)
+	testutil.MustDo(t, "export replication", err)
+	lines := strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	noDigest := strings.Join(lines[:len(lines)-1], "")
+	if _, err := c.ImportReplication(ctx, replica, strings.NewReader(noDigest)); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("ImportReplication() of a stream without digest err=%v, expected %s", err, ErrDigestMismatch)
+	}
+	tampered := strings.Replace(buf.String(), `"name":"master"`, `"name":"changed"`, 1)
+	if _, err := c.ImportReplication(ctx, replica, strings.NewReader(tampered)); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("ImportReplication() of a tampered stream err=%v, expected %s", err, ErrDigestMismatch)
+	}
+	if _, err := c.GetBranchReference(ctx, replica, "changed"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("branch of a tampered stream replicated, err=%v", err)
+	}
+
+	// a promoted replica accepts changes, and does not accept streams
+	readWrite := false
+	testutil.MustDo(t, "promote replica", c.UpdateRepository(ctx, replica, UpdateRepositoryParams{ReadOnly: &readWrite}))
+	testCatalogerCreateEntry(t, ctx, c, replica, "master", "file4", nil, "")
+	_, err = c.Commit(ctx, replica, "master", "promoted", "tester", nil)
+	testutil.MustDo(t, "commit to promoted replica", err)
+	buf.Reset()
+	_, err = c.ExportReplication(ctx, primary, checkpoint, &buf)
+	testutil.MustDo(t, "export replication", err)
+	if _, err := c.ImportReplication(ctx, replica, &buf); !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("ImportReplication() to promoted replica err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+}
@@ -18,11 +18,13 @@ func (c *cataloger) ResetBranch(ctx context.Context, repository, branch string)
 		if err != nil {
 			return nil, err
 		}
-		res, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=0`, branchID)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
 			return nil, err
 		}
-		_, err = res.RowsAffected()
+		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=0`, branchID)
 		return nil, err
 	}, c.txOpts(ctx)...)
 	return err
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ResetBranchToCommit moves the head of branch back to the commit reference points to, dropping
+// the uncommitted changes and the commits made after it.  Commits are numbered per branch, so only
+// a commit of the branch itself can become its head - such a commit is always an ancestor of the
+// head, and a reference to any other commit fails with ErrOperationNotPermitted.  Reset also fails
+// if a dropped commit is still referenced: tagged, merged into another branch or used as the
+// source of a branch.
+func (c *cataloger) ResetBranchToCommit(ctx context.Context, repository, branch, reference string) error {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return err
+	}
+	if ref.Branch != branch {
+		return fmt.Errorf("%w: commit is not on branch %s", ErrOperationNotPermitted, branch)
+	}
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch id: %w", err)
+		}
+		commitID := ref.CommitID
+		if commitID <= 0 {
+			commitID, err = getLastCommitIDByBranchID(tx, branchID)
+			if err != nil {
+				return nil, fmt.Errorf("last commit: %w", err)
+			}
+		} else {
+			var exists bool
+			err = tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM catalog_commits WHERE branch_id = $1 AND commit_id = $2)`,
+				branchID, commitID)
+			if err != nil {
+				return nil, fmt.Errorf("check commit: %w", err)
+			}
+			if !exists {
+				return nil, ErrCommitNotFound
+			}
+		}
+		if err := checkCommitsUnreferenced(tx, branchID, commitID); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
+			return nil, err
+		}
+		// uncommitted entries and entries committed after the commit
+		if _, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id = $1 AND (min_commit = 0 OR min_commit > $2)`,
+			branchID, commitID); err != nil {
+			return nil, fmt.Errorf("delete entries: %w", err)
+		}
+		// entries overwritten or deleted after the commit: their max_commit is the commit the
+		// branch was at when it was closed
+		if _, err := tx.Exec(`UPDATE catalog_entries SET max_commit = catalog_max_commit_id()
+			WHERE branch_id = $1 AND max_commit >= $2 AND max_commit <> catalog_max_commit_id()`,
+			branchID, commitID); err != nil {
+			return nil, fmt.Errorf("restore entries: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM catalog_commits WHERE branch_id = $1 AND commit_id > $2`,
+			branchID, commitID); err != nil {
+			return nil, fmt.Errorf("delete commits: %w", err)
+		}
+		if err := insertRepositoryEvent(tx, repository, EventTypeBranchReset, branch, "", MakeReference(branch, commitID)); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// checkCommitsUnreferenced fails with ErrOperationNotPermitted if a commit of branchID after
+// commitID is tagged, or was the source of a merge or of a branch
+func checkCommitsUnreferenced(tx db.Tx, branchID int64, commitID CommitID) error {
+	var referenced bool
+	err := tx.Get(&referenced, `SELECT EXISTS (SELECT 1 FROM catalog_commits
+			WHERE merge_source_branch = $1 AND merge_source_commit > $2 AND branch_id <> $1)`,
+		branchID, commitID)
+	if err != nil {
+		return fmt.Errorf("check merged commits: %w", err)
+	}
+	if referenced {
+		return fmt.Errorf("%w: a later commit was merged or branched from", ErrOperationNotPermitted)
+	}
+	err = tx.Get(&referenced, `SELECT EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id = $1 AND commit_id > $2)`,
+		branchID, commitID)
+	if err != nil {
+		return fmt.Errorf("check tagged commits: %w", err)
+	}
+	if referenced {
+		return fmt.Errorf("%w: a later commit is tagged", ErrOperationNotPermitted)
+	}
+	return nil
+}
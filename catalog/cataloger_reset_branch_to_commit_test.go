@@ -0,0 +1,93 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ResetBranchToCommit(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	c1, err := c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+
+	// overwrite, delete and add entries in later commits
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "seed2")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "master", "file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	c2, err := c.Commit(ctx, repository, "master", "c2", "tester", nil)
+	testutil.MustDo(t, "commit c2", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file4", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "c3", "tester", nil)
+	testutil.MustDo(t, "commit c3", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file5", nil, "")
+
+	// a tag on a later commit blocks the reset
+	_, err = c.CreateTag(ctx, repository, "v2", c2.Reference, "tester", "v2", nil)
+	testutil.MustDo(t, "create tag", err)
+	err = c.ResetBranchToCommit(ctx, repository, "master", c1.Reference)
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Fatalf("ResetBranchToCommit with tagged commit err=%s, expected %s", err, ErrOperationNotPermitted)
+	}
+	testutil.MustDo(t, "delete tag", c.DeleteTag(ctx, repository, "v2"))
+
+	testutil.MustDo(t, "reset to c1", c.ResetBranchToCommit(ctx, repository, "master", c1.Reference))
+
+	entries, _, err := c.ListEntries(ctx, repository, "master", "", "", "", -1)
+	testutil.MustDo(t, "list entries", err)
+	if len(entries) != 2 || entries[0].Path != "file1" || entries[1].Path != "file2" {
+		t.Fatalf("ListEntries after reset got %+v, expected file1 and file2", entries)
+	}
+	if entries[0].Checksum != testCreateEntryCalcChecksum("file1", "") {
+		t.Errorf("file1 checksum %s, expected the committed file1", entries[0].Checksum)
+	}
+	commits, _, err := c.ListCommits(ctx, repository, "master", "", -1)
+	testutil.MustDo(t, "list commits", err)
+	if len(commits) == 0 || commits[0].Reference != c1.Reference {
+		t.Fatalf("ListCommits after reset got %+v, expected head %s", commits, c1.Reference)
+	}
+
+	// the branch continues from the commit
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file6", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "c4", "tester", nil)
+	testutil.MustDo(t, "commit c4", err)
+	entries, _, err = c.ListEntries(ctx, repository, "master", "", "", "", -1)
+	testutil.MustDo(t, "list entries", err)
+	if len(entries) != 3 {
+		t.Fatalf("ListEntries after commit got %d entries, expected 3", len(entries))
+	}
+}
+
+func TestCataloger_ResetBranchToCommit_OtherBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	m1, err := c.Commit(ctx, repository, "master", "m1", "tester", nil)
+	testutil.MustDo(t, "commit m1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "m2", "tester", nil)
+	testutil.MustDo(t, "commit m2", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	// commits of another branch are not ancestors
+	err = c.ResetBranchToCommit(ctx, repository, "branch1", m1.Reference)
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("ResetBranchToCommit to master commit err=%s, expected %s", err, ErrOperationNotPermitted)
+	}
+	// master commits after m1 were branched from
+	err = c.ResetBranchToCommit(ctx, repository, "master", m1.Reference)
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("ResetBranchToCommit of branched commit err=%s, expected %s", err, ErrOperationNotPermitted)
+	}
+}
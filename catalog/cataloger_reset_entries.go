@@ -18,6 +18,12 @@ func (c *cataloger) ResetEntries(ctx context.Context, repository, branch string,
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
+			return nil, err
+		}
 		prefixCond := db.Prefix(prefix)
 		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND path LIKE $2 AND min_commit=0`, branchID, prefixCond)
 		return nil, err
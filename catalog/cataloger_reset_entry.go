@@ -21,6 +21,9 @@ func (c *cataloger) ResetEntry(ctx context.Context, repository, branch string, p
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		res, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND path=$2 AND min_commit=0`, branchID, path)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// ResetPath drops the uncommitted changes to path, or to the entries under it when path is a
+// directory, leaving the rest of the uncommitted changes on the branch.  Unlike ResetEntries
+// "a/b" does not match "a/bc".  Deleting an entry stores an uncommitted tombstone, so deletes
+// under path are undone as well.  Resetting a path without uncommitted changes is not an error.
+func (c *cataloger) ResetPath(ctx context.Context, repository, branch string, path string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "path", IsValid: ValidatePath(path)},
+	}); err != nil {
+		return err
+	}
+	dirPrefix := path
+	if !strings.HasSuffix(dirPrefix, DefaultPathDelimiter) {
+		dirPrefix += DefaultPathDelimiter
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=0 AND (path=$2 OR path LIKE $3)`,
+			branchID, path, db.Prefix(dirPrefix))
+		return nil, err
+	}, c.txOpts(ctx)...)
+	return err
+}
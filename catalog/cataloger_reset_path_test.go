@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ResetPath(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/committed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/bc", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+
+	// uncommitted changes: a new entry, an overwrite and a delete under a/b, and two outside it
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/c/new", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b", nil, "")
+	testutil.MustDo(t, "delete committed", c.DeleteEntry(ctx, repository, "master", "a/b/committed"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/bc", nil, "seed2")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/other", nil, "")
+
+	before, err := c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get branch", err)
+	testutil.MustDo(t, "reset path", c.ResetPath(ctx, repository, "master", "a/b"))
+	after, err := c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get branch after reset", err)
+	if after.Version != before.Version+1 {
+		t.Errorf("Branch version after ResetPath = %d, expected %d", after.Version, before.Version+1)
+	}
+
+	differences, _, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	var paths []string
+	for _, d := range differences {
+		paths = append(paths, d.Path)
+	}
+	if len(paths) != 2 || paths[0] != "a/bc" || paths[1] != "a/other" {
+		t.Fatalf("uncommitted changes after ResetPath %v, expected [a/bc a/other]", paths)
+	}
+	if _, err := c.GetEntry(ctx, repository, "master", "a/b/committed", GetEntryParams{}); err != nil {
+		t.Errorf("delete of a/b/committed was not reset: %s", err)
+	}
+
+	// nothing left to reset
+	testutil.MustDo(t, "reset path again", c.ResetPath(ctx, repository, "master", "a/b/"))
+}
@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/cache"
+	"github.com/treeverse/lakefs/db"
+)
+
+// ResolveReference resolves a reference expression to a reference.  Expressions are a branch at
+// a point in time ("master@{2020-03-01T00:00:00Z}") or a reference followed by ancestor suffixes
+// ("master~2", "master^"), which are resolved by following the first parent of each commit.  The
+// first parent of a branch's first commit is the commit the branch was created from.  A name is
+// resolved to the branch with that name, or if there is no such branch to the commit reference
+// of the tag with that name.  Any other reference is returned as is.
+func (c *cataloger) ResolveReference(ctx context.Context, repository, reference string) (string, error) {
+	if IsTimestampRef(reference) {
+		branch, ts, err := ParseTimestampRef(reference)
+		if err != nil {
+			return "", err
+		}
+		commit, err := c.GetCommitAt(ctx, repository, branch, ts)
+		if err != nil {
+			return "", err
+		}
+		return commit.Reference, nil
+	}
+	base, generations, err := ParseAncestorRef(reference)
+	if err != nil {
+		return "", err
+	}
+	if generations == 0 {
+		return c.resolveName(ctx, repository, base)
+	}
+	base, err = c.ResolveReference(ctx, repository, base)
+	if err != nil {
+		return "", err
+	}
+	// ancestors of a branch are the ancestors of its last commit
+	ref, err := ParseRef(base)
+	if err != nil {
+		return "", err
+	}
+	if ref.CommitID == UncommittedID {
+		base = MakeReference(ref.Branch, CommittedID)
+	}
+	commit, err := c.GetCommit(ctx, repository, base)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < generations; i++ {
+		parent := commit.FirstParent()
+		if parent == "" {
+			return "", ErrCommitNotFound
+		}
+		commit, err = c.GetCommit(ctx, repository, parent)
+		if err != nil {
+			return "", err
+		}
+	}
+	return commit.Reference, nil
+}
+
+// resolveName resolves a branch or a tag name.  Branches take precedence over tags with the same
+// name.  A reference that is neither is returned as is, so the caller reports the branch lookup
+// error it reported before tags were introduced.
+func (c *cataloger) resolveName(ctx context.Context, repository, reference string) (string, error) {
+	if strings.HasPrefix(reference, CommitPrefix) || strings.HasSuffix(reference, CommittedSuffix) ||
+		!IsValidTagName(reference) || !IsValidRepositoryName(repository) {
+		return reference, nil
+	}
+	// a cached branch resolves with no database access; on a miss the branch, and if there is no
+	// such branch the tag, are looked up by a single transaction
+	var tagReference string
+	lookup := func(repository string, branch string) (int64, error) {
+		res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+			branchID, err := getBranchID(tx, repository, branch, LockTypeNone)
+			if !errors.Is(err, db.ErrNotFound) {
+				return branchID, err
+			}
+			tagReference, err = getTagCommitReference(tx, repository, branch)
+			if err == nil {
+				// not a branch - fail the lookup so the name is not cached as a branch
+				err = db.ErrNotFound
+			}
+			return nil, err
+		}, c.txOpts(ctx, db.ReadOnly())...)
+		if err != nil {
+			return 0, err
+		}
+		return res.(int64), nil
+	}
+	_, err := c.cache.BranchID(repository, reference, lookup)
+	if errors.Is(err, cache.ErrCacheItemNotFound) {
+		// a concurrent lookup of the same name failed, possibly because it is a tag
+		_, err = lookup(repository, reference)
+	}
+	switch {
+	case tagReference != "":
+		return tagReference, nil
+	case err == nil, errors.Is(err, db.ErrNotFound):
+		return reference, nil
+	default:
+		return "", err
+	}
+}
+
+// getTagCommitReference returns the commit reference of the tag named name, or an empty
+// reference if there is no such tag
+func getTagCommitReference(tx db.Tx, repository, name string) (string, error) {
+	repoID, err := getRepositoryID(tx, repository)
+	if errors.Is(err, db.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	query, args, err := sqTags(repoID).Where(sq.Eq{"t.name": name}).ToSql()
+	if err != nil {
+		return "", fmt.Errorf("build sql: %w", err)
+	}
+	var raw tagRaw
+	err = tx.Get(&raw, query, args...)
+	if errors.Is(err, db.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return raw.toTag().CommitReference, nil
+}
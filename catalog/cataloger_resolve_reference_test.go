@@ -0,0 +1,84 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ResolveReference(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	initialCommit, err := c.GetCommit(ctx, repository, "master:HEAD")
+	testutil.MustDo(t, "initial commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "tester", nil)
+	testutil.MustDo(t, "commit1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file2", nil, "")
+	commit2, err := c.Commit(ctx, repository, "master", "commit2", "tester", nil)
+	testutil.MustDo(t, "commit2", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	_, err = c.CreateTag(ctx, repository, "v1", commit1.Reference, "tester", "", nil)
+	testutil.MustDo(t, "tag v1", err)
+	_, err = c.CreateTag(ctx, repository, "branch1", commit1.Reference, "tester", "", nil)
+	testutil.MustDo(t, "tag branch1", err)
+
+	tests := []struct {
+		name      string
+		reference string
+		want      string
+		wantErr   error
+	}{
+		{name: "branch", reference: "master", want: "master"},
+		{name: "commit", reference: commit1.Reference, want: commit1.Reference},
+		{name: "branch parent", reference: "master^", want: commit1.Reference},
+		{name: "branch ancestor", reference: "master~2", want: initialCommit.Reference},
+		{name: "committed branch parent", reference: "master:HEAD^", want: commit1.Reference},
+		{name: "commit parent", reference: commit2.Reference + "^", want: commit1.Reference},
+		{name: "zero generations", reference: "master~0", want: "master"},
+		{name: "branch first commit parent", reference: "branch1^", want: commit2.Reference},
+		{name: "branch first commit ancestor", reference: "branch1~2", want: commit1.Reference},
+		{name: "timestamp", reference: "master@{" + commit2.CreationDate.Add(time.Second).Format(time.RFC3339) + "}", want: commit2.Reference},
+		{name: "tag", reference: "v1", want: commit1.Reference},
+		{name: "tag parent", reference: "v1^", want: initialCommit.Reference},
+		{name: "branch before tag", reference: "branch1", want: "branch1"},
+		{name: "unknown name", reference: "no-such-ref", want: "no-such-ref"},
+		{name: "too far back", reference: "master~3", wantErr: ErrCommitNotFound},
+		{name: "invalid suffix", reference: "master~x", wantErr: ErrInvalidReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.ResolveReference(ctx, repository, tt.reference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveReference() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// reads accept reference expressions
+	if _, err := c.GetEntry(ctx, repository, "master^", "/file1", GetEntryParams{}); err != nil {
+		t.Errorf("GetEntry() at master^ error = %s, expected entry", err)
+	}
+	if _, err := c.GetEntry(ctx, repository, "master^", "/file2", GetEntryParams{}); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("GetEntry() at master^ error = %v, expected %s", err, ErrEntryNotFound)
+	}
+	entries, _, err := c.ListEntries(ctx, repository, "master~2", "", "", "", -1)
+	testutil.MustDo(t, "list entries at master~2", err)
+	if len(entries) != 0 {
+		t.Errorf("ListEntries() at master~2 got %d entries, expected none", len(entries))
+	}
+	commits, _, err := c.ListCommits(ctx, repository, "v1", "", -1)
+	testutil.MustDo(t, "list commits at v1", err)
+	if len(commits) == 0 || commits[0].Reference != commit1.Reference {
+		t.Errorf("ListCommits() at v1 expected to start at %s", commit1.Reference)
+	}
+}
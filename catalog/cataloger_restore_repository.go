@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/ident"
+)
+
+// RestoreRepository creates repository from a backup written by DumpRepository, after verifying the
+// digest of the backup and the content address of each of its tags.  The repository uses
+// storageNamespace, or the storage namespace of the dumped repository when empty.  When they
+// differ, relative physical addresses are qualified with the dumped storage namespace, so entries
+// keep pointing to the same objects.  Commits keep their IDs.
+func (c *cataloger) RestoreRepository(ctx context.Context, repository, storageNamespace string, r io.Reader) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	rr, err := newReplicationReader(r)
+	if err != nil {
+		return err
+	}
+	if rr.Header.After != 0 {
+		return fmt.Errorf("%w: backup starts after commit %d", ErrInvalidValue, rr.Header.After)
+	}
+	if storageNamespace == "" {
+		storageNamespace = rr.Header.StorageNamespace
+	}
+	qualifyAddress := storageNamespace != rr.Header.StorageNamespace
+	prepare := func(rec *replicationRecord) error {
+		switch {
+		case rec.Type == replicationRecordTag:
+			tag := rec.Tag
+			ok, err := ident.Verify(tag.ID, &Tag{
+				Name:            tag.Name,
+				CommitReference: MakeReference(tag.Branch, tag.CommitID),
+				Tagger:          tag.Tagger,
+				Message:         tag.Message,
+				Metadata:        tag.Metadata,
+				CreationDate:    tag.CreationDate,
+			})
+			if err != nil {
+				return fmt.Errorf("tag %s: %w", tag.Name, err)
+			}
+			if !ok {
+				return fmt.Errorf("%w: tag %s does not hash to %s", ErrDigestMismatch, tag.Name, tag.ID)
+			}
+		case rec.Type == replicationRecordEntry && qualifyAddress && rec.Entry.PhysicalAddress != "":
+			rec.Entry.PhysicalAddress = qualifyPhysicalAddress(rr.Header.StorageNamespace, rec.Entry.PhysicalAddress)
+		}
+		return nil
+	}
+
+	// the backup is applied while it is read, so the transaction cannot be retried
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		// the default branch is set once the branches are in place
+		if _, err := tx.Exec(`SET CONSTRAINTS catalog_repositories_branches_id_fk DEFERRED`); err != nil {
+			return nil, fmt.Errorf("set constraints: %w", err)
+		}
+		var repoID int
+		err := tx.Get(&repoID, `INSERT INTO catalog_repositories (id,name,storage_namespace,creation_date,default_branch,hash_algorithm)
+			VALUES (nextval('catalog_repositories_id_seq'), $1, $2, transaction_timestamp(), 0, $3)
+			RETURNING id`,
+			repository, storageNamespace, rr.Header.hashAlgorithm())
+		if db.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("repository %s: %w", repository, db.ErrAlreadyExists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("insert repository: %w", err)
+		}
+		if err := c.checkRepositoriesQuota(tx); err != nil {
+			return nil, err
+		}
+		return nil, applyReplicationStream(tx, repoID, rr, prepare)
+	}, c.txOpts(ctx, db.WithMaxAttempts(1))...)
+	return err
+}
@@ -68,12 +68,22 @@ func byRepository(repository string) sq.Sqlizer {
 	return sq.Expr("catalog_repositories.name = ?", repository)
 }
 
+// retentionBranchName is the branch name retention rules match: a deleted branch is matched by
+// the name it had before it was deleted.
+const retentionBranchName = "COALESCE(catalog_branches.deleted_name, catalog_branches.name)"
+
+// byPathPrefix selects entries by a "branch/path" prefix.  The branch part may be a search
+// pattern (e.g. "feature-*/") in order to apply a rule to a group of branches.
 func byPathPrefix(pathPrefix string) sq.Sqlizer {
 	if len(pathPrefix) == 0 {
 		return sq.Eq{}
 	}
 	parts := strings.SplitN(pathPrefix, "/", 2)
-	branchExpr := sq.Eq{"catalog_branches.name": parts[0]}
+	var branchExpr sq.Sqlizer = sq.Eq{retentionBranchName: parts[0]}
+	if strings.ContainsAny(parts[0], searchPatternAnyChars+searchPatternSingleChar) {
+		_, likePattern := searchPatternToLike(parts[0])
+		branchExpr = sq.Like{retentionBranchName: likePattern}
+	}
 	if len(parts) == 1 || parts[1] == "" {
 		return branchExpr
 	}
@@ -193,18 +203,18 @@ func (e *expiryRows) Read() (*ExpireResult, error) {
 	}, nil
 }
 
-func (c *cataloger) QueryEntriesToExpire(ctx context.Context, repositoryName string, policy *Policy) (ExpiryRows, error) {
-	logger := logging.FromContext(ctx).WithField("policy", *policy)
-
+// buildExpiryQuery returns the SQL selecting the entries of repositoryName to expire according
+// to policy, keeping only those entries whose object has no other references.
+func buildExpiryQuery(repositoryName string, policy *Policy) (string, []interface{}, error) {
 	// TODO(ariels): page!
 	expiryByEntriesQuery, err := buildRetentionQuery(repositoryName, policy, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("building query: %w", err)
+		return "", nil, fmt.Errorf("building query: %w", err)
 	}
 	// TODO(ariels): Get lowest possible isolation level here.
 	expiryByEntriesQueryString, args, err := expiryByEntriesQuery.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("converting query to SQL: %w", err)
+		return "", nil, fmt.Errorf("converting query to SQL: %w", err)
 	}
 
 	// Hold retention query results as a CTE in a WITH prefix.  Everything must live in a
@@ -212,26 +222,40 @@ func (c *cataloger) QueryEntriesToExpire(ctx context.Context, repositoryName str
 	// and using a transaction would close the returned rows iterator on exit... preventing
 	// the caller from reading the returned rows.
 
+	// Return only those entries to expire for which *all* entry references are due:
+	// An object may have been deduped onto several branches with different names
+	// and will have multiple entries; it can only be remove once it expires from
+	// all of those.  Entries copied from another repository reference the object by its
+	// qualified address.
 	dedupedQuery := fmt.Sprintf(`
-                    WITH to_expire AS (%s) SELECT * FROM to_expire
+                    WITH to_expire AS (%s),
+                        refs AS (SELECT physical_address, COUNT(*) c FROM catalog_entries GROUP BY physical_address)
+                    SELECT * FROM to_expire
                     WHERE physical_address IN (
                         SELECT a.physical_address FROM
-                            ((SELECT physical_address, COUNT(*) c FROM to_expire GROUP BY physical_address) AS a JOIN
-                             (SELECT physical_address, COUNT(*) c FROM catalog_entries GROUP BY physical_address) AS b
-                             ON a.physical_address = b.physical_address)
-                            WHERE a.c = b.c)
+                            (SELECT physical_address, COUNT(*) c FROM to_expire GROUP BY physical_address) AS a
+                            JOIN refs b ON a.physical_address = b.physical_address
+                            LEFT JOIN refs q ON q.physical_address = catalog_qualified_address(
+                                    (SELECT storage_namespace FROM catalog_repositories WHERE name = $%d), a.physical_address)
+                                AND q.physical_address <> a.physical_address
+                            WHERE a.c = b.c + COALESCE(q.c, 0))
                     `,
-		expiryByEntriesQueryString,
+		expiryByEntriesQueryString, len(args)+1,
 	)
+	return dedupedQuery, append(args, repositoryName), nil
+}
 
+func (c *cataloger) QueryEntriesToExpire(ctx context.Context, repositoryName string, policy *Policy) (ExpiryRows, error) {
+	logger := logging.FromContext(ctx).WithField("policy", *policy)
+
+	dedupedQuery, args, err := buildExpiryQuery(repositoryName, policy)
+	if err != nil {
+		return nil, err
+	}
 	logger.WithFields(logging.Fields{
 		"dedupe_query": dedupedQuery,
 		"args":         args,
 	}).Info("retention dedupe")
-	// Return only those entries to expire for which *all* entry references are due:
-	// An object may have been deduped onto several branches with different names
-	// and will have multiple entries; it can only be remove once it expires from
-	// all of those.
 	rows, err := c.db.WithContext(ctx).Query(dedupedQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("running query: %w", err)
@@ -298,21 +322,29 @@ func (c *cataloger) MarkEntriesExpired(ctx context.Context, repositoryName strin
 	return nil
 }
 
+// objectEntriesCondition matches the entries e that reference the object d of repository r:
+// entries of the repository by its address, and entries copied to other repositories by its
+// qualified address
+const objectEntriesCondition = `e.physical_address IN (d.physical_address, catalog_qualified_address(r.storage_namespace, d.physical_address))`
+
+// MarkObjectsForDeletion marks the repository objects that are no longer needed: objects whose
+// entries all expired, and objects no entry references any more, after the branches that held
+// them were reset or deleted.  Entries copied to other repositories reference an object by its
+// qualified address, and keep it.
 // TODO(ariels): chunk.
 func (c *cataloger) MarkObjectsForDeletion(ctx context.Context, repositoryName string) (int64, error) {
 	// TODO(ariels): This query is difficult to chunk.  One way: Perform the inner SELECT
 	// once into a temporary table, then in a separate transaction chunk the UPDATE by
 	// dedup_id (this is not yet the real deletion).
 	result, err := c.db.WithContext(ctx).Exec(`
-                    UPDATE catalog_object_dedup SET deleting=true
-                    WHERE repository_id IN (SELECT id FROM catalog_repositories WHERE name = $1) AND
-                          physical_address IN (
-                              SELECT physical_address FROM (
-                                SELECT physical_address, bool_and(is_expired) all_expired
-                                FROM catalog_entries
-                                GROUP BY physical_address
-                              ) physical_addresses_with_expiry
-                              WHERE all_expired)`, repositoryName)
+                    UPDATE catalog_object_dedup d SET deleting=true
+                    FROM catalog_repositories r
+                    WHERE r.id = d.repository_id AND r.name = $1 AND
+                          NOT EXISTS (
+                              SELECT 1 FROM catalog_entries e
+                              WHERE `+objectEntriesCondition+`
+                                  AND NOT e.is_expired)`,
+		repositoryName)
 	if err != nil {
 		return 0, err
 	}
@@ -345,19 +377,17 @@ func (s *StringRows) Read() (string, error) {
 //     the duration.
 func (c *cataloger) DeleteOrUnmarkObjectsForDeletion(ctx context.Context, repositoryName string) (StringRows, error) {
 	rows, err := c.db.WithContext(ctx).Query(`
-		WITH ids AS (SELECT id repository_id FROM catalog_repositories WHERE name = $1),
+		WITH ids AS (SELECT id repository_id, storage_namespace FROM catalog_repositories WHERE name = $1),
 		    update_result AS (
-			UPDATE catalog_object_dedup SET deleting=all_expired
-			 FROM (
-			     SELECT physical_address, bool_and(is_expired) all_expired
-			     FROM catalog_entries
-			     GROUP BY physical_address
-			 ) AS by_entries
-			 WHERE repository_id IN (SELECT repository_id FROM ids) AND
-			       catalog_object_dedup.physical_address = by_entries.physical_address
-			 RETURNING by_entries.physical_address, all_expired
+			UPDATE catalog_object_dedup SET deleting=NOT EXISTS (
+			     SELECT 1 FROM catalog_entries e
+			     WHERE e.physical_address IN (catalog_object_dedup.physical_address,
+			         catalog_qualified_address((SELECT storage_namespace FROM ids), catalog_object_dedup.physical_address))
+			         AND NOT e.is_expired)
+			 WHERE repository_id IN (SELECT repository_id FROM ids)
+			 RETURNING physical_address, deleting
 		    )
-		SELECT physical_address FROM update_result WHERE all_expired`,
+		SELECT physical_address FROM update_result WHERE deleting`,
 		repositoryName,
 	)
 	return StringRows{rows}, err
@@ -808,3 +808,219 @@ func TestCataloger_DeleteOrUnmarkObjectsForDeletion(t *testing.T) {
 		t.Errorf("expected other deleting: %s\nexpected %v got %v", diffs, expectedDeleting, deleting)
 	}
 }
+
+func TestCataloger_MarkObjectsForDeletion_Unreferenced(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	makeDedup := func(id int) CreateEntryParams {
+		return CreateEntryParams{
+			Dedup: DedupParams{
+				ID:               fmt.Sprintf("%08x", id),
+				StorageNamespace: "foo",
+			},
+		}
+	}
+	entries := []struct {
+		branch string
+		entry  Entry
+		params CreateEntryParams
+	}{
+		{branch: "branch1", entry: Entry{Path: "reset/1", PhysicalAddress: "reset-me", Checksum: "aa"}, params: makeDedup(111)},
+		{branch: "branch1", entry: Entry{Path: "reset/2", PhysicalAddress: "reset-me:x", Checksum: "aa"}, params: makeDedup(111)},
+		{branch: "master", entry: Entry{Path: "keep/1", PhysicalAddress: "keep-me", Checksum: "bb"}, params: makeDedup(222)},
+		{branch: "master", entry: Entry{Path: "keep/2", PhysicalAddress: "keep-me:x", Checksum: "bb"}, params: makeDedup(222)},
+	}
+	for _, e := range entries {
+		if err := c.CreateEntry(ctx, repository, e.branch, e.entry, e.params); err != nil {
+			t.Fatalf("failed to set up entry %v: %s", e, err)
+		}
+	}
+	// Expecting one dedup report for each physical address
+	for i := 0; i < 2; i++ {
+		select {
+		case <-c.DedupReportChannel():
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for dedup report %v", i)
+		}
+	}
+
+	// drop the only entries that reference reset-me
+	testutil.MustDo(t, "reset branch1", c.ResetBranch(ctx, repository, "branch1"))
+
+	count, err := c.MarkObjectsForDeletion(ctx, repository)
+	testutil.MustDo(t, "mark objects for deletion", err)
+	if count != 1 {
+		t.Errorf("expected 1 object marked for deletion, got %d", count)
+	}
+
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	rows, err := conn.Query("SELECT physical_address, deleting FROM catalog_object_dedup")
+	if err != nil {
+		t.Fatalf("failed to query catalog_object_dedup: %s", err)
+	}
+	deleting := getDeleting(t, rows)
+	expectedDeleting := map[string]bool{
+		"reset-me": true,
+		"keep-me":  false,
+	}
+	if diffs := deep.Equal(expectedDeleting, deleting); diffs != nil {
+		t.Errorf("expected other deleting: %s\nexpected %v got %v", diffs, expectedDeleting, deleting)
+	}
+}
+
+func TestCataloger_ExpireCopiedObjects(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	source := "source-" + testCatalogerUniqueID()
+	testutil.MustDo(t, "create source", c.CreateRepository(ctx, source, "s3://source-bucket", "master"))
+	repository := "consumer-" + testCatalogerUniqueID()
+	testutil.MustDo(t, "create repository", c.CreateRepository(ctx, repository, "s3://consumer-bucket", "master"))
+
+	makeDedup := func(id int) CreateEntryParams {
+		return CreateEntryParams{
+			Dedup: DedupParams{
+				ID:               fmt.Sprintf("%08x", id),
+				StorageNamespace: "s3://source-bucket",
+			},
+		}
+	}
+	testutil.MustDo(t, "create committed entry", c.CreateEntry(ctx, source, "master", Entry{
+		Path:            "data/1",
+		PhysicalAddress: "obj-1",
+		Checksum:        "aa",
+		CreationDate:    time.Now().Add(-20 * time.Hour),
+	}, makeDedup(111)))
+	_, err := c.Commit(ctx, source, "master", "commit data/1", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	testutil.MustDo(t, "create uncommitted entry", c.CreateEntry(ctx, source, "master", Entry{
+		Path:            "data/2",
+		PhysicalAddress: "obj-2",
+		Checksum:        "bb",
+	}, makeDedup(222)))
+	for i := 0; i < 2; i++ {
+		select {
+		case <-c.DedupReportChannel():
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for dedup report %v", i)
+		}
+	}
+	_, err = c.CopyPath(ctx, source, "master", "data", repository, "master", "copy")
+	testutil.MustDo(t, "copy path", err)
+
+	// the copy keeps the committed source entry from expiring
+	expire, err := readEntriesToExpire(t, ctx, c, source, &Policy{Rules: []Rule{{
+		Enabled:    true,
+		Expiration: Expiration{All: makeHours(10)},
+	}}})
+	testutil.MustDo(t, "read entries to expire", err)
+	if len(expire) != 0 {
+		t.Errorf("expected no entries to expire while copied, got %v", expire)
+	}
+
+	// the copy keeps the object of the reset source entry
+	testutil.MustDo(t, "reset source", c.ResetBranch(ctx, source, "master"))
+	count, err := c.MarkObjectsForDeletion(ctx, source)
+	testutil.MustDo(t, "mark objects for deletion", err)
+	if count != 0 {
+		t.Errorf("expected no objects marked for deletion while copied, got %d", count)
+	}
+	rows, err := c.DeleteOrUnmarkObjectsForDeletion(ctx, source)
+	testutil.MustDo(t, "delete objects", err)
+	for rows.Next() {
+		address, err := rows.Read()
+		testutil.MustDo(t, "read deleted object", err)
+		t.Errorf("object %s deleted while copied", address)
+	}
+	testutil.MustDo(t, "close deleted objects", rows.Close())
+	for path, address := range map[string]string{"copy/1": "s3://source-bucket/obj-1", "copy/2": "s3://source-bucket/obj-2"} {
+		entry, err := c.GetEntry(ctx, repository, "master", path, GetEntryParams{})
+		testutil.MustDo(t, "get copied entry", err)
+		if entry.PhysicalAddress != address {
+			t.Errorf("copied entry %s at %s, expected %s", path, entry.PhysicalAddress, address)
+		}
+	}
+
+	// once the copy is gone, the object is deleted
+	testutil.MustDo(t, "reset repository", c.ResetBranch(ctx, repository, "master"))
+	count, err = c.MarkObjectsForDeletion(ctx, source)
+	testutil.MustDo(t, "mark objects for deletion", err)
+	if count != 1 {
+		t.Errorf("expected 1 object marked for deletion, got %d", count)
+	}
+}
+
+func TestCataloger_ScanExpired_DeletedBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "feature-1", "master")
+	testutil.MustDo(t, "create entry", c.CreateEntry(ctx, repository, "feature-1", Entry{
+		Path:            "data/1",
+		PhysicalAddress: "obj-1",
+		Checksum:        "aa",
+		CreationDate:    time.Now().Add(-20 * time.Hour),
+	}, CreateEntryParams{}))
+	_, err := c.Commit(ctx, repository, "feature-1", "commit data/1", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	_, err = c.DeleteBranch(ctx, repository, "feature-1")
+	testutil.MustDo(t, "delete branch", err)
+
+	// the entries of a deleted branch are matched by the name it had
+	for _, prefix := range []string{"feature-1/data/", "feature-*/"} {
+		expire, err := readEntriesToExpire(t, ctx, c, repository, &Policy{Rules: []Rule{{
+			Enabled:      true,
+			FilterPrefix: prefix,
+			Expiration:   Expiration{All: makeHours(10)},
+		}}})
+		testutil.MustDo(t, "read entries to expire", err)
+		if len(expire) != 1 || expire[0].PhysicalAddress != "obj-1" {
+			t.Errorf("rule %s expected to expire obj-1 of the deleted branch, got %v", prefix, expire)
+		}
+	}
+	expire, err := readEntriesToExpire(t, ctx, c, repository, &Policy{Rules: []Rule{{
+		Enabled:      true,
+		FilterPrefix: "master/",
+		Expiration:   Expiration{All: makeHours(10)},
+	}}})
+	testutil.MustDo(t, "read entries to expire", err)
+	if len(expire) != 0 {
+		t.Errorf("rule of another branch expected to expire nothing, got %v", expire)
+	}
+}
+
+func TestByPathPrefix(t *testing.T) {
+	tests := []struct {
+		pathPrefix string
+		wantSQL    string
+		wantArgs   []interface{}
+	}{
+		{pathPrefix: "", wantSQL: "(1=1)", wantArgs: nil},
+		{pathPrefix: "master", wantSQL: "COALESCE(catalog_branches.deleted_name, catalog_branches.name) = ?", wantArgs: []interface{}{"master"}},
+		{pathPrefix: "master/logs/", wantSQL: "(COALESCE(catalog_branches.deleted_name, catalog_branches.name) = ? AND path LIKE ?)", wantArgs: []interface{}{"master", "logs/%"}},
+		{pathPrefix: "feature-*", wantSQL: "COALESCE(catalog_branches.deleted_name, catalog_branches.name) LIKE ?", wantArgs: []interface{}{"feature-%"}},
+		{pathPrefix: "feature_?/data", wantSQL: "(COALESCE(catalog_branches.deleted_name, catalog_branches.name) LIKE ? AND path LIKE ?)", wantArgs: []interface{}{"feature\\__", "data%"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pathPrefix, func(t *testing.T) {
+			gotSQL, gotArgs, err := byPathPrefix(tt.pathPrefix).ToSql()
+			if err != nil {
+				t.Fatalf("byPathPrefix() ToSql error = %s", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("byPathPrefix() sql = %s, want %s", gotSQL, tt.wantSQL)
+			}
+			if diffs := deep.Equal(gotArgs, tt.wantArgs); diffs != nil {
+				t.Errorf("byPathPrefix() args diffs %s", diffs)
+			}
+		})
+	}
+}
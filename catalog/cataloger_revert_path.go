@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// RevertPath restores path, or the entries under it when path is a directory, on branch to their
+// state at reference.  Entries changed or deleted since reference are staged back with the
+// object they pointed to, and entries added since are deleted, all as uncommitted changes on the
+// branch.  Fails with ErrEntryNotFound if path has no entries on both the branch and reference.
+func (c *cataloger) RevertPath(ctx context.Context, repository, branch, reference, path string) error {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "path", IsValid: ValidatePath(path)},
+	}); err != nil {
+		return err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return err
+	}
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, path); err != nil {
+			return nil, err
+		}
+		sourceBranchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		sourceEntries, err := selectPathEntries(tx, sourceBranchID, ref.CommitID, path)
+		if err != nil {
+			return nil, fmt.Errorf("reference entries: %w", err)
+		}
+		currentEntries, err := selectPathEntries(tx, branchID, UncommittedID, path)
+		if err != nil {
+			return nil, fmt.Errorf("branch entries: %w", err)
+		}
+		if len(sourceEntries) == 0 && len(currentEntries) == 0 {
+			return nil, ErrEntryNotFound
+		}
+
+		current := make(map[string]Entry, len(currentEntries))
+		for _, entry := range currentEntries {
+			current[entry.Path] = entry
+		}
+		var (
+			restoredEntries []*Entry
+			restoredPaths   []string
+		)
+		for i := range sourceEntries {
+			entry := &sourceEntries[i]
+			if entry.Expired {
+				return nil, fmt.Errorf("%s: %w", entry.Path, ErrExpired)
+			}
+			currentEntry, ok := current[entry.Path]
+			delete(current, entry.Path)
+			if ok && currentEntry.PhysicalAddress == entry.PhysicalAddress && currentEntry.Checksum == entry.Checksum {
+				continue
+			}
+			restoredEntries = append(restoredEntries, entry)
+			restoredPaths = append(restoredPaths, entry.Path)
+		}
+		// entries left in current were added after reference
+		deletedPaths := make([]string, 0, len(current))
+		for p := range current {
+			deletedPaths = append(deletedPaths, p)
+		}
+		sort.Strings(deletedPaths)
+		// rules may protect entries under path without matching path itself
+		if err := c.checkPathsWritable(ctx, tx, repository, branch, append(restoredPaths, deletedPaths...)...); err != nil {
+			return nil, err
+		}
+		if _, err := updateBranchVersion(tx, branchID, 0); err != nil {
+			return nil, err
+		}
+		for _, entry := range restoredEntries {
+			entry.CreationDate = time.Now()
+			if _, err := insertEntry(tx, branchID, entry); err != nil {
+				return nil, err
+			}
+		}
+		if len(deletedPaths) > 0 {
+			lineage, err := getLineage(tx, branchID, UncommittedID)
+			if err != nil {
+				return nil, fmt.Errorf("get lineage: %w", err)
+			}
+			batchSize := c.BatchWrite.EntriesInsertSize
+			for i := 0; i < len(deletedPaths); i += batchSize {
+				j := i + batchSize
+				if j > len(deletedPaths) {
+					j = len(deletedPaths)
+				}
+				if err := deleteEntries(tx, branchID, lineage, deletedPaths[i:j]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := c.checkObjectsQuota(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := insertRepositoryEvents(tx, repository, EventTypeObjectStaged, branch, restoredPaths); err != nil {
+			return nil, err
+		}
+		return nil, insertRepositoryEvents(tx, repository, EventTypeObjectDeleted, branch, deletedPaths)
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// selectPathEntries returns the entries of branchID at commitID that are at path or under it
+func selectPathEntries(tx db.Tx, branchID int64, commitID CommitID, path string) ([]Entry, error) {
+	lineage, err := getLineage(tx, branchID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	dirPrefix := path
+	if !strings.HasSuffix(dirPrefix, DefaultPathDelimiter) {
+		dirPrefix += DefaultPathDelimiter
+	}
+	query, args, err := psql.
+		Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired").
+		FromSelect(sqEntriesLineage(branchID, commitID, lineage), "entries").
+		Where(sq.And{
+			sq.Or{sq.Eq{"path": path}, sq.Like{"path": db.Prefix(dirPrefix)}},
+			sq.Eq{"is_deleted": false},
+		}).
+		OrderBy("path").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var entries []Entry
+	if err := tx.Select(&entries, query, args...); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
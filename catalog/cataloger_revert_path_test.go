@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_RevertPath(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/changed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/deleted", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/same", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/bc", nil, "")
+	c1, err := c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/changed", nil, "seed2")
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "master", "a/b/deleted"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/b/added", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/bc", nil, "seed2")
+	_, err = c.Commit(ctx, repository, "master", "c2", "tester", nil)
+	testutil.MustDo(t, "commit c2", err)
+
+	before, err := c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get branch", err)
+	testutil.MustDo(t, "revert path", c.RevertPath(ctx, repository, "master", c1.Reference, "a/b"))
+	after, err := c.GetBranch(ctx, repository, "master")
+	testutil.MustDo(t, "get branch after revert", err)
+	if after.Version != before.Version+1 {
+		t.Errorf("Branch version after RevertPath = %d, expected %d", after.Version, before.Version+1)
+	}
+
+	differences, _, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	expected := Differences{
+		{Path: "a/b/added", Type: DifferenceTypeRemoved},
+		{Path: "a/b/changed", Type: DifferenceTypeChanged},
+		{Path: "a/b/deleted", Type: DifferenceTypeAdded},
+	}
+	if len(differences) != len(expected) {
+		t.Fatalf("uncommitted changes after RevertPath %+v, expected %+v", differences, expected)
+	}
+	for i := range expected {
+		if differences[i].Path != expected[i].Path || differences[i].Type != expected[i].Type {
+			t.Errorf("uncommitted change %d: %s %s, expected %s %s", i,
+				differences[i].Path, differences[i].Type, expected[i].Path, expected[i].Type)
+		}
+	}
+	entry, err := c.GetEntry(ctx, repository, "master", "a/b/changed", GetEntryParams{})
+	testutil.MustDo(t, "get reverted entry", err)
+	if entry.Checksum != testCreateEntryCalcChecksum("a/b/changed", "") {
+		t.Errorf("a/b/changed checksum %s, expected the c1 version", entry.Checksum)
+	}
+
+	err = c.RevertPath(ctx, repository, "master", c1.Reference, "a/missing")
+	if !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("RevertPath of missing path err=%s, expected %s", err, ErrEntryNotFound)
+	}
+}
+
+func TestCataloger_RevertPath_ProtectedEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "data/public/file", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "data/secret/file", nil, "")
+	c1, err := c.Commit(ctx, repository, "master", "c1", "tester", nil)
+	testutil.MustDo(t, "commit c1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "data/secret/file", nil, "seed2")
+	_, err = c.Commit(ctx, repository, "master", "c2", "tester", nil)
+	testutil.MustDo(t, "commit c2", err)
+	testutil.MustDo(t, "set protected paths", c.SetProtectedPaths(ctx, repository, []*ProtectedPathRule{
+		{Pattern: "data/secret/**"},
+	}))
+
+	// the rule does not match the reverted directory, only an entry under it
+	err = c.RevertPath(ctx, repository, "master", c1.Reference, "data")
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("RevertPath over a protected entry err=%v, expected %s", err, ErrPathProtected)
+	}
+	differences, _, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	if len(differences) != 0 {
+		t.Errorf("uncommitted changes after rejected RevertPath %+v, expected none", differences)
+	}
+}
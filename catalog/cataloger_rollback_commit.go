@@ -2,14 +2,17 @@ package catalog
 
 import (
 	"context"
-
-	"github.com/treeverse/lakefs/logging"
 )
 
+// RollbackCommit resets the branch of reference to the commit it points to, see ResetBranchToCommit
 func (c *cataloger) RollbackCommit(ctx context.Context, repository, reference string) error {
-	c.log.WithContext(ctx).WithFields(logging.Fields{
-		"repository": repository,
-		"reference":  reference,
-	}).Debug("Rollback commit - feature not supported")
-	return ErrFeatureNotSupported
+	resolved, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return err
+	}
+	ref, err := ParseRef(resolved)
+	if err != nil {
+		return err
+	}
+	return c.ResetBranchToCommit(ctx, repository, ref.Branch, resolved)
 }
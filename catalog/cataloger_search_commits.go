@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const SearchCommitsMaxLimit = 1000
+
+// SearchCommitsParams filters the commits returned by SearchCommits.  Empty fields match
+// all commits.
+type SearchCommitsParams struct {
+	// Message matches commits whose message contains the value, case insensitive
+	Message string
+	// MessagePattern matches commits whose message matches the POSIX regular expression, case
+	// insensitive, as supported by PostgreSQL
+	MessagePattern string
+	// Committer matches commits by the committer
+	Committer string
+	// Since matches commits created at or after the time
+	Since time.Time
+	// Until matches commits created before the time
+	Until time.Time
+	// Metadata matches commits whose metadata contains all the key/value pairs
+	Metadata Metadata
+}
+
+// SearchCommits returns the commits of all the branches in repository that match params, newest
+// commit first.  Commits of deleted branches are not searched, unless a branch was created from
+// the deleted branch.  Use after with a commit reference to continue the search from that commit.
+// Message searches are served by a trigram index on commit messages and metadata searches by an
+// index on commit metadata; a search that uses neither scans the commits of the repository.
+func (c *cataloger) SearchCommits(ctx context.Context, repository string, params SearchCommitsParams, limit int, after string) ([]*CommitLog, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "after", IsValid: ValidateOptionalString(after, IsValidReference)},
+	}); err != nil {
+		return nil, false, err
+	}
+	afterRef, err := ParseRef(after)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > SearchCommitsMaxLimit {
+		limit = SearchCommitsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		q := psql.Select("b.name as branch_name", "c.commit_id", "c.previous_commit_id", "c.committer", "c.message",
+			"c.creation_date", "c.metadata",
+			"COALESCE(bb.name,'') as merge_source_branch_name", "COALESCE(c.merge_source_commit,0) as merge_source_commit", "c.squashed").
+			From("catalog_commits c").
+			Join("catalog_branches b ON b.id = c.branch_id").
+			LeftJoin("catalog_branches bb ON bb.id = c.merge_source_branch").
+			Where(sq.Eq{"b.repository_id": repoID}).
+			Where("catalog_branch_in_use(b.id)")
+		if afterRef.CommitID > 0 {
+			q = q.Where(sq.Lt{"c.commit_id": afterRef.CommitID})
+		}
+		if params.Message != "" {
+			q = q.Where(sq.ILike{"c.message": db.Contains(params.Message)})
+		}
+		if params.MessagePattern != "" {
+			q = q.Where("c.message ~* ?", params.MessagePattern)
+		}
+		if params.Committer != "" {
+			q = q.Where(sq.Eq{"c.committer": params.Committer})
+		}
+		if !params.Since.IsZero() {
+			q = q.Where(sq.GtOrEq{"c.creation_date": params.Since})
+		}
+		if !params.Until.IsZero() {
+			q = q.Where(sq.Lt{"c.creation_date": params.Until})
+		}
+		if len(params.Metadata) > 0 {
+			q = q.Where("c.metadata @> ?::jsonb", params.Metadata)
+		}
+		query, args, err := q.OrderBy("c.commit_id DESC").
+			Limit(uint64(limit) + 1).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+		var rawCommits []*commitLogRaw
+		err = tx.Select(&rawCommits, query, args...)
+		if db.IsInvalidRegularExpression(err) {
+			return nil, fmt.Errorf("message pattern: %s: %w", err, ErrInvalidValue)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return convertRawCommits(rawCommits), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	commits := res.([]*CommitLog)
+	hasMore := paginateSlice(&commits, limit)
+	return commits, hasMore, nil
+}
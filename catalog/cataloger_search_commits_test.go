@@ -0,0 +1,180 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SearchCommits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	commits := []struct {
+		branch    string
+		message   string
+		committer string
+		metadata  Metadata
+	}{
+		{branch: "master", message: "Add daily partition", committer: "ingest-job", metadata: Metadata{"job": "ingest", "date": "2020-10-01"}},
+		{branch: "branch1", message: "Fix schema", committer: "tester", metadata: Metadata{"job": "fix"}},
+		{branch: "master", message: "Add DAILY partition", committer: "ingest-job", metadata: Metadata{"job": "ingest", "date": "2020-10-02"}},
+	}
+	refs := make([]string, len(commits))
+	dates := make([]time.Time, len(commits))
+	for i, commit := range commits {
+		testCatalogerCreateEntry(t, ctx, c, repository, commit.branch, "/file"+commit.message, nil, "")
+		commitLog, err := c.Commit(ctx, repository, commit.branch, commit.message, commit.committer, commit.metadata)
+		testutil.MustDo(t, "commit "+commit.message, err)
+		refs[i] = commitLog.Reference
+		dates[i] = commitLog.CreationDate
+	}
+
+	tests := []struct {
+		name     string
+		params   SearchCommitsParams
+		limit    int
+		after    string
+		want     []string
+		wantMore bool
+	}{
+		{
+			name:   "message",
+			params: SearchCommitsParams{Message: "daily"},
+			limit:  -1,
+			want:   []string{refs[2], refs[0]},
+		},
+		{
+			name:   "metadata",
+			params: SearchCommitsParams{Metadata: Metadata{"job": "fix"}},
+			limit:  -1,
+			want:   []string{refs[1]},
+		},
+		{
+			name:   "message and metadata",
+			params: SearchCommitsParams{Message: "partition", Metadata: Metadata{"date": "2020-10-01"}},
+			limit:  -1,
+			want:   []string{refs[0]},
+		},
+		{
+			name:   "committer",
+			params: SearchCommitsParams{Committer: "tester"},
+			limit:  -1,
+			want:   []string{refs[1]},
+		},
+		{
+			name:   "since",
+			params: SearchCommitsParams{Since: dates[1]},
+			limit:  -1,
+			want:   []string{refs[2], refs[1]},
+		},
+		{
+			name:   "until",
+			params: SearchCommitsParams{Until: dates[1], Metadata: Metadata{"job": "ingest"}},
+			limit:  -1,
+			want:   []string{refs[0]},
+		},
+		{
+			name:   "committer and time range",
+			params: SearchCommitsParams{Committer: "ingest-job", Since: dates[0], Until: dates[2]},
+			limit:  -1,
+			want:   []string{refs[0]},
+		},
+		{
+			name:     "paginate",
+			params:   SearchCommitsParams{Metadata: Metadata{"job": "ingest"}},
+			limit:    1,
+			want:     []string{refs[2]},
+			wantMore: true,
+		},
+		{
+			name:   "after",
+			params: SearchCommitsParams{Metadata: Metadata{"job": "ingest"}},
+			limit:  1,
+			after:  refs[2],
+			want:   []string{refs[0]},
+		},
+		{
+			name:   "message pattern",
+			params: SearchCommitsParams{MessagePattern: "^add .* partition$"},
+			limit:  -1,
+			want:   []string{refs[2], refs[0]},
+		},
+		{
+			name:   "message pattern and committer",
+			params: SearchCommitsParams{MessagePattern: "^fix|partition$", Committer: "tester"},
+			limit:  -1,
+			want:   []string{refs[1]},
+		},
+		{
+			name:   "message with like wildcards",
+			params: SearchCommitsParams{Message: `add_daily%\`},
+			limit:  -1,
+			want:   nil,
+		},
+		{
+			name:   "no match",
+			params: SearchCommitsParams{Message: "nothing"},
+			limit:  -1,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotMore, err := c.SearchCommits(ctx, repository, tt.params, tt.limit, tt.after)
+			testutil.MustDo(t, "search commits", err)
+			var gotRefs []string
+			for _, commit := range got {
+				gotRefs = append(gotRefs, commit.Reference)
+			}
+			if !reflect.DeepEqual(gotRefs, tt.want) {
+				t.Errorf("SearchCommits() got = %v, want %v", gotRefs, tt.want)
+			}
+			if gotMore != tt.wantMore {
+				t.Errorf("SearchCommits() more = %t, want %t", gotMore, tt.wantMore)
+			}
+		})
+	}
+
+	_, _, err := c.SearchCommits(ctx, repository, SearchCommitsParams{MessagePattern: "(partition"}, -1, "")
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("SearchCommits() with invalid message pattern err=%v, expected %s", err, ErrInvalidValue)
+	}
+}
+
+func TestCataloger_SearchCommits_DeletedBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "branch1", "Fix schema", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	params := SearchCommitsParams{Message: "schema"}
+
+	// the commits of a deleted branch are not found
+	head, err := c.DeleteBranch(ctx, repository, "branch1")
+	testutil.MustDo(t, "delete branch", err)
+	got, _, err := c.SearchCommits(ctx, repository, params, -1, "")
+	testutil.MustDo(t, "search commits", err)
+	if len(got) != 0 {
+		t.Fatalf("SearchCommits() after delete got %d commits, expected none", len(got))
+	}
+
+	// until a branch is created from it again
+	testCatalogerBranch(t, ctx, c, repository, "branch1", head)
+	got, _, err = c.SearchCommits(ctx, repository, params, -1, "")
+	testutil.MustDo(t, "search commits", err)
+	if len(got) != 1 || got[0].Message != commitLog.Message {
+		t.Fatalf("SearchCommits() after re-create got %v, expected the commit of the deleted branch", got)
+	}
+}
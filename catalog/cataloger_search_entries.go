@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	SearchEntriesMaxLimit = 1000
+
+	searchPatternAnyChars   = "*"
+	searchPatternSingleChar = "?"
+)
+
+// SearchEntriesParams filters the entries returned by SearchEntries
+type SearchEntriesParams struct {
+	// Pattern matches the entry path.  The pattern can use '*' to match any sequence of
+	// characters (including the path delimiter) and '?' to match a single character.
+	Pattern string
+	// Metadata matches entries whose user metadata contains all the key/value pairs
+	Metadata Metadata
+}
+
+// SearchEntries returns the entries at reference that match params.  The constant prefix of the
+// pattern is used to narrow the scan over the entries path index, and the rest of the pattern and
+// the metadata filter are served by the trigram index on entry paths and the index on entry
+// metadata.
+func (c *cataloger) SearchEntries(ctx context.Context, repository, reference string, params SearchEntriesParams, after string, limit int) ([]*Entry, bool, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "pattern", IsValid: ValidatePath(params.Pattern)},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > SearchEntriesMaxLimit {
+		limit = SearchEntriesMaxLimit
+	}
+	prefix, likePattern := searchPatternToLike(params.Pattern)
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		q := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata").
+			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+			Where(sq.And{
+				sq.Like{"path": db.Prefix(prefix)},
+				sq.Like{"path": likePattern},
+				sq.Eq{"is_deleted": false},
+				sq.Gt{"path": after},
+			})
+		if len(params.Metadata) > 0 {
+			q = q.Where("metadata @> ?::jsonb", params.Metadata)
+		}
+		entriesSQL, args, err := q.
+			OrderBy("path").
+			Limit(uint64(limit) + 1).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entries []*Entry
+		if err := tx.Select(&entries, entriesSQL, args...); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*Entry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
+
+// searchPatternToLike converts a search pattern to the constant prefix of the pattern and a SQL
+// LIKE pattern
+func searchPatternToLike(pattern string) (string, string) {
+	prefix := pattern
+	if idx := strings.IndexAny(pattern, searchPatternAnyChars+searchPatternSingleChar); idx != -1 {
+		prefix = pattern[:idx]
+	}
+	var sb strings.Builder
+	for _, ch := range pattern {
+		switch string(ch) {
+		case searchPatternAnyChars:
+			sb.WriteString("%")
+		case searchPatternSingleChar:
+			sb.WriteString("_")
+		case "%", "_", "\\":
+			sb.WriteString("\\" + string(ch))
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+	return prefix, sb.String()
+}
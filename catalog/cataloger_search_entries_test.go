@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SearchEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, p := range []string{"logs/2020/a.parquet", "logs/2020/b.csv", "logs/2021/c.parquet", "tables/t1/d.parquet", "tables/t_2/e.csv"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, Metadata{"dir": path.Dir(p)}, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "logs/2021/f.parquet", nil, "")
+
+	tests := []struct {
+		name      string
+		reference string
+		params    SearchEntriesParams
+		after     string
+		limit     int
+		want      []string
+		wantMore  bool
+	}{
+		{
+			name:      "suffix",
+			reference: "master",
+			params:    SearchEntriesParams{Pattern: "*.parquet"},
+			limit:     -1,
+			want:      []string{"logs/2020/a.parquet", "logs/2021/c.parquet", "logs/2021/f.parquet", "tables/t1/d.parquet"},
+		},
+		{
+			name:      "committed",
+			reference: "master:HEAD",
+			params:    SearchEntriesParams{Pattern: "logs/*.parquet"},
+			limit:     -1,
+			want:      []string{"logs/2020/a.parquet", "logs/2021/c.parquet"},
+		},
+		{
+			name:      "single char",
+			reference: "master",
+			params:    SearchEntriesParams{Pattern: "logs/202?/?.csv"},
+			limit:     -1,
+			want:      []string{"logs/2020/b.csv"},
+		},
+		{
+			name:      "escape underscore",
+			reference: "master",
+			params:    SearchEntriesParams{Pattern: "tables/t_*"},
+			limit:     -1,
+			want:      []string{"tables/t_2/e.csv"},
+		},
+		{
+			name:      "metadata",
+			reference: "master",
+			params:    SearchEntriesParams{Pattern: "*", Metadata: Metadata{"dir": "logs/2021"}},
+			limit:     -1,
+			want:      []string{"logs/2021/c.parquet"},
+		},
+		{
+			name:      "paginate",
+			reference: "master",
+			params:    SearchEntriesParams{Pattern: "logs/*"},
+			limit:     2,
+			after:     "logs/2020/a.parquet",
+			want:      []string{"logs/2020/b.csv", "logs/2021/c.parquet"},
+			wantMore:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, more, err := c.SearchEntries(ctx, repository, tt.reference, tt.params, tt.after, tt.limit)
+			testutil.MustDo(t, "search entries", err)
+			var paths []string
+			for _, ent := range entries {
+				paths = append(paths, ent.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("SearchEntries() paths = %v, want %v", paths, tt.want)
+			}
+			if more != tt.wantMore {
+				t.Errorf("SearchEntries() more = %t, want %t", more, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestSearchPatternToLike(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		wantPrefix string
+		wantLike   string
+	}{
+		{pattern: "logs/2020/a.csv", wantPrefix: "logs/2020/a.csv", wantLike: "logs/2020/a.csv"},
+		{pattern: "logs/*.csv", wantPrefix: "logs/", wantLike: "logs/%.csv"},
+		{pattern: "logs/20?0/*", wantPrefix: "logs/20", wantLike: "logs/20_0/%"},
+		{pattern: "*", wantPrefix: "", wantLike: "%"},
+		{pattern: "a_b%c/*", wantPrefix: "a_b%c/", wantLike: "a\\_b\\%c/%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			prefix, like := searchPatternToLike(tt.pattern)
+			if prefix != tt.wantPrefix {
+				t.Errorf("searchPatternToLike() prefix = %s, want %s", prefix, tt.wantPrefix)
+			}
+			if like != tt.wantLike {
+				t.Errorf("searchPatternToLike() like = %s, want %s", like, tt.wantLike)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestCataloger_SetBranchExpiryPolicy(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	if _, err := c.GetBranchExpiryPolicy(ctx, repository); !errors.Is(err, ErrBranchExpiryPolicyNotFound) {
+		t.Fatalf("GetBranchExpiryPolicy() error = %v, expected %v", err, ErrBranchExpiryPolicyNotFound)
+	}
+
+	tests := []struct {
+		name    string
+		policy  BranchExpiryPolicy
+		wantErr error
+	}{
+		{name: "valid", policy: BranchExpiryPolicy{Patterns: []string{"tmp-*"}, OlderThanDays: 7, Exempt: []string{"tmp-keep"}, Description: "temporary"}},
+		{name: "replace", policy: BranchExpiryPolicy{Patterns: []string{"tmp-*", "ci-?"}, OlderThanDays: 1}},
+		{name: "no patterns", policy: BranchExpiryPolicy{OlderThanDays: 7}, wantErr: ErrInvalidValue},
+		{name: "bad pattern", policy: BranchExpiryPolicy{Patterns: []string{"tmp-["}, OlderThanDays: 7}, wantErr: ErrInvalidValue},
+		{name: "no age", policy: BranchExpiryPolicy{Patterns: []string{"tmp-*"}}, wantErr: ErrInvalidValue},
+	}
+	var current *BranchExpiryPolicy
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.SetBranchExpiryPolicy(ctx, repository, &tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetBranchExpiryPolicy() error = %v, expected %v", err, tt.wantErr)
+			}
+			if err == nil {
+				policy := tt.policy
+				current = &policy
+			}
+			got, err := c.GetBranchExpiryPolicy(ctx, repository)
+			if err != nil {
+				t.Fatal("GetBranchExpiryPolicy:", err)
+			}
+			if diff := deep.Equal(&got.BranchExpiryPolicy, current); diff != nil {
+				t.Fatal("GetBranchExpiryPolicy() diff:", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// SetProtectedPaths replaces the protected path rules of repository.  An empty list removes the
+// protection.
+func (c *cataloger) SetProtectedPaths(ctx context.Context, repository string, rules []*ProtectedPathRule) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	if rules == nil {
+		rules = []*ProtectedPathRule{}
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return nil, setRepositoryConfig(tx, repoID, protectedPathsConfigKey, rules, "")
+	}, c.txOpts(ctx)...)
+	return err
+}
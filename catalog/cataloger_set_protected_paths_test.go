@@ -0,0 +1,73 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SetProtectedPaths(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "prod/file0", nil, "")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	rules := []*ProtectedPathRule{{Pattern: "prod/**", Branches: []string{"master"}}}
+	testutil.MustDo(t, "set protected paths", c.SetProtectedPaths(ctx, repository, rules))
+	got, err := c.GetProtectedPaths(ctx, repository)
+	testutil.MustDo(t, "get protected paths", err)
+	if diff := deep.Equal(got, rules); diff != nil {
+		t.Fatal("Get protected paths diff:", diff)
+	}
+
+	err = c.CreateEntry(ctx, repository, "master", Entry{Path: "prod/file1", PhysicalAddress: "addr1", Checksum: "ff"}, CreateEntryParams{})
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("CreateEntry on protected path err=%v, expected %v", err, ErrPathProtected)
+	}
+	err = c.DeleteEntry(ctx, repository, "master", "prod/file0")
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("DeleteEntry on protected path err=%v, expected %v", err, ErrPathProtected)
+	}
+	err = c.DeleteEntries(ctx, repository, "master", []string{"other", "prod/file0"})
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("DeleteEntries on protected path err=%v, expected %v", err, ErrPathProtected)
+	}
+	_, err = c.CopyEntry(ctx, repository, "branch1", "prod/file0", "master", "prod/file2")
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("CopyEntry to protected path err=%v, expected %v", err, ErrPathProtected)
+	}
+
+	// other paths and branches are not protected
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "dev/file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "prod/file1", nil, "")
+
+	// the authorizer is asked only about protected paths
+	var authorized [][]string
+	deniedCtx := WithProtectedPathsAuthorizer(ctx, func(paths []string) error {
+		authorized = append(authorized, paths)
+		return ErrOperationNotPermitted
+	})
+	testCatalogerCreateEntry(t, deniedCtx, c, repository, "master", "dev/file2", nil, "")
+	err = c.DeleteEntry(deniedCtx, repository, "master", "prod/file0")
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("DeleteEntry on protected path with denied authorization err=%v, expected %v", err, ErrPathProtected)
+	}
+	allowedCtx := WithProtectedPathsAuthorizer(ctx, func(paths []string) error {
+		authorized = append(authorized, paths)
+		return nil
+	})
+	testCatalogerCreateEntry(t, allowedCtx, c, repository, "master", "prod/file1", nil, "")
+	testutil.MustDo(t, "delete protected path with allowed context",
+		c.DeleteEntries(allowedCtx, repository, "master", []string{"dev/file2", "prod/file0"}))
+	if diff := deep.Equal(authorized, [][]string{{"prod/file0"}, {"prod/file1"}, {"prod/file0"}}); diff != nil {
+		t.Fatal("Authorized protected paths diff:", diff)
+	}
+
+	// removing the rules removes the protection
+	testutil.MustDo(t, "clear protected paths", c.SetProtectedPaths(ctx, repository, nil))
+	testutil.MustDo(t, "delete unprotected path", c.DeleteEntry(ctx, repository, "master", "prod/file1"))
+}
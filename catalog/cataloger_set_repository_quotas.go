@@ -0,0 +1,31 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// SetRepositoryQuotas replaces the quotas of repository.  Quotas are checked on the next change
+// and do not remove branches or objects the repository already has.
+func (c *cataloger) SetRepositoryQuotas(ctx context.Context, repository string, quotas *RepositoryQuotas) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if err := quotas.Validate(); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if err := setRepositoryConfig(tx, repoID, quotasConfigKey, quotas, ""); err != nil {
+			return nil, err
+		}
+		return nil, setRepositoryUsageTracking(tx, repoID, quotas.MaxObjects > 0 || quotas.MaxSizeBytes > 0)
+	}, c.txOpts(ctx)...)
+	return err
+}
@@ -0,0 +1,161 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SetRepositoryQuotas(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+
+	quotas := &RepositoryQuotas{MaxBranches: 2, MaxObjects: 2}
+	testutil.MustDo(t, "set quotas", c.SetRepositoryQuotas(ctx, repository, quotas))
+	got, err := c.GetRepositoryQuotas(ctx, repository)
+	testutil.MustDo(t, "get quotas", err)
+	if diff := deep.Equal(got, quotas); diff != nil {
+		t.Fatal("Get quotas diff:", diff)
+	}
+
+	// branches
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	_, err = c.CreateBranch(ctx, repository, "branch2", "master")
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("CreateBranch over quota err=%v, expected %v", err, ErrQuotaExceeded)
+	}
+
+	// objects - overwriting an uncommitted entry does not add an object
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "seed")
+	err = c.CreateEntry(ctx, repository, "master", Entry{Path: "file2", PhysicalAddress: "addr2", Checksum: "ff"}, CreateEntryParams{})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("CreateEntry over quota err=%v, expected %v", err, ErrQuotaExceeded)
+	}
+	_, err = c.CopyEntry(ctx, repository, "master", "file0", "master", "file3")
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("CopyEntry over quota err=%v, expected %v", err, ErrQuotaExceeded)
+	}
+
+	usage, err := c.GetRepositoryUsage(ctx, repository)
+	testutil.MustDo(t, "get usage", err)
+	if usage.Branches != 2 || usage.Objects != 2 {
+		t.Fatalf("GetRepositoryUsage branches=%d objects=%d, expected 2 and 2", usage.Branches, usage.Objects)
+	}
+
+	// zero quotas remove the limits
+	testutil.MustDo(t, "clear quotas", c.SetRepositoryQuotas(ctx, repository, &RepositoryQuotas{}))
+	testCatalogerBranch(t, ctx, c, repository, "branch2", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+
+	err = c.SetRepositoryQuotas(ctx, repository, &RepositoryQuotas{MaxObjects: -1})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("SetRepositoryQuotas with negative quota err=%v, expected %v", err, ErrInvalidValue)
+	}
+}
+
+func TestCataloger_SetRepositoryQuotas_TrackedUsage(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	testutil.MustDo(t, "set quotas", c.SetRepositoryQuotas(ctx, repository, &RepositoryQuotas{MaxObjects: 100}))
+
+	conn, err := db.ConnectDB(params.Database{Driver: db.DatabaseDriver, ConnectionString: c.DbConnURI})
+	if err != nil {
+		t.Fatalf("failed to connect to DB on %s", c.DbConnURI)
+	}
+	defer func() { _ = conn.Close() }()
+	// the usage maintained on every change matches a full count
+	checkUsage := func(step string) {
+		t.Helper()
+		_, err := conn.Transact(func(tx db.Tx) (interface{}, error) {
+			repoID, err := getRepositoryID(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			objects, sizeBytes, err := getRepositoryTrackedObjectsUsage(tx, repoID)
+			if err != nil {
+				return nil, err
+			}
+			countedObjects, countedSizeBytes, err := getRepositoryObjectsUsage(tx, repoID)
+			if err != nil {
+				return nil, err
+			}
+			if objects != countedObjects || sizeBytes != countedSizeBytes {
+				t.Errorf("%s: tracked usage %d objects %d bytes, counted %d objects %d bytes",
+					step, objects, sizeBytes, countedObjects, countedSizeBytes)
+			}
+			return nil, nil
+		})
+		testutil.MustDo(t, step+" usage", err)
+	}
+	checkUsage("set quotas")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "seed")
+	checkUsage("create entries")
+	_, err = c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	checkUsage("commit")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file0", nil, "seed")
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "branch1", "file1"))
+	checkUsage("branch changes")
+	_, err = c.Commit(ctx, repository, "branch1", "commit branch1", "tester", nil)
+	testutil.MustDo(t, "commit branch1", err)
+	checkUsage("commit branch1")
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "merge", nil)
+	testutil.MustDo(t, "merge", err)
+	checkUsage("merge")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	testutil.MustDo(t, "reset branch", c.ResetBranch(ctx, repository, "master"))
+	checkUsage("reset branch")
+	head, err := c.DeleteBranch(ctx, repository, "branch1")
+	testutil.MustDo(t, "delete branch", err)
+	checkUsage("delete branch")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", head)
+	checkUsage("re-create branch")
+}
+
+func TestCataloger_SetRepositoryQuotas_DeletedBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testutil.MustDo(t, "set quotas", c.SetRepositoryQuotas(ctx, repository, &RepositoryQuotas{MaxObjects: 2}))
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	_, err := c.Commit(ctx, repository, "branch1", "commit branch1", "tester", nil)
+	testutil.MustDo(t, "commit branch1", err)
+
+	// the objects of a deleted branch are not counted
+	head, err := c.DeleteBranch(ctx, repository, "branch1")
+	testutil.MustDo(t, "delete branch", err)
+	usage, err := c.GetRepositoryUsage(ctx, repository)
+	testutil.MustDo(t, "get usage", err)
+	if usage.Branches != 1 || usage.Objects != 0 {
+		t.Fatalf("GetRepositoryUsage after delete branches=%d objects=%d, expected 1 and 0", usage.Branches, usage.Objects)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+
+	// until a branch is created from it again
+	_, err = c.CreateBranch(ctx, repository, "branch1", head)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("CreateBranch from deleted branch over quota err=%v, expected %v", err, ErrQuotaExceeded)
+	}
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "master", "file3"))
+	testCatalogerBranch(t, ctx, c, repository, "branch1", head)
+	usage, err = c.GetRepositoryUsage(ctx, repository)
+	testutil.MustDo(t, "get usage", err)
+	if usage.Branches != 2 || usage.Objects != 2 {
+		t.Fatalf("GetRepositoryUsage after re-create branches=%d objects=%d, expected 2 and 2", usage.Branches, usage.Objects)
+	}
+}
@@ -0,0 +1,28 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// SetWorkspaceExpiryPolicy sets the policy used by ExpireWorkspace on repository, replacing any
+// previous policy.
+func (c *cataloger) SetWorkspaceExpiryPolicy(ctx context.Context, repository string, policy *WorkspaceExpiryPolicy) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return nil, setRepositoryConfig(tx, repoID, workspaceExpiryPolicyConfigKey, policy, policy.Description)
+	}, c.txOpts(ctx)...)
+	return err
+}
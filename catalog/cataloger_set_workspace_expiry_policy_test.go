@@ -0,0 +1,36 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCataloger_SetWorkspaceExpiryPolicy(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	if _, err := c.GetWorkspaceExpiryPolicy(ctx, repository); !errors.Is(err, ErrWorkspaceExpiryPolicyNotFound) {
+		t.Fatalf("GetWorkspaceExpiryPolicy() error = %v, expected %v", err, ErrWorkspaceExpiryPolicyNotFound)
+	}
+	if err := c.SetWorkspaceExpiryPolicy(ctx, repository, &WorkspaceExpiryPolicy{}); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("SetWorkspaceExpiryPolicy() without age error = %v, expected %v", err, ErrInvalidValue)
+	}
+	for _, policy := range []WorkspaceExpiryPolicy{
+		{OlderThanDays: 7, Description: "abandoned uploads"},
+		{OlderThanDays: 1},
+	} {
+		policy := policy
+		if err := c.SetWorkspaceExpiryPolicy(ctx, repository, &policy); err != nil {
+			t.Fatal("SetWorkspaceExpiryPolicy:", err)
+		}
+		got, err := c.GetWorkspaceExpiryPolicy(ctx, repository)
+		if err != nil {
+			t.Fatal("GetWorkspaceExpiryPolicy:", err)
+		}
+		if got.WorkspaceExpiryPolicy != policy {
+			t.Fatalf("GetWorkspaceExpiryPolicy() = %+v, expected %+v", got.WorkspaceExpiryPolicy, policy)
+		}
+	}
+}
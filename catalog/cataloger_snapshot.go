@@ -0,0 +1,28 @@
+package catalog
+
+import (
+	"context"
+)
+
+// Snapshot returns a commit reference to the version reference points to.  Reads through the
+// returned reference (GetEntry, ListEntries, Diff, the S3 gateway) all observe the same version,
+// even while the branch advances - scanning many objects with it is consistent.  A branch is
+// pinned at its last commit: uncommitted changes are not part of a snapshot.
+func (c *cataloger) Snapshot(ctx context.Context, repository, reference string) (string, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return "", err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return "", err
+	}
+	if ref.CommitID == UncommittedID {
+		reference = MakeReference(ref.Branch, CommittedID)
+	}
+	commit, err := c.GetCommit(ctx, repository, reference)
+	if err != nil {
+		return "", err
+	}
+	return commit.Reference, nil
+}
@@ -0,0 +1,49 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "tester", nil)
+	testutil.MustDo(t, "commit1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+
+	snapshot, err := c.Snapshot(ctx, repository, "master")
+	testutil.MustDo(t, "snapshot", err)
+	if snapshot != commit1.Reference {
+		t.Fatalf("Snapshot() = %s, expected %s", snapshot, commit1.Reference)
+	}
+
+	// the branch advances - reads through the snapshot do not change
+	_, err = c.Commit(ctx, repository, "master", "commit2", "tester", nil)
+	testutil.MustDo(t, "commit2", err)
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, repository, "master", "file1"))
+	entries, _, err := c.ListEntries(ctx, repository, snapshot, "", "", "", -1)
+	testutil.MustDo(t, "list snapshot", err)
+	if len(entries) != 1 || entries[0].Path != "file1" {
+		t.Fatalf("ListEntries() on snapshot got %d entries, expected file1", len(entries))
+	}
+	_, err = c.GetEntry(ctx, repository, snapshot, "file2", GetEntryParams{})
+	if !errors.Is(err, db.ErrNotFound) {
+		t.Fatalf("GetEntry() on snapshot of entry committed later err=%v, expected %s", err, db.ErrNotFound)
+	}
+
+	// a snapshot of a commit is the commit
+	got, err := c.Snapshot(ctx, repository, snapshot)
+	testutil.MustDo(t, "snapshot of commit", err)
+	if got != snapshot {
+		t.Fatalf("Snapshot() of commit = %s, expected %s", got, snapshot)
+	}
+}
@@ -0,0 +1,73 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// EntryStat is an entry with the commit that last wrote it
+type EntryStat struct {
+	Entry
+	// CommitReference is the commit that last wrote the entry, empty if the entry is uncommitted
+	CommitReference string
+}
+
+// StatEntry returns the entry for path in reference together with the commit that last wrote
+// it.  Expired entries are returned with Expired set and no error.
+func (c *cataloger) StatEntry(ctx context.Context, repository, reference string, path string) (*EntryStat, error) {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "path", IsValid: ValidatePath(path)},
+	}); err != nil {
+		return nil, err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		query, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata", "is_expired",
+				"min_commit", "(SELECT name FROM catalog_branches WHERE id = source_branch) AS source_branch_name").
+			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+			Where(sq.Eq{"path": path, "is_deleted": false}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var ent struct {
+			Entry
+			MinCommit        CommitID `db:"min_commit"`
+			SourceBranchName string   `db:"source_branch_name"`
+		}
+		if err := tx.Get(&ent, query, args...); err != nil {
+			return nil, err
+		}
+		stat := &EntryStat{Entry: ent.Entry}
+		if ent.MinCommit > 0 {
+			stat.CommitReference = MakeReference(ent.SourceBranchName, ent.MinCommit)
+		}
+		return stat, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*EntryStat), nil
+}
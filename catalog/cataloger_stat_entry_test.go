@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_StatEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit1", "tester", nil)
+	testutil.MustDo(t, "commit1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+
+	tests := []struct {
+		name       string
+		reference  string
+		path       string
+		wantCommit string
+		wantErr    error
+	}{
+		{name: "committed", reference: "master", path: "file1", wantCommit: commit1.Reference},
+		{name: "committed on parent", reference: "branch1", path: "file1", wantCommit: commit1.Reference},
+		{name: "uncommitted", reference: "branch1", path: "file2", wantCommit: ""},
+		{name: "not found", reference: "master", path: "file2", wantErr: db.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.StatEntry(ctx, repository, tt.reference, tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StatEntry() err = %v, expected %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Path != tt.path || got.PhysicalAddress == "" {
+				t.Errorf("StatEntry() path=%s address=%s, expected %s with an address", got.Path, got.PhysicalAddress, tt.path)
+			}
+			if got.CommitReference != tt.wantCommit {
+				t.Errorf("StatEntry() commit = %s, expected %s", got.CommitReference, tt.wantCommit)
+			}
+		})
+	}
+}
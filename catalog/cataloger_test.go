@@ -38,7 +38,7 @@ func testCatalogerUniqueID() string {
 func testCatalogerRepo(t testing.TB, ctx context.Context, c Cataloger, prefix string, branch string) string {
 	t.Helper()
 	name := prefix + "-" + testCatalogerUniqueID()
-	if err := c.CreateRepository(ctx, name, "s3://bucket", branch); err != nil {
+	if err := c.CreateRepository(ctx, name, "s3://"+name, branch); err != nil {
 		t.Fatalf("create repository %s, branch %s, failed: %s", name, branch, err)
 	}
 	return name
@@ -55,10 +55,7 @@ func testCatalogerBranch(t testing.TB, ctx context.Context, c Cataloger, reposit
 func testCatalogerCreateEntry(t testing.TB, ctx context.Context, c Cataloger, repository, branch, path string, metadata Metadata, seed string) {
 	t.Helper()
 	checksum := testCreateEntryCalcChecksum(path, seed)
-	var size int64
-	for i := range checksum {
-		size += int64(checksum[i])
-	}
+	size := testCreateEntryCalcSize(path, seed)
 	err := c.CreateEntry(ctx, repository, branch, Entry{
 		Path:            path,
 		Checksum:        checksum,
@@ -90,6 +87,16 @@ func testCreateEntryCalcChecksum(key string, seed string) string {
 	return checksum
 }
 
+// testCreateEntryCalcSize returns the size of the entry testCatalogerCreateEntry creates
+func testCreateEntryCalcSize(key string, seed string) int64 {
+	checksum := testCreateEntryCalcChecksum(key, seed)
+	var size int64
+	for i := range checksum {
+		size += int64(checksum[i])
+	}
+	return size
+}
+
 func testVerifyEntries(t testing.TB, ctx context.Context, c Cataloger, repository string, reference string, entries []testEntryInfo) {
 	for _, entry := range entries {
 		ent, err := c.GetEntry(ctx, repository, reference, entry.Path, GetEntryParams{})
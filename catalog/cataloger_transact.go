@@ -0,0 +1,210 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// RepositoryTx performs writes and commits on branches of a single repository.  All operations
+// done using RepositoryTx are applied atomically when the transaction function returns with no
+// error, or not at all.
+type RepositoryTx interface {
+	CreateEntry(branch string, entry Entry) error
+	DeleteEntry(branch string, path string) error
+	Commit(branch string, message string, committer string, metadata Metadata, opts ...CommitOption) (*CommitLog, error)
+}
+
+type RepositoryTxFunc func(tx RepositoryTx) error
+
+type repositoryTx struct {
+	ctx        context.Context
+	c          *cataloger
+	tx         db.Tx
+	repository string
+	// branchIDs holds the branches locked by this transaction
+	branchIDs map[string]int64
+	commits   []branchCommit
+}
+
+// branchCommit is a commit made by a RepositoryTx, reported to post-commit hooks once the
+// transaction completes
+type branchCommit struct {
+	branch    string
+	commitLog *CommitLog
+}
+
+// Transact runs fn with a RepositoryTx that can update branches of repository.  The changes
+// are committed in a single database transaction - ex: update data on one branch and its
+// manifest on another branch together.  All branches fn uses must be listed in branches: they
+// are locked up front in branch id order, so concurrent transactions on overlapping branches
+// cannot deadlock.  Writes are subject to the same repository read-only, protected paths and
+// quota checks as the single operation methods.  Commits run the pre-commit hooks inside the
+// transaction, with the changes made so far; the transaction is then not retried, so the
+// hooks are called at most once per commit.
+func (c *cataloger) Transact(ctx context.Context, repository string, branches []string, fn RepositoryTxFunc) error {
+	fields := ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}
+	for _, branch := range branches {
+		fields = append(fields, ValidateField{Name: "branch", IsValid: ValidateBranchName(branch)})
+	}
+	if err := Validate(fields); err != nil {
+		return err
+	}
+	var txOpts []db.TxOpt
+	if len(c.hooks.preCommit) > 0 {
+		txOpts = append(txOpts, db.WithMaxAttempts(1))
+	}
+	var commits []branchCommit
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchIDs, err := lockBranches(tx, repository, branches)
+		if err != nil {
+			return nil, err
+		}
+		rtx := &repositoryTx{
+			ctx:        ctx,
+			c:          c,
+			tx:         tx,
+			repository: repository,
+			branchIDs:  branchIDs,
+		}
+		if err := fn(rtx); err != nil {
+			return nil, err
+		}
+		commits = rtx.commits
+		return nil, nil
+	}, c.txOpts(ctx, txOpts...)...)
+	if err != nil {
+		return err
+	}
+	for _, commit := range commits {
+		c.hooks.runPostCommit(ctx, repository, commit.branch, commit.commitLog)
+	}
+	return nil
+}
+
+// lockBranches locks branches of repository for update in branch id order and returns their
+// ids by name
+func lockBranches(tx db.Tx, repository string, branches []string) (map[string]int64, error) {
+	var rows []struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	err := tx.Select(&rows, `SELECT b.id, b.name FROM catalog_branches b JOIN catalog_repositories r ON r.id = b.repository_id
+		WHERE r.name = $1 AND b.name = ANY(string_to_array($2,','))
+		ORDER BY b.id
+		FOR UPDATE OF b`, repository, strings.Join(branches, ","))
+	if err != nil {
+		return nil, fmt.Errorf("lock branches: %w", err)
+	}
+	branchIDs := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		branchIDs[row.Name] = row.ID
+	}
+	for _, branch := range branches {
+		if _, ok := branchIDs[branch]; !ok {
+			return nil, fmt.Errorf("branch %s: %w", branch, db.ErrNotFound)
+		}
+	}
+	return branchIDs, nil
+}
+
+// branchID returns the id of a branch locked by the transaction
+func (r *repositoryTx) branchID(branch string) (int64, error) {
+	id, ok := r.branchIDs[branch]
+	if !ok {
+		return 0, fmt.Errorf("%w: branch %s was not passed to Transact", ErrInvalidValue, branch)
+	}
+	return id, nil
+}
+
+func (r *repositoryTx) CreateEntry(branch string, entry Entry) error {
+	if err := Validate(ValidateFields{
+		{Name: "path", IsValid: ValidatePath(entry.Path)},
+	}); err != nil {
+		return err
+	}
+	branchID, err := r.branchID(branch)
+	if err != nil {
+		return err
+	}
+	if err := r.c.checkPathsWritable(r.ctx, r.tx, r.repository, branch, entry.Path); err != nil {
+		return err
+	}
+	if err := r.c.checkAddressesInNamespace(r.ctx, r.tx, r.repository, &entry); err != nil {
+		return err
+	}
+	if _, err := insertEntry(r.tx, branchID, &entry); err != nil {
+		return err
+	}
+	if err := r.c.checkObjectsQuota(r.tx, r.repository); err != nil {
+		return err
+	}
+	return insertRepositoryEvent(r.tx, r.repository, EventTypeObjectStaged, branch, entry.Path, "")
+}
+
+func (r *repositoryTx) DeleteEntry(branch string, path string) error {
+	if path == "" {
+		return db.ErrNotFound
+	}
+	branchID, err := r.branchID(branch)
+	if err != nil {
+		return err
+	}
+	if err := r.c.checkPathsWritable(r.ctx, r.tx, r.repository, branch, path); err != nil {
+		return err
+	}
+	if err := deleteEntry(r.tx, branchID, path); err != nil {
+		return err
+	}
+	return insertRepositoryEvent(r.tx, r.repository, EventTypeObjectDeleted, branch, path, "")
+}
+
+func (r *repositoryTx) Commit(branch string, message string, committer string, metadata Metadata, opts ...CommitOption) (*CommitLog, error) {
+	var options CommitOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := Validate(ValidateFields{
+		{Name: "message", IsValid: ValidateCommitMessage(message)},
+		{Name: "committer", IsValid: ValidateCommitter(committer)},
+	}); err != nil {
+		return nil, err
+	}
+	branchID, err := r.branchID(branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.c.hooks.preCommit) > 0 {
+		changes, err := diffUncommitted(r.tx, branchID, MaxHookChanges+1, "")
+		if err != nil {
+			return nil, fmt.Errorf("pre-commit changes: %w", err)
+		}
+		err = r.c.hooks.callPreCommit(r.ctx, &PendingCommit{
+			Repository: r.repository,
+			Branch:     branch,
+			Committer:  committer,
+			Message:    message,
+			Metadata:   metadata,
+			Changes:    changes,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	commitLog, err := commitBranch(r.tx, branchID, branch, message, committer, metadata, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertRepositoryEvent(r.tx, r.repository, EventTypeCommitCreated, branch, "", commitLog.Reference); err != nil {
+		return nil, err
+	}
+	r.commits = append(r.commits, branchCommit{branch: branch, commitLog: commitLog})
+	return commitLog, nil
+}
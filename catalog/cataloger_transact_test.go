@@ -0,0 +1,105 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Transact(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "manifest", "master")
+
+	t.Run("commit both", func(t *testing.T) {
+		err := c.Transact(ctx, repository, []string{"master", "manifest"}, func(tx RepositoryTx) error {
+			if err := tx.CreateEntry("master", Entry{Path: "data/file1", PhysicalAddress: "addr1", Checksum: "ff", Size: 1}); err != nil {
+				return err
+			}
+			if err := tx.CreateEntry("manifest", Entry{Path: "manifest.json", PhysicalAddress: "addr2", Checksum: "ee", Size: 2}); err != nil {
+				return err
+			}
+			if _, err := tx.Commit("master", "data", "tester", nil); err != nil {
+				return err
+			}
+			_, err := tx.Commit("manifest", "manifest", "tester", nil)
+			return err
+		})
+		testutil.MustDo(t, "transact", err)
+		testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "data/file1", true)
+		testCatalogerGetEntry(t, ctx, c, repository, "manifest:HEAD", "manifest.json", true)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		errRollback := errors.New("rollback")
+		err := c.Transact(ctx, repository, []string{"master", "manifest"}, func(tx RepositoryTx) error {
+			if err := tx.CreateEntry("master", Entry{Path: "data/file2", PhysicalAddress: "addr3", Checksum: "dd", Size: 3}); err != nil {
+				return err
+			}
+			if err := tx.DeleteEntry("manifest", "manifest.json"); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Transact() error = %v, expected %s", err, errRollback)
+		}
+		testCatalogerGetEntry(t, ctx, c, repository, "master", "data/file2", false)
+		testCatalogerGetEntry(t, ctx, c, repository, "manifest", "manifest.json", true)
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		err := c.Transact(ctx, repository, []string{"master", "no-branch"}, func(tx RepositoryTx) error {
+			return tx.CreateEntry("no-branch", Entry{Path: "file", PhysicalAddress: "addr4", Checksum: "cc", Size: 4})
+		})
+		if err == nil {
+			t.Fatal("Transact() expected error on unknown branch")
+		}
+	})
+
+	t.Run("branch not locked", func(t *testing.T) {
+		err := c.Transact(ctx, repository, []string{"master"}, func(tx RepositoryTx) error {
+			return tx.CreateEntry("manifest", Entry{Path: "file", PhysicalAddress: "addr4", Checksum: "cc", Size: 4})
+		})
+		if !errors.Is(err, ErrInvalidValue) {
+			t.Fatalf("Transact() error = %v, expected %s", err, ErrInvalidValue)
+		}
+		testCatalogerGetEntry(t, ctx, c, repository, "manifest", "file", false)
+	})
+
+	t.Run("protected path", func(t *testing.T) {
+		testutil.MustDo(t, "set protected paths", c.SetProtectedPaths(ctx, repository, []*ProtectedPathRule{
+			{Pattern: "manifest.json", Branches: []string{"manifest"}},
+		}))
+		defer func() {
+			testutil.MustDo(t, "clear protected paths", c.SetProtectedPaths(ctx, repository, nil))
+		}()
+		err := c.Transact(ctx, repository, []string{"manifest"}, func(tx RepositoryTx) error {
+			return tx.DeleteEntry("manifest", "manifest.json")
+		})
+		if !errors.Is(err, ErrPathProtected) {
+			t.Fatalf("Transact() error = %v, expected %s", err, ErrPathProtected)
+		}
+		testCatalogerGetEntry(t, ctx, c, repository, "manifest", "manifest.json", true)
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		readOnly := true
+		testutil.MustDo(t, "set read-only", c.UpdateRepository(ctx, repository, UpdateRepositoryParams{ReadOnly: &readOnly}))
+		defer func() {
+			readOnly = false
+			testutil.MustDo(t, "set writable", c.UpdateRepository(ctx, repository, UpdateRepositoryParams{ReadOnly: &readOnly}))
+		}()
+		err := c.Transact(ctx, repository, []string{"master"}, func(tx RepositoryTx) error {
+			return tx.CreateEntry("master", Entry{Path: "data/file3", PhysicalAddress: "addr5", Checksum: "bb", Size: 5})
+		})
+		if !errors.Is(err, ErrRepositoryReadOnly) {
+			t.Fatalf("Transact() error = %v, expected %s", err, ErrRepositoryReadOnly)
+		}
+	})
+}
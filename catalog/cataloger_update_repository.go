@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/ident"
+)
+
+// UpdateRepositoryParams are the repository settings that can be changed after the repository is
+// created.  Only the settings that are set are changed.
+type UpdateRepositoryParams struct {
+	DefaultBranch string
+	// ReadOnly marks the repository read-only, all changes to a read-only repository fail with
+	// ErrRepositoryReadOnly
+	ReadOnly *bool
+	// Labels replace the labels of the repository, an empty non-nil map removes all labels
+	Labels Metadata
+	// HashAlgorithm computes the content addresses of tags created from now on.  Existing tags
+	// keep the addresses they were created with.
+	HashAlgorithm ident.Algorithm
+}
+
+// UpdateRepository changes the settings of an existing repository, and removes the repository from
+// the cache of this cataloger.  Other lakeFS instances may observe the previous settings until
+// their cached item expires.  The read-only setting is verified by each change without the cache
+// and applies immediately.
+func (c *cataloger) UpdateRepository(ctx context.Context, repository string, params UpdateRepositoryParams) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "settings", IsValid: func() bool {
+			return params.DefaultBranch != "" || params.ReadOnly != nil || params.Labels != nil || params.HashAlgorithm != ""
+		}},
+		{Name: "defaultBranch", IsValid: ValidateOptionalString(params.DefaultBranch, IsValidBranchName)},
+		{Name: "hashAlgorithm", IsValid: ValidateOptionalString(string(params.HashAlgorithm), IsValidHashAlgorithm)},
+	}); err != nil {
+		return err
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		q := psql.Update("catalog_repositories").Where(sq.Eq{"name": repository})
+		if params.DefaultBranch != "" {
+			branchID, err := getBranchID(tx, repository, params.DefaultBranch, LockTypeShare)
+			if err != nil {
+				return nil, err
+			}
+			q = q.Set("default_branch", branchID)
+		}
+		if params.ReadOnly != nil {
+			q = q.Set("read_only", *params.ReadOnly)
+		}
+		if params.Labels != nil {
+			q = q.Set("labels", params.Labels)
+		}
+		if params.HashAlgorithm != "" {
+			q = q.Set("hash_algorithm", params.HashAlgorithm)
+		}
+		query, args, err := q.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected != 1 {
+			return nil, ErrRepositoryNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	c.cache.RemoveRepository(repository)
+	return nil
+}
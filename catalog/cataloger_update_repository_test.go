@@ -0,0 +1,151 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/ident"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_UpdateRepository(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(true))
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "main", "master")
+	// read the repository to cache it, the update evicts it
+	_, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{DefaultBranch: "main"})
+	testutil.MustDo(t, "update default branch", err)
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if repo.DefaultBranch != "main" {
+		t.Errorf("UpdateRepository() default branch = %s, expected main", repo.DefaultBranch)
+	}
+
+	// the default branch can't be deleted
+	_, err = c.DeleteBranch(ctx, repository, "main")
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("DeleteBranch() of default branch err = %v, expected %s", err, ErrOperationNotPermitted)
+	}
+
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{DefaultBranch: "no-branch"})
+	if err == nil {
+		t.Error("UpdateRepository() to unknown branch expected to fail")
+	}
+	err = c.UpdateRepository(ctx, "no-repo", UpdateRepositoryParams{DefaultBranch: "master"})
+	if err == nil {
+		t.Error("UpdateRepository() of unknown repository expected to fail")
+	}
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("UpdateRepository() without settings err = %v, expected %s", err, ErrInvalidValue)
+	}
+}
+
+func TestCataloger_UpdateRepository_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(false))
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+
+	readOnly := true
+	err := c.UpdateRepository(ctx, repository, UpdateRepositoryParams{ReadOnly: &readOnly})
+	testutil.MustDo(t, "set repository read-only", err)
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if !repo.ReadOnly {
+		t.Error("UpdateRepository() expected repository to be read-only")
+	}
+	if repo.DefaultBranch != "master" {
+		t.Errorf("UpdateRepository() default branch = %s, expected master", repo.DefaultBranch)
+	}
+
+	// reads keep working
+	if _, err := c.GetEntry(ctx, repository, "master", "/file1", GetEntryParams{}); err != nil {
+		t.Errorf("GetEntry() on read-only repository: %s", err)
+	}
+	// changes fail
+	err = c.CreateEntry(ctx, repository, "master", Entry{Path: "/file2", PhysicalAddress: "/addr2", Checksum: "ff", Size: 1}, CreateEntryParams{})
+	if !errors.Is(err, ErrRepositoryReadOnly) {
+		t.Errorf("CreateEntry() on read-only repository err = %v, expected %s", err, ErrRepositoryReadOnly)
+	}
+	_, err = c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	if !errors.Is(err, ErrRepositoryReadOnly) {
+		t.Errorf("Commit() on read-only repository err = %v, expected %s", err, ErrRepositoryReadOnly)
+	}
+	_, err = c.CreateBranch(ctx, repository, "branch1", "master")
+	if !errors.Is(err, ErrRepositoryReadOnly) {
+		t.Errorf("CreateBranch() on read-only repository err = %v, expected %s", err, ErrRepositoryReadOnly)
+	}
+
+	readOnly = false
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{ReadOnly: &readOnly})
+	testutil.MustDo(t, "set repository writable", err)
+	err = c.CreateEntry(ctx, repository, "master", Entry{Path: "/file2", PhysicalAddress: "/addr2", Checksum: "ff", Size: 1}, CreateEntryParams{})
+	testutil.MustDo(t, "create entry on writable repository", err)
+}
+
+func TestCataloger_UpdateRepository_Labels(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(false))
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	labels := Metadata{"team": "data", "env": "prod"}
+	err := c.UpdateRepository(ctx, repository, UpdateRepositoryParams{Labels: labels})
+	testutil.MustDo(t, "set repository labels", err)
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if diff := deep.Equal(repo.Labels, labels); diff != nil {
+		t.Errorf("UpdateRepository() labels diff = %s", diff)
+	}
+
+	// other settings keep the labels
+	readOnly := false
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{ReadOnly: &readOnly})
+	testutil.MustDo(t, "update repository", err)
+	repo, err = c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if diff := deep.Equal(repo.Labels, labels); diff != nil {
+		t.Errorf("UpdateRepository() labels diff = %s", diff)
+	}
+
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{Labels: Metadata{}})
+	testutil.MustDo(t, "remove repository labels", err)
+	repo, err = c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if len(repo.Labels) != 0 {
+		t.Errorf("UpdateRepository() labels = %v, expected none", repo.Labels)
+	}
+}
+
+func TestCataloger_UpdateRepository_HashAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(false))
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if repo.HashAlgorithm != ident.DefaultAlgorithm {
+		t.Errorf("GetRepository() hash algorithm = %s, expected %s", repo.HashAlgorithm, ident.DefaultAlgorithm)
+	}
+
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{HashAlgorithm: ident.AlgorithmBLAKE2b})
+	testutil.MustDo(t, "set repository hash algorithm", err)
+	repo, err = c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if repo.HashAlgorithm != ident.AlgorithmBLAKE2b {
+		t.Errorf("UpdateRepository() hash algorithm = %s, expected %s", repo.HashAlgorithm, ident.AlgorithmBLAKE2b)
+	}
+
+	err = c.UpdateRepository(ctx, repository, UpdateRepositoryParams{HashAlgorithm: "md4"})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("UpdateRepository() unknown hash algorithm err = %v, expected %s", err, ErrInvalidValue)
+	}
+}
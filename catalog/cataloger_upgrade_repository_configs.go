@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const repositoryConfigUpgradeBatchSize = 1000
+
+// UpgradeRepositoryConfigs rewrites the repository configuration values of all repositories that
+// were stored with an older version than the current version of their key, and returns the number
+// of values upgraded.  Values are upgraded in batches, each in its own transaction, so an
+// interrupted upgrade is completed by running it again.
+func (c *cataloger) UpgradeRepositoryConfigs(ctx context.Context) (int, error) {
+	keys := make([]string, 0, len(repositoryConfigUpgrades))
+	for key := range repositoryConfigUpgrades {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	upgraded := 0
+	for _, key := range keys {
+		for {
+			res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+				return upgradeRepositoryConfigsBatch(tx, key)
+			}, c.txOpts(ctx)...)
+			if err != nil {
+				return upgraded, err
+			}
+			batch := res.(int)
+			upgraded += batch
+			if batch < repositoryConfigUpgradeBatchSize {
+				break
+			}
+		}
+	}
+	return upgraded, nil
+}
+
+// upgradeRepositoryConfigsBatch upgrades up to repositoryConfigUpgradeBatchSize values of key
+// stored with an older version, and returns the number of values upgraded
+func upgradeRepositoryConfigsBatch(tx db.Tx, key string) (int, error) {
+	version := repositoryConfigVersion(key)
+	var rows []struct {
+		RepositoryID int    `db:"repository_id"`
+		Value        []byte `db:"value"`
+		Version      int    `db:"version"`
+	}
+	if err := tx.Select(&rows, `SELECT repository_id, value, version FROM catalog_repositories_config
+		WHERE key = $1 AND version < $2
+		ORDER BY repository_id
+		LIMIT $3
+		FOR UPDATE`,
+		key, version, repositoryConfigUpgradeBatchSize); err != nil {
+		return 0, fmt.Errorf("select %s values: %w", key, err)
+	}
+	for _, row := range rows {
+		value, err := upgradeRepositoryConfig(key, row.Version, row.Value)
+		if err != nil {
+			return 0, fmt.Errorf("repository %d: %w", row.RepositoryID, err)
+		}
+		if _, err := tx.Exec(`UPDATE catalog_repositories_config SET value = $1, version = $2
+			WHERE repository_id = $3 AND key = $4`,
+			string(value), version, row.RepositoryID, key); err != nil {
+			return 0, fmt.Errorf("update %s of repository %d: %w", key, row.RepositoryID, err)
+		}
+	}
+	return len(rows), nil
+}
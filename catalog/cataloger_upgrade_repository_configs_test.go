@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_UpgradeRepositoryConfigs(t *testing.T) {
+	ctx := context.Background()
+	conn, _ := testutil.GetDB(t, databaseURI)
+	c := NewCataloger(conn)
+	defer func() { _ = c.Close() }()
+
+	type valueV1 struct{ Name string }
+	type valueV2 struct{ Title string }
+	const key = "testUpgrade"
+	repositories := []string{
+		testCatalogerRepo(t, ctx, c, "repo", "master"),
+		testCatalogerRepo(t, ctx, c, "repo", "master"),
+	}
+	repoIDs := make([]int, len(repositories))
+	for i, repository := range repositories {
+		_, err := conn.Transact(func(tx db.Tx) (interface{}, error) {
+			var err error
+			repoIDs[i], err = getRepositoryID(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			return nil, setRepositoryConfig(tx, repoIDs[i], key, valueV1{Name: repository}, "")
+		})
+		testutil.MustDo(t, "set version 1 value", err)
+	}
+
+	repositoryConfigUpgrades[key] = []RepositoryConfigUpgrade{
+		func(value json.RawMessage) (json.RawMessage, error) {
+			var v1 valueV1
+			if err := json.Unmarshal(value, &v1); err != nil {
+				return nil, err
+			}
+			return json.Marshal(valueV2{Title: v1.Name})
+		},
+	}
+	defer delete(repositoryConfigUpgrades, key)
+
+	readValue := func(repoID int) (valueV2, int) {
+		t.Helper()
+		var value valueV2
+		var version int
+		_, err := conn.Transact(func(tx db.Tx) (interface{}, error) {
+			if _, err := getRepositoryConfig(tx, repoID, key, &value); err != nil {
+				return nil, err
+			}
+			return nil, tx.Get(&version, `SELECT version FROM catalog_repositories_config WHERE repository_id = $1 AND key = $2`,
+				repoID, key)
+		})
+		testutil.MustDo(t, "read value", err)
+		return value, version
+	}
+
+	// reads upgrade the value without storing it
+	value, version := readValue(repoIDs[0])
+	if value.Title != repositories[0] || version != 1 {
+		t.Errorf("read %+v stored at version %d, expected title %s stored at version 1", value, version, repositories[0])
+	}
+
+	upgraded, err := c.UpgradeRepositoryConfigs(ctx)
+	testutil.MustDo(t, "upgrade repository configs", err)
+	if upgraded != len(repositories) {
+		t.Errorf("UpgradeRepositoryConfigs() upgraded %d values, expected %d", upgraded, len(repositories))
+	}
+	for i, repoID := range repoIDs {
+		value, version := readValue(repoID)
+		if value.Title != repositories[i] || version != 2 {
+			t.Errorf("read %+v stored at version %d, expected title %s stored at version 2", value, version, repositories[i])
+		}
+	}
+	upgraded, err = c.UpgradeRepositoryConfigs(ctx)
+	testutil.MustDo(t, "upgrade repository configs again", err)
+	if upgraded != 0 {
+		t.Errorf("UpgradeRepositoryConfigs() again upgraded %d values, expected none", upgraded)
+	}
+
+	// values of a newer lakeFS are not read
+	_, err = conn.Transact(func(tx db.Tx) (interface{}, error) {
+		if _, err := tx.Exec(`UPDATE catalog_repositories_config SET version = 3 WHERE repository_id = $1 AND key = $2`,
+			repoIDs[0], key); err != nil {
+			return nil, err
+		}
+		var value valueV2
+		_, err := getRepositoryConfig(tx, repoIDs[0], key, &value)
+		return nil, err
+	})
+	if !errors.Is(err, ErrRepositoryConfigVersion) {
+		t.Errorf("read a newer version err=%v, expected %s", err, ErrRepositoryConfigVersion)
+	}
+}
@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+// WalkEntriesFunc is called by WalkEntries for each entry.  Returning an error stops the walk,
+// and WalkEntries returns it.
+type WalkEntriesFunc func(entry *Entry) error
+
+// WalkEntries calls fn for every entry under prefix of reference, in path order.  Entries are
+// streamed from a single read-only transaction: the walk observes one consistent version of
+// the reference without loading all entries into memory, and without paginating.  fn runs
+// while the transaction is open - slow callbacks hold a database connection for the duration
+// of the walk.
+func (c *cataloger) WalkEntries(ctx context.Context, repository, reference string, prefix string, fn WalkEntriesFunc) error {
+	reference, err := c.ResolveReference(ctx, repository, reference)
+	if err != nil {
+		return err
+	}
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return err
+	}
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return err
+	}
+
+	// repeatable read: a read-only transaction never fails on serialization, so fn is never
+	// called again for the same entry by a retry
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		entriesSQL, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "content_type", "metadata").
+			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+			Where(sq.And{sq.Like{"path": db.Prefix(prefix)}, sq.Eq{"is_deleted": false}}).
+			OrderBy("path").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		rows, err := tx.Query(entriesSQL, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var entry Entry
+			if err := rows.StructScan(&entry); err != nil {
+				return nil, fmt.Errorf("scan entry: %w", err)
+			}
+			if err := fn(&entry); err != nil {
+				return nil, err
+			}
+		}
+		return nil, rows.Err()
+	}, c.txOpts(ctx, db.ReadOnly(), db.WithIsolationLevel(sql.LevelRepeatableRead))...)
+	return err
+}
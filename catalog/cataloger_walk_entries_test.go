@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_WalkEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, p := range []string{"a/1", "a/2", "b/1"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "a/3", nil, "")
+	testutil.MustDo(t, "delete a/1", c.DeleteEntry(ctx, repository, "branch1", "a/1"))
+
+	tests := []struct {
+		name      string
+		reference string
+		prefix    string
+		want      []string
+	}{
+		{name: "all", reference: "master", prefix: "", want: []string{"a/1", "a/2", "b/1"}},
+		{name: "prefix", reference: "master", prefix: "a/", want: []string{"a/1", "a/2"}},
+		{name: "child uncommitted", reference: "branch1", prefix: "a/", want: []string{"a/2", "a/3"}},
+		{name: "child committed", reference: "branch1:HEAD", prefix: "a/", want: []string{"a/1", "a/2"}},
+		{name: "no match", reference: "master", prefix: "c/", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := c.WalkEntries(ctx, repository, tt.reference, tt.prefix, func(entry *Entry) error {
+				got = append(got, entry.Path)
+				return nil
+			})
+			testutil.MustDo(t, "walk entries", err)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Fatal("WalkEntries() paths diff:", diff)
+			}
+		})
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var walked int
+		err := c.WalkEntries(ctx, repository, "master", "", func(entry *Entry) error {
+			walked++
+			return errStop
+		})
+		if !errors.Is(err, errStop) || walked != 1 {
+			t.Fatalf("WalkEntries() stopped after %d entries with err=%v, expected 1 and %s", walked, err, errStop)
+		}
+	})
+}
@@ -1,12 +1,16 @@
 package catalog
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 
+	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/db"
 )
 
@@ -60,15 +64,102 @@ func getLastCommitIDByBranchID(tx db.Tx, branchID int64) (CommitID, error) {
 	return commitID, err
 }
 
+// updateBranchVersion increments the version of the branch, called by every write that moves
+// the branch head or discards its changes.  If expectedVersion is set, fails with
+// ErrBranchConcurrentUpdate unless the branch is still at that version.
+func updateBranchVersion(tx db.Tx, branchID int64, expectedVersion int64) (int64, error) {
+	var version int64
+	err := tx.Get(&version, `UPDATE catalog_branches SET version = version + 1
+		WHERE id = $1 AND ($2::bigint = 0 OR version = $2::bigint)
+		RETURNING version`, branchID, expectedVersion)
+	if errors.Is(err, db.ErrNotFound) {
+		return 0, fmt.Errorf("%w: expected version %d", ErrBranchConcurrentUpdate, expectedVersion)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("update branch version: %w", err)
+	}
+	return version, nil
+}
+
 func getNextCommitID(tx db.Tx) (CommitID, error) {
 	var commitID CommitID
 	err := tx.Get(&commitID, `SELECT nextval('catalog_commit_id_seq');`)
 	return commitID, err
 }
 
+// checkRepositoryWritable returns ErrRepositoryReadOnly if the repository is read-only.  The
+// setting is read by the calling transaction and not from the cache, so changes fail as soon as
+// the repository is set read-only.
+func checkRepositoryWritable(tx db.Tx, repository string) error {
+	var readOnly bool
+	if err := tx.Get(&readOnly, `SELECT read_only FROM catalog_repositories WHERE name = $1`, repository); err != nil {
+		return err
+	}
+	if readOnly {
+		return ErrRepositoryReadOnly
+	}
+	return nil
+}
+
+// getRepositoryConfig decodes the repository configuration value stored under key into value,
+// and returns when it was stored.  A value stored with an older version is upgraded to the current
+// version of key before it is decoded, but not written back.
+func getRepositoryConfig(tx db.Tx, repoID int, key string, value interface{}) (time.Time, error) {
+	var row struct {
+		Value     []byte    `db:"value"`
+		Version   int       `db:"version"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := tx.Get(&row, `SELECT value, version, created_at FROM catalog_repositories_config WHERE repository_id = $1 AND key = $2`,
+		repoID, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := upgradeRepositoryConfig(key, row.Version, row.Value)
+	if err != nil {
+		return row.CreatedAt, err
+	}
+	return row.CreatedAt, json.Unmarshal(data, value)
+}
+
+// setRepositoryConfig stores value as the repository configuration under key with the current
+// version of key, replacing the previous value
+func setRepositoryConfig(tx db.Tx, repoID int, key string, value interface{}, description string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO catalog_repositories_config (repository_id, key, value, version, description, created_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (repository_id, key)
+		DO UPDATE SET (value, version, description, created_at) = (EXCLUDED.value, EXCLUDED.version, EXCLUDED.description, EXCLUDED.created_at)`,
+		repoID, key, string(data), repositoryConfigVersion(key), description)
+	return err
+}
+
+// checkStorageNamespaceOverlap fails with ErrStorageNamespaceOverlap if the storage namespace of a
+// repository overlaps storageNamespace.  Repositories are locked against concurrent changes, so
+// repositories created concurrently are checked against each other.
+func checkStorageNamespaceOverlap(tx db.Tx, storageNamespace string) error {
+	if _, err := tx.Exec(`LOCK TABLE catalog_repositories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("lock repositories: %w", err)
+	}
+	var repos []*Repository
+	if err := tx.Select(&repos, `SELECT name, storage_namespace FROM catalog_repositories`); err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+	for _, repo := range repos {
+		if block.NamespacesOverlap(repo.StorageNamespace, storageNamespace) {
+			return fmt.Errorf("%w: %s overlaps %s of repository %s",
+				ErrStorageNamespaceOverlap, storageNamespace, repo.StorageNamespace, repo.Name)
+		}
+	}
+	return nil
+}
+
 func getRepository(tx db.Tx, repository string) (*Repository, error) {
 	var r Repository
-	err := tx.Get(&r, `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date
+	err := tx.Get(&r, `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date, r.read_only, r.labels, r.hash_algorithm
 			FROM catalog_repositories r, catalog_branches b
 			WHERE r.id = b.repository_id AND r.default_branch = b.id AND r.name = $1`,
 		repository)
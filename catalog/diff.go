@@ -9,9 +9,51 @@ const (
 	DifferenceTypeConflict
 )
 
+// DifferenceEntryType is the kind of entry a difference is about
+type DifferenceEntryType int
+
+const (
+	DifferenceEntryTypeObject DifferenceEntryType = iota
+	DifferenceEntryTypeTree
+)
+
+// Difference is a single change between two references.  It is the representation of changes
+// shared by the API, the CLI and hooks.
 type Difference struct {
 	Type DifferenceType `db:"diff_type"`
 	Path string         `db:"path"`
+	// EntryType is the kind of entry at Path.  The catalog diffs objects, trees are reported
+	// by diffs of hierarchical collections such as metastore tables.
+	EntryType DifferenceEntryType `db:"entry_type"`
+	// SizeDelta is the size of the object on the left side minus its size on the right side,
+	// a missing object has no size
+	SizeDelta int64 `db:"size_delta"`
+}
+
+func (t DifferenceType) String() string {
+	switch t {
+	case DifferenceTypeAdded:
+		return "added"
+	case DifferenceTypeRemoved:
+		return "removed"
+	case DifferenceTypeChanged:
+		return "changed"
+	case DifferenceTypeConflict:
+		return "conflict"
+	default:
+		return ""
+	}
+}
+
+func (t DifferenceEntryType) String() string {
+	switch t {
+	case DifferenceEntryTypeObject:
+		return "object"
+	case DifferenceEntryTypeTree:
+		return "tree"
+	default:
+		return ""
+	}
 }
 
 func (d Difference) String() string {
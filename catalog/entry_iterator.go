@@ -0,0 +1,80 @@
+package catalog
+
+import "context"
+
+// EntryLister lists entries, as EntryCataloger.ListEntries does
+type EntryLister interface {
+	ListEntries(ctx context.Context, repository, reference string, prefix, after string, delimiter string, limit int) ([]*Entry, bool, error)
+}
+
+// EntryIterator walks the entries under a prefix in path order, reading them from the catalog
+// in batches.  Only the current batch is held in memory.
+type EntryIterator struct {
+	ctx        context.Context
+	lister     EntryLister
+	repository string
+	reference  string
+	prefix     string
+	batchSize  int
+	after      string
+	buf        []*Entry
+	value      *Entry
+	hasMore    bool
+	err        error
+}
+
+func NewEntryIterator(ctx context.Context, lister EntryLister, repository, reference, prefix string, batchSize int) *EntryIterator {
+	return &EntryIterator{
+		ctx:        ctx,
+		lister:     lister,
+		repository: repository,
+		reference:  reference,
+		prefix:     prefix,
+		batchSize:  batchSize,
+		hasMore:    true,
+	}
+}
+
+// Next advances to the next entry, and returns false when there are no more entries or an
+// error occurred.
+func (it *EntryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.buf) == 0 {
+		if !it.hasMore {
+			it.value = nil
+			return false
+		}
+		it.buf, it.hasMore, it.err = it.lister.ListEntries(it.ctx, it.repository, it.reference, it.prefix, it.after, "", it.batchSize)
+		if it.err != nil || len(it.buf) == 0 {
+			it.value = nil
+			it.hasMore = false
+			return false
+		}
+	}
+	it.value = it.buf[0]
+	it.buf = it.buf[1:]
+	it.after = it.value.Path
+	return true
+}
+
+// Seek positions the iterator after path: the following call to Next reads the first entry
+// with a greater path.
+func (it *EntryIterator) Seek(path string) {
+	it.after = path
+	it.buf = nil
+	it.value = nil
+	it.hasMore = true
+	it.err = nil
+}
+
+// Value returns the current entry, or nil before the first call to Next and after Next returns
+// false.
+func (it *EntryIterator) Value() *Entry {
+	return it.value
+}
+
+func (it *EntryIterator) Err() error {
+	return it.err
+}
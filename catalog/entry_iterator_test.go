@@ -0,0 +1,83 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testEntryLister struct {
+	paths []string
+	calls int
+	err   error
+}
+
+func (l *testEntryLister) ListEntries(_ context.Context, _, _ string, prefix, after string, _ string, limit int) ([]*Entry, bool, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	var res []*Entry
+	for _, p := range l.paths {
+		if !strings.HasPrefix(p, prefix) || p <= after {
+			continue
+		}
+		if len(res) == limit {
+			return res, true, nil
+		}
+		res = append(res, &Entry{Path: p})
+	}
+	return res, false, nil
+}
+
+func readEntryIterator(it *EntryIterator) []string {
+	var paths []string
+	for it.Next() {
+		paths = append(paths, it.Value().Path)
+	}
+	return paths
+}
+
+func TestEntryIterator(t *testing.T) {
+	ctx := context.Background()
+	lister := &testEntryLister{paths: []string{"a/1", "a/2", "a/3", "b/1", "b/2"}}
+
+	t.Run("prefix", func(t *testing.T) {
+		it := NewEntryIterator(ctx, lister, "repo", "master", "a/", 2)
+		got := readEntryIterator(it)
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %s", err)
+		}
+		if fmt.Sprint(got) != "[a/1 a/2 a/3]" {
+			t.Fatalf("iterator read %v, expected [a/1 a/2 a/3]", got)
+		}
+		if it.Value() != nil {
+			t.Errorf("Value() after end = %v, expected nil", it.Value())
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		it := NewEntryIterator(ctx, lister, "repo", "master", "", 2)
+		if !it.Next() || it.Value().Path != "a/1" {
+			t.Fatalf("first entry = %v, expected a/1", it.Value())
+		}
+		it.Seek("a/3")
+		got := readEntryIterator(it)
+		if fmt.Sprint(got) != "[b/1 b/2]" {
+			t.Fatalf("iterator read %v after seek, expected [b/1 b/2]", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errList := errors.New("list failed")
+		it := NewEntryIterator(ctx, &testEntryLister{err: errList}, "repo", "master", "", 2)
+		if it.Next() {
+			t.Fatal("Next() = true, expected false on error")
+		}
+		if !errors.Is(it.Err(), errList) {
+			t.Fatalf("Err() = %v, expected %s", it.Err(), errList)
+		}
+	})
+}
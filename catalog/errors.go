@@ -8,22 +8,36 @@ import (
 )
 
 var (
-	ErrFeatureNotSupported      = errors.New("feature not supported")
-	ErrOperationNotPermitted    = errors.New("operation not permitted")
-	ErrInvalidLockValue         = errors.New("invalid lock value")
-	ErrNothingToCommit          = errors.New("nothing to commit")
-	ErrNoDifferenceWasFound     = errors.New("no difference was found")
-	ErrConflictFound            = errors.New("conflict found")
-	ErrUnsupportedRelation      = errors.New("unsupported relation")
-	ErrUnsupportedDelimiter     = errors.New("unsupported delimiter")
-	ErrInvalidReference         = errors.New("invalid reference")
-	ErrBranchNotFound           = fmt.Errorf("branch %w", db.ErrNotFound)
-	ErrCommitNotFound           = fmt.Errorf("commit %w", db.ErrNotFound)
-	ErrRepositoryNotFound       = fmt.Errorf("repository %w", db.ErrNotFound)
-	ErrMultipartUploadNotFound  = fmt.Errorf("multipart upload %w", db.ErrNotFound)
-	ErrEntryNotFound            = fmt.Errorf("entry %w", db.ErrNotFound)
-	ErrByteSliceTypeAssertion   = errors.New("type assertion to []byte failed")
-	ErrInvalidMetadataSrcFormat = errors.New("invalid metadata src format")
-	ErrUnexpected               = errors.New("unexpected error")
-	ErrReadEntryTimeout         = errors.New("read entry timeout")
+	ErrFeatureNotSupported           = errors.New("feature not supported")
+	ErrOperationNotPermitted         = errors.New("operation not permitted")
+	ErrInvalidLockValue              = errors.New("invalid lock value")
+	ErrNothingToCommit               = errors.New("nothing to commit")
+	ErrNoDifferenceWasFound          = errors.New("no difference was found")
+	ErrConflictFound                 = errors.New("conflict found")
+	ErrBranchHeadMoved               = errors.New("branch head moved")
+	ErrBranchConcurrentUpdate        = errors.New("branch was updated concurrently")
+	ErrUnsupportedRelation           = errors.New("unsupported relation")
+	ErrInvalidReference              = errors.New("invalid reference")
+	ErrBranchNotFound                = fmt.Errorf("branch %w", db.ErrNotFound)
+	ErrCommitNotFound                = fmt.Errorf("commit %w", db.ErrNotFound)
+	ErrRepositoryNotFound            = fmt.Errorf("repository %w", db.ErrNotFound)
+	ErrMultipartUploadNotFound       = fmt.Errorf("multipart upload %w", db.ErrNotFound)
+	ErrEntryNotFound                 = fmt.Errorf("entry %w", db.ErrNotFound)
+	ErrTagNotFound                   = fmt.Errorf("tag %w", db.ErrNotFound)
+	ErrBranchExpiryPolicyNotFound    = fmt.Errorf("branch expiry policy %w", db.ErrNotFound)
+	ErrWorkspaceExpiryPolicyNotFound = fmt.Errorf("workspace expiry policy %w", db.ErrNotFound)
+	ErrEntryAlreadyExists            = errors.New("entry already exists")
+	ErrTagAlreadyExists              = errors.New("tag already exists")
+	ErrEntryUncommitted              = errors.New("entry is uncommitted")
+	ErrRepositoryReadOnly            = errors.New("repository is read-only")
+	ErrPathProtected                 = errors.New("path is protected")
+	ErrQuotaExceeded                 = errors.New("quota exceeded")
+	ErrStorageNamespaceOverlap       = errors.New("storage namespace overlaps another repository")
+	ErrAddressOutsideNamespace       = errors.New("physical address is outside the storage namespace")
+	ErrHookRejected                  = errors.New("rejected by hook")
+	ErrByteSliceTypeAssertion        = errors.New("type assertion to []byte failed")
+	ErrInvalidMetadataSrcFormat      = errors.New("invalid metadata src format")
+	ErrUnexpected                    = errors.New("unexpected error")
+	ErrReadEntryTimeout              = errors.New("read entry timeout")
+	ErrRepositoryConfigVersion       = errors.New("unsupported repository configuration version")
 )
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	EventTypeObjectStaged  = "object_staged"
+	EventTypeObjectDeleted = "object_deleted"
+	EventTypeCommitCreated = "commit_created"
+	EventTypeBranchCreated = "branch_created"
+	EventTypeBranchDeleted = "branch_deleted"
+	EventTypeBranchReset   = "branch_reset"
+
+	eventsInsertBatchSize = 1000
+	eventsDeleteBatchSize = 10000
+)
+
+// insertRepositoryEvent records event with the next number of the global events sequence
+func insertRepositoryEvent(tx db.Tx, repository, eventType, branch, path, reference string) error {
+	_, err := tx.Exec(`INSERT INTO catalog_repository_events (repository_id, event_type, branch, path, reference)
+		SELECT id, $2, $3, $4, $5 FROM catalog_repositories WHERE name = $1`,
+		repository, eventType, branch, path, reference)
+	if err != nil {
+		return fmt.Errorf("insert %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// insertRepositoryEvents records an event of eventType for each of paths, in batched inserts
+func insertRepositoryEvents(tx db.Tx, repository, eventType, branch string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	repoID, err := getRepositoryID(tx, repository)
+	if err != nil {
+		return fmt.Errorf("%s events repository: %w", eventType, err)
+	}
+	for i := 0; i < len(paths); i += eventsInsertBatchSize {
+		j := i + eventsInsertBatchSize
+		if j > len(paths) {
+			j = len(paths)
+		}
+		sqInsert := psql.Insert("catalog_repository_events").
+			Columns("repository_id", "event_type", "branch", "path")
+		for _, path := range paths[i:j] {
+			sqInsert = sqInsert.Values(repoID, eventType, branch, path)
+		}
+		query, args, err := sqInsert.ToSql()
+		if err != nil {
+			return fmt.Errorf("build %s events sql: %w", eventType, err)
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("insert %s events: %w", eventType, err)
+		}
+	}
+	return nil
+}
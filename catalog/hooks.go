@@ -0,0 +1,272 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+// MaxHookChanges is the maximal number of changes passed to a pre-commit or pre-merge hook.
+// Hooks of larger commits and merges get the first changes by path, with ChangesTruncated set.
+const MaxHookChanges = 1000
+
+// PendingCommit describes a commit about to be made, with the uncommitted changes it includes
+type PendingCommit struct {
+	Repository       string
+	Branch           string
+	Committer        string
+	Message          string
+	Metadata         Metadata
+	Changes          Differences
+	ChangesTruncated bool
+}
+
+// PendingMerge describes a merge about to be made, with the changes it merges
+type PendingMerge struct {
+	Repository        string
+	SourceBranch      string
+	DestinationBranch string
+	Committer         string
+	Message           string
+	Metadata          Metadata
+	Summary           map[DifferenceType]int
+	Changes           Differences
+	ChangesTruncated  bool
+}
+
+// PreCommitHookFunc is called once before a commit is made, outside of the commit transaction.
+// Returning an error aborts the commit; hooks rejecting a commit should wrap ErrHookRejected.
+// The commit fails with ErrBranchConcurrentUpdate if the branch changed while the hooks ran.
+type PreCommitHookFunc func(ctx context.Context, commit *PendingCommit) error
+
+// PreMergeHookFunc is called once before a merge is made, outside of the merge transaction.
+// Returning an error aborts the merge; hooks rejecting a merge should wrap ErrHookRejected.  The
+// merge fails with ErrBranchConcurrentUpdate if one of the branches changed while the hooks ran.
+type PreMergeHookFunc func(ctx context.Context, merge *PendingMerge) error
+
+// PostCommitHookFunc is called after a commit to branch was stored.  Hooks run synchronously
+// once the database transaction completed successfully, and should return quickly.
+type PostCommitHookFunc func(ctx context.Context, repository, branch string, commitLog *CommitLog)
+
+// PostMergeHookFunc is called after sourceBranch was merged into destinationBranch, with the
+// merge commit created on destinationBranch.
+type PostMergeHookFunc func(ctx context.Context, repository, sourceBranch, destinationBranch string, commitLog *CommitLog)
+
+type hooks struct {
+	preCommit  []PreCommitHookFunc
+	preMerge   []PreMergeHookFunc
+	postCommit []PostCommitHookFunc
+	postMerge  []PostMergeHookFunc
+}
+
+func WithPreCommitHook(fn PreCommitHookFunc) CatalogerOption {
+	return func(c *cataloger) {
+		c.hooks.preCommit = append(c.hooks.preCommit, fn)
+	}
+}
+
+func WithPreMergeHook(fn PreMergeHookFunc) CatalogerOption {
+	return func(c *cataloger) {
+		c.hooks.preMerge = append(c.hooks.preMerge, fn)
+	}
+}
+
+func WithPostCommitHook(fn PostCommitHookFunc) CatalogerOption {
+	return func(c *cataloger) {
+		c.hooks.postCommit = append(c.hooks.postCommit, fn)
+	}
+}
+
+func WithPostMergeHook(fn PostMergeHookFunc) CatalogerOption {
+	return func(c *cataloger) {
+		c.hooks.postMerge = append(c.hooks.postMerge, fn)
+	}
+}
+
+// preCommitCheck is the state of a branch seen by the pre-commit hooks
+type preCommitCheck struct {
+	Version int64  `db:"version"`
+	Digest  string `db:"digest"`
+}
+
+// runPreCommit calls the pre-commit hooks with the uncommitted changes of the branch, stopping at
+// the first hook that fails.  The changes are read by their own transaction, and the returned
+// check verifies that the commit transaction commits the same changes.
+func (c *cataloger) runPreCommit(ctx context.Context, commit *PendingCommit) (*preCommitCheck, error) {
+	if len(c.hooks.preCommit) == 0 {
+		return nil, nil
+	}
+	var check *preCommitCheck
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := getBranchID(tx, commit.Repository, commit.Branch, LockTypeNone)
+		if err != nil {
+			return nil, fmt.Errorf("get branch id: %w", err)
+		}
+		check, err = readPreCommitCheck(tx, branchID)
+		if err != nil {
+			return nil, err
+		}
+		commit.Changes, err = diffUncommitted(tx, branchID, MaxHookChanges+1, "")
+		return nil, err
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, fmt.Errorf("pre-commit changes: %w", err)
+	}
+	if err := c.hooks.callPreCommit(ctx, commit); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// callPreCommit calls the pre-commit hooks with at most MaxHookChanges of the commit changes
+func (h *hooks) callPreCommit(ctx context.Context, commit *PendingCommit) error {
+	if len(commit.Changes) > MaxHookChanges {
+		commit.Changes = commit.Changes[:MaxHookChanges]
+		commit.ChangesTruncated = true
+	}
+	for _, fn := range h.preCommit {
+		if err := fn(ctx, commit); err != nil {
+			return fmt.Errorf("pre-commit hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// readPreCommitCheck reads the branch version and a digest of its uncommitted entries
+func readPreCommitCheck(tx db.Tx, branchID int64) (*preCommitCheck, error) {
+	var check preCommitCheck
+	err := tx.Get(&check, `SELECT
+			(SELECT version FROM catalog_branches WHERE id = $1) AS version,
+			COALESCE(md5(string_agg(path || ' ' || checksum || ' ' || max_commit, ',' ORDER BY path)), '') AS digest
+		FROM catalog_entries WHERE branch_id = $1 AND min_commit = 0`, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("branch uncommitted digest: %w", err)
+	}
+	return &check, nil
+}
+
+// verify returns ErrBranchConcurrentUpdate unless the branch is in the state seen by the hooks.
+// Called by the commit transaction after the branch was locked; a nil check always passes.
+func (p *preCommitCheck) verify(tx db.Tx, branchID int64) error {
+	if p == nil {
+		return nil
+	}
+	current, err := readPreCommitCheck(tx, branchID)
+	if err != nil {
+		return err
+	}
+	if *current != *p {
+		return fmt.Errorf("%w: branch changed while pre-commit hooks ran", ErrBranchConcurrentUpdate)
+	}
+	return nil
+}
+
+// preMergeCheck is the state of the merged branches seen by the pre-merge hooks
+type preMergeCheck struct {
+	SourceVersion      int64 `db:"source_version"`
+	DestinationVersion int64 `db:"destination_version"`
+	// called is set if the hooks accepted the merge - they are not called for merges that
+	// fail on conflicts or have no differences
+	called bool
+}
+
+// runPreMerge calls the pre-merge hooks with the merged changes, stopping at the first hook that
+// fails.  The changes are computed by their own transaction, and the returned check verifies
+// that the merge transaction merges the same branch versions.
+func (c *cataloger) runPreMerge(ctx context.Context, options mergeOptions, merge *PendingMerge) (*preMergeCheck, error) {
+	if len(c.hooks.preMerge) == 0 {
+		return nil, nil
+	}
+	var check *preMergeCheck
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		sourceID, err := getBranchID(tx, merge.Repository, merge.SourceBranch, LockTypeNone)
+		if err != nil {
+			return nil, fmt.Errorf("source branch: %w", err)
+		}
+		destinationID, err := getBranchID(tx, merge.Repository, merge.DestinationBranch, LockTypeNone)
+		if err != nil {
+			return nil, fmt.Errorf("destination branch: %w", err)
+		}
+		check, err = readPreMergeCheck(tx, sourceID, destinationID)
+		if err != nil {
+			return nil, err
+		}
+		_, merge.Summary, err = c.diffMerge(tx, sourceID, destinationID, options)
+		if err != nil {
+			return nil, err
+		}
+		if merge.Summary[DifferenceTypeConflict] > 0 {
+			return nil, nil
+		}
+		merge.Changes, err = getDiffDifferences(tx, MaxHookChanges+1, "")
+		if err != nil {
+			return nil, err
+		}
+		if len(merge.Changes) == 0 {
+			commitDifferences, err := hasCommitDifferences(tx, sourceID, destinationID)
+			if err != nil || !commitDifferences {
+				return nil, err
+			}
+		}
+		check.called = true
+		return nil, setDifferencesSizeDelta(tx, merge.Changes, sourceID, CommittedID, destinationID, UncommittedID)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("pre-merge changes: %w", err)
+	}
+	if !check.called {
+		// the merge transaction fails on the conflicts or missing differences
+		return check, nil
+	}
+	if len(merge.Changes) > MaxHookChanges {
+		merge.Changes = merge.Changes[:MaxHookChanges]
+		merge.ChangesTruncated = true
+	}
+	for _, fn := range c.hooks.preMerge {
+		if err := fn(ctx, merge); err != nil {
+			return nil, fmt.Errorf("pre-merge hook: %w", err)
+		}
+	}
+	return check, nil
+}
+
+func readPreMergeCheck(tx db.Tx, sourceID, destinationID int64) (*preMergeCheck, error) {
+	var check preMergeCheck
+	err := tx.Get(&check, `SELECT
+			(SELECT version FROM catalog_branches WHERE id = $1) AS source_version,
+			(SELECT version FROM catalog_branches WHERE id = $2) AS destination_version`, sourceID, destinationID)
+	if err != nil {
+		return nil, fmt.Errorf("branch versions: %w", err)
+	}
+	return &check, nil
+}
+
+// verify returns ErrBranchConcurrentUpdate unless the branches are at the versions seen by the
+// hooks and the hooks accepted the merge.  Called by the merge transaction after the branches were
+// locked, once the merge is known to have no conflicts; a nil check always passes.
+func (p *preMergeCheck) verify(tx db.Tx, sourceID, destinationID int64) error {
+	if p == nil {
+		return nil
+	}
+	current, err := readPreMergeCheck(tx, sourceID, destinationID)
+	if err != nil {
+		return err
+	}
+	if !p.called || current.SourceVersion != p.SourceVersion || current.DestinationVersion != p.DestinationVersion {
+		return fmt.Errorf("%w: branches changed while pre-merge hooks ran", ErrBranchConcurrentUpdate)
+	}
+	return nil
+}
+
+func (h *hooks) runPostCommit(ctx context.Context, repository, branch string, commitLog *CommitLog) {
+	for _, fn := range h.postCommit {
+		fn(ctx, repository, branch, commitLog)
+	}
+}
+
+func (h *hooks) runPostMerge(ctx context.Context, repository, sourceBranch, destinationBranch string, commitLog *CommitLog) {
+	for _, fn := range h.postMerge {
+		fn(ctx, repository, sourceBranch, destinationBranch, commitLog)
+	}
+}
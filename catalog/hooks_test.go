@@ -0,0 +1,234 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_PostCommitHook(t *testing.T) {
+	ctx := context.Background()
+	var calls []*CommitLog
+	c := testCataloger(t, WithPostCommitHook(func(_ context.Context, repository, branch string, commitLog *CommitLog) {
+		if branch != "master" {
+			t.Errorf("post-commit hook branch = %s, expected master", branch)
+		}
+		calls = append(calls, commitLog)
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit file1", err)
+	if len(calls) != 1 {
+		t.Fatalf("post-commit hook called %d times, expected 1", len(calls))
+	}
+	if calls[0].Reference != commitLog.Reference {
+		t.Errorf("post-commit hook reference = %s, expected %s", calls[0].Reference, commitLog.Reference)
+	}
+
+	// failed commit does not call the hook
+	_, err = c.Commit(ctx, repository, "master", "nothing", "tester", nil)
+	if err == nil {
+		t.Fatal("Commit() with no changes succeeded, expected an error")
+	}
+	if len(calls) != 1 {
+		t.Fatalf("post-commit hook called %d times after failed commit, expected 1", len(calls))
+	}
+}
+
+func TestCataloger_PostMergeHook(t *testing.T) {
+	ctx := context.Background()
+	var calls []*CommitLog
+	c := testCataloger(t, WithPostMergeHook(func(_ context.Context, repository, sourceBranch, destinationBranch string, commitLog *CommitLog) {
+		if sourceBranch != "branch1" || destinationBranch != "master" {
+			t.Errorf("post-merge hook branches = %s -> %s, expected branch1 -> master", sourceBranch, destinationBranch)
+		}
+		calls = append(calls, commitLog)
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit file1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file2", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit file2", "tester", nil)
+	testutil.MustDo(t, "commit file2", err)
+
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "merge branch1", Metadata{"key": "value"})
+	testutil.MustDo(t, "merge branch1", err)
+	if len(calls) != 1 {
+		t.Fatalf("post-merge hook called %d times, expected 1", len(calls))
+	}
+	if calls[0].Reference != res.Reference {
+		t.Errorf("post-merge hook reference = %s, expected %s", calls[0].Reference, res.Reference)
+	}
+	if calls[0].Message != "merge branch1" || calls[0].Metadata["key"] != "value" {
+		t.Errorf("post-merge hook commit = %+v, expected merge message and metadata", calls[0])
+	}
+}
+
+func TestCataloger_PreCommitHook(t *testing.T) {
+	ctx := context.Background()
+	var calls []*PendingCommit
+	c := testCataloger(t, WithPreCommitHook(func(_ context.Context, commit *PendingCommit) error {
+		calls = append(calls, commit)
+		for _, change := range commit.Changes {
+			if change.Type != DifferenceTypeRemoved && strings.HasPrefix(change.Path, "tables/") && !strings.HasSuffix(change.Path, ".parquet") {
+				return fmt.Errorf("%w: %s is not a parquet file", ErrHookRejected, change.Path)
+			}
+		}
+		return nil
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "tables/t1/part1.parquet", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "tables/t1/part2.csv", nil, "")
+
+	_, err := c.Commit(ctx, repository, "master", "commit csv", "tester", Metadata{"key": "value"})
+	if !errors.Is(err, ErrHookRejected) {
+		t.Fatalf("Commit() error = %v, expected %s", err, ErrHookRejected)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("pre-commit hook called %d times, expected 1", len(calls))
+	}
+	commit := calls[0]
+	if commit.Repository != repository || commit.Branch != "master" || commit.Committer != "tester" ||
+		commit.Message != "commit csv" || commit.Metadata["key"] != "value" {
+		t.Errorf("pre-commit hook got %+v", commit)
+	}
+	expectedChanges := Differences{
+		{Type: DifferenceTypeAdded, Path: "tables/t1/part1.parquet", SizeDelta: testCreateEntryCalcSize("tables/t1/part1.parquet", "")},
+		{Type: DifferenceTypeAdded, Path: "tables/t1/part2.csv", SizeDelta: testCreateEntryCalcSize("tables/t1/part2.csv", "")},
+	}
+	if len(commit.Changes) != len(expectedChanges) {
+		t.Fatalf("pre-commit hook got changes %s, expected %s", commit.Changes, expectedChanges)
+	}
+	for i := range expectedChanges {
+		if commit.Changes[i] != expectedChanges[i] {
+			t.Fatalf("pre-commit hook got changes %s, expected %s", commit.Changes, expectedChanges)
+		}
+	}
+
+	// rejected commit leaves the changes uncommitted
+	workspace, _, err := c.ListWorkspace(ctx, repository, "master", -1, "")
+	testutil.MustDo(t, "list workspace", err)
+	if len(workspace) != 2 {
+		t.Fatalf("workspace has %d entries after rejected commit, expected 2", len(workspace))
+	}
+
+	testutil.MustDo(t, "delete csv", c.DeleteEntry(ctx, repository, "master", "tables/t1/part2.csv"))
+	_, err = c.Commit(ctx, repository, "master", "commit parquet", "tester", nil)
+	testutil.MustDo(t, "commit parquet", err)
+}
+
+func TestCataloger_PreCommitHook_ConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	var (
+		c     Cataloger
+		calls int
+	)
+	c = testCataloger(t, WithPreCommitHook(func(ctx context.Context, commit *PendingCommit) error {
+		calls++
+		// the hook runs outside of the commit transaction - a write while it runs is not committed unchecked
+		return c.CreateEntry(ctx, commit.Repository, commit.Branch,
+			Entry{Path: fmt.Sprintf("/unchecked%d", calls), PhysicalAddress: "/addr", Checksum: "ff", Size: 1}, CreateEntryParams{})
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+
+	_, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	if !errors.Is(err, ErrBranchConcurrentUpdate) {
+		t.Fatalf("Commit() error = %v, expected %s", err, ErrBranchConcurrentUpdate)
+	}
+	if calls != 1 {
+		t.Fatalf("pre-commit hook called %d times, expected 1", calls)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "/file1", false)
+}
+
+func TestCataloger_PreMergeHook(t *testing.T) {
+	ctx := context.Background()
+	var calls []*PendingMerge
+	reject := true
+	c := testCataloger(t, WithPreMergeHook(func(_ context.Context, merge *PendingMerge) error {
+		calls = append(calls, merge)
+		if reject {
+			return fmt.Errorf("%w: row count check failed", ErrHookRejected)
+		}
+		return nil
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit file1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file2", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit file2", "tester", nil)
+	testutil.MustDo(t, "commit file2", err)
+
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	if !errors.Is(err, ErrHookRejected) {
+		t.Fatalf("Merge() error = %v, expected %s", err, ErrHookRejected)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("pre-merge hook called %d times, expected 1", len(calls))
+	}
+	merge := calls[0]
+	if merge.Repository != repository || merge.SourceBranch != "branch1" || merge.DestinationBranch != "master" ||
+		merge.Committer != "tester" || merge.Message == "" {
+		t.Errorf("pre-merge hook got %+v", merge)
+	}
+	expectedChange := Difference{Type: DifferenceTypeAdded, Path: "/file2", SizeDelta: testCreateEntryCalcSize("/file2", "")}
+	if len(merge.Changes) != 1 || merge.Changes[0] != expectedChange {
+		t.Errorf("pre-merge hook got changes %s, expected + /file2", merge.Changes)
+	}
+	if merge.Summary[DifferenceTypeAdded] != 1 {
+		t.Errorf("pre-merge hook got summary %v, expected 1 added", merge.Summary)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file2", false)
+
+	reject = false
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	testutil.MustDo(t, "merge branch1", err)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file2", true)
+}
+
+func TestCataloger_PreMergeHook_ConcurrentCommit(t *testing.T) {
+	ctx := context.Background()
+	var (
+		c     Cataloger
+		calls int
+	)
+	c = testCataloger(t, WithPreMergeHook(func(ctx context.Context, merge *PendingMerge) error {
+		calls++
+		// a commit on the source branch while the hook runs is not merged unchecked
+		path := fmt.Sprintf("/unchecked%d", calls)
+		if err := c.CreateEntry(ctx, merge.Repository, merge.SourceBranch,
+			Entry{Path: path, PhysicalAddress: "/addr", Checksum: "ff", Size: 1}, CreateEntryParams{}); err != nil {
+			return err
+		}
+		_, err := c.Commit(ctx, merge.Repository, merge.SourceBranch, "commit "+path, "tester", nil)
+		return err
+	}))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil)
+	testutil.MustDo(t, "commit file1", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file2", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit file2", "tester", nil)
+	testutil.MustDo(t, "commit file2", err)
+
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	if !errors.Is(err, ErrBranchConcurrentUpdate) {
+		t.Fatalf("Merge() error = %v, expected %s", err, ErrBranchConcurrentUpdate)
+	}
+	if calls != 1 {
+		t.Fatalf("pre-merge hook called %d times, expected 1", calls)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file2", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/unchecked1", false)
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"time"
+
+	"github.com/treeverse/lakefs/ident"
 )
 
 type Metadata map[string]string
@@ -13,6 +15,10 @@ type Repository struct {
 	StorageNamespace string    `db:"storage_namespace"`
 	DefaultBranch    string    `db:"default_branch"`
 	CreationDate     time.Time `db:"creation_date"`
+	ReadOnly         bool      `db:"read_only"`
+	Labels           Metadata  `db:"labels"`
+	// HashAlgorithm computes the content addresses (tag IDs) of the repository
+	HashAlgorithm ident.Algorithm `db:"hash_algorithm"`
 }
 
 type Entry struct {
@@ -22,22 +28,103 @@ type Entry struct {
 	CreationDate    time.Time `db:"creation_date"`
 	Size            int64     `db:"size"`
 	Checksum        string    `db:"checksum"`
+	ContentType     string    `db:"content_type"`
 	Metadata        Metadata  `db:"metadata"`
 	Expired         bool      `db:"is_expired"`
 }
 
+// WorkspaceEntry is an uncommitted entry on a branch.  A tombstone entry marks an uncommitted
+// delete of the path.
+type WorkspaceEntry struct {
+	Entry
+	Tombstone bool `db:"is_tombstone"`
+}
+
+// RepositoryEvent is a change made to a repository.  Events of a repository are numbered by Seq
+// in the order their changes were made.
+type RepositoryEvent struct {
+	Seq          int64     `db:"seq"`
+	Type         string    `db:"event_type"`
+	Branch       string    `db:"branch"`
+	Path         string    `db:"path"`
+	Reference    string    `db:"reference"`
+	CreationDate time.Time `db:"creation_date"`
+}
+
 type CommitLog struct {
 	Reference    string
 	Committer    string    `db:"committer"`
 	Message      string    `db:"message"`
 	CreationDate time.Time `db:"creation_date"`
 	Metadata     Metadata  `db:"metadata"`
-	Parents      []string
+	// Parents are the references of the parent commits.  A merge commit lists the merge source
+	// first and the previous commit of its branch last.  The first commit of a branch has the
+	// commit the branch was created from as its only parent.
+	Parents []string
+}
+
+// IsMerge returns true if the commit has more than one parent
+func (c *CommitLog) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// FirstParent returns the parent commit followed by first-parent traversal: the previous commit
+// of the branch, or the commit the branch was created from.  Empty if there is no parent.
+func (c *CommitLog) FirstParent() string {
+	if len(c.Parents) == 0 {
+		return ""
+	}
+	return c.Parents[len(c.Parents)-1]
+}
+
+// MergeSource returns the merged commit of a merge commit, empty if the commit is not a merge
+func (c *CommitLog) MergeSource() string {
+	if !c.IsMerge() {
+		return ""
+	}
+	return c.Parents[0]
 }
 
 type MergeResult struct {
 	Summary   map[DifferenceType]int
 	Reference string
+	// Conflicts holds up to MergeConflictsMaxLimit conflicts of a failed merge, ordered by path.
+	// All of them are kept until the next merge of the branches and listed by ListMergeConflicts.
+	Conflicts []*MergeConflict
+}
+
+// ConflictType describes the changes of the source and destination branches to a conflicting path
+type ConflictType string
+
+const (
+	// ConflictTypeBothModified - the path was created or changed on both branches
+	ConflictTypeBothModified ConflictType = "both-modified"
+	// ConflictTypeDeletedBySource - the path was deleted on the source and changed on the destination
+	ConflictTypeDeletedBySource ConflictType = "deleted-by-source"
+	// ConflictTypeDeletedByDestination - the path was changed on the source and deleted on the destination
+	ConflictTypeDeletedByDestination ConflictType = "deleted-by-destination"
+)
+
+type MergeConflict struct {
+	Path string       `db:"path"`
+	Type ConflictType `db:"conflict_type"`
+}
+
+type RebaseResult struct {
+	// Reference is the new head of the rebased branch
+	Reference string
+	// Commits holds the references of the replayed commits, oldest first
+	Commits []string
+	// Conflicts holds the conflicts of a failed rebase, ordered by the replayed commit that first
+	// changed their path
+	Conflicts []*RebaseConflict
+}
+
+// RebaseConflict is a conflict of a rebase, with the reference of the replayed commit that first
+// changed its path
+type RebaseConflict struct {
+	Reference string
+	MergeConflict
 }
 
 type commitLogRaw struct {
@@ -50,6 +137,7 @@ type commitLogRaw struct {
 	Metadata              Metadata  `db:"metadata"`
 	MergeSourceBranchName string    `db:"merge_source_branch_name"`
 	MergeSourceCommit     CommitID  `db:"merge_source_commit"`
+	Squashed              bool      `db:"squashed"`
 }
 
 type lineageCommit struct {
@@ -62,6 +150,44 @@ type Branch struct {
 	Name       string `db:"name"`
 }
 
+// Tag is a name for a commit, annotated with the tagger, a message and metadata.  ID is the
+// content address of the tag.
+type Tag struct {
+	Repository      string
+	Name            string
+	ID              string
+	CommitReference string
+	Tagger          string
+	Message         string
+	Metadata        Metadata
+	CreationDate    time.Time
+}
+
+const tagIdentityVersion = 1
+
+// Identity writes the tag fields, except its repository and ID, in their canonical encoding
+func (t *Tag) Identity(e *ident.Encoder) {
+	e.String("tag")
+	e.Byte(tagIdentityVersion)
+	e.String(t.Name)
+	e.String(t.CommitReference)
+	e.String(t.Tagger)
+	e.String(t.Message)
+	e.Time(t.CreationDate)
+	e.Map(t.Metadata)
+}
+
+// BranchInfo is the branch state - the last commit and whether it has uncommitted changes
+type BranchInfo struct {
+	Repository      string
+	Name            string
+	CommitReference string
+	CommitDate      time.Time
+	Dirty           bool
+	// Version is incremented by every commit, merge into, reset or revert of the branch
+	Version int64
+}
+
 type MultipartUpload struct {
 	Repository      string    `db:"repository"`
 	UploadID        string    `db:"upload_id"`
@@ -70,6 +196,14 @@ type MultipartUpload struct {
 	PhysicalAddress string    `db:"physical_address"`
 }
 
+// MultipartUploadPart is a part uploaded as part of a multipart upload
+type MultipartUploadPart struct {
+	PartNumber   int       `db:"part_number"`
+	ETag         string    `db:"etag"`
+	Size         int64     `db:"size"`
+	CreationDate time.Time `db:"creation_date"`
+}
+
 func (j Metadata) Value() (driver.Value, error) {
 	if j == nil {
 		return json.Marshal(struct{}{})
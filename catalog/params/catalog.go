@@ -21,8 +21,13 @@ type BatchWrite struct {
 	EntriesInsertSize int
 }
 
+type Quotas struct {
+	MaxRepositories int
+}
+
 type Catalog struct {
 	BatchRead  BatchRead
 	BatchWrite BatchWrite
 	Cache      Cache
+	Quotas     Quotas
 }
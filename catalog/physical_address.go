@@ -0,0 +1,37 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/db"
+)
+
+type externalAddressesContextKey struct{}
+
+// WithExternalAddresses returns a context that allows CreateEntries to write entries whose physical
+// address is outside the storage namespace of the repository, such as objects imported in place from
+// an existing bucket.  Entries are read from their physical address, so only trusted callers set it,
+// never on behalf of a user supplied address.
+func WithExternalAddresses(ctx context.Context) context.Context {
+	return context.WithValue(ctx, externalAddressesContextKey{}, true)
+}
+
+// checkAddressesInNamespace fails with ErrAddressOutsideNamespace if the physical address of an entry
+// does not resolve inside the storage namespace of repository, unless ctx allows external addresses
+func (c *cataloger) checkAddressesInNamespace(ctx context.Context, tx db.Tx, repository string, entries ...*Entry) error {
+	if allowed, _ := ctx.Value(externalAddressesContextKey{}).(bool); allowed {
+		return nil
+	}
+	repo, err := c.getRepositoryCache(tx, repository)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !block.IsKeyInNamespace(repo.StorageNamespace, entry.PhysicalAddress) {
+			return fmt.Errorf("%w: %s of %s", ErrAddressOutsideNamespace, entry.PhysicalAddress, entry.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	protectedPathsConfigKey = "protectedPaths"
+
+	// protectedPathAnySegments is the pattern segment that matches any number of path segments
+	protectedPathAnySegments = "**"
+)
+
+// ProtectedPathRule rejects writes and deletes of paths matching Pattern on the branches matching
+// one of Branches (all branches when empty).  Pattern segments use path.Match syntax, and a "**"
+// segment matches any number of segments, e.g. "prod/**".  Branches use path.Match syntax.
+type ProtectedPathRule struct {
+	Pattern  string   `json:"pattern"`
+	Branches []string `json:"branches,omitempty"`
+}
+
+type protectedPathsAuthorizerContextKey struct{}
+
+// ProtectedPathsAuthorizer authorizes changes to paths protected by a rule
+type ProtectedPathsAuthorizer func(paths []string) error
+
+// WithProtectedPathsAuthorizer returns a context that allows changes to protected paths accepted
+// by authorize.  It is called only with the paths matched by a rule, so writes that touch no
+// protected path need no authorization.
+func WithProtectedPathsAuthorizer(ctx context.Context, authorize ProtectedPathsAuthorizer) context.Context {
+	return context.WithValue(ctx, protectedPathsAuthorizerContextKey{}, authorize)
+}
+
+// Validate checks the rule pattern and branches are well formed
+func (r *ProtectedPathRule) Validate() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("%w: pattern", ErrInvalidValue)
+	}
+	for _, segment := range strings.Split(r.Pattern, DefaultPathDelimiter) {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("%w: pattern '%s'", ErrInvalidValue, r.Pattern)
+		}
+	}
+	for _, branch := range r.Branches {
+		if _, err := path.Match(branch, ""); err != nil {
+			return fmt.Errorf("%w: branch '%s'", ErrInvalidValue, branch)
+		}
+	}
+	return nil
+}
+
+// NamesBranch reports whether one of the rule Branches matches branch.  A rule without Branches
+// applies to all branches but names none of them.
+func (r *ProtectedPathRule) NamesBranch(branch string) bool {
+	for _, pattern := range r.Branches {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the rule protects p on branch
+func (r *ProtectedPathRule) Matches(branch, p string) bool {
+	if len(r.Branches) > 0 && !r.NamesBranch(branch) {
+		return false
+	}
+	return matchPathSegments(strings.Split(r.Pattern, DefaultPathDelimiter), strings.Split(p, DefaultPathDelimiter))
+}
+
+func matchPathSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == protectedPathAnySegments {
+			for i := 0; i <= len(segments); i++ {
+				if matchPathSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// getProtectedPaths returns the protected path rules of the repository
+func getProtectedPaths(tx db.Tx, repoID int) ([]*ProtectedPathRule, error) {
+	var rules []*ProtectedPathRule
+	_, err := getRepositoryConfig(tx, repoID, protectedPathsConfigKey, &rules)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get protected paths: %w", err)
+	}
+	return rules, nil
+}
+
+// checkPathsWritable returns ErrPathProtected if one of paths is protected on branch and the
+// ProtectedPathsAuthorizer of ctx does not accept the protected paths.  The rules are read by the
+// calling transaction.
+func (c *cataloger) checkPathsWritable(ctx context.Context, tx db.Tx, repository, branch string, paths ...string) error {
+	repoID, err := c.getRepositoryIDCache(tx, repository)
+	if err != nil {
+		return err
+	}
+	rules, err := getProtectedPaths(tx, repoID)
+	if err != nil {
+		return err
+	}
+	var protected []string
+	var matchErr error
+	for _, p := range paths {
+		for _, rule := range rules {
+			if rule.Matches(branch, p) {
+				protected = append(protected, p)
+				if matchErr == nil {
+					matchErr = fmt.Errorf("%w: %s matches %s", ErrPathProtected, p, rule.Pattern)
+				}
+				break
+			}
+		}
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+	authorize, ok := ctx.Value(protectedPathsAuthorizerContextKey{}).(ProtectedPathsAuthorizer)
+	if !ok || authorize(protected) != nil {
+		return matchErr
+	}
+	return nil
+}
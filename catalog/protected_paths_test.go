@@ -0,0 +1,52 @@
+package catalog
+
+import "testing"
+
+func TestProtectedPathRule_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   ProtectedPathRule
+		branch string
+		path   string
+		want   bool
+	}{
+		{name: "any depth", rule: ProtectedPathRule{Pattern: "prod/**"}, branch: "master", path: "prod/a/b/c", want: true},
+		{name: "any depth direct", rule: ProtectedPathRule{Pattern: "prod/**"}, branch: "master", path: "prod/a", want: true},
+		{name: "any depth other prefix", rule: ProtectedPathRule{Pattern: "prod/**"}, branch: "master", path: "production/a", want: false},
+		{name: "single segment", rule: ProtectedPathRule{Pattern: "prod/*"}, branch: "master", path: "prod/a/b", want: false},
+		{name: "middle", rule: ProtectedPathRule{Pattern: "**/_SUCCESS"}, branch: "master", path: "tables/t1/_SUCCESS", want: true},
+		{name: "middle root", rule: ProtectedPathRule{Pattern: "**/_SUCCESS"}, branch: "master", path: "_SUCCESS", want: true},
+		{name: "glob segment", rule: ProtectedPathRule{Pattern: "data/*.parquet"}, branch: "master", path: "data/part-1.parquet", want: true},
+		{name: "exact", rule: ProtectedPathRule{Pattern: "config.yaml"}, branch: "master", path: "config.yaml", want: true},
+		{name: "branch matches", rule: ProtectedPathRule{Pattern: "prod/**", Branches: []string{"main", "release-*"}}, branch: "release-1", path: "prod/a", want: true},
+		{name: "branch not matched", rule: ProtectedPathRule{Pattern: "prod/**", Branches: []string{"main", "release-*"}}, branch: "feature", path: "prod/a", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.branch, tt.path); got != tt.want {
+				t.Errorf("Matches(%s, %s) = %t, expected %t", tt.branch, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectedPathRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ProtectedPathRule
+		wantErr bool
+	}{
+		{name: "valid", rule: ProtectedPathRule{Pattern: "prod/**", Branches: []string{"main"}}},
+		{name: "empty pattern", rule: ProtectedPathRule{}, wantErr: true},
+		{name: "bad pattern", rule: ProtectedPathRule{Pattern: "prod/[a"}, wantErr: true},
+		{name: "bad branch", rule: ProtectedPathRule{Pattern: "prod/**", Branches: []string{"[a"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() err = %v, expected error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
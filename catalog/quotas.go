@@ -0,0 +1,175 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+const quotasConfigKey = "quotas"
+
+// RepositoryQuotas limits the resources used by a repository.  A zero limit is unlimited.
+type RepositoryQuotas struct {
+	// MaxBranches is the maximal number of branches, including the default branch
+	MaxBranches int64 `json:"max_branches,omitempty"`
+	// MaxObjects is the maximal number of objects stored on all branches
+	MaxObjects int64 `json:"max_objects,omitempty"`
+	// MaxSizeBytes is the maximal total (logical) size of the objects stored on all branches
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty"`
+}
+
+// RepositoryUsage is the usage counted against the repository quotas
+type RepositoryUsage struct {
+	Branches  int64 `db:"branches"`
+	Objects   int64 `db:"objects"`
+	SizeBytes int64 `db:"size_bytes"`
+}
+
+// Validate checks the quotas are not negative
+func (q *RepositoryQuotas) Validate() error {
+	if q.MaxBranches < 0 {
+		return fmt.Errorf("%w: max branches", ErrInvalidValue)
+	}
+	if q.MaxObjects < 0 {
+		return fmt.Errorf("%w: max objects", ErrInvalidValue)
+	}
+	if q.MaxSizeBytes < 0 {
+		return fmt.Errorf("%w: max size", ErrInvalidValue)
+	}
+	return nil
+}
+
+// getRepositoryQuotas returns the quotas of the repository, without limits if none were set
+func getRepositoryQuotas(tx db.Tx, repoID int) (*RepositoryQuotas, error) {
+	var quotas RepositoryQuotas
+	_, err := getRepositoryConfig(tx, repoID, quotasConfigKey, &quotas)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("get quotas: %w", err)
+	}
+	return &quotas, nil
+}
+
+func getRepositoryBranchesUsage(tx db.Tx, repoID int) (int64, error) {
+	var branches int64
+	err := tx.Get(&branches, `SELECT COUNT(*) FROM catalog_branches
+		WHERE repository_id = $1 AND name NOT LIKE '`+deletedBranchPrefix+`%'`, repoID)
+	return branches, err
+}
+
+// getRepositoryObjectsUsage counts the current entries stored on the branches of the repository.
+// Entries of a branch that are overwritten or deleted by uncommitted changes are counted until
+// the changes are committed.  Entries of a deleted branch are counted only while a branch was
+// created from it.
+func getRepositoryObjectsUsage(tx db.Tx, repoID int) (objects int64, sizeBytes int64, err error) {
+	var usage RepositoryUsage
+	err = tx.Get(&usage, `SELECT COUNT(*) AS objects, COALESCE(SUM(e.size), 0) AS size_bytes
+		FROM catalog_entries e JOIN catalog_branches b ON e.branch_id = b.id
+		WHERE b.repository_id = $1 AND e.max_commit = catalog_max_commit_id() AND NOT e.is_expired
+			AND catalog_branch_in_use(b.id)`, repoID)
+	return usage.Objects, usage.SizeBytes, err
+}
+
+// setRepositoryUsageTracking starts or stops maintaining the objects usage of the repository in
+// catalog_repositories_usage.  The usage is counted once when tracking starts, and then updated
+// by a trigger on every change to the entries of the repository.
+func setRepositoryUsageTracking(tx db.Tx, repoID int, track bool) error {
+	if !track {
+		_, err := tx.Exec(`DELETE FROM catalog_repositories_usage WHERE repository_id = $1`, repoID)
+		return err
+	}
+	_, err := tx.Exec(`INSERT INTO catalog_repositories_usage (repository_id, objects, size_bytes)
+		SELECT $1, COUNT(*), COALESCE(SUM(e.size), 0)
+		FROM catalog_entries e JOIN catalog_branches b ON e.branch_id = b.id
+		WHERE b.repository_id = $1 AND e.max_commit = catalog_max_commit_id() AND NOT e.is_expired
+			AND catalog_branch_in_use(b.id)
+		ON CONFLICT DO NOTHING`, repoID)
+	return err
+}
+
+// recountRepositoryTrackedUsage counts again the tracked objects usage of the repository, if it
+// is tracked.  Deleting a branch, or creating a branch from a deleted branch, changes which
+// branches are counted without changing their entries.
+func recountRepositoryTrackedUsage(tx db.Tx, repoID int) error {
+	_, err := tx.Exec(`UPDATE catalog_repositories_usage u
+		SET objects = c.objects, size_bytes = c.size_bytes
+		FROM (SELECT COUNT(*) AS objects, COALESCE(SUM(e.size), 0) AS size_bytes
+			FROM catalog_entries e JOIN catalog_branches b ON e.branch_id = b.id
+			WHERE b.repository_id = $1 AND e.max_commit = catalog_max_commit_id() AND NOT e.is_expired
+				AND catalog_branch_in_use(b.id)) c
+		WHERE u.repository_id = $1`, repoID)
+	return err
+}
+
+// getRepositoryTrackedObjectsUsage returns the objects usage maintained for repositories with
+// objects quotas, or db.ErrNotFound if the usage of the repository is not tracked
+func getRepositoryTrackedObjectsUsage(tx db.Tx, repoID int) (objects int64, sizeBytes int64, err error) {
+	var usage RepositoryUsage
+	err = tx.Get(&usage, `SELECT objects, size_bytes FROM catalog_repositories_usage WHERE repository_id = $1`, repoID)
+	return usage.Objects, usage.SizeBytes, err
+}
+
+// checkBranchQuota returns ErrQuotaExceeded if the repository has more branches than its quota.
+// Called by the creating transaction after the branch was added.
+func (c *cataloger) checkBranchQuota(tx db.Tx, repository string) error {
+	repoID, err := c.getRepositoryIDCache(tx, repository)
+	if err != nil {
+		return err
+	}
+	quotas, err := getRepositoryQuotas(tx, repoID)
+	if err != nil || quotas.MaxBranches == 0 {
+		return err
+	}
+	branches, err := getRepositoryBranchesUsage(tx, repoID)
+	if err != nil {
+		return fmt.Errorf("count branches: %w", err)
+	}
+	if branches > quotas.MaxBranches {
+		return fmt.Errorf("%w: %d branches, quota is %d", ErrQuotaExceeded, branches, quotas.MaxBranches)
+	}
+	return nil
+}
+
+// checkObjectsQuota returns ErrQuotaExceeded if the objects of the repository exceed its quotas.
+// Called by writing transactions after the entries were added.  Reads the usage maintained since
+// the quotas were set, and counts the objects only if it is missing.
+func (c *cataloger) checkObjectsQuota(tx db.Tx, repository string) error {
+	repoID, err := c.getRepositoryIDCache(tx, repository)
+	if err != nil {
+		return err
+	}
+	quotas, err := getRepositoryQuotas(tx, repoID)
+	if err != nil || (quotas.MaxObjects == 0 && quotas.MaxSizeBytes == 0) {
+		return err
+	}
+	objects, sizeBytes, err := getRepositoryTrackedObjectsUsage(tx, repoID)
+	if errors.Is(err, db.ErrNotFound) {
+		objects, sizeBytes, err = getRepositoryObjectsUsage(tx, repoID)
+	}
+	if err != nil {
+		return fmt.Errorf("count objects: %w", err)
+	}
+	if quotas.MaxObjects > 0 && objects > quotas.MaxObjects {
+		return fmt.Errorf("%w: %d objects, quota is %d", ErrQuotaExceeded, objects, quotas.MaxObjects)
+	}
+	if quotas.MaxSizeBytes > 0 && sizeBytes > quotas.MaxSizeBytes {
+		return fmt.Errorf("%w: %d bytes, quota is %d", ErrQuotaExceeded, sizeBytes, quotas.MaxSizeBytes)
+	}
+	return nil
+}
+
+// checkRepositoriesQuota returns ErrQuotaExceeded if there are more repositories than the
+// configured maximum.  Called by the creating transaction after the repository was added.
+func (c *cataloger) checkRepositoriesQuota(tx db.Tx) error {
+	if c.Quotas.MaxRepositories == 0 {
+		return nil
+	}
+	var repositories int
+	if err := tx.Get(&repositories, `SELECT COUNT(*) FROM catalog_repositories`); err != nil {
+		return fmt.Errorf("count repositories: %w", err)
+	}
+	if repositories > c.Quotas.MaxRepositories {
+		return fmt.Errorf("%w: %d repositories, quota is %d", ErrQuotaExceeded, repositories, c.Quotas.MaxRepositories)
+	}
+	return nil
+}
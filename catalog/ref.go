@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/mr-tron/base58"
@@ -19,6 +20,12 @@ const (
 	CommittedSuffix = ":HEAD"
 	CommitPrefix    = "~"
 
+	TimestampRefPrefix = "@{"
+	TimestampRefSuffix = "}"
+
+	AncestorRefSuffix = "~"
+	ParentRefSuffix   = "^"
+
 	InternalObjectRefSeparator = "$"
 	InternalObjectRefFormat    = "int:pbm:%s"
 	InternalObjectRefParts     = 3
@@ -84,6 +91,73 @@ func ParseRef(ref string) (*Ref, error) {
 	}, nil
 }
 
+// IsTimestampRef returns true if ref is a "branch@{timestamp}" expression
+func IsTimestampRef(ref string) bool {
+	return strings.HasSuffix(ref, TimestampRefSuffix) && strings.Contains(ref, TimestampRefPrefix)
+}
+
+// ParseTimestampRef parses a "branch@{timestamp}" expression into the branch name and the
+// timestamp.  The timestamp is formatted as RFC3339, ex: "master@{2020-03-01T00:00:00Z}".
+func ParseTimestampRef(ref string) (string, time.Time, error) {
+	if !IsTimestampRef(ref) {
+		return "", time.Time{}, fmt.Errorf("%w: missing timestamp", ErrInvalidReference)
+	}
+	idx := strings.LastIndex(ref, TimestampRefPrefix)
+	branch := ref[:idx]
+	ts, err := time.Parse(time.RFC3339, ref[idx+len(TimestampRefPrefix):len(ref)-len(TimestampRefSuffix)])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: timestamp: %s", ErrInvalidReference, err)
+	}
+	return branch, ts, nil
+}
+
+// ParseAncestorRef parses a "ref~N" or "ref^" expression into the reference and the number of
+// generations to go back from it.  Suffixes can be combined, ex: "master~2^" is 3 generations
+// back.  A reference without suffixes returns 0 generations.
+func ParseAncestorRef(ref string) (string, int, error) {
+	// skip the commit reference prefix, base58 encoding never includes the suffix characters
+	start := 0
+	if strings.HasPrefix(ref, CommitPrefix) {
+		start = len(CommitPrefix)
+	}
+	idx := strings.IndexAny(ref[start:], AncestorRefSuffix+ParentRefSuffix)
+	if idx == -1 {
+		return ref, 0, nil
+	}
+	base := ref[:start+idx]
+	suffixes := ref[start+idx:]
+	generations := 0
+	for len(suffixes) > 0 {
+		switch suffixes[:1] {
+		case ParentRefSuffix:
+			generations++
+			suffixes = suffixes[1:]
+		case AncestorRefSuffix:
+			suffixes = suffixes[1:]
+			n := 0
+			for n < len(suffixes) && suffixes[n] >= '0' && suffixes[n] <= '9' {
+				n++
+			}
+			if n == 0 {
+				generations++
+				continue
+			}
+			count, err := strconv.Atoi(suffixes[:n])
+			if err != nil {
+				return "", 0, fmt.Errorf("%w: ancestor count", ErrInvalidReference)
+			}
+			generations += count
+			suffixes = suffixes[n:]
+		default:
+			return "", 0, fmt.Errorf("%w: unexpected suffix %s", ErrInvalidReference, suffixes)
+		}
+	}
+	if base == "" {
+		return "", 0, fmt.Errorf("%w: missing reference", ErrInvalidReference)
+	}
+	return base, generations, nil
+}
+
 // InternalObjectRef provides information that uniquely identifies an object between
 // transactions.  It might be invalidated by some database changes.
 type InternalObjectRef struct {
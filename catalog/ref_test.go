@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRef_String(t *testing.T) {
@@ -105,6 +106,56 @@ func TestParseRef(t *testing.T) {
 	}
 }
 
+func TestParseTimestampRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		wantBranch string
+		wantTime   time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "basic",
+			ref:        "master@{2020-03-01T00:00:00Z}",
+			wantBranch: "master",
+			wantTime:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "with zone",
+			ref:        "feature-1@{2020-03-01T02:00:00+02:00}",
+			wantBranch: "feature-1",
+			wantTime:   time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "branch",
+			ref:     "master",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp",
+			ref:     "master@{yesterday}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branch, ts, err := ParseTimestampRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimestampRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if branch != tt.wantBranch {
+				t.Errorf("ParseTimestampRef() branch = %s, want %s", branch, tt.wantBranch)
+			}
+			if !ts.Equal(tt.wantTime) {
+				t.Errorf("ParseTimestampRef() time = %s, want %s", ts, tt.wantTime)
+			}
+		})
+	}
+}
+
 func TestParseInternalObjectRef(t *testing.T) {
 	// Internal representation is _not_ user-visible, test just round-trip encode and parse.
 	tests := []struct {
@@ -161,3 +212,37 @@ func TestParseInternalObjectRefStringFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAncestorRef(t *testing.T) {
+	commitRef := MakeReference("feature", 10)
+	tests := []struct {
+		ref             string
+		wantRef         string
+		wantGenerations int
+		wantErr         bool
+	}{
+		{ref: "master", wantRef: "master", wantGenerations: 0},
+		{ref: "master^", wantRef: "master", wantGenerations: 1},
+		{ref: "master^^", wantRef: "master", wantGenerations: 2},
+		{ref: "master~", wantRef: "master", wantGenerations: 1},
+		{ref: "master~3", wantRef: "master", wantGenerations: 3},
+		{ref: "master~2^", wantRef: "master", wantGenerations: 3},
+		{ref: "master~0", wantRef: "master", wantGenerations: 0},
+		{ref: "master:HEAD~1", wantRef: "master:HEAD", wantGenerations: 1},
+		{ref: commitRef, wantRef: commitRef, wantGenerations: 0},
+		{ref: commitRef + "~12", wantRef: commitRef, wantGenerations: 12},
+		{ref: "master~1x", wantErr: true},
+		{ref: "^", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			gotRef, gotGenerations, err := ParseAncestorRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAncestorRef() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if gotRef != tt.wantRef || gotGenerations != tt.wantGenerations {
+				t.Errorf("ParseAncestorRef() = %s, %d, want %s, %d", gotRef, gotGenerations, tt.wantRef, tt.wantGenerations)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/ident"
+)
+
+const (
+	ReplicationStreamVersion = 1
+
+	replicationCheckpointConfigKey = "replicationCheckpoint"
+
+	replicationRecordBranch      = "branch"
+	replicationRecordCommit      = "commit"
+	replicationRecordEntry       = "entry"
+	replicationRecordEntryClosed = "entry_closed"
+	replicationRecordTag         = "tag"
+	replicationRecordDigest      = "digest"
+)
+
+var (
+	ErrReplicationVersion    = errors.New("unsupported replication stream version")
+	ErrReplicationCheckpoint = errors.New("replication checkpoint mismatch")
+	ErrDigestMismatch        = errors.New("digest mismatch")
+)
+
+// replicationHeader is the first line of a replication stream.  The stream holds the changes of
+// the commits with an ID greater than After, up to and including Checkpoint.
+type replicationHeader struct {
+	Version          int      `json:"version"`
+	Repository       string   `json:"repository"`
+	StorageNamespace string   `json:"storage_namespace"`
+	DefaultBranch    string   `json:"default_branch"`
+	After            CommitID `json:"after"`
+	Checkpoint       CommitID `json:"checkpoint"`
+	// HashAlgorithm of the repository, empty in streams written before repositories recorded
+	// their algorithm
+	HashAlgorithm ident.Algorithm `json:"hash_algorithm,omitempty"`
+}
+
+// hashAlgorithm returns the hash algorithm of the replicated repository
+func (h replicationHeader) hashAlgorithm() ident.Algorithm {
+	if h.HashAlgorithm == "" {
+		return ident.DefaultAlgorithm
+	}
+	return h.HashAlgorithm
+}
+
+// replicationRecord is a line of a replication stream, holding the field that matches its type.
+// A stream ends with a digest record, the hex encoded SHA-256 of all the lines before it.
+type replicationRecord struct {
+	Type   string             `json:"type"`
+	Branch *replicationBranch `json:"branch,omitempty"`
+	Commit *replicationCommit `json:"commit,omitempty"`
+	Entry  *replicationEntry  `json:"entry,omitempty"`
+	Tag    *replicationTag    `json:"tag,omitempty"`
+	Digest string             `json:"digest,omitempty"`
+}
+
+// replicationBranch is a branch of the primary.  Every stream lists all the branches, a branch
+// missing from the stream was deleted.  FirstCommit identifies the branch across deletion and
+// re-creation under the same name.
+type replicationBranch struct {
+	Name        string   `json:"name"`
+	Lineage     []string `json:"lineage,omitempty"`
+	FirstCommit CommitID `json:"first_commit"`
+}
+
+type replicationCommit struct {
+	Branch            string     `json:"branch"`
+	CommitID          CommitID   `json:"commit_id"`
+	PreviousCommitID  CommitID   `json:"previous_commit_id"`
+	Committer         string     `json:"committer"`
+	Message           string     `json:"message"`
+	CreationDate      time.Time  `json:"creation_date"`
+	Metadata          Metadata   `json:"metadata,omitempty"`
+	MergeType         string     `json:"merge_type"`
+	MergeSourceBranch string     `json:"merge_source_branch,omitempty"`
+	MergeSourceCommit CommitID   `json:"merge_source_commit,omitempty"`
+	LineageCommits    []CommitID `json:"lineage_commits,omitempty"`
+	Squashed          bool       `json:"squashed,omitempty"`
+}
+
+// replicationEntry is a committed entry.  A record of type entry_closed sets the max commit of an
+// entry that was replicated by a previous stream, and only holds the fields that identify it.
+type replicationEntry struct {
+	Branch          string    `json:"branch" db:"branch"`
+	Path            string    `json:"path" db:"path"`
+	PhysicalAddress string    `json:"physical_address,omitempty" db:"physical_address"`
+	CreationDate    time.Time `json:"creation_date,omitempty" db:"creation_date"`
+	Size            int64     `json:"size,omitempty" db:"size"`
+	Checksum        string    `json:"checksum,omitempty" db:"checksum"`
+	ContentType     string    `json:"content_type,omitempty" db:"content_type"`
+	Metadata        Metadata  `json:"metadata,omitempty" db:"metadata"`
+	MinCommit       CommitID  `json:"min_commit" db:"min_commit"`
+	MaxCommit       CommitID  `json:"max_commit" db:"max_commit"`
+}
+
+type replicationTag struct {
+	Name         string    `json:"name" db:"name"`
+	ID           string    `json:"id" db:"id"`
+	Branch       string    `json:"branch" db:"branch"`
+	CommitID     CommitID  `json:"commit_id" db:"commit_id"`
+	Tagger       string    `json:"tagger" db:"tagger"`
+	Message      string    `json:"message" db:"message"`
+	Metadata     Metadata  `json:"metadata,omitempty" db:"metadata"`
+	CreationDate time.Time `json:"creation_date" db:"creation_date"`
+}
+
+// replicationReader reads a replication stream one record at a time, so the stream can be applied
+// while it is read.  The stream must end with a digest record that matches its content.
+type replicationReader struct {
+	Header replicationHeader
+	br     *bufio.Reader
+	h      hash.Hash
+	done   bool
+}
+
+// newReplicationReader returns a reader of the replication stream r, after reading its header
+func newReplicationReader(r io.Reader) (*replicationReader, error) {
+	rr := &replicationReader{
+		br: bufio.NewReader(r),
+		h:  sha256.New(),
+	}
+	line, err := rr.br.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if err := json.Unmarshal(line, &rr.Header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if rr.Header.Version != ReplicationStreamVersion {
+		return nil, fmt.Errorf("%w: %d", ErrReplicationVersion, rr.Header.Version)
+	}
+	_, _ = rr.h.Write(line)
+	return rr, nil
+}
+
+// Next returns the next record of the stream, or io.EOF after the digest record was read and
+// verified.  A stream that ends without a digest fails with ErrDigestMismatch.
+func (rr *replicationReader) Next() (*replicationRecord, error) {
+	if rr.done {
+		return nil, io.EOF
+	}
+	line, err := rr.br.ReadBytes('\n')
+	if errors.Is(err, io.EOF) && len(bytes.TrimSpace(line)) == 0 {
+		return nil, fmt.Errorf("%w: stream has no digest", ErrDigestMismatch)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read record: %w", err)
+	}
+	var rec replicationRecord
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, fmt.Errorf("read record: %w", err)
+	}
+	if rec.Type == replicationRecordDigest {
+		if digest := hex.EncodeToString(rr.h.Sum(nil)); rec.Digest != digest {
+			return nil, fmt.Errorf("%w: stream digest %s, expected %s", ErrDigestMismatch, digest, rec.Digest)
+		}
+		rest, err := ioutil.ReadAll(rr.br)
+		if err != nil {
+			return nil, fmt.Errorf("read record: %w", err)
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return nil, fmt.Errorf("%w: records after digest", ErrInvalidValue)
+		}
+		rr.done = true
+		return nil, io.EOF
+	}
+	_, _ = rr.h.Write(line)
+	switch {
+	case rec.Type == replicationRecordBranch && rec.Branch != nil,
+		rec.Type == replicationRecordCommit && rec.Commit != nil,
+		rec.Type == replicationRecordEntry && rec.Entry != nil,
+		rec.Type == replicationRecordEntryClosed && rec.Entry != nil,
+		rec.Type == replicationRecordTag && rec.Tag != nil:
+		return &rec, nil
+	default:
+		return nil, fmt.Errorf("%w: record type %s", ErrInvalidValue, rec.Type)
+	}
+}
+
+// splitList splits a comma separated list read from an array column
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func parseCommitIDs(s string) ([]CommitID, error) {
+	parts := splitList(s)
+	ids := make([]CommitID, len(parts))
+	for i, p := range parts {
+		id, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = CommitID(id)
+	}
+	return ids, nil
+}
+
+func formatCommitIDs(ids []CommitID) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(int64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,38 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RepositoryConfigUpgrade upgrades a repository configuration value from its version to the next
+type RepositoryConfigUpgrade func(value json.RawMessage) (json.RawMessage, error)
+
+// repositoryConfigUpgrades holds the upgrades of the configuration values of each key:
+// repositoryConfigUpgrades[key][v-1] upgrades a value of key from version v to v+1.  A key
+// without upgrades is at version 1.  Append an upgrade when the format of the value of a key
+// changes, and keep it until no stored value may be older than the version it upgrades from.
+var repositoryConfigUpgrades = map[string][]RepositoryConfigUpgrade{}
+
+// repositoryConfigVersion returns the current version of the configuration values of key
+func repositoryConfigVersion(key string) int {
+	return len(repositoryConfigUpgrades[key]) + 1
+}
+
+// upgradeRepositoryConfig applies the upgrades of key to value from version to the current
+// version of key.  Values stored by a newer lakeFS fail with ErrRepositoryConfigVersion.
+func upgradeRepositoryConfig(key string, version int, value []byte) ([]byte, error) {
+	upgrades := repositoryConfigUpgrades[key]
+	if version < 1 || version > len(upgrades)+1 {
+		return nil, fmt.Errorf("%w: %s version %d, current version %d",
+			ErrRepositoryConfigVersion, key, version, repositoryConfigVersion(key))
+	}
+	for v := version; v <= len(upgrades); v++ {
+		upgraded, err := upgrades[v-1](value)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade %s from version %d: %w", key, v, err)
+		}
+		value = upgraded
+	}
+	return value, nil
+}
@@ -19,3 +19,35 @@ var dedupRemoveObjectDroppedCounter = promauto.NewCounter(
 		Help: "A counter for dedup remove object that we dropped.",
 	},
 )
+
+var workspaceExpiredEntriesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "workspace_expired_entries",
+		Help: "Uncommitted entries discarded by workspace expiry",
+	},
+	[]string{"repository"},
+)
+
+var workspaceExpiredBytesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "workspace_expired_bytes",
+		Help: "Size of the uncommitted objects discarded by workspace expiry",
+	},
+	[]string{"repository"},
+)
+
+var mergesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "catalog_merges",
+		Help: "Merges by result (merged, conflict or dry_run)",
+	},
+	[]string{"result"},
+)
+
+var mergeChangesHistogram = promauto.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "catalog_merge_changes",
+		Help:    "Number of changes applied by a merge",
+		Buckets: prometheus.ExponentialBuckets(1, 10, 8),
+	},
+)
@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type tagRaw struct {
+	Repository   string    `db:"repository"`
+	Name         string    `db:"name"`
+	ID           string    `db:"id"`
+	BranchName   string    `db:"branch_name"`
+	CommitID     CommitID  `db:"commit_id"`
+	Tagger       string    `db:"tagger"`
+	Message      string    `db:"message"`
+	Metadata     Metadata  `db:"metadata"`
+	CreationDate time.Time `db:"creation_date"`
+}
+
+func (t *tagRaw) toTag() *Tag {
+	return &Tag{
+		Repository:      t.Repository,
+		Name:            t.Name,
+		ID:              t.ID,
+		CommitReference: MakeReference(t.BranchName, t.CommitID),
+		Tagger:          t.Tagger,
+		Message:         t.Message,
+		Metadata:        t.Metadata,
+		CreationDate:    t.CreationDate,
+	}
+}
+
+// sqTags selects the tags of the repository with repoID
+func sqTags(repoID int) sq.SelectBuilder {
+	return psql.Select("r.name AS repository", "t.name", "t.id", "b.name AS branch_name", "t.commit_id",
+		"t.tagger", "t.message", "t.metadata", "t.creation_date").
+		From("catalog_tags t").
+		Join("catalog_repositories r ON r.id = t.repository_id").
+		Join("catalog_branches b ON b.id = t.branch_id").
+		Where(sq.Eq{"t.repository_id": repoID})
+}
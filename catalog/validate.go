@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+
+	"github.com/treeverse/lakefs/ident"
 )
 
 var (
 	ErrInvalidValue = errors.New("invalid value")
 
 	validBranchNameRegexp     = regexp.MustCompile(`^\w[-\w]*$`)
+	validTagNameRegexp        = regexp.MustCompile(`^\w[-.\w]*$`)
 	validRepositoryNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{2,62}$`)
 )
 
@@ -45,6 +48,16 @@ func IsValidBranchName(branch string) bool {
 	return validBranchNameRegexp.MatchString(branch)
 }
 
+func ValidateTagName(tag string) ValidateFunc {
+	return func() bool {
+		return IsValidTagName(tag)
+	}
+}
+
+func IsValidTagName(tag string) bool {
+	return validTagNameRegexp.MatchString(tag)
+}
+
 func ValidateRepositoryName(repository string) ValidateFunc {
 	return func() bool {
 		return IsValidRepositoryName(repository)
@@ -66,7 +79,8 @@ func IsValidReference(reference string) bool {
 	if err != nil {
 		return false
 	}
-	if !IsValidBranchName(ref.Branch) {
+	// a deleted branch is referenced only by its commits
+	if !IsValidBranchName(ref.Branch) && !(isDeletedBranchName(ref.Branch) && ref.CommitID > UncommittedID) {
 		return false
 	}
 	if ref.CommitID < CommittedID {
@@ -81,6 +95,12 @@ func ValidateUploadID(uploadID string) ValidateFunc {
 	}
 }
 
+func ValidatePartNumber(partNumber int) ValidateFunc {
+	return func() bool {
+		return partNumber >= 1 && partNumber <= MultipartUploadMaxParts
+	}
+}
+
 func ValidatePath(name string) ValidateFunc {
 	return func() bool {
 		return IsNonEmptyString(name)
@@ -105,6 +125,10 @@ func ValidateCommitter(name string) ValidateFunc {
 	}
 }
 
+func IsValidHashAlgorithm(algorithm string) bool {
+	return ident.IsKnownAlgorithm(ident.Algorithm(algorithm))
+}
+
 func ValidateStorageNamespace(storageNamespace string) ValidateFunc {
 	return func() bool {
 		return IsNonEmptyString(storageNamespace)
@@ -54,7 +54,7 @@ func sqEntriesLineage(branchID int64, requestedCommit CommitID, lineage []lineag
 		Columns(strconv.FormatInt(branchID, 10)+" AS displayed_branch",
 			"e.path", "e.branch_id AS source_branch",
 			"e.min_commit", "e.physical_address",
-			"e.creation_date", "e.size", "e.checksum", "e.metadata",
+			"e.creation_date", "e.size", "e.checksum", "e.content_type", "e.metadata",
 			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired").
 		Column(maxCommitAlias).Column(isDeletedAlias)
 	return baseSelect
@@ -91,7 +91,7 @@ func sqEntriesLineageV(branchID int64, requestedCommit CommitID, lineage []linea
 		Column("? AS displayed_branch", strconv.FormatInt(branchID, 10)).
 		Columns("e.path", "e.branch_id AS source_branch",
 			"e.min_commit", "e.physical_address",
-			"e.creation_date", "e.size", "e.checksum", "e.metadata",
+			"e.creation_date", "e.size", "e.checksum", "e.content_type", "e.metadata",
 			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired").
 		Column(maxCommitAlias).Column(isDeletedAlias)
 	return baseSelect
@@ -110,6 +110,8 @@ func sqDiffFromChildV(parentID, childID int64, parentEffectiveCommit, childEffec
 		"f.path IS NOT NULL AND (f.physical_address = s.physical_address AND f.is_deleted = s.is_deleted) AS same_object",
 		"s.entry_ctid",
 		"f.source_branch",
+		"f.path IS NOT NULL AND NOT f.is_committed AS dest_uncommitted",
+		"f.path IS NOT NULL AND f.is_deleted AS dest_deleted",
 	).
 		// Conflict detection
 		Column(`-- parent either created or deleted after last merge  - conflict
@@ -143,6 +145,15 @@ func sqDiffFromChildV(parentID, childID int64, parentEffectiveCommit, childEffec
 		Else("NULL"),
 		"entry_ctid")).
 		Column("source_branch").
+		// the difference of a conflict when the source version is taken
+		Column(sq.Alias(sq.Case().When("DifferenceTypeRemoved", "1").
+			When("DifferenceTypeChanged", "2").
+			Else("0"), "source_diff_type")).
+		Column(sq.Alias(sq.Case().
+			When("NOT DifferenceTypeRemoved", "entry_ctid").
+			Else("NULL"), "source_entry_ctid")).
+		Column("dest_uncommitted").
+		Column("dest_deleted").
 		FromSelect(RemoveNonRelevantQ, "t1")
 }
 
@@ -173,6 +184,8 @@ func sqDiffFromParentV(parentID, childID int64, lastChildMergeWithParent CommitI
 							 OR s.min_commit > ? -- created after last merge
                            OR (s.max_commit >= ? AND s.is_deleted)) -- deleted after last merge
 						  AS DifferenceTypeConflict`, childID, lastChildMergeWithParent, lastChildMergeWithParent).
+		Column("s.path IS NOT NULL AND NOT s.is_committed AS dest_uncommitted").
+		Column("s.path IS NOT NULL AND s.is_deleted AS dest_deleted").
 		FromSelect(parentLineage, "f").
 		Where("f.displayed_branch = ?", parentID).
 		JoinClause(sqChild.Prefix("LEFT JOIN (").Suffix(") AS s ON f.path = s.path")).
@@ -191,5 +204,14 @@ func sqDiffFromParentV(parentID, childID int64, lastChildMergeWithParent CommitI
 		Column(sq.Alias(sq.Case().
 			When("entry_in_child AND NOT (DifferenceTypeRemoved OR DifferenceTypeConflict)", "entry_ctid").
 			Else("NULL"), "entry_ctid")).
+		// the difference of a conflict when the source version is taken
+		Column(sq.Alias(sq.Case().When("DifferenceTypeRemoved", "1").
+			When("DifferenceTypeChanged", "2").
+			Else("0"), "source_diff_type")).
+		Column(sq.Alias(sq.Case().
+			When("entry_in_child AND NOT DifferenceTypeRemoved", "entry_ctid").
+			Else("NULL"), "source_entry_ctid")).
+		Column("dest_uncommitted").
+		Column("dest_deleted").
 		FromSelect(RemoveNonRelevantQ, "t1")
 }
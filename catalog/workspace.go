@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const WorkspaceExportVersion = 1
+
+var ErrWorkspaceVersion = errors.New("unsupported workspace export version")
+
+// workspaceHeader is the first line of an exported workspace
+type workspaceHeader struct {
+	Version          int    `json:"version"`
+	Repository       string `json:"repository"`
+	Branch           string `json:"branch"`
+	StorageNamespace string `json:"storage_namespace"`
+}
+
+// workspaceRecord is a line per uncommitted entry in an exported workspace
+type workspaceRecord struct {
+	Path            string    `json:"path"`
+	PhysicalAddress string    `json:"physical_address,omitempty"`
+	CreationDate    time.Time `json:"creation_date"`
+	Size            int64     `json:"size"`
+	Checksum        string    `json:"checksum,omitempty"`
+	ContentType     string    `json:"content_type,omitempty"`
+	Metadata        Metadata  `json:"metadata,omitempty"`
+	Tombstone       bool      `json:"tombstone,omitempty"`
+}
+
+// ExportWorkspace writes the uncommitted entries of branch to w as JSON lines.  The export holds
+// metadata only - objects data stays in the repository storage namespace.
+func ExportWorkspace(ctx context.Context, c Cataloger, repository, branch string, w io.Writer) error {
+	repo, err := c.GetRepository(ctx, repository)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	err = enc.Encode(workspaceHeader{
+		Version:          WorkspaceExportVersion,
+		Repository:       repository,
+		Branch:           branch,
+		StorageNamespace: repo.StorageNamespace,
+	})
+	if err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	after := ""
+	for {
+		entries, hasMore, err := c.ListWorkspace(ctx, repository, branch, -1, after)
+		if err != nil {
+			return err
+		}
+		for _, ent := range entries {
+			err := enc.Encode(workspaceRecord{
+				Path:            ent.Path,
+				PhysicalAddress: ent.PhysicalAddress,
+				CreationDate:    ent.CreationDate,
+				Size:            ent.Size,
+				Checksum:        ent.Checksum,
+				ContentType:     ent.ContentType,
+				Metadata:        ent.Metadata,
+				Tombstone:       ent.Tombstone,
+			})
+			if err != nil {
+				return fmt.Errorf("write entry %s: %w", ent.Path, err)
+			}
+		}
+		if !hasMore || len(entries) == 0 {
+			return nil
+		}
+		after = entries[len(entries)-1].Path
+	}
+}
+
+// ImportWorkspace applies a workspace written by ExportWorkspace as uncommitted changes on
+// branch.  The workspace is read in full, and then applied in a single transaction: either all
+// of its changes are made, or none.  The workspace is supplied by the caller, so it is imported
+// only into a repository on the storage namespace it was exported from, and every physical
+// address must resolve inside that namespace.
+func ImportWorkspace(ctx context.Context, c Cataloger, repository, branch string, r io.Reader) error {
+	repo, err := c.GetRepository(ctx, repository)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bufio.NewReader(r))
+	var header workspaceHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("%w: read header: %s", ErrInvalidValue, err)
+	}
+	if header.Version != WorkspaceExportVersion {
+		return fmt.Errorf("%w: %d", ErrWorkspaceVersion, header.Version)
+	}
+	// relative addresses resolve to the storage namespace of the repository, where the objects of
+	// a workspace exported from another namespace are not
+	if header.StorageNamespace != repo.StorageNamespace {
+		return fmt.Errorf("%w: workspace exported from storage namespace %s, repository %s uses %s",
+			ErrOperationNotPermitted, header.StorageNamespace, repository, repo.StorageNamespace)
+	}
+	var records []*workspaceRecord
+	for {
+		var rec workspaceRecord
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: read entry: %s", ErrInvalidValue, err)
+		}
+		records = append(records, &rec)
+	}
+	return c.Transact(ctx, repository, []string{branch}, func(tx RepositoryTx) error {
+		for _, rec := range records {
+			if rec.Tombstone {
+				err := tx.DeleteEntry(branch, rec.Path)
+				if err != nil && !errors.Is(err, ErrEntryNotFound) {
+					return fmt.Errorf("delete entry %s: %w", rec.Path, err)
+				}
+				continue
+			}
+			err := tx.CreateEntry(branch, Entry{
+				Path:            rec.Path,
+				PhysicalAddress: rec.PhysicalAddress,
+				CreationDate:    rec.CreationDate,
+				Size:            rec.Size,
+				Checksum:        rec.Checksum,
+				ContentType:     rec.ContentType,
+				Metadata:        rec.Metadata,
+			})
+			if err != nil {
+				return fmt.Errorf("create entry %s: %w", rec.Path, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package catalog
+
+import (
+	"fmt"
+	"time"
+)
+
+const workspaceExpiryPolicyConfigKey = "workspaceExpiryPolicy"
+
+// WorkspaceExpiryPolicy selects the uncommitted entries that ExpireWorkspace discards: entries
+// staged more than OlderThanDays ago and never committed.
+type WorkspaceExpiryPolicy struct {
+	OlderThanDays int    `json:"older_than_days"`
+	Description   string `json:"description,omitempty"`
+}
+
+// WorkspaceExpiryPolicyWithCreationTime is a stored WorkspaceExpiryPolicy
+type WorkspaceExpiryPolicyWithCreationTime struct {
+	WorkspaceExpiryPolicy
+	CreatedAt time.Time
+}
+
+// ExpiredWorkspace counts the uncommitted entries of a branch discarded (or, on a dry run, that
+// would be discarded) by ExpireWorkspace.  Bytes is the size of discarded objects - uncommitted
+// deletions are counted as entries, but not in Bytes.
+type ExpiredWorkspace struct {
+	Branch  string `db:"branch"`
+	Entries int    `db:"entries"`
+	Bytes   int64  `db:"bytes"`
+}
+
+// Validate checks the policy has a positive age
+func (p *WorkspaceExpiryPolicy) Validate() error {
+	if p.OlderThanDays < 1 {
+		return fmt.Errorf("%w: older_than_days", ErrInvalidValue)
+	}
+	return nil
+}
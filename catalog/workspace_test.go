@@ -0,0 +1,99 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// testWorkspaceRepo creates a repository on a storage namespace of its own, named after it
+func testWorkspaceRepo(t *testing.T, ctx context.Context, c Cataloger, prefix string) string {
+	t.Helper()
+	repository := prefix + "-" + testCatalogerUniqueID()
+	testutil.MustDo(t, "create repository "+repository, c.CreateRepository(ctx, repository, "s3://"+repository, "master"))
+	return repository
+}
+
+func TestExportImportWorkspace(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	// source workspace - one committed file deleted, one file added
+	source := testWorkspaceRepo(t, ctx, c, "source")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, source, "master", "commit", "tester", nil)
+	testutil.MustDo(t, "commit source", err)
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, source, "master", "file1"))
+	file2Address := testCreateEntryCalcChecksum("file2", "")
+	testutil.MustDo(t, "create file2", c.CreateEntry(ctx, source, "master", Entry{
+		Path:            "file2",
+		PhysicalAddress: file2Address,
+		Checksum:        file2Address,
+		ContentType:     "application/json",
+		Metadata:        Metadata{"k": "v"},
+	}, CreateEntryParams{}))
+
+	var buf bytes.Buffer
+	testutil.MustDo(t, "export workspace", ExportWorkspace(ctx, c, source, "master", &buf))
+	exported := buf.Bytes()
+
+	// a branch of the source repository with the same committed data
+	testCatalogerBranch(t, ctx, c, source, "import", "master")
+	testutil.MustDo(t, "import workspace", ImportWorkspace(ctx, c, source, "import", bytes.NewReader(exported)))
+	testCatalogerGetEntry(t, ctx, c, source, "import", "file1", false)
+	ent, err := c.GetEntry(ctx, source, "import", "file2", GetEntryParams{})
+	testutil.MustDo(t, "get imported entry", err)
+	if ent.PhysicalAddress != file2Address {
+		t.Errorf("imported entry address = %s, expected %s", ent.PhysicalAddress, file2Address)
+	}
+	if ent.ContentType != "application/json" {
+		t.Errorf("imported entry content type = %s, expected application/json", ent.ContentType)
+	}
+	if ent.Metadata["k"] != "v" {
+		t.Errorf("imported entry metadata = %s, expected k=v", ent.Metadata)
+	}
+
+	// a repository on another storage namespace does not hold the objects of the workspace
+	target := testWorkspaceRepo(t, ctx, c, "target")
+	err = ImportWorkspace(ctx, c, target, "master", bytes.NewReader(exported))
+	if !errors.Is(err, ErrOperationNotPermitted) {
+		t.Errorf("ImportWorkspace() to another storage namespace err=%v, expected %s", err, ErrOperationNotPermitted)
+	}
+
+	// addresses outside the storage namespace are rejected, and nothing is imported
+	testCatalogerBranch(t, ctx, c, source, "outside", "master")
+	outside := bytes.Replace(exported, []byte(`"physical_address":"`+file2Address+`"`),
+		[]byte(`"physical_address":"s3://`+target+`/`+file2Address+`"`), 1)
+	err = ImportWorkspace(ctx, c, source, "outside", bytes.NewReader(outside))
+	if !errors.Is(err, ErrAddressOutsideNamespace) {
+		t.Errorf("ImportWorkspace() of an address outside the namespace err=%v, expected %s", err, ErrAddressOutsideNamespace)
+	}
+	testCatalogerGetEntry(t, ctx, c, source, "outside", "file1", true)
+	testCatalogerGetEntry(t, ctx, c, source, "outside", "file2", false)
+}
+
+func TestImportWorkspace_Atomic(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+
+	source := testWorkspaceRepo(t, ctx, c, "source")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, source, "master", "prod/file2", nil, "")
+	var buf bytes.Buffer
+	testutil.MustDo(t, "export workspace", ExportWorkspace(ctx, c, source, "master", &buf))
+
+	// the second entry cannot be written to the target, so neither is imported
+	target := testWorkspaceRepo(t, ctx, c, "target")
+	testutil.MustDo(t, "set protected paths", c.SetProtectedPaths(ctx, target, []*ProtectedPathRule{{Pattern: "prod/**"}}))
+	err := ImportWorkspace(ctx, c, target, "master", &buf)
+	if !errors.Is(err, ErrPathProtected) {
+		t.Fatalf("ImportWorkspace() err=%v, expected %s", err, ErrPathProtected)
+	}
+	testCatalogerGetEntry(t, ctx, c, target, "master", "file1", false)
+	testCatalogerGetEntry(t, ctx, c, target, "master", "prod/file2", false)
+}
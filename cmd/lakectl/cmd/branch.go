@@ -2,15 +2,21 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
 
+const branchCopyCmdArgs = 2
+
 // branchCmd represents the branch command
 var branchCmd = &cobra.Command{
 	Use:   "branch",
@@ -83,7 +89,7 @@ var branchCreateCmd = &cobra.Command{
 			DieFmt("failed to parse source URI: %s", err)
 		}
 		if sourceURI.Repository != u.Repository {
-			Die("source branch must be in the same repository", 1)
+			Die("source reference must be in the same repository", 1)
 		}
 
 		_, err = client.CreateBranch(context.Background(), u.Repository, &models.BranchCreation{
@@ -112,22 +118,25 @@ var branchDeleteCmd = &cobra.Command{
 		}
 		client := getClient()
 		u := uri.Must(uri.Parse(args[0]))
-		err = client.DeleteBranch(context.Background(), u.Repository, u.Ref)
+		ref, err := client.DeleteBranch(context.Background(), u.Repository, u.Ref)
 		if err != nil {
 			DieErr(err)
 		}
+		Fmt("deleted branch '%s', re-create it from '%s'\n", u.Ref, ref)
 	},
 }
 
-// lakectl branch revert lakefs://myrepo@master --commit commitId --prefix path --object path
+// lakectl branch revert lakefs://myrepo@master --commit commitId --prefix path --path path --object path
 var branchRevertCmd = &cobra.Command{
 	Use:   "revert <branch uri> [flags]",
 	Short: "revert changes to specified commit, or revert uncommitted changes - all changes, or by path",
-	Long: `revert changes.  There are four different ways to revert changes:
+	Long: `revert changes.  There are six different ways to revert changes:
   1. revert to previous commit, set HEAD of branch to given commit - revert lakefs://myrepo@master --commit commitId
-  2. revert all uncommitted changes (reset) - revert lakefs://myrepo@master 
-  3. revert uncommitted changes under specific path -	revert lakefs://myrepo@master --prefix path
-  4. revert uncommitted changes for specific object - revert lakefs://myrepo@master --object path`,
+  2. revert an object or a directory to its state at a commit - revert lakefs://myrepo@master --commit commitId --path path
+  3. revert all uncommitted changes (reset) - revert lakefs://myrepo@master 
+  4. revert uncommitted changes under specific path -	revert lakefs://myrepo@master --prefix path
+  5. revert uncommitted changes to an object or a directory (unstage) - revert lakefs://myrepo@master --path path
+  6. revert uncommitted changes for specific object - revert lakefs://myrepo@master --object path`,
 	Args: cmdutils.ValidationChain(
 		cobra.ExactArgs(1),
 		cmdutils.FuncValidator(0, uri.ValidateRefURI),
@@ -144,6 +153,10 @@ var branchRevertCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		path, err := cmd.Flags().GetString("path")
+		if err != nil {
+			DieErr(err)
+		}
 		object, err := cmd.Flags().GetString("object")
 		if err != nil {
 			DieErr(err)
@@ -152,6 +165,13 @@ var branchRevertCmd = &cobra.Command{
 		var revert models.RevertCreation
 		var confirmationMsg string
 		switch {
+		case len(commitID) > 0 && len(path) > 0:
+			confirmationMsg = fmt.Sprintf("Are you sure you want to revert path: %s to commit: %s", path, commitID)
+			revert = models.RevertCreation{
+				Commit: commitID,
+				Path:   path,
+				Type:   swag.String(models.RevertCreationTypeCommitPath),
+			}
 		case len(commitID) > 0:
 			confirmationMsg = fmt.Sprintf("Are you sure you want to revert all changes to commit: %s", commitID)
 			revert = models.RevertCreation{
@@ -164,6 +184,12 @@ var branchRevertCmd = &cobra.Command{
 				Path: prefix,
 				Type: swag.String(models.RevertCreationTypeCommonPrefix),
 			}
+		case len(path) > 0:
+			confirmationMsg = fmt.Sprintf("Are you sure you want to revert all changes to path: %s to last commit", path)
+			revert = models.RevertCreation{
+				Path: path,
+				Type: swag.String(models.RevertCreationTypePath),
+			}
 		case len(object) > 0:
 			confirmationMsg = fmt.Sprintf("Are you sure you want to revert all changes for object: %s to last commit", object)
 			revert = models.RevertCreation{
@@ -189,6 +215,75 @@ var branchRevertCmd = &cobra.Command{
 	},
 }
 
+// lakectl branch copy lakefs://source-repo@master/path lakefs://myrepo@master --to path
+var branchCopyCmd = &cobra.Command{
+	Use:   "copy <source ref or path uri> <branch uri>",
+	Short: "copy a path, or cherry-pick the changes of a commit, from a repository to a branch",
+	Long: `copy objects from a repository, possibly another one, as uncommitted changes on a branch.  Objects are not
+copied, the branch shares them with the source.
+  1. copy an object or a directory - copy lakefs://source-repo@master/path lakefs://myrepo@master [--to path]
+  2. apply the changes of a commit - copy lakefs://source-repo@commitId lakefs://myrepo@master`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(branchCopyCmdArgs),
+		cmdutils.FuncValidator(1, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		source, err := uri.Parse(args[0])
+		if err != nil || source.Ref == "" {
+			DieFmt("invalid source uri %s: expected a ref or path uri\n", args[0])
+		}
+		u := uri.Must(uri.Parse(args[1]))
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			DieErr(err)
+		}
+		clt := getClient()
+		changes, err := clt.CopyToBranch(context.Background(), u.Repository, u.Ref, &models.CopyCreation{
+			SourceRepository: swag.String(source.Repository),
+			SourceRef:        swag.String(source.Ref),
+			SourcePath:       source.Path,
+			DestinationPath:  to,
+		})
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("%d changes staged on %s\n", changes, u.String())
+	},
+}
+
+var branchRebaseCmd = &cobra.Command{
+	Use:   "rebase <branch uri>",
+	Short: "replay the commits of a branch since it last merged from its parent on top of the parent head",
+	Long: `replay the commits of a branch since it last merged from its parent on top of the parent head.  The replayed
+commits replace the original ones, so the branch must have no uncommitted changes and its commits must not be
+referenced by other branches, merges or tags.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		onto, _ := cmd.Flags().GetString("onto")
+		clt := getClient()
+		result, err := clt.RebaseBranch(context.Background(), u.Repository, u.Ref, onto)
+		if errors.Is(err, catalog.ErrConflictFound) {
+			_, _ = fmt.Printf("Conflicts: %d\n", len(result.Conflicts))
+			for _, conflict := range result.Conflicts {
+				_, _ = fmt.Printf("  %s %s: %s\n", swag.StringValue(conflict.Reference),
+					swag.StringValue(conflict.Type), swag.StringValue(conflict.Path))
+			}
+			Die("rebase has conflicts", 1)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		for _, commit := range result.Commits {
+			Fmt("%s\n", commit)
+		}
+		Fmt("rebased branch '%s' onto '%s' at %s\n", u.Ref, onto, result.Reference)
+	},
+}
+
 var branchShowCmd = &cobra.Command{
 	Use:   "show <branch uri>",
 	Short: "show branch latest commit reference",
@@ -199,11 +294,68 @@ var branchShowCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		u := uri.Must(uri.Parse(args[0]))
-		ref, err := client.GetBranch(context.Background(), u.Repository, u.Ref)
+		ref, version, err := client.GetBranch(context.Background(), u.Repository, u.Ref)
 		if err != nil {
 			DieErr(err)
 		}
 		Fmt("%s\n", ref)
+		if withVersion, _ := cmd.Flags().GetBool("with-version"); withVersion {
+			Fmt("version: %d\n", version)
+		}
+	},
+}
+
+var branchWorkspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "export and import the uncommitted changes of a branch",
+}
+
+var branchWorkspaceExportCmd = &cobra.Command{
+	Use:   "export <branch uri>",
+	Short: "write the uncommitted changes of a branch to stdout, to import on another branch",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := uri.Must(uri.Parse(args[0]))
+		err := client.ExportWorkspace(context.Background(), u.Repository, u.Ref, os.Stdout)
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var branchWorkspaceImportCmd = &cobra.Command{
+	Use:   "import <branch uri>",
+	Short: "apply exported uncommitted changes on a branch, all of them or none",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := uri.Must(uri.Parse(args[0]))
+		source, _ := cmd.Flags().GetString("source")
+		var fp io.Reader
+		if source == "-" {
+			fp = os.Stdin
+		} else {
+			file, err := os.Open(source)
+			if err != nil {
+				DieErr(err)
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+			fp = file
+		}
+		err := client.ImportWorkspace(context.Background(), u.Repository, u.Ref, fp)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("imported workspace to branch '%s'\n", u.Ref)
 	},
 }
 
@@ -215,14 +367,29 @@ func init() {
 	branchCmd.AddCommand(branchListCmd)
 	branchCmd.AddCommand(branchShowCmd)
 	branchCmd.AddCommand(branchRevertCmd)
+	branchCmd.AddCommand(branchCopyCmd)
+	branchCmd.AddCommand(branchRebaseCmd)
+	branchCmd.AddCommand(branchWorkspaceCmd)
+	branchWorkspaceCmd.AddCommand(branchWorkspaceExportCmd)
+	branchWorkspaceCmd.AddCommand(branchWorkspaceImportCmd)
 
 	branchListCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
 	branchListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
 
-	branchCreateCmd.Flags().StringP("source", "s", "", "source branch uri")
+	branchCreateCmd.Flags().StringP("source", "s", "", "source branch or commit uri")
 	_ = branchCreateCmd.MarkFlagRequired("source")
 
+	branchCopyCmd.Flags().String("to", "", "path to copy the source path to (default the source path)")
+
+	branchShowCmd.Flags().Bool("with-version", false, "also show the branch version, to pass as the expected version of a commit or merge")
+
+	branchRebaseCmd.Flags().String("onto", "", "parent branch to replay the commits onto")
+	_ = branchRebaseCmd.MarkFlagRequired("onto")
+
+	branchWorkspaceImportCmd.Flags().StringP("source", "s", "-", "workspace export file to import, or - for stdin")
+
 	branchRevertCmd.Flags().String("commit", "", "commit ID to revert branch to")
 	branchRevertCmd.Flags().String("prefix", "", "prefix of the objects to be reverted")
+	branchRevertCmd.Flags().String("path", "", "path of the object or directory to be reverted")
 	branchRevertCmd.Flags().String("object", "", "path to object to be reverted")
 }
@@ -40,11 +40,12 @@ var commitCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		expectedVersion, _ := cmd.Flags().GetInt64("expected-version")
 		branchURI := uri.Must(uri.Parse(args[0]))
 
 		// do commit
 		client := getClient()
-		commit, err := client.Commit(context.Background(), branchURI.Repository, branchURI.Ref, message, kvPairs)
+		commit, err := client.Commit(context.Background(), branchURI.Repository, branchURI.Ref, message, kvPairs, expectedVersion)
 		if err != nil {
 			DieErr(err)
 		}
@@ -81,4 +82,5 @@ func init() {
 	_ = commitCmd.MarkFlagRequired("message")
 
 	commitCmd.Flags().StringSlice("meta", []string{}, "key value pair in the form of key=value")
+	commitCmd.Flags().Int64("expected-version", 0, "fail unless the branch is at this version, as shown by branch show --with-version")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/go-openapi/swag"
@@ -22,7 +23,7 @@ const (
 var diffCmd = &cobra.Command{
 	Use:   "diff <ref uri> [other ref uri]",
 	Short: "diff between commits/hashes",
-	Long:  "see the list of paths added/changed/removed in a branch or between two references (could be either commit hash or branch name)",
+	Long:  "see the list of paths added/changed/removed between two references (could be either commit hash or branch name), or, given a single branch, its uncommitted changes - what a commit would contain",
 	Args: cmdutils.ValidationChain(
 		cobra.RangeArgs(diffCmdMinArgs, diffCmdMaxArgs),
 		cmdutils.FuncValidator(0, uri.ValidateRefURI),
@@ -103,15 +104,20 @@ func FmtDiff(diff *models.Diff, withDirection bool) {
 	default:
 	}
 
+	var sizeDelta string
+	if diff.SizeDelta != 0 {
+		sizeDelta = fmt.Sprintf(" (%+d B)", diff.SizeDelta)
+	}
+
 	if !withDirection {
 		_, _ = os.Stdout.WriteString(
-			color.Sprintf("%s %s\n", action, diff.Path),
+			color.Sprintf("%s %s%s\n", action, diff.Path, sizeDelta),
 		)
 		return
 	}
 
 	_, _ = os.Stdout.WriteString(
-		color.Sprintf("%s %s\n", action, diff.Path),
+		color.Sprintf("%s %s%s\n", action, diff.Path, sizeDelta),
 	)
 }
 
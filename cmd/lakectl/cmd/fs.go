@@ -6,7 +6,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
@@ -16,7 +18,9 @@ Modified Time: {{.Mtime|date}}
 Size: {{ .SizeBytes }} bytes
 Human Size: {{ .SizeBytes|human_bytes }}
 Checksum: {{.Checksum}}
-`
+Physical Address: {{.PhysicalAddress}}
+{{ if .Commit }}Commit: {{.Commit}}
+{{ end }}`
 
 var fsStatCmd = &cobra.Command{
 	Use:   "stat <path uri>",
@@ -37,6 +41,37 @@ var fsStatCmd = &cobra.Command{
 	},
 }
 
+const fsBlameTemplate = `Path: {{.Path | yellow }}
+Commit: {{.Commit.ID|yellow}}
+Author: {{.Commit.Committer}}
+Date: {{.Commit.CreationDate|date}}
+	{{.Commit.Message}}
+`
+
+var fsBlameCmd = &cobra.Command{
+	Use:   "blame <path uri>",
+	Short: "show the commit that introduced the current version of an object",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		commit, err := client.BlameObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(fsBlameTemplate, struct {
+			Path   string
+			Commit *models.Commit
+		}{
+			Path:   pathURI.Path,
+			Commit: commit,
+		})
+	},
+}
+
 const fsLsTemplate = `{{ range $val := . -}}
 {{ $val.PathType|ljust 6 }}    {{ $val.Mtime|date|ljust 29 }}    {{ $val.SizeBytes|human_bytes|ljust 12 }}    {{ $val.Path|yellow }}
 {{ end -}}
@@ -55,11 +90,19 @@ var fsListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		pathURI := uri.Must(uri.Parse(args[0]))
-		results, _, err := client.ListObjects(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, "", -1)
-		if err != nil {
-			DieErr(err)
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		var after string
+		for {
+			results, pagination, err := client.ListObjects(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, after, -1, recursive)
+			if err != nil {
+				DieErr(err)
+			}
+			Write(fsLsTemplate, results)
+			if pagination == nil || !swag.BoolValue(pagination.HasMore) {
+				break
+			}
+			after = pagination.NextOffset
 		}
-		Write(fsLsTemplate, results)
 	},
 }
 
@@ -117,7 +160,7 @@ var fsUploadCmd = &cobra.Command{
 
 var fsRmCmd = &cobra.Command{
 	Use:   "rm <path uri>",
-	Short: "delete object",
+	Short: "delete object, or all objects under the path with --recursive",
 	Args: cmdutils.ValidationChain(
 		cobra.ExactArgs(1),
 		cmdutils.FuncValidator(0, uri.ValidatePathURI),
@@ -125,9 +168,36 @@ var fsRmCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := uri.Must(uri.Parse(args[0]))
 		client := getClient()
-		err := client.DeleteObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
-		if err != nil {
-			DieErr(err)
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		if !recursive {
+			err := client.DeleteObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+			if err != nil {
+				DieErr(err)
+			}
+			return
+		}
+		// delete each page of objects found under the path in a single request - only under it as
+		// a directory, not objects that share its name as a prefix
+		prefix := pathURI.PathPrefix()
+		var after string
+		for {
+			results, pagination, err := client.ListObjects(context.Background(), pathURI.Repository, pathURI.Ref, prefix, after, -1, true)
+			if err != nil {
+				DieErr(err)
+			}
+			if len(results) > 0 {
+				paths := make([]string, len(results))
+				for i, obj := range results {
+					paths[i] = obj.Path
+				}
+				if err := client.DeleteObjects(context.Background(), pathURI.Repository, pathURI.Ref, paths); err != nil {
+					DieErr(err)
+				}
+			}
+			if pagination == nil || !swag.BoolValue(pagination.HasMore) {
+				break
+			}
+			after = pagination.NextOffset
 		}
 	},
 }
@@ -142,11 +212,16 @@ var fsCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(fsCmd)
 	fsCmd.AddCommand(fsStatCmd)
+	fsCmd.AddCommand(fsBlameCmd)
 	fsCmd.AddCommand(fsListCmd)
 	fsCmd.AddCommand(fsCatCmd)
 	fsCmd.AddCommand(fsUploadCmd)
 	fsCmd.AddCommand(fsRmCmd)
 
+	fsListCmd.Flags().Bool("recursive", false, "list all objects under the path")
+
+	fsRmCmd.Flags().Bool("recursive", false, "delete all objects under the path")
+
 	fsUploadCmd.Flags().StringP("source", "s", "", "local file to upload, or \"-\" for stdin")
 	_ = fsUploadCmd.MarkFlagRequired("source")
 }
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
@@ -44,9 +46,20 @@ var logCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		var filter catalog.ListCommitsOptions
+		filter.Prefix, err = cmd.Flags().GetString("prefix")
+		if err != nil {
+			DieErr(err)
+		}
+		filter.Committer, err = cmd.Flags().GetString("committer")
+		if err != nil {
+			DieErr(err)
+		}
+		filter.Since = parseLogTimeFlag(cmd, "since")
+		filter.Until = parseLogTimeFlag(cmd, "until")
 		client := getClient()
 		branchURI := uri.Must(uri.Parse(args[0]))
-		commits, pagination, err := client.GetCommitLog(context.Background(), branchURI.Repository, branchURI.Ref, after, amount)
+		commits, pagination, err := client.GetCommitLog(context.Background(), branchURI.Repository, branchURI.Ref, after, amount, filter)
 		ctx := struct {
 			Commits    []*models.Commit
 			Pagination *Pagination
@@ -67,9 +80,29 @@ var logCmd = &cobra.Command{
 	},
 }
 
+// parseLogTimeFlag returns the RFC3339 timestamp of flag, or zero time if it is not set
+func parseLogTimeFlag(cmd *cobra.Command, flag string) time.Time {
+	value, err := cmd.Flags().GetString(flag)
+	if err != nil {
+		DieErr(err)
+	}
+	if value == "" {
+		return time.Time{}
+	}
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		DieFmt("invalid --%s timestamp, expected RFC3339 format: %s", flag, err)
+	}
+	return ts
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(logCmd)
 	logCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
 	logCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	logCmd.Flags().String("prefix", "", "show only commits that changed objects under this path prefix")
+	logCmd.Flags().String("committer", "", "show only commits by this committer")
+	logCmd.Flags().String("since", "", "show only commits created at or after this RFC3339 timestamp")
+	logCmd.Flags().String("until", "", "show only commits created before this RFC3339 timestamp")
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
@@ -35,10 +37,23 @@ var mergeCmd = &cobra.Command{
 			Die("both references must belong to the same repository", 1)
 		}
 
-		result, err := client.Merge(context.Background(), leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+		squash, _ := cmd.Flags().GetBool("squash")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		expectedVersion, _ := cmd.Flags().GetInt64("expected-version")
+		result, err := client.Merge(context.Background(), leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, &models.Merge{
+			Squash:                     squash,
+			Strategy:                   strategy,
+			DryRun:                     dryRun,
+			ExpectedDestinationVersion: expectedVersion,
+		})
 		if errors.Is(err, catalog.ErrConflictFound) {
 			_, _ = fmt.Printf("Conflicts: %d\n", result.Summary.Conflict)
-			return
+			for _, conflict := range result.Conflicts {
+				_, _ = fmt.Printf("  %s: %s\n", swag.StringValue(conflict.Type), swag.StringValue(conflict.Path))
+			}
+			// exit with an error so scripts can gate on a clean merge
+			Die("merge has conflicts", 1)
 		}
 		if err != nil {
 			DieErr(err)
@@ -50,4 +65,8 @@ var mergeCmd = &cobra.Command{
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().Bool("squash", false, "merge a child branch into its parent as a single commit")
+	mergeCmd.Flags().Bool("dry-run", false, "show the changes and conflicts of the merge without merging")
+	mergeCmd.Flags().Int64("expected-version", 0, "fail unless the destination branch is at this version, as shown by branch show --with-version")
+	mergeCmd.Flags().String("strategy", "", "resolve conflicts by taking the source or destination version (source-wins, dest-wins)")
 }
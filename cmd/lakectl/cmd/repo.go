@@ -16,6 +16,7 @@ import (
 const (
 	DefaultBranch     = "master"
 	repoCreateCmdArgs = 2
+	repoForkCmdArgs   = 2
 	setPolicyCmdArgs  = 2
 )
 
@@ -106,6 +107,29 @@ var repoCreateCmd = &cobra.Command{
 	},
 }
 
+// repoForkCmd represents the fork repo command
+// lakectl repo fork lakefs://myrepo lakefs://myfork
+var repoForkCmd = &cobra.Command{
+	Use:   "fork <source repository uri> <repository uri>",
+	Short: "create a new repository as a fork of an existing one, sharing its objects",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(repoForkCmdArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+		cmdutils.FuncValidator(1, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		clt := getClient()
+		source := uri.Must(uri.Parse(args[0]))
+		u := uri.Must(uri.Parse(args[1]))
+		repo, err := clt.ForkRepository(context.Background(), source.Repository, u.Repository)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("Repository '%s' forked from '%s':\nstorage namespace: %s\ndefault branch: %s\ntimestamp: %d\n",
+			repo.ID, source.Repository, repo.StorageNamespace, repo.DefaultBranch, repo.CreationDate)
+	},
+}
+
 // repoDeleteCmd represents the delete repo command
 // lakectl delete lakefs://myrepo
 var repoDeleteCmd = &cobra.Command{
@@ -188,6 +212,7 @@ func init() {
 	rootCmd.AddCommand(repoCmd)
 	repoCmd.AddCommand(repoListCmd)
 	repoCmd.AddCommand(repoCreateCmd)
+	repoCmd.AddCommand(repoForkCmd)
 	repoCmd.AddCommand(repoDeleteCmd)
 	repoCmd.AddCommand(retentionCmd)
 
@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
@@ -40,6 +42,14 @@ var showCmd = &cobra.Command{
 
 		switch showType {
 		case "commit":
+			at, _ := cmd.Flags().GetString("at")
+			if at != "" {
+				// show the commit of the branch at the given time
+				if _, err := time.Parse(time.RFC3339, at); err != nil {
+					DieFmt("invalid --at timestamp, expected RFC3339 format: %s", err)
+				}
+				identifier = identifier + catalog.TimestampRefPrefix + at + catalog.TimestampRefSuffix
+			}
 			client := getClient()
 			commit, err := client.GetCommit(context.Background(), u.Repository, identifier)
 			if err != nil {
@@ -61,4 +71,5 @@ func init() {
 	rootCmd.AddCommand(showCmd)
 
 	showCmd.Flags().String("commit", "c", "commit id to show")
+	showCmd.Flags().String("at", "", "with a branch name as --commit, show the last commit on the branch at or before this RFC3339 timestamp")
 }
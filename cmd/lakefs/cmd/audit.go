@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/audit"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const auditListBatchSize = 1000
+
+// auditCmd implements the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List the audit log of changes made through the API and S3 gateway",
+	Long: `List audit events, oldest first. Each event records the user, the operation, the repository, reference and path it addressed,
+and the resulting HTTP status code. Time range bounds use RFC 3339 format (e.g. 2020-10-01T00:00:00Z).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		actor, _ := cmd.Flags().GetString("actor")
+		repository, _ := cmd.Flags().GetString("repository")
+		amount, _ := cmd.Flags().GetInt("amount")
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		params := audit.ListParams{
+			Actor:      actor,
+			Repository: repository,
+		}
+		var err error
+		if params.From, err = parseAuditTime(from); err != nil {
+			logger.WithError(err).Fatal("invalid --from")
+		}
+		if params.To, err = parseAuditTime(to); err != nil {
+			logger.WithError(err).Fatal("invalid --to")
+		}
+
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		auditService := audit.NewDBService(dbPool)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tSERVICE\tACTOR\tACTION\tMETHOD\tREPOSITORY\tREF\tPATH\tSTATUS")
+		var after int64
+		listed := 0
+		for amount < 0 || listed < amount {
+			limit := auditListBatchSize
+			if amount >= 0 && amount-listed < limit {
+				limit = amount - listed
+			}
+			events, hasMore, err := auditService.List(ctx, params, limit, after)
+			if err != nil {
+				logger.WithError(err).Fatal("failed to list audit events")
+			}
+			for _, e := range events {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+					e.CreatedAt.Format(time.RFC3339), e.Service, e.Actor, e.Action, e.Method, e.Repository, e.Ref, e.Path, e.StatusCode)
+			}
+			listed += len(events)
+			if !hasMore || len(events) == 0 {
+				break
+			}
+			after = events[len(events)-1].ID
+		}
+		_ = w.Flush()
+	},
+}
+
+func parseAuditTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.Flags().String("from", "", "list events created at or after this time")
+	auditCmd.Flags().String("to", "", "list events created before this time")
+	auditCmd.Flags().String("actor", "", "list events of this user only")
+	auditCmd.Flags().String("repository", "", "list events of this repository only")
+	auditCmd.Flags().Int("amount", 100, "maximum number of events to list, -1 for all")
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/factory"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	DefaultCleanupOlderThan        = 7 * 24 * time.Hour
+	DefaultCleanupEventsOlderThan  = 30 * 24 * time.Hour
+	DefaultCleanupObjectsOlderThan = 7 * 24 * time.Hour
+	cleanupListBatchSize           = 1000
+)
+
+// cleanupCmd implements the cleanup command
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Abort multipart uploads that were never completed, remove unreferenced objects and old repository events",
+	Long: `Abort multipart uploads older than the given age that were never completed or aborted by their client.
+Abandoned uploads keep their parts on the underlying storage; run this command periodically (e.g. from cron) to reclaim them.
+The command also scans the storage namespace of each repository and removes the objects last modified before
+--objects-older-than that no entry, of any branch or commit, and no multipart upload points to - objects of writes that
+never reached the catalog. Scanning requires a blockstore that lists objects (S3); other repositories are skipped.
+Finally it removes repository change feed events older than --events-older-than.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		eventsOlderThan, _ := cmd.Flags().GetDuration("events-older-than")
+		objectsOlderThan, _ := cmd.Flags().GetDuration("objects-older-than")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+		blockStore, err := factory.BuildBlockAdapter(cfg)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create block adapter")
+		}
+
+		createdBefore := time.Now().Add(-olderThan)
+		eventsCreatedBefore := time.Now().Add(-eventsOlderThan)
+		objectsModifiedBefore := time.Now().Add(-objectsOlderThan)
+		numFailures := 0
+		after := ""
+		for {
+			repos, hasMore, err := cataloger.ListRepositories(ctx, catalog.ListRepositoriesParams{}, cleanupListBatchSize, after)
+			if err != nil {
+				logger.WithError(err).Fatal("cannot list repositories")
+			}
+			for _, repo := range repos {
+				repoLogger := logger.WithFields(logging.Fields{
+					"repository": repo.Name,
+					"storage":    repo.StorageNamespace,
+				})
+				aborted, err := cleanupMultipartUploads(ctx, cataloger, blockStore, repo, createdBefore, dryRun)
+				if err != nil {
+					repoLogger.WithError(err).Error("failed to clean up multipart uploads")
+					numFailures++
+				}
+				repoLogger.WithFields(logging.Fields{"aborted": aborted, "dry_run": dryRun}).Info("multipart uploads cleanup")
+				if objectsOlderThan != 0 {
+					removed, err := cleanupUnreferencedObjects(ctx, cataloger, blockStore, repo, objectsModifiedBefore, dryRun)
+					if err != nil {
+						repoLogger.WithError(err).Error("failed to clean up unreferenced objects")
+						numFailures++
+					}
+					repoLogger.WithFields(logging.Fields{"removed": removed, "dry_run": dryRun}).Info("unreferenced objects cleanup")
+				}
+				if dryRun || eventsOlderThan == 0 {
+					continue
+				}
+				deleted, err := cataloger.DeleteEvents(ctx, repo.Name, eventsCreatedBefore)
+				if err != nil {
+					repoLogger.WithError(err).Error("failed to remove old events")
+					numFailures++
+				}
+				repoLogger.WithField("deleted", deleted).Info("events cleanup")
+			}
+			if !hasMore || len(repos) == 0 {
+				break
+			}
+			after = repos[len(repos)-1].Name
+		}
+		if numFailures > 0 {
+			logger.Fatalf("Failed to clean up %d repositories; errors emitted above", numFailures)
+		}
+	},
+}
+
+// cleanupMultipartUploads aborts the repository multipart uploads created before createdBefore,
+// and returns the number of uploads aborted.
+func cleanupMultipartUploads(ctx context.Context, cataloger catalog.Cataloger, blockStore block.Adapter, repo *catalog.Repository, createdBefore time.Time, dryRun bool) (int, error) {
+	logger := logging.FromContext(ctx).WithField("repository", repo.Name)
+	aborted := 0
+	after := ""
+	for {
+		uploads, hasMore, err := cataloger.ListMultipartUploads(ctx, repo.Name, createdBefore, cleanupListBatchSize, after)
+		if err != nil {
+			return aborted, err
+		}
+		for _, upload := range uploads {
+			uploadLogger := logger.WithFields(logging.Fields{
+				"upload_id":     upload.UploadID,
+				"path":          upload.Path,
+				"creation_date": upload.CreationDate,
+			})
+			if dryRun {
+				uploadLogger.Info("would abort multipart upload")
+				aborted++
+				continue
+			}
+			err := blockStore.AbortMultiPartUpload(block.ObjectPointer{
+				StorageNamespace: repo.StorageNamespace,
+				Identifier:       upload.PhysicalAddress,
+			}, upload.UploadID)
+			if err != nil {
+				// the upload may already be gone from the storage, keep the record to retry later
+				uploadLogger.WithError(err).Warn("failed to abort multipart upload")
+				continue
+			}
+			if err := cataloger.DeleteMultipartUpload(ctx, repo.Name, upload.UploadID); err != nil {
+				return aborted, err
+			}
+			aborted++
+		}
+		if !hasMore || len(uploads) == 0 {
+			return aborted, nil
+		}
+		after = uploads[len(uploads)-1].UploadID
+	}
+}
+
+// cleanupUnreferencedObjects removes the objects in the storage namespace of repo last modified
+// before modifiedBefore that no catalog record points to, and returns the number of objects
+// removed.  Repositories on a blockstore that cannot list the namespace are skipped.
+func cleanupUnreferencedObjects(ctx context.Context, cataloger catalog.Cataloger, blockStore block.Adapter, repo *catalog.Repository, modifiedBefore time.Time, dryRun bool) (int, error) {
+	logger := logging.FromContext(ctx).WithField("repository", repo.Name)
+	inventory, err := blockStore.GenerateInventory(ctx, logger, repo.StorageNamespace, false)
+	if err != nil {
+		logger.WithError(err).Warn("cannot list storage namespace, skipping unreferenced objects")
+		return 0, nil
+	}
+	removed := 0
+	removeBatch := func(addresses []string) error {
+		unreferenced, err := cataloger.FindUnreferencedAddresses(ctx, addresses)
+		if err != nil {
+			return err
+		}
+		for _, address := range unreferenced {
+			objLogger := logger.WithField("address", address)
+			if dryRun {
+				objLogger.Info("would remove unreferenced object")
+				removed++
+				continue
+			}
+			err := blockStore.Remove(block.ObjectPointer{StorageNamespace: repo.StorageNamespace, Identifier: address})
+			if err != nil {
+				// keep removing the other objects, the next run retries this one
+				objLogger.WithError(err).Warn("failed to remove unreferenced object")
+				continue
+			}
+			removed++
+		}
+		return nil
+	}
+	it := inventory.Iterator()
+	batch := make([]string, 0, cleanupListBatchSize)
+	for it.Next() {
+		obj := it.Get()
+		// recent objects may belong to writes that did not reach the catalog yet
+		if !obj.LastModified.Before(modifiedBefore) {
+			continue
+		}
+		batch = append(batch, obj.PhysicalAddress)
+		if len(batch) == cleanupListBatchSize {
+			if err := removeBatch(batch); err != nil {
+				return removed, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := it.Err(); err != nil {
+		return removed, err
+	}
+	return removed, removeBatch(batch)
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+	cleanupCmd.Flags().Duration("older-than", DefaultCleanupOlderThan, "abort multipart uploads created before this duration")
+	cleanupCmd.Flags().Duration("events-older-than", DefaultCleanupEventsOlderThan, "remove repository events created before this duration, 0 keeps all events")
+	cleanupCmd.Flags().Duration("objects-older-than", DefaultCleanupObjectsOlderThan, "remove unreferenced objects last modified before this duration, 0 keeps all objects")
+	cleanupCmd.Flags().Bool("dry-run", false, "only log the multipart uploads that would be aborted and the objects that would be removed, and keep all events")
+}
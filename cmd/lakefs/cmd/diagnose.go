@@ -29,7 +29,7 @@ var diagnoseCmd = &cobra.Command{
 		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(conf.GetCatalogerCatalogParams()))
 
 		numFailures := 0
-		repos, _, err := cataloger.ListRepositories(ctx, -1, "")
+		repos, _, err := cataloger.ListRepositories(ctx, catalog.ListRepositoriesParams{}, -1, "")
 		if err != nil {
 			// Cannot advance last so fail everything
 			logger.WithField("error", err).Fatal("Failed to list repositories")
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// dumpCmd implements the dump command
+var dumpCmd = &cobra.Command{
+	Use:   "dump <repository>",
+	Short: "Write a backup of the committed metadata of a repository",
+	Long: `Write the branches, tags, commits and committed entries of repository to --output, or to the standard output.
+The backup ends with a digest that is verified by restore. Uncommitted changes, repository settings and objects are not
+included.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		repository := args[0]
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx).WithField("repository", repository)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+
+		var w io.Writer = os.Stdout
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create backup file")
+			}
+			defer func() {
+				if err := f.Close(); err != nil {
+					logger.WithError(err).Fatal("Failed to close backup file")
+				}
+			}()
+			w = f
+		}
+		if err := cataloger.DumpRepository(ctx, repository, w); err != nil {
+			logger.WithError(err).Fatal("Failed to dump repository")
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(dumpCmd)
+	dumpCmd.Flags().StringP("output", "o", "", "backup file to write (default standard output)")
+}
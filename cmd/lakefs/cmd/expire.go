@@ -21,7 +21,10 @@ import (
 var expireCmd = &cobra.Command{
 	Use:   "expire",
 	Short: "Apply configured retention policies to expire objects",
+	Long: `Apply configured retention policies to expire objects.  With --dry-run, only logs a report of
+the entries, objects and bytes each repository would reclaim, and the commits they belong to.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		ctx := context.Background()
 		conf := config.NewConfig()
 		logger := logging.FromContext(ctx)
@@ -30,10 +33,17 @@ var expireCmd = &cobra.Command{
 
 		awsRetentionConfig := config.NewConfig().GetAwsS3RetentionConfig()
 
-		repos, _, err := cataloger.ListRepositories(ctx, -1, "")
+		repos, _, err := cataloger.ListRepositories(ctx, catalog.ListRepositoriesParams{}, -1, "")
 		if err != nil {
 			logger.WithError(err).Fatal("cannot list repositories")
 		}
+		retentionService := retention.NewDBRetentionService(dbPool)
+		if dryRun {
+			if err := reportExpiry(ctx, cataloger, retentionService, repos); err != nil {
+				logger.WithError(err).Fatal("Expiry report failed")
+			}
+			return
+		}
 
 		// TODO(ariels: fail on failure!
 		awsCfg := cfg.GetAwsConfig()
@@ -64,8 +74,6 @@ var expireCmd = &cobra.Command{
 		s3Session.ClientConfig(s3.ServiceName)
 		s3Client := s3.New(s3Session)
 
-		retentionService := retention.NewDBRetentionService(dbPool)
-
 		// Expire by repositories.  No immediate technical reason, but administratively
 		// it is easier to understand separated logs, and safer to expire one repository
 		// at a time.
@@ -121,7 +129,48 @@ var expireCmd = &cobra.Command{
 	},
 }
 
+// reportExpiry logs what applying the retention policy of each repository would expire
+func reportExpiry(ctx context.Context, cataloger catalog.Cataloger, retentionService *retention.DBRetentionService, repos []*catalog.Repository) error {
+	for _, repo := range repos {
+		logger := logging.FromContext(ctx).WithFields(logging.Fields{
+			"repository": repo.Name,
+			"dry_run":    true,
+		})
+		policy, err := retentionService.GetPolicy(repo.Name)
+		if err != nil {
+			return fmt.Errorf("get retention policy of %s: %w", repo.Name, err)
+		}
+		if policy == nil {
+			logger.Info("no retention policy for this repository - skip")
+			continue
+		}
+		report, err := cataloger.GetExpiryReport(ctx, repo.Name, &policy.Policy)
+		if err != nil {
+			return fmt.Errorf("expiry report of %s: %w", repo.Name, err)
+		}
+		for _, commit := range report.Commits {
+			logger.WithFields(logging.Fields{
+				"branch":        commit.Branch,
+				"reference":     commit.Reference,
+				"message":       commit.Message,
+				"entries":       commit.Entries,
+				"logical_bytes": commit.LogicalBytes,
+			}).Info("Commit would expire entries")
+		}
+		logger.WithFields(logging.Fields{
+			"entries":              report.Entries,
+			"logical_bytes":        report.LogicalBytes,
+			"objects":              report.Objects,
+			"unreferenced_objects": report.UnreferencedObjects,
+			"physical_bytes":       report.PhysicalBytes,
+			"commits":              len(report.Commits),
+		}).Info("Expiry report")
+	}
+	return nil
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(expireCmd)
+	expireCmd.Flags().Bool("dry-run", false, "only log a report of what would expire")
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// expireBranchesCmd implements the expire-branches command
+var expireBranchesCmd = &cobra.Command{
+	Use:   "expire-branches [repository...]",
+	Short: "Delete stale branches selected by the branch expiry policy of each repository",
+	Long: `Delete the branches selected by the branch expiry policy of each repository: branches matching the policy name
+patterns whose last commit is older than the policy age, and that are not exempt. Expires branches of all repositories
+with a policy when none are given. With --dry-run, only logs the branches that would be deleted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+
+		if err := expireBranches(ctx, cataloger, args, dryRun); err != nil {
+			logger.WithError(err).Fatal("Failed to expire branches")
+		}
+	},
+}
+
+// expireBranches applies the branch expiry policy of each of repositories, or of all the
+// repositories that have a policy when repositories is empty.
+func expireBranches(ctx context.Context, cataloger catalog.Cataloger, repositories []string, dryRun bool) error {
+	explicit := len(repositories) > 0
+	if !explicit {
+		var err error
+		repositories, err = listRepositoryNames(ctx, cataloger)
+		if err != nil {
+			return err
+		}
+	}
+	var lastErr error
+	for _, repository := range repositories {
+		logger := logging.FromContext(ctx).WithFields(logging.Fields{
+			"repository": repository,
+			"dry_run":    dryRun,
+		})
+		expired, err := cataloger.ExpireBranches(ctx, repository, dryRun)
+		if errors.Is(err, catalog.ErrBranchExpiryPolicyNotFound) && !explicit {
+			continue
+		}
+		if err != nil {
+			// keep expiring the other repositories
+			logger.WithError(err).Error("Failed to expire branches")
+			lastErr = err
+		}
+		for _, branch := range expired {
+			logger.WithFields(logging.Fields{
+				"branch":      branch.Name,
+				"reference":   branch.CommitReference,
+				"commit_date": branch.CommitDate,
+			}).Info("Expired branch")
+		}
+	}
+	return lastErr
+}
+
+func listRepositoryNames(ctx context.Context, cataloger catalog.Cataloger) ([]string, error) {
+	repos, _, err := cataloger.ListRepositories(ctx, catalog.ListRepositoriesParams{}, -1, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+	}
+	return names, nil
+}
+
+// runEvery calls fn every interval until ctx is done
+func runEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(ctx)
+		}
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(expireBranchesCmd)
+	expireBranchesCmd.Flags().Bool("dry-run", false, "only log the branches that would be deleted")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// expireWorkspaceCmd implements the expire-workspace command
+var expireWorkspaceCmd = &cobra.Command{
+	Use:   "expire-workspace [repository...]",
+	Short: "Discard uncommitted changes older than the workspace expiry policy of each repository",
+	Long: `Discard the uncommitted changes of all branches that were staged before the age set by the workspace expiry
+policy of each repository. Expires the workspaces of all repositories with a policy when none are given. With --dry-run,
+only logs how many entries would be discarded on each branch.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+
+		if err := expireWorkspace(ctx, cataloger, args, dryRun); err != nil {
+			logger.WithError(err).Fatal("Failed to expire workspace")
+		}
+	},
+}
+
+// expireWorkspace applies the workspace expiry policy of each of repositories, or of all the
+// repositories that have a policy when repositories is empty.
+func expireWorkspace(ctx context.Context, cataloger catalog.Cataloger, repositories []string, dryRun bool) error {
+	explicit := len(repositories) > 0
+	if !explicit {
+		var err error
+		repositories, err = listRepositoryNames(ctx, cataloger)
+		if err != nil {
+			return err
+		}
+	}
+	var lastErr error
+	for _, repository := range repositories {
+		logger := logging.FromContext(ctx).WithFields(logging.Fields{
+			"repository": repository,
+			"dry_run":    dryRun,
+		})
+		expired, err := cataloger.ExpireWorkspace(ctx, repository, dryRun)
+		if errors.Is(err, catalog.ErrWorkspaceExpiryPolicyNotFound) && !explicit {
+			continue
+		}
+		if err != nil {
+			// keep expiring the other repositories
+			logger.WithError(err).Error("Failed to expire workspace")
+			lastErr = err
+		}
+		for _, workspace := range expired {
+			logger.WithFields(logging.Fields{
+				"branch":  workspace.Branch,
+				"entries": workspace.Entries,
+				"bytes":   workspace.Bytes,
+			}).Info("Expired uncommitted entries")
+		}
+	}
+	return lastErr
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(expireWorkspaceCmd)
+	expireWorkspaceCmd.Flags().Bool("dry-run", false, "only log the number of entries that would be discarded")
+}
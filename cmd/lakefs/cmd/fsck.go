@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/factory"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	fsckIssueMissingObject = "missing_object"
+	fsckListBatchSize      = 1000
+)
+
+type fsckIssue struct {
+	catalog.ConsistencyIssue
+	Path string
+}
+
+// fsckCmd implements the fsck command
+var fsckCmd = &cobra.Command{
+	Use:   "fsck [repository...]",
+	Short: "Check the consistency of repository metadata",
+	Long: `Check that the default branch, branch lineage, commit parents and committed entries of each repository reference
+existing branches and commits. With --objects, also check that the objects of every branch exist on the underlying storage.
+Checks all repositories when none are given. Issues are reported and not repaired; exits with an error if any are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkObjects, _ := cmd.Flags().GetBool("objects")
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+		var blockStore block.Adapter
+		if checkObjects {
+			var err error
+			blockStore, err = factory.BuildBlockAdapter(cfg)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create block adapter")
+			}
+		}
+
+		repositories := args
+		if len(repositories) == 0 {
+			repos, _, err := cataloger.ListRepositories(ctx, catalog.ListRepositoriesParams{}, -1, "")
+			if err != nil {
+				logger.WithError(err).Fatal("cannot list repositories")
+			}
+			for _, repo := range repos {
+				repositories = append(repositories, repo.Name)
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REPOSITORY\tISSUE\tBRANCH\tREFERENCE\tPATH")
+		numIssues := 0
+		for _, repository := range repositories {
+			issues, err := fsckRepository(ctx, cataloger, blockStore, repository)
+			if err != nil {
+				logger.WithError(err).WithField("repository", repository).Fatal("failed to check repository")
+			}
+			for _, issue := range issues {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", repository, issue.Type, issue.Branch, issue.Reference, issue.Path)
+			}
+			numIssues += len(issues)
+		}
+		_ = w.Flush()
+		if numIssues > 0 {
+			logger.Fatalf("Found %d issues", numIssues)
+		}
+	},
+}
+
+// fsckRepository returns the metadata issues of repository, and its missing objects if
+// blockStore is set
+func fsckRepository(ctx context.Context, cataloger catalog.Cataloger, blockStore block.Adapter, repository string) ([]*fsckIssue, error) {
+	consistencyIssues, err := cataloger.CheckRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]*fsckIssue, len(consistencyIssues))
+	for i, issue := range consistencyIssues {
+		issues[i] = &fsckIssue{ConsistencyIssue: *issue}
+	}
+	if blockStore == nil {
+		return issues, nil
+	}
+	repo, err := cataloger.GetRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	adapter := blockStore.WithContext(ctx)
+	checked := make(map[string]struct{})
+	after := ""
+	for {
+		branches, hasMore, err := cataloger.ListBranches(ctx, repository, "", fsckListBatchSize, after)
+		if err != nil {
+			return nil, fmt.Errorf("list branches: %w", err)
+		}
+		for _, branch := range branches {
+			it := catalog.NewEntryIterator(ctx, cataloger, repository, branch.Name, "", fsckListBatchSize)
+			for it.Next() {
+				entry := it.Value()
+				if entry.Expired {
+					continue
+				}
+				if _, ok := checked[entry.PhysicalAddress]; ok {
+					continue
+				}
+				checked[entry.PhysicalAddress] = struct{}{}
+				_, err := adapter.GetProperties(block.ObjectPointer{StorageNamespace: repo.StorageNamespace, Identifier: entry.PhysicalAddress})
+				if err != nil {
+					issues = append(issues, &fsckIssue{
+						ConsistencyIssue: catalog.ConsistencyIssue{Type: fsckIssueMissingObject, Branch: branch.Name},
+						Path:             entry.Path,
+					})
+				}
+			}
+			if err := it.Err(); err != nil {
+				return nil, fmt.Errorf("list entries of branch %s: %w", branch.Name, err)
+			}
+		}
+		if !hasMore || len(branches) == 0 {
+			break
+		}
+		after = branches[len(branches)-1].Name
+	}
+	return issues, nil
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+	fsckCmd.Flags().Bool("objects", false, "check that the objects of every branch exist on the underlying storage")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/treeverse/lakefs/uri"
 
 	"github.com/treeverse/lakefs/block/factory"
+	s3a "github.com/treeverse/lakefs/block/s3"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/config"
 	"github.com/treeverse/lakefs/db"
@@ -24,14 +25,16 @@ import (
 const (
 	DryRunFlagName      = "dry-run"
 	ManifestURLFlagName = "manifest"
+	PrefixURLFlagName   = "prefix"
 	ManifestURLFormat   = "s3://example-bucket/inventory/YYYY-MM-DDT00-00Z/manifest.json"
 	ImportCmdNumArgs    = 1
 )
 
 var importCmd = &cobra.Command{
-	Use:   "import <repository uri> --manifest <s3 uri to manifest.json>",
+	Use:   "import <repository uri> --manifest <s3 uri to manifest.json> | --prefix <s3 uri to prefix>",
 	Short: "Import data from S3 to a lakeFS repository",
-	Long:  "Import from an S3 inventory to lakeFS without copying the data.",
+	Long: `Import from an S3 inventory to lakeFS without copying the data.
+Use --prefix instead of --manifest to import the objects under an S3 prefix by listing them, for buckets without an S3 inventory.`,
 	Args: cmdutils.ValidationChain(
 		cobra.ExactArgs(ImportCmdNumArgs),
 		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
@@ -39,6 +42,7 @@ var importCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		dryRun, _ := cmd.Flags().GetBool(DryRunFlagName)
 		manifestURL, _ := cmd.Flags().GetString(ManifestURLFlagName)
+		prefixURL, _ := cmd.Flags().GetString(PrefixURLFlagName)
 		ctx := context.Background()
 		conf := config.NewConfig()
 		err := db.ValidateSchemaUpToDate(conf.GetDatabaseParams())
@@ -60,11 +64,25 @@ var importCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		repoName := u.Repository
-		parsedURL, err := url.Parse(manifestURL)
-		if err != nil || parsedURL.Scheme != "s3" || !strings.HasSuffix(parsedURL.Path, "/manifest.json") {
-			fmt.Printf("Invalid manifest url. expected format: %s\n", ManifestURLFormat)
+		if (manifestURL == "") == (prefixURL == "") {
+			fmt.Printf("Exactly one of --%s or --%s is required\n", ManifestURLFlagName, PrefixURLFlagName)
 			os.Exit(1)
 		}
+		inventoryURL := manifestURL
+		if prefixURL != "" {
+			parsedURL, err := url.Parse(prefixURL)
+			if err != nil || parsedURL.Scheme != "s3" || parsedURL.Host == "" || s3a.IsManifestURL(prefixURL) {
+				fmt.Println("Invalid prefix url. expected format: s3://example-bucket/path/to/data/")
+				os.Exit(1)
+			}
+			inventoryURL = prefixURL
+		} else {
+			parsedURL, err := url.Parse(manifestURL)
+			if err != nil || parsedURL.Scheme != "s3" || !strings.HasSuffix(parsedURL.Path, "/manifest.json") {
+				fmt.Printf("Invalid manifest url. expected format: %s\n", ManifestURLFormat)
+				os.Exit(1)
+			}
+		}
 		repo, err := cataloger.GetRepository(ctx, repoName)
 		if err != nil {
 			fmt.Printf("Failed to read repository %s: %s\n", repoName, err)
@@ -81,7 +99,7 @@ var importCmd = &cobra.Command{
 		}
 		importConfig := &onboard.Config{
 			CommitUsername:     "lakefs",
-			InventoryURL:       manifestURL,
+			InventoryURL:       inventoryURL,
 			Repository:         repoName,
 			InventoryGenerator: blockStore,
 			Cataloger:          cataloger,
@@ -144,5 +162,5 @@ func init() {
 	rootCmd.AddCommand(importCmd)
 	importCmd.Flags().Bool(DryRunFlagName, false, "Only read inventory and print stats, without making any changes")
 	importCmd.Flags().StringP(ManifestURLFlagName, "m", "", fmt.Sprintf("S3 uri to the manifest.json to use for the import. Format: %s", ManifestURLFormat))
-	_ = importCmd.MarkFlagRequired(ManifestURLFlagName)
+	importCmd.Flags().String(PrefixURLFlagName, "", "S3 uri to a prefix to import by listing its objects, instead of reading an inventory")
 }
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
 
 	"github.com/spf13/cobra"
 )
@@ -76,6 +79,28 @@ var gotoCmd = &cobra.Command{
 	},
 }
 
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Upgrade stored values to their current format",
+	Long: `Upgrade repository configuration values stored in an older format to the current format, in batches.
+Values are also upgraded when read, so running it is optional; an interrupted run is completed by running it again.
+Run after "migrate up".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		logger := logging.FromContext(ctx)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+		upgraded, err := cataloger.UpgradeRepositoryConfigs(ctx)
+		if err != nil {
+			logger.WithError(err).WithField("upgraded", upgraded).Fatal("Failed to upgrade repository configuration")
+		}
+		fmt.Printf("upgraded %d repository configuration values\n", upgraded)
+	},
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(migrateCmd)
@@ -83,6 +108,7 @@ func init() {
 	migrateCmd.AddCommand(upCmd)
 	migrateCmd.AddCommand(downCmd)
 	migrateCmd.AddCommand(gotoCmd)
+	migrateCmd.AddCommand(dataCmd)
 	_ = gotoCmd.Flags().Uint("version", 0, "version number")
 	_ = gotoCmd.MarkFlagRequired("version")
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// replicateCmd implements the replicate command
+var replicateCmd = &cobra.Command{
+	Use:   "replicate <repository>",
+	Short: "Replicate the committed metadata of a repository from a primary lakeFS installation",
+	Long: `Pull the commits, committed entries, branches and tags of repository from the lakeFS installation at --endpoint,
+and apply them to a read-only replica in this installation, named --replica or the same as the repository. Each pull
+continues from the checkpoint stored with the replica. With --interval, keeps pulling until interrupted. Objects are not
+copied - the replica uses the storage namespace of the primary repository.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		accessKeyID, _ := cmd.Flags().GetString("access-key-id")
+		secretAccessKey, _ := cmd.Flags().GetString("secret-access-key")
+		replica, _ := cmd.Flags().GetString("replica")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if accessKeyID == "" {
+			accessKeyID = os.Getenv("LAKEFS_ACCESS_KEY_ID")
+		}
+		if secretAccessKey == "" {
+			secretAccessKey = os.Getenv("LAKEFS_SECRET_ACCESS_KEY")
+		}
+		repository := args[0]
+		if replica == "" {
+			replica = repository
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		logger := logging.FromContext(ctx).WithFields(logging.Fields{
+			"repository": repository,
+			"replica":    replica,
+		})
+		client, err := api.NewClient(endpoint, accessKeyID, secretAccessKey)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create primary client")
+		}
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+
+		if err := replicate(ctx, client, cataloger, repository, replica); err != nil {
+			logger.WithError(err).Fatal("Failed to replicate")
+		}
+		if interval <= 0 {
+			return
+		}
+		go func() {
+			quit := make(chan os.Signal, 1)
+			signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+			<-quit
+			cancel()
+		}()
+		runEvery(ctx, interval, func(ctx context.Context) {
+			if err := replicate(ctx, client, cataloger, repository, replica); err != nil {
+				// keep pulling, the next pull continues from the same checkpoint
+				logger.WithError(err).Error("Failed to replicate")
+			}
+		})
+	},
+}
+
+// replicate applies the changes of repository on the primary since the checkpoint of replica,
+// seeding replica when it does not exist
+func replicate(ctx context.Context, client api.Client, cataloger catalog.Cataloger, repository, replica string) error {
+	after, err := cataloger.GetReplicationCheckpoint(ctx, replica)
+	if errors.Is(err, db.ErrNotFound) {
+		after = 0
+	} else if err != nil {
+		return err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		err := client.GetReplicationStream(ctx, repository, after, writer)
+		_ = writer.CloseWithError(err)
+	}()
+	checkpoint, err := cataloger.ImportReplication(ctx, replica, reader)
+	_ = reader.Close()
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).WithFields(logging.Fields{
+		"repository": repository,
+		"replica":    replica,
+		"after":      after,
+		"checkpoint": checkpoint,
+	}).Info("Replicated repository")
+	return nil
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(replicateCmd)
+	replicateCmd.Flags().String("endpoint", "", "API endpoint of the primary lakeFS installation, e.g. https://lakefs.example.com/api/v1")
+	replicateCmd.Flags().String("access-key-id", "", "access key ID on the primary (default $LAKEFS_ACCESS_KEY_ID)")
+	replicateCmd.Flags().String("secret-access-key", "", "secret access key on the primary (default $LAKEFS_SECRET_ACCESS_KEY)")
+	replicateCmd.Flags().String("replica", "", "name of the replica repository (default the repository name)")
+	replicateCmd.Flags().Duration("interval", 0, "keep pulling changes at this interval, pull once when 0")
+	_ = replicateCmd.MarkFlagRequired("endpoint")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// restoreCmd implements the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <repository>",
+	Short: "Create a repository from a backup written by dump",
+	Long: `Create repository from the backup in --input, or in the standard input, after verifying its digest. The repository
+uses --storage-namespace, or the storage namespace of the dumped repository. Objects are not copied - entries keep
+pointing to the objects of the dumped repository.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		storageNamespace, _ := cmd.Flags().GetString("storage-namespace")
+		repository := args[0]
+
+		ctx := context.Background()
+		logger := logging.FromContext(ctx).WithField("repository", repository)
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer func() {
+			_ = dbPool.Close()
+		}()
+		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(cfg.GetCatalogerCatalogParams()))
+
+		var r io.Reader = os.Stdin
+		if input != "" {
+			f, err := os.Open(input)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to open backup file")
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		if err := cataloger.RestoreRepository(ctx, repository, storageNamespace, r); err != nil {
+			logger.WithError(err).Fatal("Failed to restore repository")
+		}
+		logger.Info("Restored repository")
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringP("input", "i", "", "backup file to read (default standard input)")
+	restoreCmd.Flags().String("storage-namespace", "", "storage namespace of the restored repository (default the storage namespace of the dumped repository)")
+}
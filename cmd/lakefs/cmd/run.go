@@ -15,6 +15,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api"
+	"github.com/treeverse/lakefs/audit"
 	"github.com/treeverse/lakefs/auth"
 	"github.com/treeverse/lakefs/auth/crypt"
 	"github.com/treeverse/lakefs/block/factory"
@@ -24,10 +25,12 @@ import (
 	"github.com/treeverse/lakefs/dedup"
 	"github.com/treeverse/lakefs/gateway"
 	"github.com/treeverse/lakefs/gateway/simulator"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/httputil"
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/retention"
 	"github.com/treeverse/lakefs/stats"
+	"github.com/treeverse/lakefs/tracing"
 )
 
 const (
@@ -49,6 +52,7 @@ var runCmd = &cobra.Command{
 		conf := config.NewConfig()
 		logger := logging.Default()
 		logger.WithField("version", config.Version).Infof("lakeFS run")
+		tracing.Configure(conf.GetTracingParams())
 
 		// validate service names and turn on the right flags
 		dbParams := cfg.GetDatabaseParams()
@@ -61,8 +65,26 @@ var runCmd = &cobra.Command{
 		retention := retention.NewService(dbPool)
 		migrator := db.NewDatabaseMigrator(dbParams)
 
+		// init hooks
+		hooksParams, err := conf.GetHooksParams()
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to read hooks configuration")
+		}
+		hooksDispatcher, err := hooks.NewDispatcher(dbPool, hooksParams)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create hooks dispatcher")
+		}
+		defer func() {
+			_ = hooksDispatcher.Close()
+		}()
+
 		// init catalog
-		cataloger := catalog.NewCataloger(dbPool, catalog.WithParams(conf.GetCatalogerCatalogParams()))
+		cataloger := catalog.NewCataloger(dbPool,
+			catalog.WithParams(conf.GetCatalogerCatalogParams()),
+			catalog.WithPreCommitHook(hooksDispatcher.PreCommit),
+			catalog.WithPreMergeHook(hooksDispatcher.PreMerge),
+			catalog.WithPostCommitHook(hooksDispatcher.PostCommit),
+			catalog.WithPostMergeHook(hooksDispatcher.PostMerge))
 
 		// init block store
 		blockStore, err := factory.BuildBlockAdapter(cfg)
@@ -78,6 +100,8 @@ var runCmd = &cobra.Command{
 
 		meta := auth.NewDBMetadataManager(config.Version, dbPool)
 
+		auditService := audit.NewDBService(dbPool)
+
 		processID, bufferedCollectorArgs := cfg.GetStatsBufferedCollectorArgs()
 
 		// collect and write metadata
@@ -111,8 +135,10 @@ var runCmd = &cobra.Command{
 			retention,
 			migrator,
 			dedupCleaner,
+			cfg.GetBlockstoreVerifyChecksumOnRead(),
 			logger.WithField("service", "api_gateway"),
 		)
+		apiHandler = audit.Middleware(auditService, serviceAPIServer, apiHandler)
 
 		// init gateway server
 		s3gatewayHandler := gateway.NewHandler(
@@ -123,10 +149,24 @@ var runCmd = &cobra.Command{
 			cfg.GetS3GatewayDomainName(),
 			stats,
 			dedupCleaner,
+			cfg.GetBlockstoreVerifyChecksumOnRead(),
 		)
+		s3gatewayHandler = audit.Middleware(auditService, serviceS3Gateway, s3gatewayHandler)
 
 		ctx, cancelFn := context.WithCancel(context.Background())
 		go stats.Run(ctx)
+		if interval := conf.GetBranchExpiryInterval(); interval > 0 {
+			dryRun := conf.GetBranchExpiryDryRun()
+			go runEvery(ctx, interval, func(ctx context.Context) {
+				_ = expireBranches(ctx, cataloger, nil, dryRun)
+			})
+		}
+		if interval := conf.GetWorkspaceExpiryInterval(); interval > 0 {
+			dryRun := conf.GetWorkspaceExpiryDryRun()
+			go runEvery(ctx, interval, func(ctx context.Context) {
+				_ = expireWorkspace(ctx, cataloger, nil, dryRun)
+			})
+		}
 
 		stats.CollectEvent("global", "run")
 
@@ -20,7 +20,9 @@ import (
 	blockparams "github.com/treeverse/lakefs/block/params"
 	catalogparams "github.com/treeverse/lakefs/catalog/params"
 	dbparams "github.com/treeverse/lakefs/db/params"
+	hooksparams "github.com/treeverse/lakefs/hooks/params"
 	"github.com/treeverse/lakefs/stats"
+	"github.com/treeverse/lakefs/tracing"
 )
 
 const (
@@ -36,6 +38,8 @@ const (
 	DefaultBlockStoreGSStreamingChunkSize    = 2 << 19         // 1MiB by default per chunk
 	DefaultBlockStoreGSStreamingChunkTimeout = time.Second * 1 // or 1 seconds, whatever comes first
 
+	DefaultBlockStoreAzureTryTimeout = 10 * time.Minute
+
 	DefaultAuthCacheEnabled = true
 	DefaultAuthCacheSize    = 1024
 	DefaultAuthCacheTTL     = 20 * time.Second
@@ -49,6 +53,19 @@ const (
 	DefaultStatsAddr          = "https://stats.treeverse.io"
 	DefaultStatsFlushInterval = time.Second * 30
 
+	DefaultTracingSampleRatio = 1.0
+
+	DefaultHooksTimeout        = 10 * time.Second
+	DefaultHooksMaxAttempts    = 5
+	DefaultHooksInitialBackoff = time.Second
+	DefaultHooksMaxBackoff     = time.Minute
+
+	DefaultBranchExpiryInterval = 0
+	DefaultBranchExpiryDryRun   = false
+
+	DefaultWorkspaceExpiryInterval = 0
+	DefaultWorkspaceExpiryDryRun   = false
+
 	MetaStoreType          = "metastore.type"
 	MetaStoreHiveURI       = "metastore.hive.uri"
 	MetastoreGlueCatalogID = "metastore.glue.catalog-id"
@@ -101,9 +118,24 @@ func setDefaults() {
 	viper.SetDefault("blockstore.gs.streaming_chunk_size", DefaultBlockStoreGSStreamingChunkSize)
 	viper.SetDefault("blockstore.gs.streaming_chunk_timeout", DefaultBlockStoreGSStreamingChunkTimeout)
 
+	viper.SetDefault("blockstore.azure.try_timeout", DefaultBlockStoreAzureTryTimeout)
+
+	viper.SetDefault("tracing.sample_ratio", DefaultTracingSampleRatio)
+
 	viper.SetDefault("stats.enabled", DefaultStatsEnabled)
 	viper.SetDefault("stats.address", DefaultStatsAddr)
 	viper.SetDefault("stats.flush_interval", DefaultStatsFlushInterval)
+
+	viper.SetDefault("hooks.timeout", DefaultHooksTimeout)
+	viper.SetDefault("hooks.max_attempts", DefaultHooksMaxAttempts)
+	viper.SetDefault("hooks.initial_backoff", DefaultHooksInitialBackoff)
+	viper.SetDefault("hooks.max_backoff", DefaultHooksMaxBackoff)
+
+	viper.SetDefault("branch_expiry.interval", DefaultBranchExpiryInterval)
+	viper.SetDefault("branch_expiry.dry_run", DefaultBranchExpiryDryRun)
+
+	viper.SetDefault("workspace_expiry.interval", DefaultWorkspaceExpiryInterval)
+	viper.SetDefault("workspace_expiry.dry_run", DefaultWorkspaceExpiryDryRun)
 }
 
 func (c *Config) GetDatabaseParams() dbparams.Database {
@@ -113,6 +145,9 @@ func (c *Config) GetDatabaseParams() dbparams.Database {
 		MaxIdleConnections:    viper.GetInt("database.max_idle_connections"),
 		ConnectionMaxLifetime: viper.GetDuration("database.connection_max_lifetime"),
 		DisableAutoMigrate:    viper.GetBool("database.disable_auto_migrate"),
+		RetryMaxAttempts:      viper.GetInt("database.retry.max_attempts"),
+		RetryInitialInterval:  viper.GetDuration("database.retry.initial_interval"),
+		RetryMaxInterval:      viper.GetDuration("database.retry.max_interval"),
 	}
 }
 
@@ -134,7 +169,34 @@ func (c *Config) GetCatalogerCatalogParams() catalogparams.Catalog {
 			Expiry:  viper.GetDuration("cataloger.cache.expiry"),
 			Jitter:  viper.GetDuration("cataloger.cache.jitter"),
 		},
+		Quotas: catalogparams.Quotas{
+			MaxRepositories: viper.GetInt("cataloger.quotas.max_repositories"),
+		},
+	}
+}
+
+func (c *Config) GetHooksParams() (hooksparams.Hooks, error) {
+	var webhooks []hooksparams.Webhook
+	if err := viper.UnmarshalKey("hooks.webhooks", &webhooks); err != nil {
+		return hooksparams.Hooks{}, fmt.Errorf("hooks.webhooks: %w", err)
+	}
+	var preCommit []hooksparams.Webhook
+	if err := viper.UnmarshalKey("hooks.pre_commit", &preCommit); err != nil {
+		return hooksparams.Hooks{}, fmt.Errorf("hooks.pre_commit: %w", err)
 	}
+	var preMerge []hooksparams.Webhook
+	if err := viper.UnmarshalKey("hooks.pre_merge", &preMerge); err != nil {
+		return hooksparams.Hooks{}, fmt.Errorf("hooks.pre_merge: %w", err)
+	}
+	return hooksparams.Hooks{
+		Webhooks:       webhooks,
+		PreCommit:      preCommit,
+		PreMerge:       preMerge,
+		Timeout:        viper.GetDuration("hooks.timeout"),
+		MaxAttempts:    viper.GetInt("hooks.max_attempts"),
+		InitialBackoff: viper.GetDuration("hooks.initial_backoff"),
+		MaxBackoff:     viper.GetDuration("hooks.max_backoff"),
+	}, nil
 }
 
 type AwsS3RetentionConfig struct {
@@ -230,6 +292,10 @@ func (c *Config) GetBlockstoreType() string {
 	return viper.GetString("blockstore.type")
 }
 
+func (c *Config) GetBlockstoreVerifyChecksumOnRead() bool {
+	return viper.GetBool("blockstore.verify_checksum_on_read")
+}
+
 func (c *Config) GetBlockAdapterS3Params() (blockparams.S3, error) {
 	cfg := c.GetAwsConfig()
 
@@ -257,6 +323,15 @@ func (c *Config) GetBlockAdapterGSParams() (blockparams.GS, error) {
 	}, nil
 }
 
+func (c *Config) GetBlockAdapterAzureParams() (blockparams.Azure, error) {
+	return blockparams.Azure{
+		StorageAccount:   viper.GetString("blockstore.azure.storage_account"),
+		StorageAccessKey: viper.GetString("blockstore.azure.storage_access_key"),
+		Endpoint:         viper.GetString("blockstore.azure.endpoint"),
+		TryTimeout:       viper.GetDuration("blockstore.azure.try_timeout"),
+	}, nil
+}
+
 func (c *Config) GetAuthCacheConfig() authparams.ServiceCache {
 	return authparams.ServiceCache{
 		Enabled:        viper.GetBool("auth.cache.enabled"),
@@ -286,6 +361,13 @@ func (c *Config) GetListenAddress() string {
 	return viper.GetString("listen_address")
 }
 
+func (c *Config) GetTracingParams() tracing.Params {
+	return tracing.Params{
+		Enabled:     viper.GetBool("tracing.enabled"),
+		SampleRatio: viper.GetFloat64("tracing.sample_ratio"),
+	}
+}
+
 func (c *Config) GetStatsEnabled() bool {
 	return viper.GetBool("stats.enabled")
 }
@@ -298,6 +380,26 @@ func (c *Config) GetStatsFlushInterval() time.Duration {
 	return viper.GetDuration("stats.flush_interval")
 }
 
+// GetBranchExpiryInterval is the interval between background runs of branch expiry, zero
+// disables background branch expiry
+func (c *Config) GetBranchExpiryInterval() time.Duration {
+	return viper.GetDuration("branch_expiry.interval")
+}
+
+func (c *Config) GetBranchExpiryDryRun() bool {
+	return viper.GetBool("branch_expiry.dry_run")
+}
+
+// GetWorkspaceExpiryInterval is the interval between background runs of workspace expiry, zero
+// disables background workspace expiry
+func (c *Config) GetWorkspaceExpiryInterval() time.Duration {
+	return viper.GetDuration("workspace_expiry.interval")
+}
+
+func (c *Config) GetWorkspaceExpiryDryRun() bool {
+	return viper.GetBool("workspace_expiry.dry_run")
+}
+
 func (c *Config) GetStatsBufferedCollectorArgs() (processID string, opts []stats.BufferedCollectorOpts) {
 	var sender stats.Sender
 	if c.GetStatsEnabled() && !strings.HasPrefix(Version, UnreleasedVersion) {
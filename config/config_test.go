@@ -137,3 +137,31 @@ func TestConfig_JSONLogger(t *testing.T) {
 	}
 
 }
+
+func TestConfig_GetHooksParams(t *testing.T) {
+	c := newConfigFromFile("testdata/valid_hooks_config.yaml")
+	p, err := c.GetHooksParams()
+	testutil.Must(t, err)
+	if p.MaxAttempts != 3 {
+		t.Errorf("expected max attempts 3, got %d", p.MaxAttempts)
+	}
+	if p.Timeout != config.DefaultHooksTimeout {
+		t.Errorf("expected default timeout %s, got %s", config.DefaultHooksTimeout, p.Timeout)
+	}
+	if len(p.Webhooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %d", len(p.Webhooks))
+	}
+	if p.Webhooks[0].URL != "https://hooks.example.com/commits" || len(p.Webhooks[0].Events) != 1 || p.Webhooks[0].Events[0] != "post-commit" {
+		t.Errorf("unexpected first webhook %+v", p.Webhooks[0])
+	}
+	if len(p.Webhooks[1].Events) != 0 || len(p.Webhooks[1].Repositories) != 2 {
+		t.Errorf("unexpected second webhook %+v", p.Webhooks[1])
+	}
+	if len(p.PreCommit) != 1 || p.PreCommit[0].URL != "https://hooks.example.com/validate" ||
+		len(p.PreCommit[0].Branches) != 1 || p.PreCommit[0].Branches[0] != "master" {
+		t.Errorf("unexpected pre-commit webhooks %+v", p.PreCommit)
+	}
+	if len(p.PreMerge) != 1 || p.PreMerge[0].Name != "schema-validation" || len(p.PreMerge[0].Branches) != 2 {
+		t.Errorf("unexpected pre-merge webhooks %+v", p.PreMerge)
+	}
+}
@@ -36,6 +36,7 @@ func ConnectDB(p params.Database) (Database, error) {
 		"max_idle_conns":       p.MaxIdleConnections,
 		"conn_max_lifetime":    p.ConnectionMaxLifetime,
 		"disable_auto_migrate": p.DisableAutoMigrate,
+		"retry_max_attempts":   p.RetryMaxAttempts,
 	})
 	log.Info("connecting to the DB")
 	conn, err := sqlx.Connect(p.Driver, p.ConnectionString)
@@ -57,7 +58,11 @@ func ConnectDB(p params.Database) (Database, error) {
 	}
 
 	log.Info("initialized DB connection")
-	return NewSqlxDatabase(conn), nil
+	return NewSqlxDatabase(conn).WithRetryPolicy(RetryPolicy{
+		MaxAttempts:     p.RetryMaxAttempts,
+		InitialInterval: p.RetryInitialInterval,
+		MaxInterval:     p.RetryMaxInterval,
+	}), nil
 }
 
 func normalizeDBParams(p *params.Database) {
@@ -76,4 +81,16 @@ func normalizeDBParams(p *params.Database) {
 	if p.ConnectionMaxLifetime == 0 {
 		p.ConnectionMaxLifetime = DefaultConnectionMaxLifetime
 	}
+
+	if p.RetryMaxAttempts == 0 {
+		p.RetryMaxAttempts = DefaultRetryMaxAttempts
+	}
+
+	if p.RetryInitialInterval == 0 {
+		p.RetryInitialInterval = DefaultRetryInitialInterval
+	}
+
+	if p.RetryMaxInterval == 0 {
+		p.RetryMaxInterval = DefaultRetryMaxInterval
+	}
 }
@@ -9,10 +9,15 @@ import (
 	"time"
 
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/tracing"
+	"go.opencensus.io/trace"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// TxFunc is the body of a transaction.  Transact calls it again when the transaction is retried
+// after a transient error, so it must have no side effects outside of tx - ex: calling external
+// services or sending to channels should be done once the transaction returned.
 type TxFunc func(tx Tx) (interface{}, error)
 
 type Database interface {
@@ -32,10 +37,17 @@ type QueryOptions struct {
 type SqlxDatabase struct {
 	db           *sqlx.DB
 	queryOptions *QueryOptions
+	retryPolicy  RetryPolicy
 }
 
 func NewSqlxDatabase(db *sqlx.DB) *SqlxDatabase {
-	return &SqlxDatabase{db: db}
+	return &SqlxDatabase{db: db, retryPolicy: DefaultRetryPolicy()}
+}
+
+// WithRetryPolicy sets the policy used to retry transactions that failed on a transient error
+func (d *SqlxDatabase) WithRetryPolicy(policy RetryPolicy) *SqlxDatabase {
+	d.retryPolicy = policy
+	return d
 }
 
 func (d *SqlxDatabase) getLogger() logging.Logger {
@@ -54,7 +66,8 @@ func (d *SqlxDatabase) getContext() context.Context {
 
 func (d *SqlxDatabase) WithContext(ctx context.Context) Database {
 	return &SqlxDatabase{
-		db: d.db,
+		db:          d.db,
+		retryPolicy: d.retryPolicy,
 		queryOptions: &QueryOptions{
 			logger: logging.Default().WithContext(ctx),
 			ctx:    ctx,
@@ -132,65 +145,90 @@ func (d *SqlxDatabase) getTxOptions() *TxOptions {
 	return options
 }
 
+// Transact runs fn in a transaction, and tries it again on transient errors.  It is traced by a
+// span named by the operation of the transaction, with a "db.transaction" child span for each
+// attempt.
 func (d *SqlxDatabase) Transact(fn TxFunc, opts ...TxOpt) (interface{}, error) {
 	options := d.getTxOptions()
 	for _, opt := range opts {
 		opt(options)
 	}
-	var attempt int
-	var ret interface{}
-	for attempt < SerializationRetryMaxAttempts {
-		if attempt > 0 {
-			duration := time.Duration(int(SerializationRetryStartInterval) * attempt)
-			dbRetriesCount.Inc()
-			options.logger.
-				WithField("attempt", attempt).
-				WithField("sleep_interval", duration).
-				Warn("retrying transaction due to serialization error")
-			time.Sleep(duration)
-		}
+	var span *trace.Span
+	options.ctx, span = tracing.Start(options.ctx, options.operation,
+		trace.BoolAttribute("read_only", options.readOnly),
+		trace.StringAttribute("isolation_level", options.isolationLevel.String()))
+	ret, err := d.transact(fn, options)
+	tracing.End(span, err)
+	return ret, err
+}
 
+func (d *SqlxDatabase) transact(fn TxFunc, options *TxOptions) (interface{}, error) {
+	start := time.Now()
+	result := "error"
+	defer func() {
+		dbTransactionDurationHistograms.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	}()
+	maxAttempts := d.retryPolicy.MaxAttempts
+	if options.maxAttempts > 0 {
+		maxAttempts = options.maxAttempts
+	}
+	var ret interface{}
+	for attempt := 1; ; attempt++ {
+		_, span := tracing.Start(options.ctx, "db.transaction", trace.Int64Attribute("attempt", int64(attempt)))
 		tx, err := d.db.BeginTxx(options.ctx, &sql.TxOptions{
 			Isolation: options.isolationLevel,
 			ReadOnly:  options.readOnly,
 		})
 		if err != nil {
+			tracing.End(span, err)
 			return nil, err
 		}
 		ret, err = fn(&dbTx{tx: tx, logger: options.logger})
 		if err != nil {
 			rollbackErr := tx.Rollback()
 			if rollbackErr != nil {
+				tracing.End(span, rollbackErr)
 				return nil, rollbackErr
 			}
-			// retry on serialization error
-			if IsSerializationError(err) {
-				// retry
-				attempt++
-				continue
-			}
-			return nil, err
 		} else {
 			err = tx.Commit()
-			if err != nil {
-				// retry on serialization error
-				if IsSerializationError(err) {
-					attempt++
-					continue
-				}
-				// other commit error
-				return nil, err
+			if err == nil {
+				// committed successfully, we're done
+				tracing.End(span, nil)
+				result = "committed"
+				return ret, nil
 			}
-			// committed successfully, we're done
-			return ret, nil
 		}
-	}
-	if attempt == SerializationRetryMaxAttempts {
+		tracing.End(span, err)
+
+		// retry on transient errors
+		reason := transientErrorReason(err)
+		if reason == "" || options.ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt >= maxAttempts {
+			options.logger.
+				WithError(err).
+				WithField("attempt", attempt).
+				Warn("transaction failed after max attempts")
+			if IsSerializationError(err) {
+				result = "serialization_error"
+				return nil, ErrSerialization
+			}
+			return nil, err
+		}
+		duration := d.retryPolicy.Backoff(attempt)
+		dbRetriesCount.WithLabelValues(reason).Inc()
 		options.logger.
+			WithError(err).
 			WithField("attempt", attempt).
-			Warn("transaction failed after max attempts due to serialization error")
+			WithField("reason", reason).
+			WithField("sleep_interval", duration).
+			Warn("retrying transaction")
+		if err := sleepContext(options.ctx, duration); err != nil {
+			return nil, err
+		}
 	}
-	return nil, ErrSerialization
 }
 
 func (d *SqlxDatabase) Metadata() (map[string]string, error) {
@@ -4,8 +4,19 @@ import (
 	"strings"
 )
 
+// escapeLike escapes s to match literally in a LIKE pattern.  The escape character itself is
+// escaped first, so the escapes added for the wildcards are not escaped again.
+func escapeLike(s string) string {
+	v := strings.ReplaceAll(s, "\\", "\\\\")
+	v = strings.ReplaceAll(v, "%", "\\%")
+	return strings.ReplaceAll(v, "_", "\\_")
+}
+
 func Prefix(prefix string) string {
-	v := strings.ReplaceAll(prefix, "%", "\\%")
-	v = strings.ReplaceAll(v, "_", "\\_")
-	return v + "%"
+	return escapeLike(prefix) + "%"
+}
+
+// Contains returns a LIKE pattern matching any value that contains s
+func Contains(s string) string {
+	return "%" + escapeLike(s) + "%"
 }
@@ -0,0 +1,25 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+)
+
+func TestContains(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Expected string
+	}{
+		{Value: "plain", Expected: `%plain%`},
+		{Value: "50%_off", Expected: `%50\%\_off%`},
+		{Value: `a\b`, Expected: `%a\\b%`},
+		{Value: `trailing\`, Expected: `%trailing\\%`},
+		{Value: `\%`, Expected: `%\\\%%`},
+	}
+	for _, tt := range cases {
+		if got := db.Contains(tt.Value); got != tt.Expected {
+			t.Errorf("Contains(%q) = %q, expected %q", tt.Value, got, tt.Expected)
+		}
+	}
+}
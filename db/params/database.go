@@ -9,4 +9,9 @@ type Database struct {
 	MaxIdleConnections    int
 	ConnectionMaxLifetime time.Duration
 	DisableAutoMigrate    bool
+
+	// transaction retries on transient errors (serialization failures, deadlocks, lock timeouts)
+	RetryMaxAttempts     int
+	RetryInitialInterval time.Duration
+	RetryMaxInterval     time.Duration
 }
@@ -15,6 +15,12 @@ func IsUniqueViolation(err error) bool {
 	return isPGCode(err, pgerrcode.UniqueViolation)
 }
 
+// IsInvalidRegularExpression returns true if err reports a regular expression the database
+// could not compile
+func IsInvalidRegularExpression(err error) bool {
+	return isPGCode(err, pgerrcode.InvalidRegularExpression)
+}
+
 func isPGCode(err error, code string) bool {
 	var pgErr *pgconn.PgError
 	if err != nil && errors.As(err, &pgErr) {
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+)
+
+const (
+	DefaultRetryMaxAttempts     = 10
+	DefaultRetryInitialInterval = 2 * time.Millisecond
+	DefaultRetryMaxInterval     = 500 * time.Millisecond
+)
+
+// RetryPolicy controls how transactions that failed on a transient error are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a transaction is tried, including the first attempt
+	MaxAttempts int
+	// InitialInterval is the base backoff before the first retry, doubled on each retry
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff between retries
+	MaxInterval time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     DefaultRetryMaxAttempts,
+		InitialInterval: DefaultRetryInitialInterval,
+		MaxInterval:     DefaultRetryMaxInterval,
+	}
+}
+
+// Backoff returns the time to wait before retry number attempt (starting at 1): exponential
+// backoff capped at MaxInterval, with jitter - a random duration between half and all
+// of the backoff, so concurrent conflicting transactions do not retry in lockstep.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	interval := p.InitialInterval
+	for i := 1; i < attempt && interval < p.MaxInterval; i++ {
+		interval *= 2
+	}
+	if interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	half := interval / 2
+	if half <= 0 {
+		return interval
+	}
+	return half + time.Duration(rand.Int63n(int64(interval-half)+1)) //nolint:gosec
+}
+
+// sleepContext waits for duration, returning early with the context error if ctx is done
+// first
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// transientErrorReason returns a short reason if err is a transient error that is expected to
+// pass when the transaction is retried, or an empty string otherwise
+func transientErrorReason(err error) string {
+	switch {
+	case IsSerializationError(err):
+		return "serialization"
+	case isPGCode(err, pgerrcode.DeadlockDetected):
+		return "deadlock"
+	case isPGCode(err, pgerrcode.LockNotAvailable):
+		return "lock_timeout"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:     10,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     100 * time.Millisecond,
+	}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: 10 * time.Millisecond},
+		{attempt: 2, max: 20 * time.Millisecond},
+		{attempt: 3, max: 40 * time.Millisecond},
+		{attempt: 4, max: 80 * time.Millisecond},
+		{attempt: 5, max: 100 * time.Millisecond},
+		{attempt: 50, max: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := policy.Backoff(tt.attempt)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("Backoff(%d) = %s, expected between %s and %s", tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("sleepContext() err = %v, expected nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := sleepContext(ctx, time.Hour)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("sleepContext() on canceled context err = %v, expected %s", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("sleepContext() on canceled context took %s", elapsed)
+	}
+}
@@ -6,11 +6,12 @@ import (
 )
 
 var (
-	dbRetriesCount = promauto.NewCounter(
+	dbRetriesCount = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "db_retries",
-			Help: "A database retries counter",
+			Help: "A database transaction retries counter",
 		},
+		[]string{"reason"},
 	)
 
 	dbErrorsCounter = promauto.NewCounterVec(
@@ -20,4 +21,20 @@ var (
 		},
 		[]string{"op"},
 	)
+
+	dbQueryDurationHistograms = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "db_query_duration_seconds",
+			Help: "Durations of database queries",
+		},
+		[]string{"type"},
+	)
+
+	dbTransactionDurationHistograms = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "db_transaction_duration_seconds",
+			Help: "Durations of database transactions, including retries",
+		},
+		[]string{"result"},
+	)
 )
@@ -11,11 +11,6 @@ import (
 	"github.com/treeverse/lakefs/logging"
 )
 
-const (
-	SerializationRetryMaxAttempts   = 10
-	SerializationRetryStartInterval = time.Millisecond * 2
-)
-
 type Tx interface {
 	Query(query string, args ...interface{}) (*sqlx.Rows, error)
 	Select(dest interface{}, query string, args ...interface{}) error
@@ -41,6 +36,7 @@ func (d *dbTx) Query(query string, args ...interface{}) (*sqlx.Rows, error) {
 		"query": queryToString(query),
 		"took":  time.Since(start),
 	})
+	dbQueryDurationHistograms.WithLabelValues("query").Observe(time.Since(start).Seconds())
 	if err != nil {
 		log.WithError(err).Error("SQL query failed with error")
 		return nil, err
@@ -58,6 +54,7 @@ func (d *dbTx) Select(dest interface{}, query string, args ...interface{}) error
 		"query": queryToString(query),
 		"took":  time.Since(start),
 	})
+	dbQueryDurationHistograms.WithLabelValues("select").Observe(time.Since(start).Seconds())
 	if err != nil {
 		dbErrorsCounter.WithLabelValues("select").Inc()
 		log.WithError(err).Error("SQL query failed with error")
@@ -76,6 +73,7 @@ func (d *dbTx) Get(dest interface{}, query string, args ...interface{}) error {
 		"query": queryToString(query),
 		"took":  time.Since(start),
 	})
+	dbQueryDurationHistograms.WithLabelValues("get").Observe(time.Since(start).Seconds())
 	if errors.Is(err, sql.ErrNoRows) {
 		log.Trace("SQL query returned no results")
 		return ErrNotFound
@@ -98,6 +96,7 @@ func (d *dbTx) Exec(query string, args ...interface{}) (sql.Result, error) {
 		"query": queryToString(query),
 		"took":  time.Since(start),
 	})
+	dbQueryDurationHistograms.WithLabelValues("exec").Observe(time.Since(start).Seconds())
 	if err != nil {
 		dbErrorsCounter.WithLabelValues("exec").Inc()
 		log.WithError(err).Error("SQL query failed with error")
@@ -114,6 +113,8 @@ type TxOptions struct {
 	ctx            context.Context
 	isolationLevel sql.IsolationLevel
 	readOnly       bool
+	maxAttempts    int
+	operation      string
 }
 
 func DefaultTxOptions() *TxOptions {
@@ -122,6 +123,7 @@ func DefaultTxOptions() *TxOptions {
 		ctx:            context.Background(),
 		isolationLevel: sql.LevelSerializable,
 		readOnly:       false,
+		operation:      "db.Transact",
 	}
 }
 
@@ -148,3 +150,18 @@ func WithIsolationLevel(level sql.IsolationLevel) TxOpt {
 		o.isolationLevel = level
 	}
 }
+
+// WithMaxAttempts overrides the number of times the transaction is tried on transient errors,
+// including the first attempt.  Transactions whose body cannot be repeated use 1.
+func WithMaxAttempts(attempts int) TxOpt {
+	return func(o *TxOptions) {
+		o.maxAttempts = attempts
+	}
+}
+
+// WithOperation names the operation the transaction performs, which names its tracing span.
+func WithOperation(operation string) TxOpt {
+	return func(o *TxOptions) {
+		o.operation = operation
+	}
+}
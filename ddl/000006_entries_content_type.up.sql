@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_entries ADD COLUMN content_type character varying NOT NULL DEFAULT '';
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_multipart_upload_parts (
+    upload_id character varying NOT NULL,
+    part_number integer NOT NULL,
+    etag character varying NOT NULL,
+    size bigint NOT NULL,
+    creation_date timestamp with time zone DEFAULT now() NOT NULL
+);
+ALTER TABLE ONLY catalog_multipart_upload_parts
+    ADD CONSTRAINT catalog_multipart_upload_parts_pk PRIMARY KEY (upload_id, part_number);
+ALTER TABLE ONLY catalog_multipart_upload_parts
+    ADD CONSTRAINT catalog_multipart_upload_parts_upload_id_fk FOREIGN KEY (upload_id) REFERENCES catalog_multipart_uploads(upload_id) ON DELETE CASCADE;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_repositories ADD COLUMN read_only boolean NOT NULL DEFAULT false;
+COMMIT;
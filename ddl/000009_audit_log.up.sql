@@ -0,0 +1,18 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigserial NOT NULL PRIMARY KEY,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    service character varying NOT NULL,
+    actor character varying NOT NULL,
+    action character varying NOT NULL,
+    method character varying NOT NULL,
+    repository character varying NOT NULL,
+    ref character varying NOT NULL,
+    path character varying NOT NULL,
+    status_code integer NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log USING btree (created_at);
+-- audit records are append-only
+CREATE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING;
+CREATE RULE audit_log_no_delete AS ON DELETE TO audit_log DO INSTEAD NOTHING;
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS hooks_deliveries (
+    id bigserial NOT NULL PRIMARY KEY,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    webhook_url character varying NOT NULL,
+    event character varying NOT NULL,
+    repository character varying NOT NULL,
+    payload jsonb NOT NULL,
+    status character varying NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    status_code integer DEFAULT 0 NOT NULL,
+    last_error character varying DEFAULT '' NOT NULL
+);
+CREATE INDEX IF NOT EXISTS hooks_deliveries_status_idx ON hooks_deliveries USING btree (status);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS catalog_repository_events;
+DROP TABLE IF EXISTS catalog_repository_events_seq;
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_repository_events_seq (
+    repository_id integer NOT NULL PRIMARY KEY REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    seq bigint NOT NULL
+);
+CREATE TABLE IF NOT EXISTS catalog_repository_events (
+    repository_id integer NOT NULL REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    seq bigint NOT NULL,
+    event_type character varying NOT NULL,
+    branch character varying NOT NULL,
+    path character varying NOT NULL DEFAULT '',
+    reference character varying NOT NULL DEFAULT '',
+    creation_date timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (repository_id, seq)
+);
+COMMIT;
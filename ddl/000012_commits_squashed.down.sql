@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_commits DROP COLUMN IF EXISTS squashed;
+COMMIT;
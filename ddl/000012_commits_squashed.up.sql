@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_commits ADD COLUMN squashed boolean NOT NULL DEFAULT false;
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_merge_conflicts (
+    source_branch_id integer NOT NULL REFERENCES catalog_branches(id) ON DELETE CASCADE,
+    destination_branch_id integer NOT NULL REFERENCES catalog_branches(id) ON DELETE CASCADE,
+    path character varying COLLATE "C" NOT NULL,
+    conflict_type character varying NOT NULL,
+    creation_date timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (source_branch_id, destination_branch_id, path)
+);
+COMMIT;
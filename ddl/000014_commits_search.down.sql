@@ -0,0 +1,5 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_commits_metadata_idx;
+DROP INDEX IF EXISTS catalog_commits_creation_date_idx;
+DROP INDEX IF EXISTS catalog_commits_committer_idx;
+COMMIT;
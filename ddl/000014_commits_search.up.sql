@@ -0,0 +1,5 @@
+BEGIN;
+CREATE INDEX IF NOT EXISTS catalog_commits_committer_idx ON catalog_commits USING btree (committer);
+CREATE INDEX IF NOT EXISTS catalog_commits_creation_date_idx ON catalog_commits USING btree (creation_date);
+CREATE INDEX IF NOT EXISTS catalog_commits_metadata_idx ON catalog_commits USING gin (metadata jsonb_path_ops);
+COMMIT;
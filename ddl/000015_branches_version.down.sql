@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_branches DROP COLUMN IF EXISTS version;
+COMMIT;
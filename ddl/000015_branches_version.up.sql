@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_branches ADD COLUMN version bigint NOT NULL DEFAULT 1;
+COMMIT;
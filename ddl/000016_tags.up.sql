@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_tags (
+    repository_id integer NOT NULL REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    name character varying COLLATE "C" NOT NULL,
+    id character varying(64) NOT NULL,
+    branch_id bigint NOT NULL,
+    commit_id bigint NOT NULL,
+    tagger character varying NOT NULL,
+    message character varying NOT NULL,
+    metadata jsonb,
+    creation_date timestamp with time zone NOT NULL,
+    PRIMARY KEY (repository_id, name),
+    FOREIGN KEY (branch_id, commit_id) REFERENCES catalog_commits(branch_id, commit_id)
+);
+CREATE INDEX IF NOT EXISTS catalog_tags_commit_idx ON catalog_tags (branch_id, commit_id);
+COMMIT;
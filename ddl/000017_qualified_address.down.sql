@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_entries_physical_address_idx;
+DROP FUNCTION IF EXISTS catalog_qualified_address;
+COMMIT;
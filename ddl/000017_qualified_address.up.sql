@@ -0,0 +1,8 @@
+BEGIN;
+-- the full address of an object at address in storage_namespace, matching qualifyPhysicalAddress
+CREATE OR REPLACE FUNCTION catalog_qualified_address(storage_namespace character varying, address character varying) RETURNS character varying
+    LANGUAGE sql IMMUTABLE COST 1
+AS $$ SELECT CASE WHEN address ~ '^([a-zA-Z][a-zA-Z0-9+.-]*:|/)' THEN address
+    ELSE regexp_replace(storage_namespace, '/$', '') || '/' || address END $$;
+CREATE INDEX IF NOT EXISTS catalog_entries_physical_address_idx ON catalog_entries USING btree (physical_address);
+COMMIT;
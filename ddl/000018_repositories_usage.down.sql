@@ -0,0 +1,5 @@
+BEGIN;
+DROP TRIGGER IF EXISTS catalog_entries_usage ON catalog_entries;
+DROP FUNCTION IF EXISTS catalog_entries_usage;
+DROP TABLE IF EXISTS catalog_repositories_usage;
+COMMIT;
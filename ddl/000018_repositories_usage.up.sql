@@ -0,0 +1,54 @@
+BEGIN;
+-- objects usage of repositories with object quotas, maintained by catalog_entries_usage
+CREATE TABLE IF NOT EXISTS catalog_repositories_usage (
+    repository_id integer PRIMARY KEY REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    objects bigint NOT NULL,
+    size_bytes bigint NOT NULL
+);
+
+-- catalog_entries_usage applies the change of an entry to the usage of its repository: an entry
+-- is counted while it is current (max_commit is catalog_max_commit_id()) and not expired
+CREATE OR REPLACE FUNCTION catalog_entries_usage() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+DECLARE
+    objects_delta bigint := 0;
+    size_delta bigint := 0;
+    entry_branch_id bigint;
+BEGIN
+    IF TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN
+        entry_branch_id := NEW.branch_id;
+        IF NEW.max_commit = catalog_max_commit_id() AND NOT NEW.is_expired THEN
+            objects_delta := objects_delta + 1;
+            size_delta := size_delta + NEW.size;
+        END IF;
+    END IF;
+    IF TG_OP = 'UPDATE' OR TG_OP = 'DELETE' THEN
+        entry_branch_id := OLD.branch_id;
+        IF OLD.max_commit = catalog_max_commit_id() AND NOT OLD.is_expired THEN
+            objects_delta := objects_delta - 1;
+            size_delta := size_delta - OLD.size;
+        END IF;
+    END IF;
+    IF objects_delta <> 0 OR size_delta <> 0 THEN
+        UPDATE catalog_repositories_usage u
+        SET objects = u.objects + objects_delta, size_bytes = u.size_bytes + size_delta
+        FROM catalog_branches b
+        WHERE b.id = entry_branch_id AND u.repository_id = b.repository_id;
+    END IF;
+    RETURN NULL;
+END $$;
+
+DROP TRIGGER IF EXISTS catalog_entries_usage ON catalog_entries;
+CREATE TRIGGER catalog_entries_usage AFTER INSERT OR UPDATE OF max_commit, is_expired, size OR DELETE ON catalog_entries
+    FOR EACH ROW EXECUTE PROCEDURE catalog_entries_usage();
+
+INSERT INTO catalog_repositories_usage (repository_id, objects, size_bytes)
+SELECT c.repository_id, COUNT(e.branch_id), COALESCE(SUM(e.size), 0)
+FROM catalog_repositories_config c
+    JOIN catalog_branches b ON b.repository_id = c.repository_id
+    LEFT JOIN catalog_entries e ON e.branch_id = b.id AND e.max_commit = catalog_max_commit_id() AND NOT e.is_expired
+WHERE c.key = 'quotas' AND (COALESCE((c.value->>'max_objects')::bigint, 0) > 0 OR COALESCE((c.value->>'max_size_bytes')::bigint, 0) > 0)
+GROUP BY c.repository_id
+ON CONFLICT DO NOTHING;
+COMMIT;
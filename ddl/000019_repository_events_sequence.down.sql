@@ -0,0 +1,13 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_repository_events_creation_date_idx;
+DROP INDEX IF EXISTS catalog_repository_events_txid_idx;
+ALTER TABLE catalog_repository_events DROP COLUMN IF EXISTS txid;
+ALTER TABLE catalog_repository_events ALTER COLUMN seq DROP DEFAULT;
+DROP SEQUENCE IF EXISTS catalog_repository_events_seq;
+CREATE TABLE IF NOT EXISTS catalog_repository_events_seq (
+    repository_id integer NOT NULL PRIMARY KEY REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    seq bigint NOT NULL
+);
+INSERT INTO catalog_repository_events_seq (repository_id, seq)
+SELECT repository_id, MAX(seq) FROM catalog_repository_events GROUP BY repository_id;
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+-- events are numbered by a global sequence instead of a counter row per repository, continuing
+-- after the numbers already given so feed positions stay valid
+DROP TABLE IF EXISTS catalog_repository_events_seq;
+CREATE SEQUENCE IF NOT EXISTS catalog_repository_events_seq;
+SELECT setval('catalog_repository_events_seq', COALESCE(MAX(seq), 0) + 1, false) FROM catalog_repository_events;
+ALTER TABLE catalog_repository_events ALTER COLUMN seq SET DEFAULT nextval('catalog_repository_events_seq');
+ALTER SEQUENCE catalog_repository_events_seq OWNED BY catalog_repository_events.seq;
+-- the transaction that recorded the event: the feed holds back events until all the
+-- transactions before them completed, as they may still record events with lower numbers
+ALTER TABLE catalog_repository_events ADD COLUMN IF NOT EXISTS txid bigint NOT NULL DEFAULT txid_current();
+CREATE INDEX IF NOT EXISTS catalog_repository_events_txid_idx ON catalog_repository_events (repository_id, txid, seq);
+CREATE INDEX IF NOT EXISTS catalog_repository_events_creation_date_idx ON catalog_repository_events (creation_date);
+COMMIT;
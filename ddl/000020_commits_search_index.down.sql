@@ -0,0 +1,3 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_commits_message_trgm_idx;
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+-- commit search matches substrings and regular expressions of messages, which a trigram index
+-- serves; metadata containment is served by catalog_commits_metadata_idx
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS catalog_commits_message_trgm_idx ON catalog_commits USING gin (message gin_trgm_ops);
+COMMIT;
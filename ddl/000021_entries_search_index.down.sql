@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_entries_metadata_idx;
+DROP INDEX IF EXISTS catalog_entries_path_trgm_idx;
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+-- entries search matches path patterns anywhere in the path, which a trigram index serves, and
+-- user metadata containment, which a jsonb index serves
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS catalog_entries_path_trgm_idx ON catalog_entries USING gin (path gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS catalog_entries_metadata_idx ON catalog_entries USING gin (metadata jsonb_path_ops);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_repositories_labels_idx;
+ALTER TABLE catalog_repositories DROP COLUMN IF EXISTS labels;
+COMMIT;
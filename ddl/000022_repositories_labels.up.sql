@@ -0,0 +1,5 @@
+BEGIN;
+-- labels are key/value pairs set on a repository to filter repository listings by
+ALTER TABLE catalog_repositories ADD COLUMN IF NOT EXISTS labels jsonb NOT NULL DEFAULT '{}'::jsonb;
+CREATE INDEX IF NOT EXISTS catalog_repositories_labels_idx ON catalog_repositories USING gin (labels jsonb_path_ops);
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+CREATE OR REPLACE FUNCTION catalog_entries_usage() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+DECLARE
+    objects_delta bigint := 0;
+    size_delta bigint := 0;
+    entry_branch_id bigint;
+BEGIN
+    IF TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN
+        entry_branch_id := NEW.branch_id;
+        IF NEW.max_commit = catalog_max_commit_id() AND NOT NEW.is_expired THEN
+            objects_delta := objects_delta + 1;
+            size_delta := size_delta + NEW.size;
+        END IF;
+    END IF;
+    IF TG_OP = 'UPDATE' OR TG_OP = 'DELETE' THEN
+        entry_branch_id := OLD.branch_id;
+        IF OLD.max_commit = catalog_max_commit_id() AND NOT OLD.is_expired THEN
+            objects_delta := objects_delta - 1;
+            size_delta := size_delta - OLD.size;
+        END IF;
+    END IF;
+    IF objects_delta <> 0 OR size_delta <> 0 THEN
+        UPDATE catalog_repositories_usage u
+        SET objects = u.objects + objects_delta, size_bytes = u.size_bytes + size_delta
+        FROM catalog_branches b
+        WHERE b.id = entry_branch_id AND u.repository_id = b.repository_id;
+    END IF;
+    RETURN NULL;
+END $$;
+DROP FUNCTION IF EXISTS catalog_branch_in_use;
+ALTER TABLE catalog_branches DROP COLUMN IF EXISTS deleted_name;
+COMMIT;
@@ -0,0 +1,54 @@
+BEGIN;
+-- deleted_name is the name a deleted branch had before it was renamed to its deleted branch name
+ALTER TABLE catalog_branches ADD COLUMN IF NOT EXISTS deleted_name varchar NULL;
+
+-- catalog_branch_in_use reports whether the entries of a branch are in use: it was not deleted,
+-- or a branch that was not deleted was created from it
+CREATE OR REPLACE FUNCTION catalog_branch_in_use(branch_id bigint) RETURNS boolean
+    LANGUAGE sql STABLE
+AS $$
+    SELECT b.name NOT LIKE '~%' OR EXISTS (SELECT 1 FROM catalog_branches ch
+        WHERE ch.repository_id = b.repository_id AND b.id = ANY(ch.lineage) AND ch.name NOT LIKE '~%')
+    FROM catalog_branches b WHERE b.id = branch_id
+$$;
+
+-- entries of deleted branches that are not in use are not counted
+CREATE OR REPLACE FUNCTION catalog_entries_usage() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+DECLARE
+    objects_delta bigint := 0;
+    size_delta bigint := 0;
+    entry_branch_id bigint;
+BEGIN
+    IF TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN
+        entry_branch_id := NEW.branch_id;
+        IF NEW.max_commit = catalog_max_commit_id() AND NOT NEW.is_expired THEN
+            objects_delta := objects_delta + 1;
+            size_delta := size_delta + NEW.size;
+        END IF;
+    END IF;
+    IF TG_OP = 'UPDATE' OR TG_OP = 'DELETE' THEN
+        entry_branch_id := OLD.branch_id;
+        IF OLD.max_commit = catalog_max_commit_id() AND NOT OLD.is_expired THEN
+            objects_delta := objects_delta - 1;
+            size_delta := size_delta - OLD.size;
+        END IF;
+    END IF;
+    IF objects_delta <> 0 OR size_delta <> 0 THEN
+        UPDATE catalog_repositories_usage u
+        SET objects = u.objects + objects_delta, size_bytes = u.size_bytes + size_delta
+        FROM catalog_branches b
+        WHERE b.id = entry_branch_id AND u.repository_id = b.repository_id AND catalog_branch_in_use(b.id);
+    END IF;
+    RETURN NULL;
+END $$;
+
+UPDATE catalog_repositories_usage u
+SET objects = c.objects, size_bytes = c.size_bytes
+FROM (SELECT b.repository_id, COUNT(*) AS objects, COALESCE(SUM(e.size), 0) AS size_bytes
+    FROM catalog_entries e JOIN catalog_branches b ON b.id = e.branch_id
+    WHERE e.max_commit = catalog_max_commit_id() AND NOT e.is_expired AND catalog_branch_in_use(b.id)
+    GROUP BY b.repository_id) c
+WHERE u.repository_id = c.repository_id;
+COMMIT;
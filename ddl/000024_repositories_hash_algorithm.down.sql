@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_repositories DROP COLUMN IF EXISTS hash_algorithm;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+-- the hash algorithm content addresses (tag IDs) of the repository are computed with
+ALTER TABLE catalog_repositories ADD COLUMN IF NOT EXISTS hash_algorithm varchar NOT NULL DEFAULT 'sha256';
+COMMIT;
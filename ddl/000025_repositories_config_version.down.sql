@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_repositories_config DROP COLUMN IF EXISTS version;
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+-- the format version of the configuration value, upgraded by the catalog when read and by
+-- "lakefs migrate data"
+ALTER TABLE catalog_repositories_config ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+COMMIT;
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/treeverse/lakefs/audit"
 	"github.com/treeverse/lakefs/auth"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
@@ -23,6 +24,7 @@ import (
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/permissions"
 	"github.com/treeverse/lakefs/stats"
+	"github.com/treeverse/lakefs/tracing"
 )
 
 type handler struct {
@@ -42,6 +44,8 @@ type ServerContext struct {
 	authService  simulator.GatewayAuthService
 	stats        stats.Collector
 	dedupCleaner *dedup.Cleaner
+
+	verifyChecksum bool
 }
 
 const operationIDNotFound = "not_found_operation"
@@ -56,6 +60,8 @@ func (c *ServerContext) WithContext(ctx context.Context) *ServerContext {
 		authService:  c.authService,
 		stats:        c.stats,
 		dedupCleaner: c.dedupCleaner,
+
+		verifyChecksum: c.verifyChecksum,
 	}
 }
 
@@ -67,6 +73,7 @@ func NewHandler(
 	bareDomain string,
 	stats stats.Collector,
 	dedupCleaner *dedup.Cleaner,
+	verifyChecksum bool,
 ) http.Handler {
 	sc := &ServerContext{
 		ctx:          context.Background(),
@@ -77,6 +84,8 @@ func NewHandler(
 		authService:  authService,
 		stats:        stats,
 		dedupCleaner: dedupCleaner,
+
+		verifyChecksum: verifyChecksum,
 	}
 
 	// setup routes
@@ -90,6 +99,7 @@ func NewHandler(
 	h = simulator.RegisterRecorder(httputil.LoggingMiddleware(
 		"X-Amz-Request-Id", logging.Fields{"service_name": "s3_gateway"}, h,
 	), authService, region, bareDomain)
+	h = tracing.Middleware("s3_gateway", h)
 
 	logging.Default().WithFields(logging.Fields{
 		"s3_bare_domain": bareDomain,
@@ -123,6 +133,7 @@ func authenticateOperation(s *ServerContext, writer http.ResponseWriter, request
 				WithField("message_type", "action").
 				Debug("performing S3 action")
 			s.stats.CollectEvent("s3_gateway", action)
+			audit.SetAction(request.Context(), action)
 		},
 		DedupCleaner: s.dedupCleaner,
 	}
@@ -177,6 +188,7 @@ func authenticateOperation(s *ServerContext, writer http.ResponseWriter, request
 	}
 
 	op.AddLogFields(logging.Fields{"user": user.Username})
+	audit.SetActor(request.Context(), user.Username)
 
 	if perms == nil {
 		// no special permissions required, no need to authorize (used for delete-objects, where permissions are checked separately)
@@ -219,7 +231,8 @@ func operation(sc *ServerContext, writer http.ResponseWriter, request *http.Requ
 				Debug("performing S3 action")
 			sc.stats.CollectEvent("s3_gateway", action)
 		},
-		DedupCleaner: sc.dedupCleaner,
+		DedupCleaner:   sc.dedupCleaner,
+		VerifyChecksum: sc.verifyChecksum,
 	}
 }
 
@@ -274,6 +287,7 @@ func RepoOperationHandler(sc *ServerContext, repoID string, handler operations.R
 		repoOperation.AddLogFields(logging.Fields{
 			"repository": repo.Name,
 		})
+		audit.SetResource(request.Context(), repo.Name, "", "")
 		handler.Handle(repoOperation)
 	})
 }
@@ -320,6 +334,7 @@ func PathOperationHandler(sc *ServerContext, repoID, refID, path string, handler
 			"ref":        refID,
 			"path":       path,
 		})
+		audit.SetResource(request.Context(), repo.Name, refID, path)
 		handler.Handle(operation)
 	})
 }
@@ -34,6 +34,7 @@ type Operation struct {
 	Auth           simulator.GatewayAuthService
 	Incr           ActionIncr
 	DedupCleaner   *dedup.Cleaner
+	VerifyChecksum bool
 }
 
 func (o *Operation) RequestID() string {
@@ -147,6 +148,34 @@ type AuthenticatedOperation struct {
 	Principal string
 }
 
+// ProtectedPathsContext returns the operation context, allowing changes to protected paths in
+// repository that the principal may write protected objects at
+func (o *AuthenticatedOperation) ProtectedPathsContext(repository string) context.Context {
+	return catalog.WithProtectedPathsAuthorizer(o.Context(), func(paths []string) error {
+		perms := make([]permissions.Permission, len(paths))
+		for i, p := range paths {
+			perms[i] = permissions.Permission{
+				Action:   permissions.WriteProtectedObjectAction,
+				Resource: permissions.ObjectArn(repository, p),
+			}
+		}
+		authResp, err := o.Auth.Authorize(&auth.AuthorizationRequest{
+			Username:            o.Principal,
+			RequiredPermissions: perms,
+		})
+		if err != nil {
+			return err
+		}
+		if authResp.Error != nil {
+			return authResp.Error
+		}
+		if !authResp.Allowed {
+			return auth.ErrInsufficientPermissions
+		}
+		return nil
+	})
+}
+
 type RepoOperation struct {
 	*AuthenticatedOperation
 	Repository *catalog.Repository
@@ -7,6 +7,7 @@ import (
 	"github.com/treeverse/lakefs/logging"
 
 	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/permissions"
@@ -49,10 +50,15 @@ func (controller *DeleteObject) Handle(o *PathOperation) {
 
 	o.Incr("delete_object")
 	lg := o.Log().WithField("key", o.Path)
-	err := o.Cataloger.DeleteEntry(o.Context(), o.Repository.Name, o.Reference, o.Path)
+	ctx := o.ProtectedPathsContext(o.Repository.Name)
+	err := o.Cataloger.DeleteEntry(ctx, o.Repository.Name, o.Reference, o.Path)
 	switch {
 	case errors.Is(err, db.ErrNotFound):
 		lg.WithError(err).Debug("could not delete object, it doesn't exist")
+	case errors.Is(err, catalog.ErrPathProtected):
+		lg.WithError(err).Warn("could not delete protected object")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrAccessDenied))
+		return
 	case err != nil:
 		lg.WithError(err).Error("could not delete object")
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
@@ -1,15 +1,15 @@
 package operations
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/treeverse/lakefs/auth"
-	"github.com/treeverse/lakefs/db"
 	gerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/gateway/path"
 	"github.com/treeverse/lakefs/gateway/serde"
+	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/permissions"
 )
 
@@ -25,10 +25,13 @@ func (controller *DeleteObjects) Handle(o *RepoOperation) {
 	err := DecodeXMLBody(o.Request.Body, req)
 	if err != nil {
 		o.EncodeError(gerrors.Codes.ToAPIErr(gerrors.ErrBadRequest))
+		return
 	}
-	// delete all the files and collect responses
+	// authorize all the files and collect the paths to delete by branch
 	errs := make([]serde.DeleteError, 0)
 	responses := make([]serde.Deleted, 0)
+	branchKeys := make(map[string][]string)
+	branchPaths := make(map[string][]string)
 	for _, obj := range req.Object {
 		resolvedPath, err := path.ResolvePath(obj.Key)
 		if err != nil {
@@ -55,29 +58,38 @@ func (controller *DeleteObjects) Handle(o *RepoOperation) {
 				Key:     obj.Key,
 				Message: "Access Denied",
 			})
+			continue
 		}
+		branchKeys[resolvedPath.Ref] = append(branchKeys[resolvedPath.Ref], obj.Key)
+		branchPaths[resolvedPath.Ref] = append(branchPaths[resolvedPath.Ref], resolvedPath.Path)
+	}
 
-		lg := o.Log().WithField("key", obj.Key)
-		err = o.Cataloger.DeleteEntry(o.Context(), o.Repository.Name, resolvedPath.Ref, resolvedPath.Path)
-		switch {
-		case errors.Is(err, db.ErrNotFound):
-			lg.Debug("tried to delete a non-existent object")
-		case err != nil:
-			lg.WithError(err).Error("failed deleting object")
-			errs = append(errs, serde.DeleteError{
-				Code:    "ErrDeletingKey",
-				Key:     obj.Key,
-				Message: fmt.Sprintf("error deleting object: %s", err),
-			})
+	// delete the files of each branch in a single call
+	for branch, paths := range branchPaths {
+		lg := o.Log().WithFields(logging.Fields{"branch": branch, "paths": len(paths)})
+		ctx := o.ProtectedPathsContext(o.Repository.Name)
+		err := o.Cataloger.DeleteEntries(ctx, o.Repository.Name, branch, paths)
+		if err != nil {
+			lg.WithError(err).Error("failed deleting objects")
+			for _, key := range branchKeys[branch] {
+				errs = append(errs, serde.DeleteError{
+					Code:    "ErrDeletingKey",
+					Key:     key,
+					Message: fmt.Sprintf("error deleting object: %s", err),
+				})
+			}
 			continue
-		default:
-			lg.Debug("object set for deletion")
 		}
+		lg.Debug("objects set for deletion")
 		if !req.Quiet {
-			responses = append(responses, serde.Deleted{Key: obj.Key})
+			for _, key := range branchKeys[branch] {
+				responses = append(responses, serde.Deleted{Key: key})
+			}
 		}
 	}
-	// construct response
+	// construct response - sorted by key, as branches are deleted in no particular order
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Key < errs[j].Key })
+	sort.Slice(responses, func(i, j int) bool { return responses[i].Key < responses[j].Key })
 	resp := serde.DeleteResult{}
 	if len(errs) > 0 {
 		resp.Error = errs
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/treeverse/lakefs/block"
@@ -12,6 +13,7 @@ import (
 	"github.com/treeverse/lakefs/db"
 	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
 	ghttp "github.com/treeverse/lakefs/gateway/http"
+	"github.com/treeverse/lakefs/gateway/path"
 	"github.com/treeverse/lakefs/gateway/serde"
 	"github.com/treeverse/lakefs/httputil"
 	"github.com/treeverse/lakefs/permissions"
@@ -41,6 +43,11 @@ func (controller *GetObject) Handle(o *PathOperation) {
 		return
 	}
 
+	if _, exists := query[QueryParamUploadID]; exists {
+		controller.HandleListParts(o)
+		return
+	}
+
 	beforeMeta := time.Now()
 	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
 	metaTook := time.Since(beforeMeta)
@@ -56,6 +63,7 @@ func (controller *GetObject) Handle(o *PathOperation) {
 	}
 	if errors.Is(err, catalog.ErrExpired) {
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
 	}
 	if err != nil {
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
@@ -65,6 +73,9 @@ func (controller *GetObject) Handle(o *PathOperation) {
 	o.SetHeader("Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader("ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader("Accept-Ranges", "bytes")
+	if entry.ContentType != "" {
+		o.SetHeader(ContentTypeHeader, entry.ContentType)
+	}
 	// TODO: the rest of https://docs.aws.amazon.com/en_pv/AmazonS3/latest/API/API_GetObject.html
 
 	// range query
@@ -84,6 +95,9 @@ func (controller *GetObject) Handle(o *PathOperation) {
 		// assemble a response body (range-less query)
 		expected = entry.Size
 		data, err = o.BlockStore.Get(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, entry.Size)
+		if err == nil && o.VerifyChecksum {
+			data = block.NewVerifyingReader(data, entry.Checksum)
+		}
 	} else {
 		expected = rng.EndOffset - rng.StartOffset + 1 // both range ends are inclusive
 		data, err = o.BlockStore.GetRange(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, rng.StartOffset, rng.EndOffset)
@@ -100,7 +114,74 @@ func (controller *GetObject) Handle(o *PathOperation) {
 		o.SetHeader("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
 	}
 	_, err = io.Copy(o.ResponseWriter, data)
-	if err != nil {
+	if errors.Is(err, block.ErrIntegrity) {
+		o.Log().WithError(err).WithField("physical_address", entry.PhysicalAddress).Error("object content does not match its checksum")
+		// the response is already under way: close the connection so the client sees it fail
+		panic(http.ErrAbortHandler)
+	} else if err != nil {
 		o.Log().WithError(err).Error("could not write response body for object")
 	}
 }
+
+const (
+	QueryParamMaxParts         = "max-parts"
+	QueryParamPartNumberMarker = "part-number-marker"
+)
+
+// HandleListParts lists the parts uploaded to a multipart upload
+// (i.e. https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListParts.html)
+func (controller *GetObject) HandleListParts(o *PathOperation) {
+	o.Incr("list_mpu_parts")
+	query := o.Request.URL.Query()
+	uploadID := query.Get(QueryParamUploadID)
+	maxParts := catalog.MultipartUploadMaxParts
+	if maxPartsStr := query.Get(QueryParamMaxParts); maxPartsStr != "" {
+		n, err := strconv.Atoi(maxPartsStr)
+		if err != nil || n < 0 {
+			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidMaxParts))
+			return
+		}
+		if n < maxParts {
+			maxParts = n
+		}
+	}
+	var partNumberMarker int
+	if markerStr := query.Get(QueryParamPartNumberMarker); markerStr != "" {
+		n, err := strconv.Atoi(markerStr)
+		if err != nil || n < 0 {
+			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidPartNumberMarker))
+			return
+		}
+		partNumberMarker = n
+	}
+
+	parts, hasMore, err := o.Cataloger.ListMultipartUploadParts(o.Context(), o.Repository.Name, uploadID, maxParts, partNumberMarker)
+	if errors.Is(err, db.ErrNotFound) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchUpload))
+		return
+	}
+	if err != nil {
+		o.Log().WithError(err).Error("could not list multipart upload parts")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	res := serde.ListPartsResult{
+		Bucket:           o.Repository.Name,
+		Key:              path.WithRef(o.Path, o.Reference),
+		UploadID:         uploadID,
+		PartNumberMarker: partNumberMarker,
+		MaxParts:         maxParts,
+		IsTruncated:      hasMore,
+		Part:             make([]serde.Part, len(parts)),
+	}
+	for i, part := range parts {
+		res.Part[i] = serde.Part{
+			PartNumber:   part.PartNumber,
+			LastModified: serde.Timestamp(part.CreationDate),
+			ETag:         httputil.ETag(part.ETag),
+			Size:         part.Size,
+		}
+		res.NextPartNumberMarker = part.PartNumber
+	}
+	o.EncodeResponse(res, http.StatusOK)
+}
@@ -3,6 +3,7 @@ package operations
 import (
 	"net/http"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/permissions"
 
 	"github.com/treeverse/lakefs/gateway/errors"
@@ -22,7 +23,7 @@ func (controller *ListBuckets) RequiredPermissions(_ *http.Request) ([]permissio
 
 func (controller *ListBuckets) Handle(o *AuthenticatedOperation) {
 	o.Incr("list_repos")
-	repos, _, err := o.Cataloger.ListRepositories(o.Context(), -1, "")
+	repos, _, err := o.Cataloger.ListRepositories(o.Context(), catalog.ListRepositoriesParams{}, -1, "")
 	if err != nil {
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
 		return
@@ -109,15 +109,6 @@ func (controller *ListObjects) ListV2(o *RepoOperation) {
 
 	maxKeys := controller.getMaxKeys(o)
 
-	// see if this is a recursive call`
-	if len(delimiter) >= 1 {
-		if delimiter != path.Separator {
-			// we only support "/" as a delimiter
-			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
-			return
-		}
-	}
-
 	var results []*catalog.Entry
 	var hasMore bool
 	var ref string
@@ -237,15 +228,7 @@ func (controller *ListObjects) ListV1(o *RepoOperation) {
 	// handle ListObjects (v1)
 	params := o.Request.URL.Query()
 	delimiter := params.Get("delimiter")
-	descend := true
-	if len(delimiter) >= 1 {
-		if delimiter != path.Separator {
-			// we only support "/" as a delimiter
-			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
-			return
-		}
-		descend = false
-	}
+	descend := len(delimiter) == 0
 
 	maxKeys := controller.getMaxKeys(o)
 
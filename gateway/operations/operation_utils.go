@@ -1,30 +1,95 @@
 package operations
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/logging"
 )
 
-func (o *PathOperation) finishUpload(storageNamespace, checksum, physicalAddress string, size int64) error {
+const (
+	// IfNoneMatchHeader set to "*" fails an upload if an object already exists at the path
+	IfNoneMatchHeader = "If-None-Match"
+	// ContentMD5Header holds the base64 encoded MD5 digest of the uploaded content
+	ContentMD5Header  = "Content-MD5"
+	ContentTypeHeader = "Content-Type"
+)
+
+func (o *PathOperation) uploadIfAbsent() bool {
+	return o.Request.Header.Get(IfNoneMatchHeader) == "*"
+}
+
+// uploadChecksum returns the hex encoded MD5 checksum supplied by the client in the Content-MD5
+// header, or an empty string if the header is not set
+func (o *PathOperation) uploadChecksum() (string, error) {
+	contentMD5 := o.Request.Header.Get(ContentMD5Header)
+	if contentMD5 == "" {
+		return "", nil
+	}
+	digest, err := base64.StdEncoding.DecodeString(contentMD5)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest), nil
+}
+
+// finishUploadErrorCode returns the error code reported to the client when finishUpload fails
+func finishUploadErrorCode(err error) gatewayerrors.APIErrorCode {
+	if errors.Is(err, catalog.ErrEntryAlreadyExists) {
+		return gatewayerrors.ErrPreconditionFailed
+	}
+	if errors.Is(err, catalog.ErrRepositoryReadOnly) || errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+		return gatewayerrors.ErrAccessDenied
+	}
+	return gatewayerrors.ErrInternalError
+}
+
+// copyErrorCode returns the error code reported to the client when copying an object fails
+func copyErrorCode(err error) gatewayerrors.APIErrorCode {
+	if errors.Is(err, catalog.ErrEntryNotFound) || errors.Is(err, catalog.ErrExpired) {
+		return gatewayerrors.ErrInvalidCopySource
+	}
+	if errors.Is(err, catalog.ErrPathProtected) || errors.Is(err, catalog.ErrQuotaExceeded) {
+		return gatewayerrors.ErrAccessDenied
+	}
+	return gatewayerrors.ErrInvalidCopyDest
+}
+
+// entryExists returns true if an entry, including an expired one, exists at the operation path
+func (o *PathOperation) entryExists() (bool, error) {
+	_, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{ReturnExpired: true})
+	if errors.Is(err, db.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (o *PathOperation) finishUpload(storageNamespace, checksum, physicalAddress, contentType string, size int64) error {
 	// write metadata
 	writeTime := time.Now()
 	entry := catalog.Entry{
 		Path:            o.Path,
 		PhysicalAddress: physicalAddress,
 		Checksum:        checksum,
+		ContentType:     contentType,
 		Metadata:        nil, // TODO: Read whatever metadata came from the request headers/params and add here
 		Size:            size,
 		CreationDate:    writeTime,
 	}
 
-	err := o.Cataloger.CreateEntry(o.Context(), o.Repository.Name, o.Reference, entry,
+	ctx := o.ProtectedPathsContext(o.Repository.Name)
+	err := o.Cataloger.CreateEntry(ctx, o.Repository.Name, o.Reference, entry,
 		catalog.CreateEntryParams{
 			Dedup: catalog.DedupParams{
 				ID:               checksum,
 				StorageNamespace: storageNamespace,
 			},
+			IfAbsent: o.uploadIfAbsent(),
 		})
 	if err != nil {
 		o.Log().WithError(err).Error("could not update metadata")
@@ -99,9 +99,9 @@ func (controller *PostObject) HandleCompleteMultipartUpload(o *PathOperation) {
 	}
 	ch := trimQuotes(*etag)
 	checksum := strings.Split(ch, "-")[0]
-	err = o.finishUpload(o.Repository.StorageNamespace, checksum, objName, size)
+	err = o.finishUpload(o.Repository.StorageNamespace, checksum, objName, "", size)
 	if err != nil {
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		o.EncodeError(errors.Codes.ToAPIErr(finishUploadErrorCode(err)))
 		return
 	}
 	err = o.Cataloger.DeleteMultipartUpload(o.Context(), o.Repository.Name, uploadID)
@@ -6,7 +6,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
@@ -57,21 +56,12 @@ func (controller *PutObject) HandleCopy(o *PathOperation, copySource string) {
 		return
 	}
 
-	// update metadata to refer to the source hash in the destination workspace
-	ent, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, p.Reference, p.Path, catalog.GetEntryParams{})
+	// write an entry to the destination that refers to the source object
+	ctx := o.ProtectedPathsContext(o.Repository.Name)
+	ent, err := o.Cataloger.CopyEntry(ctx, o.Repository.Name, p.Reference, p.Path, o.Reference, o.Path)
 	if err != nil {
-		o.Log().WithError(err).Error("could not read copy source")
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopySource))
-		return
-	}
-	// write this object to workspace
-	// TODO: move this logic into the Index impl.
-	ent.CreationDate = time.Now()
-	ent.Path = o.Path
-	err = o.Cataloger.CreateEntry(o.Context(), o.Repository.Name, o.Reference, *ent, catalog.CreateEntryParams{})
-	if err != nil {
-		o.Log().WithError(err).Error("could not write copy destination")
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopyDest))
+		o.Log().WithError(err).Error("could not copy object")
+		o.EncodeError(errors.Codes.ToAPIErr(copyErrorCode(err)))
 		return
 	}
 
@@ -114,6 +104,16 @@ func (controller *PutObject) HandleUploadPart(o *PathOperation) {
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
 		return
 	}
+	err = o.Cataloger.CreateMultipartUploadPart(o.Context(), o.Repository.Name, uploadID, catalog.MultipartUploadPart{
+		PartNumber: int(partNumber),
+		ETag:       trimQuotes(etag),
+		Size:       byteSize,
+	})
+	if err != nil {
+		o.Log().WithError(err).Error("could not record multipart upload part")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
 	o.SetHeader("ETag", etag)
 	o.ResponseWriter.WriteHeader(http.StatusOK)
 }
@@ -158,6 +158,26 @@ func (controller *PutObject) Handle(o *PathOperation) {
 	}
 
 	o.Incr("put_object")
+	checksum, err := o.uploadChecksum()
+	if err != nil {
+		o.Log().WithError(err).Debug("invalid content md5")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidDigest))
+		return
+	}
+	// conditional write - fail early before uploading the data, the entry creation verifies it again
+	if o.uploadIfAbsent() {
+		exists, err := o.entryExists()
+		if err != nil {
+			o.Log().WithError(err).Error("could not check if object exists")
+			o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+			return
+		}
+		if exists {
+			o.EncodeError(errors.Codes.ToAPIErr(errors.ErrPreconditionFailed))
+			return
+		}
+	}
+
 	// handle the upload itself
 	blob, err := upload.WriteBlob(o.BlockStore, o.Repository.StorageNamespace, o.Request.Body, o.Request.ContentLength, opts)
 	if err != nil {
@@ -166,10 +186,19 @@ func (controller *PutObject) Handle(o *PathOperation) {
 		return
 	}
 
+	if err := blob.VerifyChecksum(checksum); err != nil {
+		o.Log().WithError(err).Debug("uploaded content does not match content md5")
+		if err := upload.RemoveBlob(o.BlockStore, o.Repository.StorageNamespace, blob); err != nil {
+			o.Log().WithError(err).Warn("could not remove uploaded content that does not match content md5")
+		}
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrBadDigest))
+		return
+	}
+
 	// write metadata
-	err = o.finishUpload(o.Repository.StorageNamespace, blob.Checksum, blob.PhysicalAddress, blob.Size)
+	err = o.finishUpload(o.Repository.StorageNamespace, blob.Checksum, blob.PhysicalAddress, o.Request.Header.Get(ContentTypeHeader), blob.Size)
 	if err != nil {
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		o.EncodeError(errors.Codes.ToAPIErr(finishUploadErrorCode(err)))
 		return
 	}
 	o.SetHeader("ETag", httputil.ETag(blob.Checksum))
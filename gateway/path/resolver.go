@@ -14,7 +14,7 @@ const (
 	Separator = "/"
 
 	rePath      = "(?P<path>.*)"
-	reReference = "(?P<ref>[a-z0-9\\-]+)"
+	reReference = "(?P<ref>[^/]+)" // branch name or commit reference
 )
 
 var (
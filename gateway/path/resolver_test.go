@@ -0,0 +1,35 @@
+package path_test
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/gateway/path"
+)
+
+func TestResolvePath(t *testing.T) {
+	commitRef := catalog.MakeReference("master", 10)
+	tests := []struct {
+		encodedPath string
+		want        path.ResolvedPath
+	}{
+		{encodedPath: "", want: path.ResolvedPath{}},
+		{encodedPath: "master", want: path.ResolvedPath{Ref: "master"}},
+		{encodedPath: "master/", want: path.ResolvedPath{Ref: "master", WithPath: true}},
+		{encodedPath: "master/a/b.csv", want: path.ResolvedPath{Ref: "master", Path: "a/b.csv", WithPath: true}},
+		{encodedPath: "/feature_A/a/b.csv", want: path.ResolvedPath{Ref: "feature_A", Path: "a/b.csv", WithPath: true}},
+		{encodedPath: "master:HEAD/a", want: path.ResolvedPath{Ref: "master:HEAD", Path: "a", WithPath: true}},
+		{encodedPath: commitRef + "/a/b.csv", want: path.ResolvedPath{Ref: commitRef, Path: "a/b.csv", WithPath: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encodedPath, func(t *testing.T) {
+			got, err := path.ResolvePath(tt.encodedPath)
+			if err != nil {
+				t.Fatalf("ResolvePath() error = %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePath() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
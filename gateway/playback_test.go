@@ -134,6 +134,7 @@ func getBasicHandler(t *testing.T, authService *simulator.PlayBackMockConf) (htt
 		authService.BareDomain,
 		&mockCollector{},
 		dedupCleaner,
+		false,
 	)
 
 	return handler, &dependencies{
@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// maxReasonSize is the size of the response body read from a rejecting webhook as the reason
+const maxReasonSize = 1024
+
+func newChanges(differences catalog.Differences) []Change {
+	changes := make([]Change, len(differences))
+	for i, diff := range differences {
+		changes[i] = Change{
+			Type:      diff.Type.String(),
+			Path:      diff.Path,
+			EntryType: diff.EntryType.String(),
+			SizeDelta: diff.SizeDelta,
+		}
+	}
+	return changes
+}
+
+// PreCommit is a catalog.PreCommitHookFunc calling the pre-commit webhooks of the committed
+// branch with the pending changes.  The commit is rejected unless all of them accept it.
+func (d *Dispatcher) PreCommit(ctx context.Context, commit *catalog.PendingCommit) error {
+	return d.check(ctx, d.params.PreCommit, &Payload{
+		EventType:  EventPreCommit,
+		Repository: commit.Repository,
+		Branch:     commit.Branch,
+		Commit: Commit{
+			Committer: commit.Committer,
+			Message:   commit.Message,
+			Metadata:  commit.Metadata,
+		},
+		Changes:          newChanges(commit.Changes),
+		ChangesTruncated: commit.ChangesTruncated,
+	})
+}
+
+// PreMerge is a catalog.PreMergeHookFunc calling the pre-merge webhooks of the destination
+// branch with the merged changes.  The merge is rejected unless all of them accept it.
+func (d *Dispatcher) PreMerge(ctx context.Context, merge *catalog.PendingMerge) error {
+	return d.check(ctx, d.params.PreMerge, &Payload{
+		EventType:    EventPreMerge,
+		Repository:   merge.Repository,
+		Branch:       merge.DestinationBranch,
+		SourceBranch: merge.SourceBranch,
+		Commit: Commit{
+			Committer: merge.Committer,
+			Message:   merge.Message,
+			Metadata:  merge.Metadata,
+		},
+		Changes:          newChanges(merge.Changes),
+		ChangesTruncated: merge.ChangesTruncated,
+	})
+}
+
+func hookName(webhook params.Webhook) string {
+	if webhook.Name != "" {
+		return webhook.Name
+	}
+	return webhook.URL
+}
+
+// check calls the webhooks matching payload one by one, and fails with catalog.ErrHookRejected
+// on the first webhook that does not accept it.  A webhook that cannot be reached rejects.
+func (d *Dispatcher) check(ctx context.Context, webhooks []params.Webhook, payload *Payload) error {
+	var body []byte
+	for _, webhook := range webhooks {
+		if !matches(webhook, payload.EventType, payload.Repository, payload.Branch) {
+			continue
+		}
+		if body == nil {
+			var err error
+			body, err = json.Marshal(payload)
+			if err != nil {
+				return fmt.Errorf("encode %s payload: %w", payload.EventType, err)
+			}
+		}
+		if err := d.call(ctx, webhook.URL, payload.EventType, body); err != nil {
+			d.log.WithContext(ctx).WithError(err).WithFields(logging.Fields{
+				"event":      payload.EventType,
+				"repository": payload.Repository,
+				"branch":     payload.Branch,
+				"hook":       hookName(webhook),
+				"url":        webhook.URL,
+			}).Info("Rejected by webhook")
+			return fmt.Errorf("%w: %s hook '%s': %s", catalog.ErrHookRejected, payload.EventType, hookName(webhook), err)
+		}
+	}
+	return nil
+}
+
+// call posts body to url, returning an error holding the response body if the webhook did not accept it
+func (d *Dispatcher) call(ctx context.Context, url, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if isSuccessStatus(resp.StatusCode) {
+		return nil
+	}
+	reason, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxReasonSize))
+	if msg := strings.TrimSpace(string(reason)); msg != "" {
+		return fmt.Errorf("%w: %s: %s", ErrDeliveryFailed, resp.Status, msg)
+	}
+	return fmt.Errorf("%w: %s", ErrDeliveryFailed, resp.Status)
+}
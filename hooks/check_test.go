@@ -0,0 +1,173 @@
+package hooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/hooks"
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// newParquetValidator returns a pre-commit webhook server rejecting non parquet files under tables/
+func newParquetValidator(t *testing.T, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var payload hooks.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode pre-commit payload: %s", err)
+		}
+		for _, change := range payload.Changes {
+			if change.Type != "removed" && strings.HasPrefix(change.Path, "tables/") && !strings.HasSuffix(change.Path, ".parquet") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(change.Path + " is not a parquet file"))
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDispatcher_PreCommit(t *testing.T) {
+	var calls int32
+	server := newParquetValidator(t, &calls)
+	d, err := hooks.NewDispatcher(nil, params.Hooks{
+		PreCommit: []params.Webhook{
+			{URL: server.URL, Repositories: []string{"repo1"}, Branches: []string{"master"}},
+		},
+		Timeout: time.Second,
+	})
+	testutil.MustDo(t, "new dispatcher", err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	tests := []struct {
+		name       string
+		repository string
+		branch     string
+		changes    catalog.Differences
+		wantCalls  int32
+		wantReject bool
+	}{
+		{
+			name:       "parquet",
+			repository: "repo1",
+			branch:     "master",
+			changes:    catalog.Differences{{Type: catalog.DifferenceTypeAdded, Path: "tables/t1/part1.parquet"}},
+			wantCalls:  1,
+		},
+		{
+			name:       "csv",
+			repository: "repo1",
+			branch:     "master",
+			changes: catalog.Differences{
+				{Type: catalog.DifferenceTypeAdded, Path: "tables/t1/part1.parquet"},
+				{Type: catalog.DifferenceTypeChanged, Path: "tables/t1/part2.csv"},
+			},
+			wantCalls:  1,
+			wantReject: true,
+		},
+		{
+			name:       "other branch",
+			repository: "repo1",
+			branch:     "branch1",
+			changes:    catalog.Differences{{Type: catalog.DifferenceTypeAdded, Path: "tables/t1/part2.csv"}},
+			wantCalls:  0,
+		},
+		{
+			name:       "other repository",
+			repository: "repo2",
+			branch:     "master",
+			changes:    catalog.Differences{{Type: catalog.DifferenceTypeAdded, Path: "tables/t1/part2.csv"}},
+			wantCalls:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			err := d.PreCommit(context.Background(), &catalog.PendingCommit{
+				Repository: tt.repository,
+				Branch:     tt.branch,
+				Committer:  "tester",
+				Message:    "message",
+				Changes:    tt.changes,
+			})
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("webhook called %d times, expected %d", got, tt.wantCalls)
+			}
+			if tt.wantReject {
+				if !errors.Is(err, catalog.ErrHookRejected) {
+					t.Fatalf("PreCommit() error = %v, expected %s", err, catalog.ErrHookRejected)
+				}
+				if !strings.Contains(err.Error(), "tables/t1/part2.csv is not a parquet file") {
+					t.Errorf("PreCommit() error = %s, expected the webhook reason", err)
+				}
+			} else if err != nil {
+				t.Fatalf("PreCommit() error = %s, expected none", err)
+			}
+		})
+	}
+}
+
+func TestDispatcher_PreCommitUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	d, err := hooks.NewDispatcher(nil, params.Hooks{
+		PreCommit: []params.Webhook{{URL: server.URL}},
+		Timeout:   time.Second,
+	})
+	testutil.MustDo(t, "new dispatcher", err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	err = d.PreCommit(context.Background(), &catalog.PendingCommit{Repository: "repo1", Branch: "master"})
+	if !errors.Is(err, catalog.ErrHookRejected) {
+		t.Fatalf("PreCommit() error = %v, expected %s", err, catalog.ErrHookRejected)
+	}
+}
+
+func TestDispatcher_PreMerge(t *testing.T) {
+	var calls int32
+	server := newParquetValidator(t, &calls)
+	d, err := hooks.NewDispatcher(nil, params.Hooks{
+		PreMerge: []params.Webhook{
+			{Name: "parquet-only", URL: server.URL, Branches: []string{"master"}},
+		},
+		Timeout: time.Second,
+	})
+	testutil.MustDo(t, "new dispatcher", err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	changes := catalog.Differences{{Type: catalog.DifferenceTypeAdded, Path: "tables/t1/part2.csv"}}
+	err = d.PreMerge(context.Background(), &catalog.PendingMerge{
+		Repository:        "repo1",
+		SourceBranch:      "master",
+		DestinationBranch: "branch1",
+		Changes:           changes,
+	})
+	if err != nil {
+		t.Fatalf("PreMerge() into unprotected branch error = %s, expected none", err)
+	}
+	err = d.PreMerge(context.Background(), &catalog.PendingMerge{
+		Repository:        "repo1",
+		SourceBranch:      "branch1",
+		DestinationBranch: "master",
+		Changes:           changes,
+	})
+	if !errors.Is(err, catalog.ErrHookRejected) {
+		t.Fatalf("PreMerge() error = %v, expected %s", err, catalog.ErrHookRejected)
+	}
+	if !strings.Contains(err.Error(), "'parquet-only'") {
+		t.Errorf("PreMerge() error = %s, expected the failed gate name", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("webhook called %d times, expected 1", got)
+	}
+}
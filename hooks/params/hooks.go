@@ -0,0 +1,31 @@
+package params
+
+import "time"
+
+type Webhook struct {
+	// Name identifies the webhook in errors, defaults to its URL
+	Name string `mapstructure:"name"`
+	// URL receives a POST request with a JSON payload for each event
+	URL string `mapstructure:"url"`
+	// Events the webhook is called for, all events if empty
+	Events []string `mapstructure:"events"`
+	// Repositories the webhook is called for, all repositories if empty
+	Repositories []string `mapstructure:"repositories"`
+	// Branches the webhook is called for, all branches if empty
+	Branches []string `mapstructure:"branches"`
+}
+
+type Hooks struct {
+	Webhooks []Webhook
+	// PreCommit webhooks are called before each commit, and reject it by failing
+	PreCommit []Webhook
+	// PreMerge webhooks are called before each merge into their branches, and reject it by failing
+	PreMerge []Webhook
+	// Timeout of a single delivery attempt
+	Timeout time.Duration
+	// MaxAttempts to deliver an event before it is marked as failed
+	MaxAttempts int
+	// InitialBackoff between delivery attempts, doubled on each retry up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
@@ -0,0 +1,406 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	EventPreCommit  = "pre-commit"
+	EventPreMerge   = "pre-merge"
+	EventPostCommit = "post-commit"
+	EventPostMerge  = "post-merge"
+
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+
+	EventHeader = "X-LakeFS-Event"
+
+	dispatchQueueSize = 1000
+	dispatchWorkers   = 4
+)
+
+var (
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	ErrQueueFull      = errors.New("delivery queue full")
+	ErrDeliveryFailed = errors.New("webhook responded with an error status")
+	ErrNotConfigured  = errors.New("webhook no longer configured")
+)
+
+// Commit is the commit part of a webhook payload
+type Commit struct {
+	Reference    string            `json:"reference,omitempty"`
+	Committer    string            `json:"committer"`
+	Message      string            `json:"message"`
+	CreationDate *time.Time        `json:"creation_date,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Parents      []string          `json:"parents,omitempty"`
+}
+
+// Change is a single changed path in a webhook payload
+type Change struct {
+	Type      string `json:"type"`
+	Path      string `json:"path"`
+	EntryType string `json:"entry_type"`
+	SizeDelta int64  `json:"size_delta"`
+}
+
+// Payload is the JSON body posted to webhooks
+type Payload struct {
+	EventType        string   `json:"event_type"`
+	Repository       string   `json:"repository"`
+	Branch           string   `json:"branch"`
+	SourceBranch     string   `json:"source_branch,omitempty"`
+	Commit           Commit   `json:"commit"`
+	Changes          []Change `json:"changes,omitempty"`
+	ChangesTruncated bool     `json:"changes_truncated,omitempty"`
+}
+
+// Delivery is the record of sending a single event to a single webhook
+type Delivery struct {
+	ID         int64     `db:"id"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+	WebhookURL string    `db:"webhook_url"`
+	Event      string    `db:"event"`
+	Repository string    `db:"repository"`
+	Payload    string    `db:"payload"`
+	Status     string    `db:"status"`
+	Attempts   int       `db:"attempts"`
+	StatusCode int       `db:"status_code"`
+	LastError  string    `db:"last_error"`
+}
+
+// Dispatcher calls the configured webhooks after commits and merges.  Its PostCommit and
+// PostMerge methods are catalog hooks.  Each delivery is recorded and sent in the background,
+// retrying with exponential backoff until it succeeds or runs out of attempts.  Deliveries left
+// pending by a previous run are resumed when the dispatcher is created.
+type Dispatcher struct {
+	db     db.Database
+	params params.Hooks
+	client *http.Client
+	queue  chan *Delivery
+	done   chan struct{}
+	wg     sync.WaitGroup
+	log    logging.Logger
+}
+
+func NewDispatcher(database db.Database, p params.Hooks) (*Dispatcher, error) {
+	if err := validateWebhooks(p.Webhooks, EventPostCommit, EventPostMerge); err != nil {
+		return nil, err
+	}
+	if err := validateWebhooks(p.PreCommit); err != nil {
+		return nil, err
+	}
+	if err := validateWebhooks(p.PreMerge); err != nil {
+		return nil, err
+	}
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	d := &Dispatcher{
+		db:     database,
+		params: p,
+		client: &http.Client{Timeout: p.Timeout},
+		queue:  make(chan *Delivery, dispatchQueueSize),
+		done:   make(chan struct{}),
+		log:    logging.Default().WithField("service_name", "hooks"),
+	}
+	var pending []*Delivery
+	if len(p.Webhooks) > 0 {
+		var err error
+		pending, err = d.loadPending()
+		if err != nil {
+			// the database may not be migrated yet, new deliveries are still sent
+			d.log.WithError(err).Warn("Failed to load pending webhook deliveries")
+		}
+	}
+	d.wg.Add(dispatchWorkers + 1)
+	for i := 0; i < dispatchWorkers; i++ {
+		go d.worker()
+	}
+	go d.resume(pending)
+	return d, nil
+}
+
+// loadPending returns the deliveries left pending by a previous run, oldest first
+func (d *Dispatcher) loadPending() ([]*Delivery, error) {
+	res, err := d.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var deliveries []*Delivery
+		err := tx.Select(&deliveries, `SELECT id, created_at, updated_at, webhook_url, event, repository, payload, status, attempts, status_code, last_error
+			FROM hooks_deliveries
+			WHERE status = $1
+			ORDER BY id`, DeliveryStatusPending)
+		return deliveries, err
+	}, db.ReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*Delivery), nil
+}
+
+// resume queues the pending deliveries to webhooks that are still configured for their event,
+// and fails the rest.  Resumed deliveries keep their attempts count.
+func (d *Dispatcher) resume(pending []*Delivery) {
+	defer d.wg.Done()
+	for _, delivery := range pending {
+		if !d.isConfigured(delivery) {
+			d.updateDelivery(delivery, DeliveryStatusFailed, delivery.StatusCode, ErrNotConfigured)
+			continue
+		}
+		select {
+		case d.queue <- delivery:
+		case <-d.done:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) isConfigured(delivery *Delivery) bool {
+	for _, webhook := range d.params.Webhooks {
+		if webhook.URL == delivery.WebhookURL && contains(webhook.Events, delivery.Event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops delivering events.  Deliveries in progress are left pending, and are resumed by
+// the next dispatcher.
+func (d *Dispatcher) Close() error {
+	close(d.done)
+	d.wg.Wait()
+	return nil
+}
+
+// PostCommit is a catalog.PostCommitHookFunc dispatching post-commit events
+func (d *Dispatcher) PostCommit(ctx context.Context, repository, branch string, commitLog *catalog.CommitLog) {
+	d.dispatch(ctx, &Payload{
+		EventType:  EventPostCommit,
+		Repository: repository,
+		Branch:     branch,
+		Commit:     newCommit(commitLog),
+	})
+}
+
+// PostMerge is a catalog.PostMergeHookFunc dispatching post-merge events
+func (d *Dispatcher) PostMerge(ctx context.Context, repository, sourceBranch, destinationBranch string, commitLog *catalog.CommitLog) {
+	d.dispatch(ctx, &Payload{
+		EventType:    EventPostMerge,
+		Repository:   repository,
+		Branch:       destinationBranch,
+		SourceBranch: sourceBranch,
+		Commit:       newCommit(commitLog),
+	})
+}
+
+func newCommit(commitLog *catalog.CommitLog) Commit {
+	creationDate := commitLog.CreationDate
+	return Commit{
+		Reference:    commitLog.Reference,
+		Committer:    commitLog.Committer,
+		Message:      commitLog.Message,
+		CreationDate: &creationDate,
+		Metadata:     commitLog.Metadata,
+		Parents:      commitLog.Parents,
+	}
+}
+
+// validateWebhooks checks the webhooks URLs, and that their events are in allowedEvents
+func validateWebhooks(webhooks []params.Webhook, allowedEvents ...string) error {
+	for _, webhook := range webhooks {
+		if _, err := url.ParseRequestURI(webhook.URL); err != nil {
+			return fmt.Errorf("%w: url '%s': %s", ErrInvalidWebhook, webhook.URL, err)
+		}
+		for _, event := range webhook.Events {
+			if len(allowedEvents) == 0 || !contains(allowedEvents, event) {
+				return fmt.Errorf("%w: url '%s': unsupported event '%s'", ErrInvalidWebhook, webhook.URL, event)
+			}
+		}
+	}
+	return nil
+}
+
+func matches(webhook params.Webhook, event, repository, branch string) bool {
+	return contains(webhook.Events, event) &&
+		contains(webhook.Repositories, repository) &&
+		contains(webhook.Branches, branch)
+}
+
+// contains reports whether values holds s, an empty values holds everything
+func contains(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, payload *Payload) {
+	log := d.log.WithContext(ctx).WithFields(logging.Fields{
+		"event":      payload.EventType,
+		"repository": payload.Repository,
+		"reference":  payload.Commit.Reference,
+	})
+	var body []byte
+	for _, webhook := range d.params.Webhooks {
+		if !matches(webhook, payload.EventType, payload.Repository, payload.Branch) {
+			continue
+		}
+		if body == nil {
+			var err error
+			body, err = json.Marshal(payload)
+			if err != nil {
+				log.WithError(err).Error("Failed to encode webhook payload")
+				return
+			}
+		}
+		delivery := &Delivery{
+			WebhookURL: webhook.URL,
+			Event:      payload.EventType,
+			Repository: payload.Repository,
+			Payload:    string(body),
+			Status:     DeliveryStatusPending,
+		}
+		if err := d.insertDelivery(ctx, delivery); err != nil {
+			log.WithError(err).WithField("url", webhook.URL).Error("Failed to record webhook delivery")
+			continue
+		}
+		select {
+		case d.queue <- delivery:
+		default:
+			log.WithField("url", webhook.URL).Warn("Webhook delivery queue full")
+			d.updateDelivery(delivery, DeliveryStatusFailed, 0, ErrQueueFull)
+		}
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for {
+		select {
+		case delivery := <-d.queue:
+			d.deliver(delivery)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(delivery *Delivery) {
+	backoff := d.params.InitialBackoff
+	for {
+		statusCode, err := d.post(delivery)
+		delivery.Attempts++
+		status := DeliveryStatusPending
+		switch {
+		case err == nil:
+			status = DeliveryStatusDelivered
+		case delivery.Attempts >= d.params.MaxAttempts:
+			status = DeliveryStatusFailed
+		}
+		d.updateDelivery(delivery, status, statusCode, err)
+		if status != DeliveryStatusPending {
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-d.done:
+			return
+		}
+		backoff *= 2
+		if d.params.MaxBackoff > 0 && backoff > d.params.MaxBackoff {
+			backoff = d.params.MaxBackoff
+		}
+	}
+}
+
+func (d *Dispatcher) post(delivery *Delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.WebhookURL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	if !isSuccessStatus(resp.StatusCode) {
+		return resp.StatusCode, fmt.Errorf("%w: %s", ErrDeliveryFailed, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
+
+func (d *Dispatcher) insertDelivery(ctx context.Context, delivery *Delivery) error {
+	_, err := d.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return nil, tx.Get(delivery, `INSERT INTO hooks_deliveries (webhook_url, event, repository, payload, status)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at, updated_at, webhook_url, event, repository, payload, status, attempts, status_code, last_error`,
+			delivery.WebhookURL, delivery.Event, delivery.Repository, delivery.Payload, delivery.Status)
+	}, db.WithContext(ctx))
+	return err
+}
+
+func (d *Dispatcher) updateDelivery(delivery *Delivery, status string, statusCode int, deliveryErr error) {
+	lastError := ""
+	if deliveryErr != nil {
+		lastError = deliveryErr.Error()
+	}
+	delivery.Status = status
+	delivery.StatusCode = statusCode
+	delivery.LastError = lastError
+	_, err := d.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`UPDATE hooks_deliveries SET status = $2, attempts = $3, status_code = $4, last_error = $5, updated_at = now()
+			WHERE id = $1`,
+			delivery.ID, status, delivery.Attempts, statusCode, lastError)
+	})
+	if err != nil {
+		d.log.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to update webhook delivery")
+	}
+}
+
+// ListDeliveries returns up to limit delivery records with id greater than after, oldest
+// first.  The returned bool reports whether more records are available.
+func (d *Dispatcher) ListDeliveries(ctx context.Context, limit int, after int64) ([]*Delivery, bool, error) {
+	res, err := d.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var deliveries []*Delivery
+		err := tx.Select(&deliveries, `SELECT id, created_at, updated_at, webhook_url, event, repository, payload, status, attempts, status_code, last_error
+			FROM hooks_deliveries
+			WHERE id > $1
+			ORDER BY id
+			LIMIT $2`, after, limit+1)
+		return deliveries, err
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, false, err
+	}
+	deliveries := res.([]*Delivery)
+	hasMore := len(deliveries) > limit
+	if hasMore {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, hasMore, nil
+}
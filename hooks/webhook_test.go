@@ -0,0 +1,215 @@
+package hooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/hooks"
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	failures int
+	payloads []hooks.Payload
+}
+
+// newWebhookServer returns a webhook server that fails the first failures requests
+func newWebhookServer(t *testing.T, failures int) *webhookServer {
+	s := &webhookServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read webhook body: %s", err)
+		}
+		var payload hooks.Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("decode webhook payload: %s", err)
+		}
+		if r.Header.Get(hooks.EventHeader) != payload.EventType {
+			t.Errorf("webhook event header %s, expected %s", r.Header.Get(hooks.EventHeader), payload.EventType)
+		}
+		s.payloads = append(s.payloads, payload)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) Payloads() []hooks.Payload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]hooks.Payload(nil), s.payloads...)
+}
+
+func waitForDeliveries(t *testing.T, d *hooks.Dispatcher, count int) []*hooks.Delivery {
+	t.Helper()
+	ctx := context.Background()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		deliveries, _, err := d.ListDeliveries(ctx, 100, 0)
+		testutil.MustDo(t, "list deliveries", err)
+		done := 0
+		for _, delivery := range deliveries {
+			if delivery.Status != hooks.DeliveryStatusPending {
+				done++
+			}
+		}
+		if done == count && len(deliveries) == count {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d deliveries, got %+v", count, deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testDispatcher(t *testing.T, webhooks []params.Webhook, maxAttempts int) *hooks.Dispatcher {
+	t.Helper()
+	cdb, _ := testutil.GetDB(t, databaseURI)
+	d, err := hooks.NewDispatcher(cdb, params.Hooks{
+		Webhooks:       webhooks,
+		Timeout:        time.Second,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+	testutil.MustDo(t, "new dispatcher", err)
+	t.Cleanup(func() { _ = d.Close() })
+	return d
+}
+
+func TestDispatcher_PostCommit(t *testing.T) {
+	server := newWebhookServer(t, 2)
+	other := newWebhookServer(t, 0)
+	d := testDispatcher(t, []params.Webhook{
+		{URL: server.URL, Events: []string{hooks.EventPostCommit}},
+		{URL: other.URL, Events: []string{hooks.EventPostMerge}},
+	}, 3)
+
+	commitLog := &catalog.CommitLog{
+		Reference: "~ref1",
+		Committer: "tester",
+		Message:   "message",
+		Metadata:  catalog.Metadata{"key": "value"},
+		Parents:   []string{"~ref0"},
+	}
+	d.PostCommit(context.Background(), "repo1", "master", commitLog)
+
+	deliveries := waitForDeliveries(t, d, 1)
+	if deliveries[0].Status != hooks.DeliveryStatusDelivered || deliveries[0].Attempts != 3 {
+		t.Fatalf("delivery status %s after %d attempts, expected %s after 3", deliveries[0].Status, deliveries[0].Attempts, hooks.DeliveryStatusDelivered)
+	}
+	payloads := server.Payloads()
+	if len(payloads) != 1 {
+		t.Fatalf("webhook got %d payloads, expected 1", len(payloads))
+	}
+	p := payloads[0]
+	if p.EventType != hooks.EventPostCommit || p.Repository != "repo1" || p.Branch != "master" ||
+		p.Commit.Reference != "~ref1" || p.Commit.Metadata["key"] != "value" {
+		t.Errorf("unexpected payload %+v", p)
+	}
+	if len(other.Payloads()) != 0 {
+		t.Errorf("post-merge webhook called on commit")
+	}
+}
+
+func TestDispatcher_Failed(t *testing.T) {
+	server := newWebhookServer(t, 100)
+	d := testDispatcher(t, []params.Webhook{
+		{URL: server.URL, Repositories: []string{"repo1"}},
+	}, 2)
+
+	commitLog := &catalog.CommitLog{Reference: "~ref1"}
+	d.PostMerge(context.Background(), "repo2", "branch1", "master", commitLog)
+	d.PostMerge(context.Background(), "repo1", "branch1", "master", commitLog)
+
+	deliveries := waitForDeliveries(t, d, 1)
+	delivery := deliveries[0]
+	if delivery.Repository != "repo1" || delivery.Event != hooks.EventPostMerge {
+		t.Errorf("unexpected delivery %+v", delivery)
+	}
+	if delivery.Status != hooks.DeliveryStatusFailed || delivery.Attempts != 2 || delivery.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("delivery status %s after %d attempts with status code %d, expected %s after 2 with %d",
+			delivery.Status, delivery.Attempts, delivery.StatusCode, hooks.DeliveryStatusFailed, http.StatusServiceUnavailable)
+	}
+}
+
+func TestNewDispatcher_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook params.Webhook
+	}{
+		{name: "url", webhook: params.Webhook{URL: "not a url"}},
+		{name: "event", webhook: params.Webhook{URL: "http://example.com", Events: []string{"pre-commit"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := hooks.NewDispatcher(nil, params.Hooks{Webhooks: []params.Webhook{tt.webhook}})
+			if !errors.Is(err, hooks.ErrInvalidWebhook) {
+				t.Errorf("NewDispatcher() error = %v, expected %s", err, hooks.ErrInvalidWebhook)
+			}
+			_, err = hooks.NewDispatcher(nil, params.Hooks{PreCommit: []params.Webhook{tt.webhook}})
+			if !errors.Is(err, hooks.ErrInvalidWebhook) {
+				t.Errorf("NewDispatcher() error = %v, expected %s", err, hooks.ErrInvalidWebhook)
+			}
+		})
+	}
+}
+
+func TestDispatcher_ResumePending(t *testing.T) {
+	cdb, _ := testutil.GetDB(t, databaseURI)
+	server := newWebhookServer(t, 1)
+	p := params.Hooks{
+		Webhooks:       []params.Webhook{{URL: server.URL}},
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+	}
+
+	// the first attempt fails, and the delivery is pending for its next attempt when closed
+	d, err := hooks.NewDispatcher(cdb, p)
+	testutil.MustDo(t, "new dispatcher", err)
+	d.PostCommit(context.Background(), "repo1", "master", &catalog.CommitLog{Reference: "~ref1"})
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		deliveries, _, err := d.ListDeliveries(context.Background(), 100, 0)
+		testutil.MustDo(t, "list deliveries", err)
+		if len(deliveries) == 1 && deliveries[0].Attempts == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for first attempt, got %+v", deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	testutil.MustDo(t, "close dispatcher", d.Close())
+
+	d, err = hooks.NewDispatcher(cdb, p)
+	testutil.MustDo(t, "new dispatcher", err)
+	t.Cleanup(func() { _ = d.Close() })
+	deliveries := waitForDeliveries(t, d, 1)
+	if deliveries[0].Status != hooks.DeliveryStatusDelivered || deliveries[0].Attempts != 2 {
+		t.Fatalf("delivery status %s after %d attempts, expected %s after 2", deliveries[0].Status, deliveries[0].Attempts, hooks.DeliveryStatusDelivered)
+	}
+	if payloads := server.Payloads(); len(payloads) != 1 || payloads[0].Commit.Reference != "~ref1" {
+		t.Errorf("webhook got payloads %+v, expected the resumed commit", payloads)
+	}
+}
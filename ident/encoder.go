@@ -0,0 +1,96 @@
+// Package ident writes values in a canonical byte encoding for hashing.  The encoding of a value
+// never changes, so a hash computed from it identifies the same content on every version of
+// lakeFS.  See design/canonical_serialization.md.
+package ident
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"time"
+)
+
+// Identifiable is a model that has a content address: it writes its type tag, encoding version
+// and fields in a fixed order
+type Identifiable interface {
+	Identity(e *Encoder)
+}
+
+// Encoder writes values to a hash in the canonical encoding
+type Encoder struct {
+	h   hash.Hash
+	buf [binary.MaxVarintLen64]byte
+}
+
+func NewEncoder(h hash.Hash) *Encoder {
+	return &Encoder{h: h}
+}
+
+// uvarint writes v as an unsigned varint
+func (e *Encoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.buf[:], v)
+	_, _ = e.h.Write(e.buf[:n])
+}
+
+// String writes the length of s as a uvarint followed by its bytes
+func (e *Encoder) String(s string) {
+	e.uvarint(uint64(len(s)))
+	_, _ = e.h.Write([]byte(s))
+}
+
+// Int64 writes v as 8 bytes, big endian
+func (e *Encoder) Int64(v int64) {
+	binary.BigEndian.PutUint64(e.buf[:8], uint64(v))
+	_, _ = e.h.Write(e.buf[:8])
+}
+
+// Byte writes b as is.  Used for the encoding version that follows the type tag of a model.
+func (e *Encoder) Byte(b byte) {
+	_, _ = e.h.Write([]byte{b})
+}
+
+// Bool writes v as a single byte, 0 or 1
+func (e *Encoder) Bool(v bool) {
+	var b byte
+	if v {
+		b = 1
+	}
+	e.Byte(b)
+}
+
+// Time writes t as Int64 of its UTC Unix time in nanoseconds
+func (e *Encoder) Time(t time.Time) {
+	e.Int64(t.UTC().UnixNano())
+}
+
+// Map writes the number of entries as a uvarint, followed by key and value pairs sorted by key
+// bytes
+func (e *Encoder) Map(m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	e.uvarint(uint64(len(keys)))
+	for _, k := range keys {
+		e.String(k)
+		e.String(m[k])
+	}
+}
+
+// Strings writes the number of strings as a uvarint, followed by each string in order
+func (e *Encoder) Strings(s []string) {
+	e.uvarint(uint64(len(s)))
+	for _, v := range s {
+		e.String(v)
+	}
+}
+
+// ContentAddress returns the hex encoded SHA-256 of the canonical encoding of obj
+func ContentAddress(obj Identifiable) string {
+	h := sha256.New()
+	obj.Identity(NewEncoder(h))
+	return hex.EncodeToString(h.Sum(nil))
+}
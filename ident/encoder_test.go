@@ -0,0 +1,91 @@
+package ident_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/ident"
+)
+
+type testModel struct {
+	Name     string
+	Size     int64
+	Deleted  bool
+	Date     time.Time
+	Metadata map[string]string
+	Parents  []string
+}
+
+func (m testModel) Identity(e *ident.Encoder) {
+	e.String("test")
+	e.Byte(1)
+	e.String(m.Name)
+	e.Int64(m.Size)
+	e.Bool(m.Deleted)
+	e.Time(m.Date)
+	e.Map(m.Metadata)
+	e.Strings(m.Parents)
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(e *ident.Encoder)
+		want string
+	}{
+		{name: "string", fn: func(e *ident.Encoder) { e.String("abc") }, want: "03616263"},
+		{name: "empty string", fn: func(e *ident.Encoder) { e.String("") }, want: "00"},
+		{name: "int64", fn: func(e *ident.Encoder) { e.Int64(258) }, want: "0000000000000102"},
+		{name: "negative int64", fn: func(e *ident.Encoder) { e.Int64(-1) }, want: "ffffffffffffffff"},
+		{name: "bool", fn: func(e *ident.Encoder) { e.Bool(true); e.Bool(false) }, want: "0100"},
+		{name: "time", fn: func(e *ident.Encoder) { e.Time(time.Unix(1, 0).In(time.FixedZone("X", 3600))) }, want: "000000003b9aca00"},
+		{name: "map sorted", fn: func(e *ident.Encoder) { e.Map(map[string]string{"b": "2", "a": "1"}) }, want: "020161013101620132"},
+		{name: "strings in order", fn: func(e *ident.Encoder) { e.Strings([]string{"b", "a"}) }, want: "0201620161"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &recorder{}
+			tt.fn(ident.NewEncoder(h))
+			if got := hex.EncodeToString(h.data); got != tt.want {
+				t.Fatalf("encoding = %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentAddress(t *testing.T) {
+	m := testModel{
+		Name:     "name",
+		Size:     10,
+		Date:     time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC),
+		Metadata: map[string]string{"key": "value"},
+		Parents:  []string{"p1", "p2"},
+	}
+	got := ident.ContentAddress(m)
+	// golden value - a change to the encoding changes every stored address
+	const want = "581e49119696b5914c3694a0a522701459e0bb442ea0f341cbd75c8e6ba3f16f"
+	if got != want {
+		t.Fatalf("ContentAddress() = %s, expected %s", got, want)
+	}
+}
+
+// recorder is a hash.Hash that keeps the bytes written to it
+type recorder struct {
+	data []byte
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.data = append(r.data, p...)
+	return len(p), nil
+}
+
+func (r *recorder) Sum(b []byte) []byte {
+	s := sha256.Sum256(r.data)
+	return append(b, s[:]...)
+}
+
+func (r *recorder) Reset()         { r.data = nil }
+func (r *recorder) Size() int      { return sha256.Size }
+func (r *recorder) BlockSize() int { return sha256.BlockSize }
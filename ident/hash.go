@@ -0,0 +1,100 @@
+package ident
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm names a hash function content addresses are computed with
+type Algorithm string
+
+const (
+	AlgorithmSHA256  Algorithm = "sha256"
+	AlgorithmBLAKE2b Algorithm = "blake2b"
+
+	// DefaultAlgorithm addresses are written without an algorithm prefix, so addresses
+	// computed before algorithms were configurable keep their value
+	DefaultAlgorithm = AlgorithmSHA256
+
+	addressSeparator = ":"
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown hash algorithm")
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[Algorithm]func() hash.Hash{
+		AlgorithmSHA256: sha256.New,
+		AlgorithmBLAKE2b: func() hash.Hash {
+			h, _ := blake2b.New256(nil) // fails only for keys longer than 64 bytes
+			return h
+		},
+	}
+)
+
+// RegisterAlgorithm makes the hash function newHash available as algorithm, replacing any
+// function registered under the same name
+func RegisterAlgorithm(algorithm Algorithm, newHash func() hash.Hash) {
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	algorithms[algorithm] = newHash
+}
+
+// IsKnownAlgorithm returns true if algorithm is registered
+func IsKnownAlgorithm(algorithm Algorithm) bool {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	_, ok := algorithms[algorithm]
+	return ok
+}
+
+func newHash(algorithm Algorithm) (hash.Hash, error) {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	fn, ok := algorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, algorithm)
+	}
+	return fn(), nil
+}
+
+// Address returns the content address of obj computed with algorithm: the hex encoded hash of
+// its canonical encoding, prefixed by "<algorithm>:" unless algorithm is the DefaultAlgorithm.
+// Addresses of different algorithms never compare equal.
+func Address(algorithm Algorithm, obj Identifiable) (string, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	obj.Identity(NewEncoder(h))
+	digest := hex.EncodeToString(h.Sum(nil))
+	if algorithm == DefaultAlgorithm {
+		return digest, nil
+	}
+	return string(algorithm) + addressSeparator + digest, nil
+}
+
+// AddressAlgorithm returns the algorithm address was computed with
+func AddressAlgorithm(address string) Algorithm {
+	if i := strings.Index(address, addressSeparator); i >= 0 {
+		return Algorithm(address[:i])
+	}
+	return DefaultAlgorithm
+}
+
+// Verify recomputes the address of obj with the algorithm of address, and returns true if it
+// matches
+func Verify(address string, obj Identifiable) (bool, error) {
+	computed, err := Address(AddressAlgorithm(address), obj)
+	if err != nil {
+		return false, err
+	}
+	return computed == address, nil
+}
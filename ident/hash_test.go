@@ -0,0 +1,78 @@
+package ident_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/ident"
+)
+
+func TestAddress(t *testing.T) {
+	m := testModel{
+		Name:     "name",
+		Size:     10,
+		Date:     time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC),
+		Metadata: map[string]string{"key": "value"},
+		Parents:  []string{"p1", "p2"},
+	}
+	sha, err := ident.Address(ident.AlgorithmSHA256, m)
+	if err != nil {
+		t.Fatalf("Address(sha256) err=%s", err)
+	}
+	if sha != ident.ContentAddress(m) {
+		t.Errorf("Address(sha256) = %s, expected the ContentAddress %s", sha, ident.ContentAddress(m))
+	}
+
+	blake, err := ident.Address(ident.AlgorithmBLAKE2b, m)
+	if err != nil {
+		t.Fatalf("Address(blake2b) err=%s", err)
+	}
+	if !strings.HasPrefix(blake, "blake2b:") || len(blake) != len("blake2b:")+64 {
+		t.Errorf("Address(blake2b) = %s, expected a prefixed 256 bit digest", blake)
+	}
+	if algorithm := ident.AddressAlgorithm(blake); algorithm != ident.AlgorithmBLAKE2b {
+		t.Errorf("AddressAlgorithm(%s) = %s, expected %s", blake, algorithm, ident.AlgorithmBLAKE2b)
+	}
+	if algorithm := ident.AddressAlgorithm(sha); algorithm != ident.AlgorithmSHA256 {
+		t.Errorf("AddressAlgorithm(%s) = %s, expected %s", sha, algorithm, ident.AlgorithmSHA256)
+	}
+
+	for _, address := range []string{sha, blake} {
+		if ok, err := ident.Verify(address, m); err != nil || !ok {
+			t.Errorf("Verify(%s) = %t, %v, expected to match", address, ok, err)
+		}
+	}
+	m.Size++
+	if ok, _ := ident.Verify(blake, m); ok {
+		t.Errorf("Verify(%s) of a changed model matched", blake)
+	}
+
+	if _, err := ident.Address("md4", m); !errors.Is(err, ident.ErrUnknownAlgorithm) {
+		t.Errorf("Address(md4) err=%v, expected %v", err, ident.ErrUnknownAlgorithm)
+	}
+	if _, err := ident.Verify("md4:00", m); !errors.Is(err, ident.ErrUnknownAlgorithm) {
+		t.Errorf("Verify(md4:00) err=%v, expected %v", err, ident.ErrUnknownAlgorithm)
+	}
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	const algorithm = ident.Algorithm("test-recorder")
+	if ident.IsKnownAlgorithm(algorithm) {
+		t.Fatalf("%s known before it is registered", algorithm)
+	}
+	ident.RegisterAlgorithm(algorithm, func() hash.Hash { return &recorder{} })
+	if !ident.IsKnownAlgorithm(algorithm) {
+		t.Fatalf("%s not known after it is registered", algorithm)
+	}
+	address, err := ident.Address(algorithm, testModel{Name: "name"})
+	if err != nil {
+		t.Fatalf("Address(%s) err=%s", algorithm, err)
+	}
+	if !strings.HasPrefix(address, string(algorithm)+":") || len(address) != len(algorithm)+1+2*sha256.Size {
+		t.Errorf("Address(%s) = %s, expected the prefixed recorder sum", algorithm, address)
+	}
+}
@@ -76,6 +76,7 @@ func TestLocalLoad(t *testing.T) {
 		retentionService,
 		migrator,
 		dedupCleaner,
+		false,
 		logging.Default(),
 	)
 
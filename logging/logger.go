@@ -51,8 +51,14 @@ type logrusEntryWrapper struct {
 	e *logrus.Entry
 }
 
+// WithContext returns a logger that also logs the fields added to ctx by AddFields - e.g. the
+// request id and user of the request that ctx belongs to
 func (l *logrusEntryWrapper) WithContext(ctx context.Context) Logger {
-	return &logrusEntryWrapper{l.e.WithContext(ctx)}
+	e := l.e.WithContext(ctx)
+	if fields, ok := ctx.Value(LogFieldsContextKey).(Fields); ok {
+		e = e.WithFields(logrus.Fields(fields))
+	}
+	return &logrusEntryWrapper{e}
 }
 
 func (l *logrusEntryWrapper) WithField(key string, value interface{}) Logger {
@@ -63,6 +63,8 @@ func worker(wg *sync.WaitGroup, tasks <-chan *task) {
 }
 
 func (c *CatalogRepoActions) ApplyImport(ctx context.Context, it Iterator, dryRun bool) (*Stats, error) {
+	// imported entries keep pointing to the objects in the imported bucket
+	ctx = catalog.WithExternalAddresses(ctx)
 	var stats Stats
 	var wg sync.WaitGroup
 	batchSize := DefaultWriteBatchSize
@@ -15,6 +15,7 @@ const (
 	ReadRepositoryAction   = "fs:ReadRepository"
 	CreateRepositoryAction = "fs:CreateRepository"
 	DeleteRepositoryAction = "fs:DeleteRepository"
+	UpdateRepositoryAction = "fs:UpdateRepository"
 	ListRepositoriesAction = "fs:ListRepositories"
 	ReadObjectAction       = "fs:ReadObject"
 	WriteObjectAction      = "fs:WriteObject"
@@ -27,6 +28,16 @@ const (
 	ReadBranchAction       = "fs:ReadBranch"
 	RevertBranchAction     = "fs:RevertBranch"
 	ListBranchesAction     = "fs:ListBranches"
+	CreateTagAction        = "fs:CreateTag"
+	DeleteTagAction        = "fs:DeleteTag"
+	ReadTagAction          = "fs:ReadTag"
+	ListTagsAction         = "fs:ListTags"
+
+	// WriteProtectedObjectAction allows writes and deletes of paths protected by the repository
+	WriteProtectedObjectAction = "fs:WriteProtectedObject"
+
+	// SetQuotasAction allows changing the quotas of a repository
+	SetQuotasAction = "fs:SetQuotas"
 
 	RetentionReadPolicyAction  = "retention:GetPolicy"
 	RetentionWritePolicyAction = "retention:WritePolicy"
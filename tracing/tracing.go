@@ -0,0 +1,96 @@
+// Package tracing traces operations across the API, the catalog and its database transactions.
+// Spans are OpenCensus spans, continued from the W3C traceparent header of incoming requests and
+// exported to the log.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/treeverse/lakefs/logging"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.opencensus.io/trace"
+)
+
+type Params struct {
+	Enabled bool
+	// SampleRatio is the fraction of traces started by lakeFS that are recorded.  Traces
+	// continued from a sampled caller are always recorded.
+	SampleRatio float64
+}
+
+var exporter = &logExporter{}
+
+// Configure applies params to the spans started from now on.  When tracing is disabled spans
+// are never recorded, and starting them costs little.
+func Configure(params Params) {
+	sampler := trace.NeverSample()
+	if params.Enabled {
+		sampler = trace.ProbabilitySampler(params.SampleRatio)
+		trace.RegisterExporter(exporter)
+	} else {
+		trace.UnregisterExporter(exporter)
+	}
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler})
+}
+
+// Start starts a span named name, a child of the span of ctx if it has one.
+func Start(ctx context.Context, name string, attributes ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	if len(attributes) > 0 {
+		span.AddAttributes(attributes...)
+	}
+	return ctx, span
+}
+
+// End ends span, recording err as its status.
+func End(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// Middleware starts a span for each request, named by operation and continuing the trace of the
+// traceparent header.  The trace ID is added to the log fields of the request.
+func Middleware(operation string, next http.Handler) http.Handler {
+	return &ochttp.Handler{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if span := trace.FromContext(r.Context()); span != nil && span.IsRecordingEvents() {
+				r = r.WithContext(logging.AddFields(r.Context(), logging.Fields{
+					"trace_id": span.SpanContext().TraceID.String(),
+				}))
+			}
+			next.ServeHTTP(w, r)
+		}),
+		Propagation: &tracecontext.HTTPFormat{},
+		FormatSpanName: func(r *http.Request) string {
+			return operation + " " + r.Method
+		},
+	}
+}
+
+// logExporter writes the recorded spans to the log
+type logExporter struct{}
+
+func (e *logExporter) ExportSpan(s *trace.SpanData) {
+	fields := logging.Fields{
+		"trace_id": s.TraceID.String(),
+		"span_id":  s.SpanID.String(),
+		"span":     s.Name,
+		"took":     s.EndTime.Sub(s.StartTime).Round(time.Microsecond),
+	}
+	if s.ParentSpanID != (trace.SpanID{}) {
+		fields["parent_span_id"] = s.ParentSpanID.String()
+	}
+	for k, v := range s.Attributes {
+		fields["span."+k] = v
+	}
+	log := logging.Default().WithFields(fields)
+	if s.Code != trace.StatusCodeOK {
+		log = log.WithField("status", s.Message)
+	}
+	log.Info("span ended")
+}
@@ -0,0 +1,93 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/treeverse/lakefs/tracing"
+	"go.opencensus.io/trace"
+)
+
+type captureExporter struct {
+	mu    sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (e *captureExporter) ExportSpan(s *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+func testExporter(t *testing.T) *captureExporter {
+	t.Helper()
+	tracing.Configure(tracing.Params{Enabled: true, SampleRatio: 1})
+	e := &captureExporter{}
+	trace.RegisterExporter(e)
+	t.Cleanup(func() {
+		trace.UnregisterExporter(e)
+		tracing.Configure(tracing.Params{})
+	})
+	return e
+}
+
+func TestStartEnd(t *testing.T) {
+	e := testExporter(t)
+	ctx, parent := tracing.Start(context.Background(), "parent", trace.StringAttribute("repository", "repo"))
+	_, child := tracing.Start(ctx, "child")
+	tracing.End(child, errors.New("failed"))
+	tracing.End(parent, nil)
+
+	if len(e.spans) != 2 {
+		t.Fatalf("exported %d spans, expected 2", len(e.spans))
+	}
+	childData, parentData := e.spans[0], e.spans[1]
+	if childData.ParentSpanID != parentData.SpanID || childData.TraceID != parentData.TraceID {
+		t.Errorf("span child is not a child of span parent")
+	}
+	if childData.Code == trace.StatusCodeOK || childData.Message != "failed" {
+		t.Errorf("span child status %d %s, expected the error", childData.Code, childData.Message)
+	}
+	if parentData.Code != trace.StatusCodeOK {
+		t.Errorf("span parent status %d, expected OK", parentData.Code)
+	}
+	if parentData.Attributes["repository"] != "repo" {
+		t.Errorf("span parent attributes %v, expected repository", parentData.Attributes)
+	}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	tracing.Configure(tracing.Params{})
+	e := &captureExporter{}
+	trace.RegisterExporter(e)
+	defer trace.UnregisterExporter(e)
+
+	_, span := tracing.Start(context.Background(), "span")
+	tracing.End(span, nil)
+	if len(e.spans) != 0 {
+		t.Errorf("exported %d spans while tracing is disabled", len(e.spans))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	e := testExporter(t)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var gotTraceID string
+	handler := tracing.Middleware("api", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceID = trace.FromContext(r.Context()).SpanContext().TraceID.String()
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/repositories", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotTraceID != traceID {
+		t.Errorf("request trace %s, expected the trace of traceparent %s", gotTraceID, traceID)
+	}
+	if len(e.spans) != 1 || e.spans[0].Name != "api GET" {
+		t.Fatalf("exported spans %v, expected the request span", e.spans)
+	}
+}
@@ -2,12 +2,16 @@ package upload
 
 import (
 	"encoding/hex"
+	"errors"
 	"io"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/treeverse/lakefs/block"
 )
 
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
 type Blob struct {
 	PhysicalAddress string
 	Checksum        string
@@ -36,3 +40,21 @@ func WriteBlob(adapter block.Adapter, bucketName string, body io.Reader, content
 		Size:            hashReader.CopiedSize,
 	}, nil
 }
+
+// VerifyChecksum checks the blob content against a client supplied hex encoded MD5 checksum.
+// An empty checksum is not verified.
+func (b *Blob) VerifyChecksum(checksum string) error {
+	if checksum == "" || strings.EqualFold(checksum, b.Checksum) {
+		return nil
+	}
+	return ErrChecksumMismatch
+}
+
+// RemoveBlob removes the data of a blob that is not referenced by any entry, such as a blob that
+// failed checksum verification
+func RemoveBlob(adapter block.Adapter, bucketName string, blob *Blob) error {
+	return adapter.Remove(block.ObjectPointer{
+		StorageNamespace: bucketName,
+		Identifier:       blob.PhysicalAddress,
+	})
+}
@@ -0,0 +1,53 @@
+package upload_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/upload"
+)
+
+func TestBlob_VerifyChecksum(t *testing.T) {
+	const content = "hello world"
+	blob, err := upload.WriteBlob(mem.New(), "mem://upload", strings.NewReader(content), int64(len(content)), block.PutOpts{})
+	if err != nil {
+		t.Fatalf("WriteBlob() error = %s", err)
+	}
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  error
+	}{
+		{name: "empty", checksum: ""},
+		{name: "match", checksum: "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{name: "upper case", checksum: "5EB63BBBE01EEED093CB22BB8F5ACDC3"},
+		{name: "mismatch", checksum: "00000000000000000000000000000000", wantErr: upload.ErrChecksumMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := blob.VerifyChecksum(tt.checksum)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyChecksum() error = %v, expected %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoveBlob(t *testing.T) {
+	const content = "hello world"
+	adapter := mem.New()
+	blob, err := upload.WriteBlob(adapter, "mem://upload", strings.NewReader(content), int64(len(content)), block.PutOpts{})
+	if err != nil {
+		t.Fatalf("WriteBlob() error = %s", err)
+	}
+	if err := upload.RemoveBlob(adapter, "mem://upload", blob); err != nil {
+		t.Fatalf("RemoveBlob() error = %s", err)
+	}
+	_, err = adapter.Get(block.ObjectPointer{StorageNamespace: "mem://upload", Identifier: blob.PhysicalAddress}, blob.Size)
+	if err == nil {
+		t.Error("Get() of a removed blob expected to fail")
+	}
+}
@@ -66,6 +66,15 @@ func (u *URI) String() string {
 	return buf.String()
 }
 
+// PathPrefix returns the path as a directory prefix, ending with a path separator, so it matches
+// only the objects under the path and not siblings that share its name as a prefix
+func (u *URI) PathPrefix() string {
+	if u.Path == "" || strings.HasSuffix(u.Path, string(PathSeparator)) {
+		return u.Path
+	}
+	return u.Path + string(PathSeparator)
+}
+
 func Parse(str string) (*URI, error) {
 	// start with protocol
 	protoParts := strings.Split(str, ProtocolSeparator)
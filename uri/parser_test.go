@@ -96,6 +96,24 @@ func TestURI_String(t *testing.T) {
 	}
 }
 
+func TestURI_PathPrefix(t *testing.T) {
+	cases := []struct {
+		Path     string
+		Expected string
+	}{
+		{Path: "", Expected: ""},
+		{Path: "data", Expected: "data/"},
+		{Path: "data/", Expected: "data/"},
+		{Path: "data/logs", Expected: "data/logs/"},
+	}
+	for _, test := range cases {
+		u := &uri.URI{Protocol: "lakefs", Repository: "foo", Ref: "bar", Path: test.Path}
+		if prefix := u.PathPrefix(); prefix != test.Expected {
+			t.Fatalf("PathPrefix() of path '%s' = '%s', expected '%s'", test.Path, prefix, test.Expected)
+		}
+	}
+}
+
 func TestIsValid(t *testing.T) {
 	cases := []struct {
 		Input    string